@@ -163,6 +163,23 @@ func NewKeyedTransactorWithChainID(key *ecdsa.PrivateKey, chainID *big.Int) (*Tr
 	}, nil
 }
 
+// NewKeyedFeePayerSigner is a utility method to easily create a fee payer
+// countersigning function from a single private key, to be used as the
+// FeePayerSigner of a TransactOpts.
+func NewKeyedFeePayerSigner(key *ecdsa.PrivateKey, chainID *big.Int) (common.Address, SignerFn, error) {
+	keyAddr := crypto.PubkeyToAddress(key.PublicKey)
+	if chainID == nil {
+		return common.Address{}, nil, ErrNoChainID
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	return keyAddr, func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != keyAddr {
+			return nil, ErrNotAuthorized
+		}
+		return types.SignFeePayerTx(tx, signer, key)
+	}, nil
+}
+
 // NewClefTransactor is a utility method to easily create a transaction signer
 // with a clef backend.
 func NewClefTransactor(clef *external.ExternalSigner, account accounts.Account) *TransactOpts {
@@ -68,6 +68,9 @@ type TransactOpts struct {
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 
 	NoSend bool // Do all transact steps but do not send the transaction
+
+	FeePayer       common.Address // Account sponsoring the gas of the transaction (requires FeePayerSigner)
+	FeePayerSigner SignerFn       // Method to countersign a sponsored transaction (nil = sender pays the gas)
 }
 
 // FilterOpts is the collection of options to fine tune filtering for events
@@ -311,6 +314,60 @@ func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *common.Add
 	return types.NewTx(baseTx), nil
 }
 
+func (c *BoundContract) createFeePayerTx(opts *TransactOpts, contract *common.Address, input []byte, head *types.Header) (*types.Transaction, error) {
+	// Normalize value
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	// Estimate TipCap
+	gasTipCap := opts.GasTipCap
+	if gasTipCap == nil {
+		tip, err := c.transactor.SuggestGasTipCap(ensureContext(opts.Context))
+		if err != nil {
+			return nil, err
+		}
+		gasTipCap = tip
+	}
+	// Estimate FeeCap
+	gasFeeCap := opts.GasFeeCap
+	if gasFeeCap == nil {
+		gasFeeCap = new(big.Int).Add(
+			gasTipCap,
+			new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier)),
+		)
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+	}
+	// Estimate GasLimit without any pricing, the sender isn't the one paying
+	// for the gas so its balance must not cap the estimate
+	gasLimit := opts.GasLimit
+	if opts.GasLimit == 0 {
+		var err error
+		gasLimit, err = c.estimateGasLimit(opts, contract, input, nil, nil, nil, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// create the transaction
+	nonce, err := c.getNonce(opts)
+	if err != nil {
+		return nil, err
+	}
+	baseTx := &types.FeePayerTx{
+		To:        contract,
+		Nonce:     nonce,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Gas:       gasLimit,
+		Value:     value,
+		Data:      input,
+		FeePayer:  opts.FeePayer,
+	}
+	return types.NewTx(baseTx), nil
+}
+
 func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
 	if opts.GasFeeCap != nil || opts.GasTipCap != nil {
 		return nil, errors.New("maxFeePerGas or maxPriorityFeePerGas specified but london is not active yet")
@@ -394,7 +451,21 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		rawTx *types.Transaction
 		err   error
 	)
-	if opts.GasPrice != nil {
+	if opts.FeePayerSigner != nil {
+		if opts.GasPrice != nil {
+			return nil, errors.New("gasPrice specified for a sponsored transaction")
+		}
+		var head *types.Header
+		if opts.GasFeeCap == nil || opts.GasTipCap == nil {
+			if head, err = c.transactor.HeaderByNumber(ensureContext(opts.Context), nil); err != nil {
+				return nil, err
+			}
+			if head.BaseFee == nil {
+				return nil, errors.New("sponsored transactions require london to be active")
+			}
+		}
+		rawTx, err = c.createFeePayerTx(opts, contract, input, head)
+	} else if opts.GasPrice != nil {
 		rawTx, err = c.createLegacyTx(opts, contract, input)
 	} else if opts.GasFeeCap != nil && opts.GasTipCap != nil {
 		rawTx, err = c.createDynamicTx(opts, contract, input, nil)
@@ -420,6 +491,12 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 	if err != nil {
 		return nil, err
 	}
+	// Have the fee payer countersign the already signed transaction
+	if opts.FeePayerSigner != nil {
+		if signedTx, err = opts.FeePayerSigner(opts.FeePayer, signedTx); err != nil {
+			return nil, err
+		}
+	}
 	if opts.NoSend {
 		return signedTx, nil
 	}
@@ -38,6 +38,10 @@ var (
 		Name:  "trace.jsonconfig",
 		Usage: "The configurations for the custom tracer specified by --trace.tracer. If provided, must be in JSON format",
 	}
+	TraceGasProfileFlag = &cli.StringFlag{
+		Name:  "trace.gasprofile",
+		Usage: "Configures the use of the gas profiler, aggregating the gas used per call stack and opcode in the given format (folded or pprof). The profiles are written to files as gasprofile-<txIndex>-<txHash>.<folded|pb.gz>",
+	}
 	TraceEnableMemoryFlag = &cli.BoolFlag{
 		Name:  "trace.memory",
 		Usage: "Enable full memory dump in traces",
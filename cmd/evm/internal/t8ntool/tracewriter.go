@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -33,6 +34,7 @@ import (
 type traceWriter struct {
 	inner vm.EVMLogger
 	f     io.WriteCloser
+	write func(w io.Writer, result json.RawMessage) error // Writes the tracer result, as JSON if nil
 }
 
 // Compile-time interface check
@@ -48,7 +50,11 @@ func (t *traceWriter) CaptureTxEnd(restGas uint64) {
 			log.Warn("Error in tracer", "err", err)
 			return
 		}
-		err = json.NewEncoder(t.f).Encode(result)
+		if t.write != nil {
+			err = t.write(t.f, result)
+		} else {
+			err = json.NewEncoder(t.f).Encode(result)
+		}
 		if err != nil {
 			log.Warn("Error writing tracer output", "err", err)
 			return
@@ -79,3 +85,31 @@ func (t *traceWriter) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, sc
 func (t *traceWriter) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
 	t.inner.CaptureFault(pc, op, gas, cost, scope, depth, err)
 }
+
+// GasProfileExt returns the file extension of the gas profiles in the given
+// format.
+func GasProfileExt(format string) string {
+	if format == native.GasProfilePprof {
+		return "pb.gz"
+	}
+	return native.GasProfileFolded
+}
+
+// WriteGasProfile writes the result of the gas profiler in its raw form, the
+// folded stacks as text or the pprof profile as binary.
+func WriteGasProfile(w io.Writer, result json.RawMessage, format string) error {
+	var data []byte
+	if format == native.GasProfilePprof {
+		if err := json.Unmarshal(result, &data); err != nil {
+			return err
+		}
+	} else {
+		var folded string
+		if err := json.Unmarshal(result, &folded); err != nil {
+			return err
+		}
+		data = []byte(folded)
+	}
+	_, err := w.Write(data)
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"path"
@@ -100,7 +101,7 @@ func Transition(ctx *cli.Context) error {
 			if err != nil {
 				return nil, NewError(ErrorIO, fmt.Errorf("failed creating trace-file: %v", err))
 			}
-			return &traceWriter{logger.NewJSONLogger(logConfig, traceFile), traceFile}, nil
+			return &traceWriter{inner: logger.NewJSONLogger(logConfig, traceFile), f: traceFile}, nil
 		}
 	} else if ctx.IsSet(TraceTracerFlag.Name) {
 		var config json.RawMessage
@@ -116,7 +117,27 @@ func Transition(ctx *cli.Context) error {
 			if err != nil {
 				return nil, NewError(ErrorConfig, fmt.Errorf("failed instantiating tracer: %w", err))
 			}
-			return &traceWriter{tracer, traceFile}, nil
+			return &traceWriter{inner: tracer, f: traceFile}, nil
+		}
+	} else if ctx.IsSet(TraceGasProfileFlag.Name) {
+		format := ctx.String(TraceGasProfileFlag.Name)
+		config, err := json.Marshal(map[string]string{"format": format})
+		if err != nil {
+			return NewError(ErrorConfig, err)
+		}
+		getTracer = func(txIndex int, txHash common.Hash) (vm.EVMLogger, error) {
+			profiler, err := tracers.DefaultDirectory.New("gasProfiler", nil, config)
+			if err != nil {
+				return nil, NewError(ErrorConfig, fmt.Errorf("failed instantiating gas profiler: %w", err))
+			}
+			profileFile, err := os.Create(path.Join(baseDir, fmt.Sprintf("gasprofile-%d-%v.%s", txIndex, txHash.String(), GasProfileExt(format))))
+			if err != nil {
+				return nil, NewError(ErrorIO, fmt.Errorf("failed creating profile-file: %v", err))
+			}
+			write := func(w io.Writer, result json.RawMessage) error {
+				return WriteGasProfile(w, result, format)
+			}
+			return &traceWriter{inner: profiler, f: profileFile, write: write}, nil
 		}
 	}
 	// We need to load three things: alloc, env and transactions. May be either in
@@ -138,6 +138,17 @@ var (
 		Usage:    "enable return data output",
 		Category: flags.VMCategory,
 	}
+	GasProfileFlag = &cli.StringFlag{
+		Name:     "gasprofile",
+		Usage:    "write a profile of the gas used per call stack and opcode to the given file",
+		Category: flags.VMCategory,
+	}
+	GasProfileFormatFlag = &cli.StringFlag{
+		Name:     "gasprofile.format",
+		Usage:    "format of the gas profile, folded stacks (folded) or pprof protobuf (pprof)",
+		Value:    "folded",
+		Category: flags.VMCategory,
+	}
 )
 
 var stateTransitionCommand = &cli.Command{
@@ -149,6 +160,7 @@ var stateTransitionCommand = &cli.Command{
 		t8ntool.TraceFlag,
 		t8ntool.TraceTracerFlag,
 		t8ntool.TraceTracerConfigFlag,
+		t8ntool.TraceGasProfileFlag,
 		t8ntool.TraceEnableMemoryFlag,
 		t8ntool.TraceDisableStackFlag,
 		t8ntool.TraceEnableReturnDataFlag,
@@ -219,6 +231,8 @@ var traceFlags = []cli.Flag{
 	DisableStackFlag,
 	DisableStorageFlag,
 	DisableReturnDataFlag,
+	GasProfileFlag,
+	GasProfileFormatFlag,
 }
 
 var app = flags.NewApp("the evm command line interface")
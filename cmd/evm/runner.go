@@ -19,6 +19,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -28,6 +29,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/evm/internal/compiler"
+	"github.com/ethereum/go-ethereum/cmd/evm/internal/t8ntool"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -35,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
@@ -134,6 +137,19 @@ func runCmd(ctx *cli.Context) error {
 	} else {
 		debugLogger = logger.NewStructLogger(logconfig)
 	}
+	var profiler tracers.Tracer
+	if ctx.IsSet(GasProfileFlag.Name) {
+		if tracer != nil {
+			return errors.New("gas profiling can't be combined with tracing")
+		}
+		config, err := json.Marshal(map[string]string{"format": ctx.String(GasProfileFormatFlag.Name)})
+		if err != nil {
+			return err
+		}
+		if profiler, err = tracers.DefaultDirectory.New("gasProfiler", nil, config); err != nil {
+			return err
+		}
+	}
 
 	initialGas := ctx.Uint64(GasFlag.Name)
 	genesisConfig := new(core.Genesis)
@@ -228,6 +244,10 @@ func runCmd(ctx *cli.Context) error {
 		},
 	}
 
+	if profiler != nil {
+		runtimeConfig.EVMConfig.Tracer = profiler
+	}
+
 	if chainConfig != nil {
 		runtimeConfig.ChainConfig = chainConfig
 	} else {
@@ -270,6 +290,12 @@ func runCmd(ctx *cli.Context) error {
 	bench := ctx.Bool(BenchFlag.Name)
 	output, leftOverGas, stats, err := timedExec(bench, execFunc)
 
+	if profiler != nil {
+		if err := writeGasProfile(ctx, profiler); err != nil {
+			return err
+		}
+	}
+
 	if ctx.Bool(DumpFlag.Name) {
 		statedb.Commit(genesisConfig.Number, true)
 		fmt.Println(string(statedb.Dump(nil)))
@@ -300,3 +326,19 @@ allocated bytes: %d
 
 	return nil
 }
+
+// writeGasProfile writes the result of the gas profiler to the file configured
+// by the user.
+func writeGasProfile(ctx *cli.Context, profiler tracers.Tracer) error {
+	result, err := profiler.GetResult()
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(ctx.String(GasProfileFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t8ntool.WriteGasProfile(file, result, ctx.String(GasProfileFormatFlag.Name))
+}
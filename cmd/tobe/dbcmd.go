@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -79,6 +81,8 @@ Remove blockchain and state databases`,
 			dbExportCmd,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
+			dbReindexTokensCmd,
+			dbReindexLogsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -203,6 +207,28 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "Shows metadata about the chain status.",
 	}
+	dbReindexTokensCmd = &cli.Command{
+		Action: reindexTokens,
+		Name:   "reindex-tokens",
+		Usage:  "Rebuilds the token transfer index from the canonical chain",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command drops the ERC-20/721/1155 token transfer index and rebuilds it
+from the receipts of the canonical chain. If interrupted, the node completes the
+rebuild on startup when running with --history.tokens.index.`,
+	}
+	dbReindexLogsCmd = &cli.Command{
+		Action: reindexLogs,
+		Name:   "reindex-logs",
+		Usage:  "Rebuilds the log index from the canonical chain",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command drops the address and topic log index and rebuilds it from the
+receipts of the canonical chain. If interrupted, the node completes the rebuild
+on startup when running with --history.logs.index.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -759,3 +785,53 @@ func showMetaData(ctx *cli.Context) error {
 	table.Render()
 	return nil
 }
+
+// reindexTokens rebuilds the token transfer index from the canonical chain.
+func reindexTokens(ctx *cli.Context) error {
+	var (
+		stack, _  = makeConfigNode(ctx)
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	defer stack.Close()
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during token reindexing, stopping at next block")
+		}
+		close(stop)
+	}()
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil {
+		return errors.New("chain config not found")
+	}
+	return core.RebuildTokenIndex(db, config, stop)
+}
+
+// reindexLogs rebuilds the log index from the canonical chain.
+func reindexLogs(ctx *cli.Context) error {
+	var (
+		stack, _  = makeConfigNode(ctx)
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	defer stack.Close()
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during log reindexing, stopping at next block")
+		}
+		close(stop)
+	}()
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	return core.RebuildLogIndex(db, stop)
+}
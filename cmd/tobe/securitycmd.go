@@ -0,0 +1,659 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+)
+
+// Names of the security policy lists handled by the security command.
+const (
+	securityWhitelist = "whitelist"
+	securityBlacklist = "blacklist"
+	securityFrozen    = "frozen"
+	securityContracts = "contracts"
+)
+
+var securityListNames = []string{securityWhitelist, securityBlacklist, securityFrozen, securityContracts}
+
+var (
+	securityListFlag = &cli.StringFlag{
+		Name:  "list",
+		Usage: "Restrict the operation to a single list (whitelist, blacklist, frozen, contracts)",
+	}
+	securityFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "File format (json, csv); derived from the file extension if not set",
+	}
+	securityReplaceFlag = &cli.BoolFlag{
+		Name:  "replace",
+		Usage: "Replace the selected lists instead of merging the file into them",
+	}
+
+	securityCommand = &cli.Command{
+		Name:      "security",
+		Usage:     "Offline management of the security policy lists",
+		ArgsUsage: "",
+		Subcommands: []*cli.Command{
+			securityShowCmd,
+			securityImportCmd,
+			securityExportCmd,
+			securityDiffCmd,
+		},
+		Description: `
+The security commands operate directly on the security policy stored in the node
+database, without a running node. They are meant for bootstrapping and auditing
+the whitelist, blacklist, frozen accounts and deployed contracts lists.
+
+JSON files hold an object with the keys "whitelist", "blacklist", "frozen" (arrays
+of addresses) and "contracts" (an object mapping owners to contract addresses). If
+--list is set, the file instead holds just that list.
+
+CSV files hold one entry per row as "<list>,<address>", or "contracts,<owner>,<contract>"
+for deployed contracts. If --list is set, the list column is omitted. Empty rows,
+rows starting with '#' and a leading "list,address,contract" header row (without
+the list column if --list is set) are ignored. The contracts of an owner are
+registered in file order.`,
+	}
+	securityShowCmd = &cli.Command{
+		Action: securityShow,
+		Name:   "show",
+		Usage:  "Print the security policy lists stored in the database",
+		Flags: flags.Merge([]cli.Flag{
+			securityListFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+	}
+	securityImportCmd = &cli.Command{
+		Action:    securityImport,
+		Name:      "import",
+		Usage:     "Import security policy lists from a JSON or CSV file",
+		ArgsUsage: "<file>",
+		Flags: flags.Merge([]cli.Flag{
+			securityListFlag,
+			securityFormatFlag,
+			securityReplaceFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command merges the entries of the given file into the stored lists.
+If --replace is set, the selected lists are cleared first.`,
+	}
+	securityExportCmd = &cli.Command{
+		Action:    securityExport,
+		Name:      "export",
+		Usage:     "Export security policy lists to a JSON or CSV file",
+		ArgsUsage: "<file>",
+		Flags: flags.Merge([]cli.Flag{
+			securityListFlag,
+			securityFormatFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: "This command writes the stored lists to the given file, or to stdout if the file is '-'.",
+	}
+	securityDiffCmd = &cli.Command{
+		Action:    securityDiff,
+		Name:      "diff",
+		Usage:     "Compare the stored security policy lists against a file",
+		ArgsUsage: "<file>",
+		Flags: flags.Merge([]cli.Flag{
+			securityListFlag,
+			securityFormatFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command prints the entries only present in the file prefixed with '+'
+and the entries only present in the database prefixed with '-'.`,
+	}
+)
+
+// securityLists is the file representation of the security policy lists.
+type securityLists struct {
+	Whitelist []common.Address                    `json:"whitelist,omitempty"`
+	Blacklist []common.Address                    `json:"blacklist,omitempty"`
+	Frozen    []common.Address                    `json:"frozen,omitempty"`
+	Contracts map[common.Address][]common.Address `json:"contracts,omitempty"`
+}
+
+// securityEntry is a single flattened entry of a security policy list. The
+// contract field is only set for the contracts list.
+type securityEntry struct {
+	list     string
+	address  common.Address
+	contract common.Address
+}
+
+func (e securityEntry) String() string {
+	if e.list == securityContracts {
+		return fmt.Sprintf("%s %s %s", e.list, e.address.Hex(), e.contract.Hex())
+	}
+	return fmt.Sprintf("%s %s", e.list, e.address.Hex())
+}
+
+// entries flattens the selected lists into an entry slice sorted by list and
+// address.
+func (l *securityLists) entries(selected []string) []securityEntry {
+	var entries []securityEntry
+	for _, list := range selected {
+		switch list {
+		case securityWhitelist:
+			for _, addr := range l.Whitelist {
+				entries = append(entries, securityEntry{list: list, address: addr})
+			}
+		case securityBlacklist:
+			for _, addr := range l.Blacklist {
+				entries = append(entries, securityEntry{list: list, address: addr})
+			}
+		case securityFrozen:
+			for _, addr := range l.Frozen {
+				entries = append(entries, securityEntry{list: list, address: addr})
+			}
+		case securityContracts:
+			for owner, contracts := range l.Contracts {
+				for _, contract := range contracts {
+					entries = append(entries, securityEntry{list: list, address: owner, contract: contract})
+				}
+			}
+		}
+	}
+	// Contracts of an owner keep their registration order, as the stored policy
+	// indexes them by position.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].list != entries[j].list {
+			return entries[i].list < entries[j].list
+		}
+		return entries[i].address.Cmp(entries[j].address) < 0
+	})
+	return entries
+}
+
+// add inserts a single entry into the lists.
+func (l *securityLists) add(e securityEntry) {
+	switch e.list {
+	case securityWhitelist:
+		l.Whitelist = append(l.Whitelist, e.address)
+	case securityBlacklist:
+		l.Blacklist = append(l.Blacklist, e.address)
+	case securityFrozen:
+		l.Frozen = append(l.Frozen, e.address)
+	case securityContracts:
+		if l.Contracts == nil {
+			l.Contracts = make(map[common.Address][]common.Address)
+		}
+		l.Contracts[e.address] = append(l.Contracts[e.address], e.contract)
+	}
+}
+
+// securityListsFromConfig extracts the lists from a security config.
+func securityListsFromConfig(sc *core.SecurityConfig) *securityLists {
+	lists := &securityLists{
+		Contracts: sc.GetAllDeployedContracts(),
+	}
+	for addr := range sc.GetWhitelistAddresses() {
+		lists.Whitelist = append(lists.Whitelist, addr)
+	}
+	for addr := range sc.GetBlacklistAddresses() {
+		lists.Blacklist = append(lists.Blacklist, addr)
+	}
+	for addr := range sc.GetFrozenAddresses() {
+		lists.Frozen = append(lists.Frozen, addr)
+	}
+	return lists
+}
+
+// applyToConfig adds all entries of the selected lists to the security config.
+func (l *securityLists) applyToConfig(sc *core.SecurityConfig, selected []string) error {
+	for _, e := range l.entries(selected) {
+		var err error
+		switch e.list {
+		case securityWhitelist:
+			err = sc.AddToWhitelist(e.address)
+		case securityBlacklist:
+			err = sc.AddToBlacklist(e.address)
+		case securityFrozen:
+			err = sc.FreezeAccount(e.address)
+		case securityContracts:
+			err = sc.RegisterDeployedContract(e.address, e.contract)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s entry %s: %v", e.list, e.address.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// selectedSecurityLists returns the lists selected by the --list flag.
+func selectedSecurityLists(ctx *cli.Context) ([]string, error) {
+	if !ctx.IsSet(securityListFlag.Name) {
+		return securityListNames, nil
+	}
+	list := ctx.String(securityListFlag.Name)
+	for _, name := range securityListNames {
+		if name == list {
+			return []string{list}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown list %q, want one of %s", list, strings.Join(securityListNames, ", "))
+}
+
+// securityFileFormat returns the file format selected by the --format flag or
+// derived from the file extension.
+func securityFileFormat(ctx *cli.Context, path string) (string, error) {
+	format := ctx.String(securityFormatFlag.Name)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case "json", "csv":
+		return format, nil
+	case "":
+		return "", errors.New("unable to derive file format, use --format")
+	default:
+		return "", fmt.Errorf("unsupported file format %q", format)
+	}
+}
+
+// readSecurityLists parses a security lists file. If single is non-empty, the
+// file holds only the entries of that list.
+func readSecurityLists(r io.Reader, format string, single string) (*securityLists, error) {
+	lists := new(securityLists)
+	switch format {
+	case "json":
+		dec := json.NewDecoder(r)
+		switch single {
+		case "":
+			if err := dec.Decode(lists); err != nil {
+				return nil, err
+			}
+		case securityContracts:
+			if err := dec.Decode(&lists.Contracts); err != nil {
+				return nil, err
+			}
+		default:
+			var addrs []common.Address
+			if err := dec.Decode(&addrs); err != nil {
+				return nil, err
+			}
+			for _, addr := range addrs {
+				lists.add(securityEntry{list: single, address: addr})
+			}
+		}
+		return lists, nil
+
+	case "csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.Comment = '#'
+		reader.TrimLeadingSpace = true
+
+		for row := 0; ; row++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return lists, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Tolerate a header row at the top of the file
+			if row == 0 && isSecurityHeader(record, single) {
+				continue
+			}
+			e, err := parseSecurityRecord(record, single)
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			lists.add(e)
+		}
+	}
+	return nil, fmt.Errorf("unsupported file format %q", format)
+}
+
+// isSecurityHeader reports whether the CSV record is a header row naming the
+// expected columns.
+func isSecurityHeader(record []string, single string) bool {
+	columns := []string{"list", "address", "contract"}
+	if single != "" {
+		columns = columns[1:]
+	}
+	if len(record) == 0 || len(record) > len(columns) {
+		return false
+	}
+	for i, field := range record {
+		if !strings.EqualFold(strings.TrimSpace(field), columns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSecurityRecord parses a single CSV record into a list entry.
+func parseSecurityRecord(record []string, single string) (securityEntry, error) {
+	e := securityEntry{list: single}
+	if single == "" {
+		if len(record) == 0 {
+			return e, errors.New("empty record")
+		}
+		e.list, record = strings.TrimSpace(record[0]), record[1:]
+	}
+	want := 1
+	if e.list == securityContracts {
+		want = 2
+	}
+	if len(record) != want {
+		return e, fmt.Errorf("invalid %s record, want %d address field(s), have %d", e.list, want, len(record))
+	}
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if !common.IsHexAddress(field) {
+			return e, fmt.Errorf("invalid address %q", field)
+		}
+		if i == 0 {
+			e.address = common.HexToAddress(field)
+		} else {
+			e.contract = common.HexToAddress(field)
+		}
+	}
+	switch e.list {
+	case securityWhitelist, securityBlacklist, securityFrozen, securityContracts:
+		return e, nil
+	}
+	return e, fmt.Errorf("unknown list %q", e.list)
+}
+
+// writeSecurityLists writes the lists in the given format. If single is
+// non-empty, only the entries of that list are written.
+func writeSecurityLists(w io.Writer, lists *securityLists, format string, single string) error {
+	selected := securityListNames
+	if single != "" {
+		selected = []string{single}
+	}
+	entries := lists.entries(selected)
+
+	switch format {
+	case "json":
+		var out interface{}
+		sorted := new(securityLists)
+		for _, e := range entries {
+			sorted.add(e)
+		}
+		switch single {
+		case "":
+			out = sorted
+		case securityWhitelist:
+			out = orEmpty(sorted.Whitelist)
+		case securityBlacklist:
+			out = orEmpty(sorted.Blacklist)
+		case securityFrozen:
+			out = orEmpty(sorted.Frozen)
+		case securityContracts:
+			if sorted.Contracts == nil {
+				sorted.Contracts = make(map[common.Address][]common.Address)
+			}
+			out = sorted.Contracts
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+
+	case "csv":
+		writer := csv.NewWriter(w)
+		for _, e := range entries {
+			var record []string
+			if single == "" {
+				record = append(record, e.list)
+			}
+			record = append(record, e.address.Hex())
+			if e.list == securityContracts {
+				record = append(record, e.contract.Hex())
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return fmt.Errorf("unsupported file format %q", format)
+}
+
+func orEmpty(addrs []common.Address) []common.Address {
+	if addrs == nil {
+		return []common.Address{}
+	}
+	return addrs
+}
+
+// singleSecurityList returns the list name if exactly one list is selected.
+func singleSecurityList(selected []string) string {
+	if len(selected) == 1 {
+		return selected[0]
+	}
+	return ""
+}
+
+func securityShow(ctx *cli.Context) error {
+	selected, err := selectedSecurityLists(ctx)
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	sc, err := core.LoadSecurityConfig(db)
+	if err != nil {
+		return err
+	}
+	lists := securityListsFromConfig(sc)
+	for _, list := range selected {
+		entries := lists.entries([]string{list})
+		fmt.Printf("%s (%d entries)\n", list, len(entries))
+		for _, e := range entries {
+			if list == securityContracts {
+				fmt.Printf("  %s -> %s\n", e.address.Hex(), e.contract.Hex())
+			} else {
+				fmt.Printf("  %s\n", e.address.Hex())
+			}
+		}
+	}
+	return nil
+}
+
+func securityImport(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	selected, err := selectedSecurityLists(ctx)
+	if err != nil {
+		return err
+	}
+	path := ctx.Args().First()
+	format, err := securityFileFormat(ctx, path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	lists, err := readSecurityLists(f, format, singleSecurityList(selected))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	sc, err := core.LoadSecurityConfig(db)
+	if err != nil {
+		return err
+	}
+	// Assemble the resulting policy in memory and write it out in a single batch,
+	// so an interrupted import leaves the stored policy untouched.
+	keep := securityListNames
+	if ctx.Bool(securityReplaceFlag.Name) {
+		keep = nil
+		for _, list := range securityListNames {
+			if !slices.Contains(selected, list) {
+				keep = append(keep, list)
+			}
+		}
+	}
+	merged := core.NewSecurityConfig()
+	if err := securityListsFromConfig(sc).applyToConfig(merged, keep); err != nil {
+		return err
+	}
+	for _, r := range sc.GetRestrictions() {
+		if err := merged.AddRestriction(r); err != nil {
+			return err
+		}
+	}
+	if err := lists.applyToConfig(merged, selected); err != nil {
+		return err
+	}
+	if err := merged.SaveConfig(db); err != nil {
+		return err
+	}
+	log.Info("Imported security policy lists", "file", path, "entries", len(lists.entries(selected)))
+	return nil
+}
+
+func securityExport(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	selected, err := selectedSecurityLists(ctx)
+	if err != nil {
+		return err
+	}
+	path := ctx.Args().First()
+	format, err := securityFileFormat(ctx, path)
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	sc, err := core.LoadSecurityConfig(db)
+	if err != nil {
+		return err
+	}
+	lists := securityListsFromConfig(sc)
+
+	out := io.Writer(os.Stdout)
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeSecurityLists(out, lists, format, singleSecurityList(selected)); err != nil {
+		return err
+	}
+	if path != "-" {
+		log.Info("Exported security policy lists", "file", path, "entries", len(lists.entries(selected)))
+	}
+	return nil
+}
+
+func securityDiff(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	selected, err := selectedSecurityLists(ctx)
+	if err != nil {
+		return err
+	}
+	path := ctx.Args().First()
+	format, err := securityFileFormat(ctx, path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file, err := readSecurityLists(f, format, singleSecurityList(selected))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	sc, err := core.LoadSecurityConfig(db)
+	if err != nil {
+		return err
+	}
+	added, removed := diffSecurityLists(securityListsFromConfig(sc), file, selected)
+	for _, e := range added {
+		fmt.Printf("+ %s\n", e)
+	}
+	for _, e := range removed {
+		fmt.Printf("- %s\n", e)
+	}
+	fmt.Printf("%d entries only in file, %d entries only in database\n", len(added), len(removed))
+	return nil
+}
+
+// diffSecurityLists returns the entries only present in b and the entries only
+// present in a.
+func diffSecurityLists(a, b *securityLists, selected []string) (added, removed []securityEntry) {
+	have := make(map[securityEntry]bool)
+	for _, e := range a.entries(selected) {
+		have[e] = true
+	}
+	want := make(map[securityEntry]bool)
+	for _, e := range b.entries(selected) {
+		if !have[e] && !want[e] {
+			added = append(added, e)
+		}
+		want[e] = true
+	}
+	for _, e := range a.entries(selected) {
+		if !want[e] {
+			removed = append(removed, e)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+func testSecurityLists() *securityLists {
+	return &securityLists{
+		Whitelist: []common.Address{common.HexToAddress("0x02"), common.HexToAddress("0x01")},
+		Blacklist: []common.Address{common.HexToAddress("0x03")},
+		Frozen:    []common.Address{common.HexToAddress("0x04")},
+		Contracts: map[common.Address][]common.Address{
+			common.HexToAddress("0x05"): {common.HexToAddress("0x07"), common.HexToAddress("0x06")},
+		},
+	}
+}
+
+// Tests that the lists survive a write and read round trip in all formats,
+// both as a whole and one list at a time.
+func TestSecurityListsRoundTrip(t *testing.T) {
+	lists := testSecurityLists()
+	for _, format := range []string{"json", "csv"} {
+		for _, single := range append([]string{""}, securityListNames...) {
+			selected := securityListNames
+			if single != "" {
+				selected = []string{single}
+			}
+			var buf bytes.Buffer
+			if err := writeSecurityLists(&buf, lists, format, single); err != nil {
+				t.Fatalf("%s/%q: failed to write lists: %v", format, single, err)
+			}
+			read, err := readSecurityLists(&buf, format, single)
+			if err != nil {
+				t.Fatalf("%s/%q: failed to read lists: %v", format, single, err)
+			}
+			if have, want := read.entries(selected), lists.entries(selected); !reflect.DeepEqual(have, want) {
+				t.Errorf("%s/%q: entries mismatch:\nhave %v\nwant %v", format, single, have, want)
+			}
+		}
+	}
+}
+
+// Tests that deployed contracts keep their registration order through an
+// export and import of the stored policy.
+func TestSecurityListsImportOrder(t *testing.T) {
+	sc := core.NewSecurityConfig()
+	if err := testSecurityLists().applyToConfig(sc, securityListNames); err != nil {
+		t.Fatalf("failed to apply lists: %v", err)
+	}
+	owner := common.HexToAddress("0x05")
+	want := []common.Address{common.HexToAddress("0x07"), common.HexToAddress("0x06")}
+	if have := sc.GetDeployedContracts(owner); !reflect.DeepEqual(have, want) {
+		t.Fatalf("registration order mismatch: have %v, want %v", have, want)
+	}
+	for _, format := range []string{"json", "csv"} {
+		var buf bytes.Buffer
+		if err := writeSecurityLists(&buf, securityListsFromConfig(sc), format, ""); err != nil {
+			t.Fatalf("%s: failed to export lists: %v", format, err)
+		}
+		lists, err := readSecurityLists(&buf, format, "")
+		if err != nil {
+			t.Fatalf("%s: failed to import lists: %v", format, err)
+		}
+		imported := core.NewSecurityConfig()
+		if err := lists.applyToConfig(imported, securityListNames); err != nil {
+			t.Fatalf("%s: failed to apply lists: %v", format, err)
+		}
+		if have := imported.GetDeployedContracts(owner); !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: imported order mismatch: have %v, want %v", format, have, want)
+		}
+	}
+}
+
+func TestReadSecurityListsCSV(t *testing.T) {
+	input := `list,address,contract
+# comments are skipped
+whitelist, 0x0000000000000000000000000000000000000001
+contracts,0x0000000000000000000000000000000000000005,0x0000000000000000000000000000000000000006
+`
+	lists, err := readSecurityLists(strings.NewReader(input), "csv", "")
+	if err != nil {
+		t.Fatalf("failed to read lists: %v", err)
+	}
+	want := []securityEntry{
+		{list: securityContracts, address: common.HexToAddress("0x05"), contract: common.HexToAddress("0x06")},
+		{list: securityWhitelist, address: common.HexToAddress("0x01")},
+	}
+	if have := lists.entries(securityListNames); !reflect.DeepEqual(have, want) {
+		t.Errorf("entries mismatch:\nhave %v\nwant %v", have, want)
+	}
+	// A headerless file keeps its first row
+	lists, err = readSecurityLists(strings.NewReader("0x0000000000000000000000000000000000000003\n"), "csv", securityBlacklist)
+	if err != nil {
+		t.Fatalf("failed to read headerless list: %v", err)
+	}
+	if want := []common.Address{common.HexToAddress("0x03")}; !reflect.DeepEqual(lists.Blacklist, want) {
+		t.Errorf("headerless list mismatch: have %v, want %v", lists.Blacklist, want)
+	}
+	for _, bad := range []string{
+		"whitelist,0x01zz\nwhitelist,0x0000000000000000000000000000000000000001",
+		"list,owner\nwhitelist,0x0000000000000000000000000000000000000001",
+		"whitelist,0x0000000000000000000000000000000000000001\nwhitelist,0x01zz",
+		"whitelist,0x0000000000000000000000000000000000000001\ngreylist,0x0000000000000000000000000000000000000001",
+		"whitelist,0x0000000000000000000000000000000000000001\ncontracts,0x0000000000000000000000000000000000000005",
+		"whitelist,0x0000000000000000000000000000000000000001\nfrozen,0x0000000000000000000000000000000000000001,0x0000000000000000000000000000000000000002",
+	} {
+		if _, err := readSecurityLists(strings.NewReader(bad), "csv", ""); err == nil {
+			t.Errorf("expected error for input %q", bad)
+		}
+	}
+}
+
+func TestDiffSecurityLists(t *testing.T) {
+	a := testSecurityLists()
+	b := testSecurityLists()
+	b.Whitelist = b.Whitelist[:1]
+	b.Frozen = append(b.Frozen, common.HexToAddress("0x08"))
+
+	added, removed := diffSecurityLists(a, b, securityListNames)
+	if want := []securityEntry{{list: securityFrozen, address: common.HexToAddress("0x08")}}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added mismatch: have %v, want %v", added, want)
+	}
+	if want := []securityEntry{{list: securityWhitelist, address: common.HexToAddress("0x01")}}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed mismatch: have %v, want %v", removed, want)
+	}
+	// Unselected lists are ignored
+	if added, removed := diffSecurityLists(a, b, []string{securityBlacklist}); len(added) != 0 || len(removed) != 0 {
+		t.Errorf("unexpected differences in unchanged list: %v %v", added, removed)
+	}
+}
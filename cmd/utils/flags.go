@@ -281,6 +281,27 @@ var (
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	AddressIndexFlag = &cli.BoolFlag{
+		Name:     "history.addresses.index",
+		Usage:    "Enables indexing transactions by the addresses they touch (eth_getTransactionsByAddress)",
+		Category: flags.StateCategory,
+	}
+	AddressHistoryFlag = &cli.Uint64Flag{
+		Name:     "history.addresses",
+		Usage:    "Number of recent blocks to maintain the address transaction index for (default = entire chain)",
+		Value:    ethconfig.Defaults.AddressHistory,
+		Category: flags.StateCategory,
+	}
+	TokenIndexFlag = &cli.BoolFlag{
+		Name:     "history.tokens.index",
+		Usage:    "Enables indexing ERC-20/721/1155 token transfers (tobe_getTokenTransfers, tobe_getTokenHolders)",
+		Category: flags.StateCategory,
+	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "history.logs.index",
+		Usage:    "Enables indexing logs by address and topic to speed up large range eth_getLogs queries",
+		Category: flags.StateCategory,
+	}
 	// Transaction pool settings
 	TxPoolLocalsFlag = &cli.StringFlag{
 		Name:     "txpool.locals",
@@ -304,6 +325,11 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Rejournal,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolJournalRemotesFlag = &cli.BoolFlag{
+		Name:     "txpool.journalremotes",
+		Usage:    "Enables journaling remote transactions to survive node restarts",
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolPriceLimitFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricelimit",
 		Usage:    "Minimum gas price tip to enforce for acceptance into the pool",
@@ -346,6 +372,42 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolSenderTxRateFlag = &cli.Float64Flag{
+		Name:     "txpool.sendertxrate",
+		Usage:    "Maximum number of remote transactions per second admitted from a single sender (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.SenderTxRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSenderGasRateFlag = &cli.Uint64Flag{
+		Name:     "txpool.sendergasrate",
+		Usage:    "Maximum remote transaction gas per second admitted from a single sender (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.SenderGasRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPeerTxRateFlag = &cli.Float64Flag{
+		Name:     "txpool.peertxrate",
+		Usage:    "Maximum number of transactions per second admitted from a single peer (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.PeerTxRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPeerGasRateFlag = &cli.Uint64Flag{
+		Name:     "txpool.peergasrate",
+		Usage:    "Maximum transaction gas per second admitted from a single peer (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.PeerGasRate,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolRateBurstFlag = &cli.DurationFlag{
+		Name:     "txpool.rateburst",
+		Usage:    "Period of unused admission allowance a sender or peer may accumulate",
+		Value:    ethconfig.Defaults.TxPool.RateBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolLifecycleFlag = &cli.IntFlag{
+		Name:     "txpool.lifecycle",
+		Usage:    "Number of recently seen transactions whose lifecycle is tracked (0 = disabled)",
+		Value:    ethconfig.Defaults.TxPool.Lifecycle,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -464,6 +526,17 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerBuildBudgetFlag = &cli.DurationFlag{
+		Name:     "miner.buildbudget",
+		Usage:    "Maximum time allowance for filling a block before sealing it partially (0 = half the block period)",
+		Category: flags.MinerCategory,
+	}
+	MinerTxOrderingFlag = &cli.StringFlag{
+		Name:     "miner.txordering",
+		Usage:    "Order of transactions in mined blocks (fee, fifo, lanes)",
+		Value:    string(ethconfig.Defaults.Miner.TxOrdering),
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -496,6 +569,27 @@ var (
 		Usage:    "Record information useful for VM and contract debugging",
 		Category: flags.VMCategory,
 	}
+	VMTraceFlag = &cli.StringFlag{
+		Name:     "vmtrace",
+		Usage:    "Name of the tracer to trace the transactions of the imported and mined blocks with",
+		Category: flags.VMCategory,
+	}
+	VMTraceJsonConfigFlag = &cli.StringFlag{
+		Name:     "vmtrace.jsonconfig",
+		Usage:    "Tracer configuration (JSON)",
+		Category: flags.VMCategory,
+	}
+	VMTraceOutputFlag = &cli.StringFlag{
+		Name:     "vmtrace.output",
+		Usage:    "Directory to write the traces to as JSON lines (default = chain database)",
+		Category: flags.VMCategory,
+	}
+	VMTraceHistoryFlag = &cli.Uint64Flag{
+		Name:     "vmtrace.history",
+		Usage:    "Number of recent blocks to keep the traces of in the chain database (0 = entire chain)",
+		Value:    ethconfig.Defaults.VMTraceHistory,
+		Category: flags.VMCategory,
+	}
 
 	// API options.
 	RPCGlobalGasCapFlag = &cli.Uint64Flag{
@@ -1472,6 +1566,9 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.IsSet(TxPoolJournalRemotesFlag.Name) {
+		cfg.JournalRemotes = ctx.Bool(TxPoolJournalRemotesFlag.Name)
+	}
 	if ctx.IsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.Uint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1493,6 +1590,24 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolSenderTxRateFlag.Name) {
+		cfg.SenderTxRate = ctx.Float64(TxPoolSenderTxRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderGasRateFlag.Name) {
+		cfg.SenderGasRate = ctx.Uint64(TxPoolSenderGasRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPeerTxRateFlag.Name) {
+		cfg.PeerTxRate = ctx.Float64(TxPoolPeerTxRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPeerGasRateFlag.Name) {
+		cfg.PeerGasRate = ctx.Uint64(TxPoolPeerGasRateFlag.Name)
+	}
+	if ctx.IsSet(TxPoolRateBurstFlag.Name) {
+		cfg.RateBurst = ctx.Duration(TxPoolRateBurstFlag.Name)
+	}
+	if ctx.IsSet(TxPoolLifecycleFlag.Name) {
+		cfg.Lifecycle = ctx.Int(TxPoolLifecycleFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1511,6 +1626,17 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	if ctx.IsSet(MinerBuildBudgetFlag.Name) {
+		cfg.BuildBudget = ctx.Duration(MinerBuildBudgetFlag.Name)
+	}
+	if ctx.IsSet(MinerTxOrderingFlag.Name) {
+		switch ordering := miner.TxOrdering(ctx.String(MinerTxOrderingFlag.Name)); ordering {
+		case miner.TxOrderingFee, miner.TxOrderingFIFO, miner.TxOrderingLanes:
+			cfg.TxOrdering = ordering
+		default:
+			Fatalf("--%s: unknown transaction ordering %q", MinerTxOrderingFlag.Name, ordering)
+		}
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1592,6 +1718,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
+	if ctx.IsSet(TxPoolLifecycleFlag.Name) {
+		cfg.BlobPool.Lifecycle = ctx.Int(TxPoolLifecycleFlag.Name)
+	}
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 	setLes(ctx, cfg)
@@ -1665,6 +1794,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Warn("The flag --txlookuplimit is deprecated and will be removed, please use --history.transactions")
 		cfg.TransactionHistory = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.Bool(AddressIndexFlag.Name)
+	}
+	if ctx.IsSet(AddressHistoryFlag.Name) {
+		cfg.AddressHistory = ctx.Uint64(AddressHistoryFlag.Name)
+	}
+	if ctx.IsSet(TokenIndexFlag.Name) {
+		cfg.TokenIndex = ctx.Bool(TokenIndexFlag.Name)
+	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
 	if ctx.String(GCModeFlag.Name) == "archive" && cfg.TransactionHistory != 0 {
 		cfg.TransactionHistory = 0
 		log.Warn("Disabled transaction unindexing for archive node")
@@ -1703,6 +1844,14 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.Bool(VMEnableDebugFlag.Name)
 	}
+	if ctx.IsSet(VMTraceFlag.Name) {
+		cfg.VMTrace = ctx.String(VMTraceFlag.Name)
+		cfg.VMTraceJsonConfig = ctx.String(VMTraceJsonConfigFlag.Name)
+		cfg.VMTraceOutput = ctx.String(VMTraceOutputFlag.Name)
+	}
+	if ctx.IsSet(VMTraceHistoryFlag.Name) {
+		cfg.VMTraceHistory = ctx.Uint64(VMTraceHistoryFlag.Name)
+	}
 
 	if ctx.IsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.Uint64(RPCGlobalGasCapFlag.Name)
@@ -363,6 +363,25 @@ func (beacon *Beacon) Finalize(chain consensus.ChainHeaderReader, header *types.
 	// No block reward which is issued by consensus layer instead.
 }
 
+// BlockRewards implements consensus.Rewarder, returning the withdrawals of the
+// block, or the rewards of the eth1 engine before the merge.
+func (beacon *Beacon) BlockRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header, withdrawals []*types.Withdrawal) []consensus.Reward {
+	if !beacon.IsPoSHeader(header) {
+		if rewarder, ok := beacon.ethone.(consensus.Rewarder); ok {
+			return rewarder.BlockRewards(config, header, uncles, nil)
+		}
+		return nil
+	}
+	rewards := make([]consensus.Reward, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		// Convert amount from gwei to wei.
+		amount := new(big.Int).SetUint64(w.Amount)
+		amount.Mul(amount, big.NewInt(params.GWei))
+		rewards = append(rewards, consensus.Reward{Beneficiary: w.Address, Kind: consensus.RewardWithdrawal, Amount: amount})
+	}
+	return rewards
+}
+
 // FinalizeAndAssemble implements consensus.Engine, setting the final state and
 // assembling the block.
 func (beacon *Beacon) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt, withdrawals []*types.Withdrawal) (*types.Block, error) {
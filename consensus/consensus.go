@@ -121,6 +121,28 @@ type Engine interface {
 	Close() error
 }
 
+// Kinds of the rewards credited by the consensus engines.
+const (
+	RewardBlock      = "blockReward" // Reward of the miner of the block
+	RewardUncle      = "uncleReward" // Reward of the miner of an included uncle
+	RewardWithdrawal = "withdrawal"  // Withdrawal from the consensus layer
+)
+
+// Reward is a balance credited by a consensus engine when finalizing a block,
+// outside of any transaction.
+type Reward struct {
+	Beneficiary common.Address
+	Kind        string
+	Amount      *big.Int
+}
+
+// Rewarder is a consensus engine crediting rewards when finalizing blocks.
+type Rewarder interface {
+	// BlockRewards returns the rewards credited when finalizing the block, in
+	// the order they are credited.
+	BlockRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header, withdrawals []*types.Withdrawal) []Reward
+}
+
 // PoW is a consensus engine based on proof-of-work.
 type PoW interface {
 	Engine
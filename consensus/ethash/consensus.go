@@ -567,10 +567,24 @@ var (
 	u256_32 = uint256.NewInt(32)
 )
 
+// BlockRewards implements consensus.Rewarder, returning the rewards of the
+// miners of the block and of the included uncles.
+func (ethash *Ethash) BlockRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header, withdrawals []*types.Withdrawal) []consensus.Reward {
+	return blockRewards(config, header, uncles)
+}
+
 // AccumulateRewards credits the coinbase of the given block with the mining
 // reward. The total reward consists of the static block reward and rewards for
 // included uncles. The coinbase of each uncle block is also rewarded.
 func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, uncles []*types.Header) {
+	for _, reward := range blockRewards(config, header, uncles) {
+		state.AddBalance(reward.Beneficiary, uint256.MustFromBig(reward.Amount))
+	}
+}
+
+// blockRewards returns the rewards credited to the coinbase of the given block
+// and to the coinbases of the included uncles, in the order they are credited.
+func blockRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header) []consensus.Reward {
 	// Select the correct block reward based on chain progression
 	blockReward := FrontierBlockReward
 	if config.IsByzantium(header.Number) {
@@ -580,8 +594,11 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 		blockReward = ConstantinopleBlockReward
 	}
 	// Accumulate the rewards for the miner and any included uncles
-	reward := new(uint256.Int).Set(blockReward)
-	r := new(uint256.Int)
+	var (
+		rewards = make([]consensus.Reward, 0, len(uncles)+1)
+		reward  = new(uint256.Int).Set(blockReward)
+		r       = new(uint256.Int)
+	)
 	hNum, _ := uint256.FromBig(header.Number)
 	for _, uncle := range uncles {
 		uNum, _ := uint256.FromBig(uncle.Number)
@@ -589,10 +606,10 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 		r.Sub(r, hNum)
 		r.Mul(r, blockReward)
 		r.Div(r, u256_8)
-		state.AddBalance(uncle.Coinbase, r)
+		rewards = append(rewards, consensus.Reward{Beneficiary: uncle.Coinbase, Kind: consensus.RewardUncle, Amount: r.ToBig()})
 
 		r.Div(blockReward, u256_32)
 		reward.Add(reward, r)
 	}
-	state.AddBalance(header.Coinbase, reward)
+	return append(rewards, consensus.Reward{Beneficiary: header.Coinbase, Kind: consensus.RewardBlock, Amount: reward.ToBig()})
 }
@@ -114,6 +114,7 @@ func (poi *PoI) GetMiningConfiguration() map[string]interface{} {
         "validatorCount":   len(poi.GetValidators()),
         "miningReady":      poi.IsReadyToMine() == nil,
         "currentBlockInterval": poi.GetcurrentBlockInterval(),
+        "buildBudget":          poi.GetBuildBudget(),
     }
 }
 
@@ -251,10 +252,21 @@ func (poi *PoI) ForcecurrentBlockPreparation(chain consensus.ChainHeaderReader,
     state := parentState.Copy()
 
     receipts := make([]*types.Receipt, 0, len(txs))
+    applied := make([]*types.Transaction, 0, len(txs))
     gasPool := core.GasPool(header.GasLimit)
     gasUsed := uint64(0)
 
+    // Stop applying transactions once the building budget runs out and seal
+    // whatever got in, rather than missing the slot on heavy blocks.
+    budget := poi.GetBuildBudget()
+    deadline := time.Now().Add(budget)
+
     for _, tx := range txs {
+        if budget > 0 && time.Now().After(deadline) {
+            log.Warn("Block building budget exceeded, sealing partial block", "number", currentBlock,
+                "applied", len(applied), "skipped", len(txs)-len(applied), "budget", budget)
+            break
+        }
         if header.BaseFee == nil {
             header.BaseFee = big.NewInt(1000000000)
         }
@@ -281,10 +293,11 @@ func (poi *PoI) ForcecurrentBlockPreparation(chain consensus.ChainHeaderReader,
             continue
         }
         receipts = append(receipts, receipt)
+        applied = append(applied, tx)
     }
     header.GasUsed = gasUsed
 
-    block, err := poi.FinalizeAndAssemble(chain, header, state, txs, nil, receipts, nil)
+    block, err := poi.FinalizeAndAssemble(chain, header, state, applied, nil, receipts, nil)
     if err != nil {
         log.Error("Failed to assemble block", "error", err)
         return
@@ -310,6 +323,12 @@ func (poi *PoI) GetcurrentBlockInterval() time.Duration {
     return time.Duration(poi.config.Period) * time.Second
 }
 
+// GetBuildBudget returns the maximum time allowance for applying transactions
+// to a block being prepared, derived from the block period.
+func (poi *PoI) GetBuildBudget() time.Duration {
+    return time.Duration(float64(poi.GetcurrentBlockInterval()) * params.BuildBudgetRatio)
+}
+
 func PoIRLP(header *types.Header) []byte {
     b := make([]byte, len(header.Extra)-65)
     copy(b, header.Extra[:len(header.Extra)-65])
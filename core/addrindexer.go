@@ -0,0 +1,255 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// addrIndexer is the module responsible for maintaining the address transaction
+// history index, mapping accounts to the transactions sending to them, sent by
+// them or creating them.
+//
+// The index follows the canonical chain: blocks reorged out are unindexed, new
+// blocks are indexed going forward and older blocks backward, so the recent
+// history becomes available first. Blocks beyond the configured range are
+// pruned from the tail.
+type addrIndexer struct {
+	*indexScheduler
+
+	// limit is the maximum number of blocks from head whose transactions
+	// are indexed by address:
+	//  * 0: means the entire chain should be indexed
+	//  * N: means the latest N blocks [HEAD-N+1, HEAD] should be indexed
+	//       and all others shouldn't.
+	limit  uint64
+	db     ethdb.Database
+	config *params.ChainConfig
+}
+
+// newAddrIndexer initializes the address transaction history indexer.
+func newAddrIndexer(limit uint64, chain *BlockChain) *addrIndexer {
+	indexer := &addrIndexer{
+		limit:  limit,
+		db:     chain.db,
+		config: chain.chainConfig,
+	}
+	indexer.indexScheduler = newIndexScheduler("address", chain, indexer.update)
+
+	var msg string
+	if limit == 0 {
+		msg = "entire chain"
+	} else {
+		msg = fmt.Sprintf("last %d blocks", limit)
+	}
+	log.Info("Initialized address indexer", "range", msg)
+
+	return indexer
+}
+
+// update brings the address index in line with the canonical chain ending at
+// the given head. If the stop channel is closed, the task is terminated as soon
+// as possible, the progress made so far being retained.
+//
+// The indexed range is [tail, head marker], empty if the tail is above the head
+// marker. New canonical blocks are indexed forward from the head marker, while
+// the blocks missing at the tail are indexed backward from it.
+func (indexer *addrIndexer) update(head uint64, stop chan struct{}) {
+	var (
+		start   = time.Now()
+		batch   = indexer.db.NewBatch()
+		tail    = rawdb.ReadAddressIndexTail(indexer.db)
+		hash    = rawdb.ReadAddressIndexHead(indexer.db)
+		number  *uint64
+		indexed int
+	)
+	if tail != nil && hash != (common.Hash{}) {
+		number = rawdb.ReadHeaderNumber(indexer.db, hash)
+	}
+	// Unindex the blocks which are not canonical anymore, either due to a reorg
+	// or the chain being rewound.
+	if number != nil {
+		for *number > head || rawdb.ReadCanonicalHash(indexer.db, *number) != hash {
+			if *number < *tail {
+				// Nothing indexed on the abandoned chain, start over from scratch
+				number = nil
+				break
+			}
+			header := rawdb.ReadHeader(indexer.db, hash, *number)
+			if header == nil {
+				log.Error("Missing header of address indexed block", "number", *number, "hash", hash)
+				number = nil
+				break
+			}
+			if body := rawdb.ReadBody(indexer.db, hash, *number); body != nil {
+				indexer.unindex(batch, types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles))
+			} else {
+				log.Warn("Missing body of address indexed block", "number", *number, "hash", hash)
+			}
+			if *number == *tail {
+				// Everything indexed got reorged out, start over from scratch
+				number = nil
+				break
+			}
+			hash, *number = header.ParentHash, *number-1
+			rawdb.WriteAddressIndexHead(batch, hash)
+		}
+	}
+	// If nothing is indexed yet, start with an empty range at the head and let
+	// the backward indexing fill it in. Any entries left over from an abandoned
+	// range are dropped first, the markers going first so that an interruption
+	// leads to another start over.
+	if number == nil {
+		rawdb.DeleteAddressIndexMarkers(batch)
+		indexer.flush(batch)
+		if err := rawdb.DeleteAddressTxEntries(indexer.db); err != nil {
+			log.Error("Failed to delete stale address index entries", "err", err)
+			return
+		}
+		hash = rawdb.ReadCanonicalHash(indexer.db, head)
+		number, tail = &head, new(uint64)
+		*tail = head + 1
+
+		rawdb.WriteAddressIndexHead(batch, hash)
+		rawdb.WriteAddressIndexTail(batch, *tail)
+	}
+	// Index the new canonical blocks forward from the head marker.
+	for n := *number + 1; n <= head; n++ {
+		select {
+		case <-stop:
+			indexer.flush(batch)
+			return
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(indexer.db, n)
+		block := rawdb.ReadBlock(indexer.db, hash, n)
+		if block == nil {
+			log.Warn("Missing block for address indexing", "number", n, "hash", hash)
+			break
+		}
+		indexer.index(batch, block)
+		rawdb.WriteAddressIndexHead(batch, hash)
+		indexed++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			indexer.flush(batch)
+		}
+	}
+	// Index the blocks missing at the tail backward, or prune the blocks which
+	// fell out of the configured range.
+	var want uint64
+	if indexer.limit != 0 && head >= indexer.limit {
+		want = head - indexer.limit + 1
+	}
+	for n := *tail; n > want; n-- {
+		select {
+		case <-stop:
+			indexer.flush(batch)
+			return
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(indexer.db, n-1)
+		block := rawdb.ReadBlock(indexer.db, hash, n-1)
+		if block == nil {
+			log.Warn("Missing block for address indexing", "number", n-1, "hash", hash)
+			break
+		}
+		indexer.index(batch, block)
+		rawdb.WriteAddressIndexTail(batch, n-1)
+		indexed++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			indexer.flush(batch)
+		}
+	}
+	for n := *tail; n < want; n++ {
+		hash := rawdb.ReadCanonicalHash(indexer.db, n)
+		if block := rawdb.ReadBlock(indexer.db, hash, n); block != nil {
+			indexer.unindex(batch, block)
+		}
+		rawdb.WriteAddressIndexTail(batch, n+1)
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			indexer.flush(batch)
+		}
+	}
+	indexer.flush(batch)
+	if indexed > 0 {
+		log.Debug("Indexed transactions by address", "blocks", indexed, "head", head, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// flush writes out the accumulated index changes.
+func (indexer *addrIndexer) flush(batch ethdb.Batch) {
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	batch.Reset()
+}
+
+// touched returns the addresses touched by the transactions of the block,
+// along with the directions they are touched in.
+func (indexer *addrIndexer) touched(block *types.Block) []map[common.Address]byte {
+	var (
+		signer = types.MakeSigner(indexer.config, block.Number(), block.Time())
+		txs    = block.Transactions()
+		result = make([]map[common.Address]byte, len(txs))
+	)
+	for i, tx := range txs {
+		result[i] = make(map[common.Address]byte)
+
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			log.Error("Failed to derive transaction sender", "number", block.Number(), "index", i, "err", err)
+			continue
+		}
+		result[i][from] |= rawdb.AddressTxSender
+		if to := tx.To(); to != nil {
+			result[i][*to] |= rawdb.AddressTxRecipient
+		} else {
+			result[i][crypto.CreateAddress(from, tx.Nonce())] |= rawdb.AddressTxCreation
+		}
+	}
+	return result
+}
+
+// index adds the transactions of the block to the address index.
+func (indexer *addrIndexer) index(batch ethdb.KeyValueWriter, block *types.Block) {
+	for i, addrs := range indexer.touched(block) {
+		for addr, direction := range addrs {
+			rawdb.WriteAddressTxEntry(batch, addr, block.NumberU64(), uint32(i), direction)
+		}
+	}
+}
+
+// unindex removes the transactions of the block from the address index.
+func (indexer *addrIndexer) unindex(batch ethdb.KeyValueWriter, block *types.Block) {
+	for i, addrs := range indexer.touched(block) {
+		for addr := range addrs {
+			rawdb.DeleteAddressTxEntry(batch, addr, block.NumberU64(), uint32(i))
+		}
+	}
+}
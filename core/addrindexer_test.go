@@ -0,0 +1,201 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestAddrIndexer tests that transactions are indexed by the addresses they
+// touch, following reorgs and pruning the tail beyond the configured range.
+func TestAddrIndexer(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer   = types.LatestSigner(gspec.Config)
+		engine   = ethash.NewFaker()
+		created  = crypto.CreateAddress(testBankAddress, 0)
+		original = common.HexToAddress("0xdeadbeef")
+		reorged  = common.HexToAddress("0xcafebabe")
+	)
+	// generate creates a chain whose first block deploys a contract and the
+	// rest transfer to the given recipient.
+	generate := func(n int, recipient common.Address) []*types.Block {
+		_, blocks, _ := GenerateChainWithGenesis(gspec, engine, n, func(i int, gen *BlockGen) {
+			var to *common.Address
+			if i > 0 {
+				to = &recipient
+			}
+			tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+				Nonce:    uint64(i),
+				To:       to,
+				Value:    big.NewInt(1000),
+				Gas:      100000,
+				GasPrice: big.NewInt(10 * params.InitialBaseFee),
+			})
+			gen.AddTx(tx)
+		})
+		return blocks
+	}
+	openChain := func(db ethdb.Database, history uint64) *BlockChain {
+		config := *defaultCacheConfig
+		config.AddressIndex = true
+		config.AddressHistory = history
+
+		chain, err := NewBlockChain(db, &config, gspec, nil, engine, vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create chain: %v", err)
+		}
+		return chain
+	}
+	newChain := func(history uint64) (*BlockChain, func()) {
+		chain := openChain(rawdb.NewMemoryDatabase(), history)
+		return chain, chain.Stop
+	}
+	// wait blocks until both ends of the indexed range reached their targets.
+	wait := func(chain *BlockChain) {
+		t.Helper()
+		head := chain.CurrentBlock()
+
+		var want uint64
+		if limit := chain.addrIndexer.limit; limit != 0 && head.Number.Uint64() >= limit {
+			want = head.Number.Uint64() - limit + 1
+		}
+		for i := 0; i < 100; i++ {
+			tail := rawdb.ReadAddressIndexTail(chain.db)
+			if rawdb.ReadAddressIndexHead(chain.db) == head.Hash() && tail != nil && *tail == want {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("address index not caught up with head %d", head.Number)
+	}
+	insert := func(chain *BlockChain, blocks []*types.Block) {
+		t.Helper()
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("failed to insert chain: %v", err)
+		}
+		wait(chain)
+	}
+	// collect pages through the indexed transactions of an address.
+	collect := func(chain *BlockChain, addr common.Address) []rawdb.AddressTxEntry {
+		var (
+			result []rawdb.AddressTxEntry
+			cursor []byte
+		)
+		for {
+			entries, next, err := chain.AddressTransactions(addr, cursor, 3)
+			if err != nil {
+				t.Fatalf("failed to retrieve address transactions: %v", err)
+			}
+			if len(entries) > 3 {
+				t.Fatalf("page size exceeded: have %d, want at most 3", len(entries))
+			}
+			result = append(result, entries...)
+			if next == nil {
+				return result
+			}
+			cursor = next
+		}
+	}
+	verify := func(chain *BlockChain, addr common.Address, direction byte, numbers ...uint64) {
+		t.Helper()
+		entries := collect(chain, addr)
+		if len(entries) != len(numbers) {
+			t.Fatalf("address %x: entry count mismatch: have %d, want %d", addr, len(entries), len(numbers))
+		}
+		for i, entry := range entries {
+			if entry.BlockNumber != numbers[i] || entry.Index != 0 || entry.Direction != direction {
+				t.Fatalf("address %x: entry %d mismatch: have %+v, want block %d direction %d", addr, i, entry, numbers[i], direction)
+			}
+		}
+	}
+	chain, stop := newChain(0)
+	defer stop()
+
+	insert(chain, generate(8, original))
+	verify(chain, testBankAddress, rawdb.AddressTxSender, 8, 7, 6, 5, 4, 3, 2, 1)
+	verify(chain, original, rawdb.AddressTxRecipient, 8, 7, 6, 5, 4, 3, 2)
+	verify(chain, created, rawdb.AddressTxCreation, 1)
+
+	// Reorg to a longer chain and ensure the old transactions are unindexed
+	insert(chain, generate(10, reorged))
+	verify(chain, testBankAddress, rawdb.AddressTxSender, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1)
+	verify(chain, original, rawdb.AddressTxRecipient)
+	verify(chain, reorged, rawdb.AddressTxRecipient, 10, 9, 8, 7, 6, 5, 4, 3, 2)
+
+	// Ensure the blocks beyond the configured range are not indexed
+	pruned, stop := newChain(4)
+	defer stop()
+
+	insert(pruned, generate(8, original))
+	verify(pruned, testBankAddress, rawdb.AddressTxSender, 8, 7, 6, 5)
+	if tail := rawdb.ReadAddressIndexTail(pruned.db); tail == nil || *tail != 5 {
+		t.Fatalf("address index tail mismatch: have %v, want 5", tail)
+	}
+	insert(pruned, generate(12, original))
+	verify(pruned, testBankAddress, rawdb.AddressTxSender, 12, 11, 10, 9)
+	if tail := rawdb.ReadAddressIndexTail(pruned.db); tail == nil || *tail != 9 {
+		t.Fatalf("address index tail mismatch: have %v, want 9", tail)
+	}
+	// Ensure widening the configured range indexes the missing tail backward
+	db := rawdb.NewMemoryDatabase()
+	narrow := openChain(db, 4)
+	insert(narrow, generate(8, original))
+	narrow.Stop()
+
+	wide := openChain(db, 0)
+	defer wide.Stop()
+
+	wait(wide)
+	verify(wide, testBankAddress, rawdb.AddressTxSender, 8, 7, 6, 5, 4, 3, 2, 1)
+	verify(wide, created, rawdb.AddressTxCreation, 1)
+
+	// Ensure starting over from scratch drops the entries of the abandoned range
+	restartDB := rawdb.NewMemoryDatabase()
+	stale := openChain(restartDB, 0)
+	insert(stale, generate(8, original))
+	stale.Stop()
+
+	rawdb.WriteAddressTxEntry(restartDB, reorged, 100, 0, rawdb.AddressTxRecipient)
+	rawdb.DeleteAddressIndexMarkers(restartDB)
+
+	restarted := openChain(restartDB, 0)
+	defer restarted.Stop()
+
+	wait(restarted)
+	verify(restarted, testBankAddress, rawdb.AddressTxSender, 8, 7, 6, 5, 4, 3, 2, 1)
+	verify(restarted, reorged, rawdb.AddressTxRecipient)
+}
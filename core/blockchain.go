@@ -143,6 +143,10 @@ type CacheConfig struct {
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
+	AddressIndex        bool          // Whether to index the transactions of the chain by the addresses they touch
+	AddressHistory      uint64        // Number of blocks from head whose transactions are indexed by address (0 = entire chain)
+	TokenIndex          bool          // Whether to index the token transfers of the chain
+	LogIndex            bool          // Whether to index the logs of the chain by address and topic
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
@@ -219,6 +223,9 @@ type BlockChain struct {
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	stateCache    state.Database                   // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	addrIndexer   *addrIndexer                     // Address transaction history indexer, might be nil if not enabled
+	tokenIndexer  *tokenIndexer                    // Token transfer indexer, might be nil if not enabled
+	logIndexer    *logIndexer                      // Log address and topic indexer, might be nil if not enabled
 
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
@@ -468,6 +475,18 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if txLookupLimit != nil {
 		bc.txIndexer = newTxIndexer(*txLookupLimit, bc)
 	}
+	// Start address indexer if it's enabled.
+	if cacheConfig.AddressIndex {
+		bc.addrIndexer = newAddrIndexer(cacheConfig.AddressHistory, bc)
+	}
+	// Start token transfer indexer if it's enabled.
+	if cacheConfig.TokenIndex {
+		bc.tokenIndexer = newTokenIndexer(bc)
+	}
+	// Start log indexer if it's enabled.
+	if cacheConfig.LogIndex {
+		bc.logIndexer = newLogIndexer(bc)
+	}
 	return bc, nil
 }
 
@@ -769,6 +788,8 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
 		}
+		// Live traces are only kept in the active store.
+		rawdb.DeleteLiveTrace(db, hash, num)
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
 	// If SetHead was only called as a chain reparation method, try to skip
@@ -960,6 +981,15 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	if bc.addrIndexer != nil {
+		bc.addrIndexer.close()
+	}
+	if bc.tokenIndexer != nil {
+		bc.tokenIndexer.close()
+	}
+	if bc.logIndexer != nil {
+		bc.logIndexer.close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1437,7 +1467,14 @@ func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types
 	}
 	defer bc.chainmu.Unlock()
 
-	return bc.writeBlockAndSetHead(block, receipts, logs, state, emitHeadEvent)
+	// Sealed blocks bypass the state processor, re-execute them for the block
+	// tracer before they are written.
+	traced := bc.traceSealedBlock(block)
+	status, err = bc.writeBlockAndSetHead(block, receipts, logs, state, emitHeadEvent)
+	if traced {
+		bc.endBlockTrace(err)
+	}
+	return status, err
 }
 
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
@@ -1753,7 +1790,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 				throwaway, _ := state.New(parent.Root, bc.stateCache, bc.snaps)
 
 				go func(start time.Time, followup *types.Block, throwaway *state.StateDB) {
-					bc.prefetcher.Prefetch(followup, throwaway, bc.vmConfig, &followupInterrupt)
+					bc.prefetcher.Prefetch(followup, throwaway, untracedConfig(bc.vmConfig), &followupInterrupt)
 
 					blockPrefetchExecuteTimer.Update(time.Since(start))
 					if followupInterrupt.Load() {
@@ -1767,6 +1804,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		pstart := time.Now()
 		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
 		if err != nil {
+			bc.endBlockTrace(err)
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
 			return it.index, err
@@ -1775,6 +1813,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 
 		vstart := time.Now()
 		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+			bc.endBlockTrace(err)
 			bc.reportBlock(block, receipts, err)
 			followupInterrupt.Store(true)
 			return it.index, err
@@ -1810,6 +1849,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 			status, err = bc.writeBlockAndSetHead(block, receipts, logs, statedb, false)
 		}
 		followupInterrupt.Store(true)
+		bc.endBlockTrace(err)
 		if err != nil {
 			return it.index, err
 		}
@@ -356,6 +356,17 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, bc.snaps)
 }
 
+// HistoricState returns a new mutable state based on a historical point which
+// has left the in-memory layers, reconstructed from the state histories. It's
+// only supported by the path scheme, and the state can't be committed.
+func (bc *BlockChain) HistoricState(root common.Hash) (*state.StateDB, error) {
+	db, err := state.NewHistoricDatabase(bc.stateCache, root)
+	if err != nil {
+		return nil, err
+	}
+	return state.New(root, db, nil)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -392,9 +403,11 @@ func (bc *BlockChain) Genesis() *types.Block {
 	return bc.genesisBlock
 }
 
-// GetVMConfig returns the block chain VM config.
+// GetVMConfig returns the block chain VM config, without the block tracer
+// tracing the imported blocks if any.
 func (bc *BlockChain) GetVMConfig() *vm.Config {
-	return &bc.vmConfig
+	cfg := untracedConfig(bc.vmConfig)
+	return &cfg
 }
 
 // TxIndexProgress returns the transaction indexing progress.
@@ -405,6 +418,84 @@ func (bc *BlockChain) TxIndexProgress() (TxIndexProgress, error) {
 	return bc.txIndexer.txIndexProgress()
 }
 
+// AddressTransactions retrieves at most limit positions of the transactions
+// touching the given address, starting from the most recent one at or before
+// the cursor. The cursor of the next page is returned too, nil if there are no
+// more transactions.
+func (bc *BlockChain) AddressTransactions(addr common.Address, cursor []byte, limit int) ([]rawdb.AddressTxEntry, []byte, error) {
+	if bc.addrIndexer == nil {
+		return nil, nil, errors.New("address indexer is not enabled")
+	}
+	if cursor != nil && len(cursor) != rawdb.AddressTxCursorLength {
+		return nil, nil, errors.New("invalid address transaction cursor")
+	}
+	entries, next := rawdb.ReadAddressTxEntries(bc.db, addr, cursor, limit)
+	return entries, next, nil
+}
+
+// TokenTransfers retrieves at most limit token transfers, of the given token or
+// from and to the given holder (or both), starting from the most recent one at
+// or before the cursor. The cursor of the next page is returned too, nil if
+// there are no more transfers.
+func (bc *BlockChain) TokenTransfers(token *common.Address, holder *common.Address, cursor []byte, limit int) ([]*rawdb.TokenTransfer, []byte, error) {
+	if bc.tokenIndexer == nil {
+		return nil, nil, errors.New("token indexer is not enabled")
+	}
+	if cursor != nil && len(cursor) != rawdb.TokenTransferCursorLength {
+		return nil, nil, errors.New("invalid token transfer cursor")
+	}
+	var (
+		transfers []*rawdb.TokenTransfer
+		next      []byte
+	)
+	switch {
+	case holder != nil:
+		transfers, next = rawdb.ReadHolderTokenTransfers(bc.db, *holder, token, cursor, limit)
+	case token != nil:
+		transfers, next = rawdb.ReadTokenTransfers(bc.db, *token, cursor, limit)
+	default:
+		return nil, nil, errors.New("token or holder required")
+	}
+	return transfers, next, nil
+}
+
+// TokenHolders retrieves at most limit holders of the given token, starting
+// at the holder cursor. The cursor of the next page is returned too, nil if
+// there are no more holders.
+func (bc *BlockChain) TokenHolders(token common.Address, cursor []byte, limit int) ([]*rawdb.TokenHolder, []byte, error) {
+	if bc.tokenIndexer == nil {
+		return nil, nil, errors.New("token indexer is not enabled")
+	}
+	if cursor != nil && len(cursor) != common.AddressLength {
+		return nil, nil, errors.New("invalid token holder cursor")
+	}
+	holders, next := rawdb.ReadTokenHolders(bc.db, token, cursor, limit)
+	return holders, next, nil
+}
+
+// LogIndexHead returns the number of the latest block whose logs have been
+// indexed, if the log index is enabled and up to date with the canonical chain.
+func (bc *BlockChain) LogIndexHead() (uint64, bool) {
+	if bc.logIndexer == nil {
+		return 0, false
+	}
+	hash := rawdb.ReadLogIndexHead(bc.db)
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil || rawdb.ReadCanonicalHash(bc.db, *number) != hash {
+		return 0, false
+	}
+	return *number, true
+}
+
+// LogIndexBlocks retrieves the numbers of the blocks in the [begin, end] range
+// whose logs may match the given addresses and topics.
+func (bc *BlockChain) LogIndexBlocks(addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	if bc.logIndexer == nil {
+		return nil, errors.New("log indexer is not enabled")
+	}
+	return MatchLogIndex(bc.db, addresses, topics, begin, end)
+}
+
 // TrieDB retrieves the low level trie database used for data storage.
 func (bc *BlockChain) TrieDB() *triedb.Database {
 	return bc.triedb
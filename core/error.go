@@ -123,4 +123,10 @@ var (
     ErrFrozenAccount          = errors.New("account is frozen")
     ErrNotWhitelistedForDeploy = errors.New("address not whitelisted for contract deployment")
     ErrFrozenContract         = errors.New("contract is frozen") // Thêm dòng này
+    
+    // Lỗi do các hạn chế có thời hạn (xem Restriction)
+    ErrRestrictedSender        = errors.New("sender is restricted")
+    ErrRestrictedRecipient     = errors.New("recipient is restricted")
+    ErrRestrictedDeploy        = errors.New("address is restricted from contract deployment")
+    ErrRestrictedContractCall  = errors.New("address is restricted from interacting with contract")
 )
\ No newline at end of file
@@ -41,3 +41,21 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// SecurityPolicyEvent is posted when an entry of the security policy changes.
+type SecurityPolicyEvent struct {
+	Action      string
+	Address     common.Address
+	Contract    *common.Address // Set when a deployed contract is registered
+	Restriction *Restriction    // Set when a restriction is added or removed
+}
+
+// SecurityRejectionEvent is posted when a transaction is rejected by the
+// security policy.
+type SecurityRejectionEvent struct {
+	Hash common.Hash // Zero if the transaction was rejected before signing
+	From common.Address
+	To   *common.Address
+	Err  error
+	Path string
+}
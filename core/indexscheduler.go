@@ -0,0 +1,172 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// indexScheduler runs the update task of an optional chain index in the
+// background, bringing the index in line with the canonical chain every time
+// the chain head changes. At most one task runs at a time, the heads announced
+// meanwhile being coalesced into a single follow-up task.
+type indexScheduler struct {
+	name   string                                // Name of the index, used in logs
+	db     ethdb.Database                        // Database the chain head is read from on startup
+	task   func(head uint64, stop chan struct{}) // Task updating the index up to the given head
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newIndexScheduler creates a scheduler following the chain and starts it. If
+// the stop channel passed to the task is closed, the task should be terminated
+// as soon as possible.
+func newIndexScheduler(name string, chain *BlockChain, task func(head uint64, stop chan struct{})) *indexScheduler {
+	scheduler := &indexScheduler{
+		name:   name,
+		db:     chain.db,
+		task:   task,
+		term:   make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+	go scheduler.loop(chain)
+	return scheduler
+}
+
+// run executes the task in a separate thread, closing the done channel once the
+// task is finished.
+func (s *indexScheduler) run(head uint64, stop chan struct{}, done chan struct{}) {
+	defer func() { close(done) }()
+	s.task(head, stop)
+}
+
+// loop is the scheduler of the indexer, assigning indexing tasks depending on
+// the received chain event.
+func (s *indexScheduler) loop(chain *BlockChain) {
+	defer close(s.closed)
+
+	var (
+		stop    chan struct{} // Non-nil if background routine is active.
+		done    chan struct{} // Non-nil if background routine is active.
+		pending *uint64       // Chain head announced while the background routine was active
+
+		headCh = make(chan ChainHeadEvent)
+		sub    = chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	schedule := func(head uint64) {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go s.run(head, stop, done)
+	}
+	// Launch the initial processing if chain is not empty (head != genesis).
+	if head := rawdb.ReadHeadBlock(s.db); head != nil && head.NumberU64() != 0 {
+		schedule(head.NumberU64())
+	}
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				schedule(head.Block.NumberU64())
+			} else {
+				number := head.Block.NumberU64()
+				pending = &number
+			}
+		case <-done:
+			stop = nil
+			done = nil
+			if pending != nil {
+				schedule(*pending)
+				pending = nil
+			}
+		case ch := <-s.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background " + s.name + " indexer to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// close shutdown the indexer. Safe to be called for multiple times.
+func (s *indexScheduler) close() {
+	ch := make(chan struct{})
+	select {
+	case s.term <- ch:
+		<-ch
+	case <-s.closed:
+	}
+}
+
+// rebuildIndex drops an index and updates it from scratch up to the current
+// head block, periodically reporting the progress. The update task is given the
+// head to index up to, and returns the number of blocks indexed. The progress
+// callback returns the hash of the latest indexed block.
+func rebuildIndex(db ethdb.Database, name string, drop func() error, update func(head uint64) (int, error), progress func() common.Hash) error {
+	head := rawdb.ReadHeadBlock(db)
+	if head == nil {
+		return errors.New("head block not found")
+	}
+	if err := drop(); err != nil {
+		return err
+	}
+	var (
+		start   = time.Now()
+		done    = make(chan struct{})
+		ticker  = time.NewTicker(8 * time.Second)
+		indexed int
+		err     error
+	)
+	defer ticker.Stop()
+
+	go func() {
+		defer close(done)
+		indexed, err = update(head.NumberU64())
+	}()
+	for {
+		select {
+		case <-done:
+			if err != nil {
+				return err
+			}
+			// The update may have been interrupted, leaving the rest of the
+			// chain to the indexer of the node.
+			if progress() != head.Hash() {
+				log.Info("Interrupted "+name+" index rebuild", "blocks", indexed, "head", head.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+				return nil
+			}
+			log.Info("Rebuilt "+name+" index", "blocks", indexed, "head", head.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+			return nil
+		case <-ticker.C:
+			if number := rawdb.ReadHeaderNumber(db, progress()); number != nil {
+				log.Info("Rebuilding "+name+" index", "number", *number, "head", head.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+			}
+		}
+	}
+}
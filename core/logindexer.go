@@ -0,0 +1,256 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// logIndexValue is a value logs are indexed by: the emitting address or a topic
+// at a given position.
+type logIndexValue struct {
+	kind  byte
+	value common.Hash
+}
+
+// writeLogIndex adds the block to the posting lists of all the values in its
+// logs, or removes it from them if revert is set.
+func writeLogIndex(db ethdb.KeyValueWriter, logs [][]*types.Log, number uint64, revert bool) {
+	values := make(map[logIndexValue]struct{})
+	for _, txLogs := range logs {
+		for _, l := range txLogs {
+			values[logIndexValue{rawdb.LogIndexAddress, common.BytesToHash(l.Address.Bytes())}] = struct{}{}
+			for i, topic := range l.Topics {
+				values[logIndexValue{rawdb.LogIndexTopic + byte(i), topic}] = struct{}{}
+			}
+		}
+	}
+	for v := range values {
+		if revert {
+			rawdb.DeleteLogIndexEntry(db, v.kind, v.value, number)
+		} else {
+			rawdb.WriteLogIndexEntry(db, v.kind, v.value, number)
+		}
+	}
+}
+
+// MatchLogIndex retrieves the numbers of the blocks in the [begin, end] range
+// containing logs emitted by any of the addresses and with any of the topics at
+// each position, in ascending order. The criteria may be met by different logs
+// of a block, so the logs of the matched blocks still need to be filtered. At
+// least one address or topic is required.
+func MatchLogIndex(db ethdb.Iteratee, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	var criteria []logIndexCriterion
+	if len(addresses) > 0 {
+		values := make([]common.Hash, len(addresses))
+		for i, address := range addresses {
+			values[i] = common.BytesToHash(address.Bytes())
+		}
+		criteria = append(criteria, logIndexCriterion{rawdb.LogIndexAddress, values})
+	}
+	for i, sub := range topics {
+		if len(sub) > 0 {
+			criteria = append(criteria, logIndexCriterion{rawdb.LogIndexTopic + byte(i), sub})
+		}
+	}
+	if len(criteria) == 0 {
+		return nil, errors.New("address or topic required")
+	}
+	var matches []uint64
+	for i, criterion := range criteria {
+		var union []uint64
+		for _, value := range criterion.values {
+			union = mergeBlockNumbers(union, rawdb.ReadLogIndexBlocks(db, criterion.kind, value, begin, end))
+		}
+		if i == 0 {
+			matches = union
+		} else {
+			matches = intersectBlockNumbers(matches, union)
+		}
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		// Narrow the range scanned for the remaining criteria
+		begin, end = matches[0], matches[len(matches)-1]
+	}
+	return matches, nil
+}
+
+// logIndexCriterion is a set of alternative values, one of which is required
+// to be present in the logs of a block.
+type logIndexCriterion struct {
+	kind   byte
+	values []common.Hash
+}
+
+// mergeBlockNumbers returns the sorted union of two sorted lists of numbers.
+func mergeBlockNumbers(a, b []uint64) []uint64 {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	merged := make([]uint64, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			merged, a = append(merged, a[0]), a[1:]
+		case a[0] > b[0]:
+			merged, b = append(merged, b[0]), b[1:]
+		default:
+			merged, a, b = append(merged, a[0]), a[1:], b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+// intersectBlockNumbers returns the sorted intersection of two sorted lists of
+// numbers.
+func intersectBlockNumbers(a, b []uint64) []uint64 {
+	var shared []uint64
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			a = a[1:]
+		case a[0] > b[0]:
+			b = b[1:]
+		default:
+			shared, a, b = append(shared, a[0]), a[1:], b[1:]
+		}
+	}
+	return shared
+}
+
+// updateLogIndex brings the log index in line with the canonical chain ending
+// at the given head, unindexing the blocks which are not canonical anymore. If
+// the stop channel is closed, the update is interrupted. The number of blocks
+// indexed is returned.
+func updateLogIndex(db ethdb.Database, head uint64, stop chan struct{}) (int, error) {
+	var (
+		batch = db.NewBatch()
+		next  uint64
+	)
+	flush := func() {
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed writing batch to db", "error", err)
+		}
+		batch.Reset()
+	}
+	if hash := rawdb.ReadLogIndexHead(db); hash != (common.Hash{}) {
+		number := rawdb.ReadHeaderNumber(db, hash)
+		if number == nil {
+			return 0, errors.New("log indexed block not found")
+		}
+		n := *number
+		for n > head || rawdb.ReadCanonicalHash(db, n) != hash {
+			header := rawdb.ReadHeader(db, hash, n)
+			if header == nil {
+				return 0, errors.New("log indexed block not found")
+			}
+			logs := rawdb.ReadLogs(db, hash, n)
+			// Reverting without the receipts would leave the logs of the block
+			// in the index, bail out instead.
+			if logs == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+				flush()
+				return 0, fmt.Errorf("missing receipts of log indexed block %d (%x)", n, hash)
+			}
+			writeLogIndex(batch, logs, n, true)
+			hash, n = header.ParentHash, n-1
+			rawdb.WriteLogIndexHead(batch, hash)
+		}
+		next = n + 1
+	}
+	var indexed int
+	for n := next; n <= head; n++ {
+		select {
+		case <-stop:
+			flush()
+			return indexed, nil
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(db, n)
+		header := rawdb.ReadHeader(db, hash, n)
+		if header == nil {
+			break
+		}
+		logs := rawdb.ReadLogs(db, hash, n)
+		if logs == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+			log.Warn("Missing receipts for log indexing", "number", n, "hash", hash)
+			break
+		}
+		writeLogIndex(batch, logs, n, false)
+		rawdb.WriteLogIndexHead(batch, hash)
+		indexed++
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			flush()
+		}
+	}
+	flush()
+	return indexed, nil
+}
+
+// RebuildLogIndex drops the log index and indexes the canonical chain from
+// scratch. If the stop channel is closed, the rebuild is interrupted and
+// resumed by the log indexer of the node.
+func RebuildLogIndex(db ethdb.Database, stop chan struct{}) error {
+	return rebuildIndex(db, "log",
+		func() error { return rawdb.DeleteLogIndex(db) },
+		func(head uint64) (int, error) { return updateLogIndex(db, head, stop) },
+		func() common.Hash { return rawdb.ReadLogIndexHead(db) },
+	)
+}
+
+// logIndexer is the module responsible for maintaining the log index, following
+// the canonical chain from genesis.
+type logIndexer struct {
+	*indexScheduler
+	db ethdb.Database
+}
+
+// newLogIndexer initializes the log indexer.
+func newLogIndexer(chain *BlockChain) *logIndexer {
+	indexer := &logIndexer{db: chain.db}
+	indexer.indexScheduler = newIndexScheduler("log", chain, indexer.update)
+
+	log.Info("Initialized log indexer")
+	return indexer
+}
+
+// update brings the log index up to the given head. If the stop channel is
+// closed, the task should be terminated as soon as possible.
+func (indexer *logIndexer) update(head uint64, stop chan struct{}) {
+	start := time.Now()
+	indexed, err := updateLogIndex(indexer.db, head, stop)
+	if err != nil {
+		log.Error("Failed to update log index, rebuild it offline", "err", err)
+		return
+	}
+	if indexed > 0 {
+		log.Debug("Indexed logs", "blocks", indexed, "head", head, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the log index follows the canonical chain through reorgs, can be
+// rebuilt from scratch and matches blocks by address and positional topics.
+func TestLogIndexer(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		// The contract emits a Transfer event from the zero address to the
+		// address in the first calldata word.
+		emitter = common.HexToAddress("0x70")
+		code    = common.FromHex("6020356000526000356000" + "7f" + transferEventTopic.Hex()[2:] + "60206000a300")
+
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				testBankAddress: {Balance: testBankFunds},
+				emitter:         {Code: code},
+			},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer   = types.LatestSigner(gspec.Config)
+		engine   = ethash.NewFaker()
+		original = common.HexToAddress("0xdeadbeef")
+		reorged  = common.HexToAddress("0xcafebabe")
+	)
+	// generate creates a chain whose odd blocks emit a log with the recipient
+	// as the third topic.
+	generate := func(n int, recipient common.Address) []*types.Block {
+		_, blocks, _ := GenerateChainWithGenesis(gspec, engine, n, func(i int, gen *BlockGen) {
+			if i%2 != 0 {
+				return
+			}
+			tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+				Nonce:    gen.TxNonce(testBankAddress),
+				To:       &emitter,
+				Gas:      100000,
+				GasPrice: big.NewInt(10 * params.InitialBaseFee),
+				Data:     common.BytesToHash(recipient.Bytes()).Bytes(),
+			})
+			gen.AddTx(tx)
+		})
+		return blocks
+	}
+	config := *defaultCacheConfig
+	config.LogIndex = true
+
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, &config, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	insert := func(blocks []*types.Block) {
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("failed to insert chain: %v", err)
+		}
+		for i := 0; i < 100; i++ {
+			if head, ok := chain.LogIndexHead(); ok && head == chain.CurrentBlock().Number.Uint64() {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("log index not caught up with head %d", chain.CurrentBlock().Number)
+	}
+	match := func(addresses []common.Address, topics [][]common.Hash, begin, end uint64, want ...uint64) {
+		t.Helper()
+		have, err := MatchLogIndex(db, addresses, topics, begin, end)
+		if err != nil {
+			t.Fatalf("failed to match log index: %v", err)
+		}
+		if len(have) != 0 || len(want) != 0 {
+			if !reflect.DeepEqual(have, want) {
+				t.Fatalf("matched blocks mismatch: have %v, want %v", have, want)
+			}
+		}
+	}
+	var (
+		originalTopic = common.BytesToHash(original.Bytes())
+		reorgedTopic  = common.BytesToHash(reorged.Bytes())
+		zeroTopic     = common.Hash{}
+	)
+	insert(generate(6, original))
+	match([]common.Address{emitter}, nil, 0, 6, 1, 3, 5)
+	match([]common.Address{emitter}, nil, 2, 4, 3)
+	match(nil, [][]common.Hash{{transferEventTopic}, {zeroTopic}, {originalTopic}}, 0, 6, 1, 3, 5)
+	match(nil, [][]common.Hash{nil, nil, {originalTopic, reorgedTopic}}, 0, 6, 1, 3, 5)
+	match([]common.Address{emitter}, [][]common.Hash{{originalTopic}}, 0, 6)
+	match([]common.Address{testBankAddress}, nil, 0, 6)
+
+	// Reorg to a longer chain and ensure the old logs are unindexed
+	insert(generate(8, reorged))
+	match(nil, [][]common.Hash{nil, nil, {originalTopic}}, 0, 8)
+	match([]common.Address{emitter}, [][]common.Hash{nil, nil, {reorgedTopic}}, 0, 8, 1, 3, 5, 7)
+
+	// Rebuild the index offline and ensure it's the same
+	chain.Stop()
+	if err := RebuildLogIndex(db, nil); err != nil {
+		t.Fatalf("failed to rebuild log index: %v", err)
+	}
+	match(nil, [][]common.Hash{nil, nil, {originalTopic}}, 0, 8)
+	match([]common.Address{emitter}, [][]common.Hash{nil, nil, {reorgedTopic}}, 0, 8, 1, 3, 5, 7)
+
+	if _, err := MatchLogIndex(db, nil, [][]common.Hash{nil}, 0, 8); err == nil {
+		t.Fatal("expected error for missing criteria")
+	}
+}
+
+// Tests that unindexing a reorged block without its receipts fails instead of
+// leaving its logs in the index.
+func TestLogIndexMissingReceipts(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	genesis := &types.Header{Number: big.NewInt(0), ReceiptHash: types.EmptyReceiptsHash}
+	rawdb.WriteHeader(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), 0)
+
+	reorged := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(1), ReceiptHash: common.Hash{0x01}}
+	rawdb.WriteHeader(db, reorged)
+	rawdb.WriteLogIndexHead(db, reorged.Hash())
+
+	if _, err := updateLogIndex(db, 0, nil); err == nil {
+		t.Fatal("expected missing receipts error")
+	}
+	if head := rawdb.ReadLogIndexHead(db); head != reorged.Hash() {
+		t.Fatalf("log index head changed: have %x, want %x", head, reorged.Hash())
+	}
+}
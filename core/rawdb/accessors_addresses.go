@@ -0,0 +1,142 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Directions in which a transaction touches an indexed address. A transaction
+// may touch an address in several directions, e.g. a self transfer.
+const (
+	AddressTxSender    byte = 1 << iota // Address sent the transaction
+	AddressTxRecipient                  // Address is the recipient of the transaction
+	AddressTxCreation                   // Address is the contract created by the transaction
+)
+
+// AddressTxCursorLength is the length of the cursors pointing into the
+// transaction history of an address.
+const AddressTxCursorLength = 12
+
+// AddressTxEntry is a positional metadata to help looking up a transaction
+// touching an address.
+type AddressTxEntry struct {
+	BlockNumber uint64
+	Index       uint32
+	Direction   byte
+}
+
+// ReadAddressTxEntries retrieves at most limit transactions touching the given
+// address, starting from the most recent one at or before the given cursor. A
+// nil cursor starts from the most recent transaction. The cursor of the next
+// page is returned too, nil if there are no more transactions.
+func ReadAddressTxEntries(db ethdb.Iteratee, address common.Address, cursor []byte, limit int) ([]AddressTxEntry, []byte) {
+	prefix := append(append([]byte{}, AddressTxPrefix...), address.Bytes()...)
+	it := db.NewIterator(prefix, cursor)
+	defer it.Release()
+
+	var entries []AddressTxEntry
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != AddressTxCursorLength || len(it.Value()) != 1 {
+			continue
+		}
+		if len(entries) == limit {
+			return entries, common.CopyBytes(key)
+		}
+		entries = append(entries, AddressTxEntry{
+			BlockNumber: ^binary.BigEndian.Uint64(key),
+			Index:       ^binary.BigEndian.Uint32(key[8:]),
+			Direction:   it.Value()[0],
+		})
+	}
+	return entries, nil
+}
+
+// WriteAddressTxEntry stores a positional metadata for a transaction touching
+// the given address.
+func WriteAddressTxEntry(db ethdb.KeyValueWriter, address common.Address, number uint64, index uint32, direction byte) {
+	if err := db.Put(addressTxKey(address, number, index), []byte{direction}); err != nil {
+		log.Crit("Failed to store address transaction entry", "err", err)
+	}
+}
+
+// DeleteAddressTxEntry removes the positional metadata of a transaction
+// touching the given address.
+func DeleteAddressTxEntry(db ethdb.KeyValueWriter, address common.Address, number uint64, index uint32) {
+	if err := db.Delete(addressTxKey(address, number, index)); err != nil {
+		log.Crit("Failed to delete address transaction entry", "err", err)
+	}
+}
+
+// DeleteAddressTxEntries removes the positional metadata of all transactions
+// indexed by address.
+func DeleteAddressTxEntries(db ethdb.KeyValueStore) error {
+	return deletePrefix(db, AddressTxPrefix)
+}
+
+// ReadAddressIndexTail retrieves the number of oldest block whose transactions
+// have been indexed by address.
+func ReadAddressIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(addressIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteAddressIndexTail stores the number of oldest block whose transactions
+// have been indexed by address.
+func WriteAddressIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(addressIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the address index tail", "err", err)
+	}
+}
+
+// ReadAddressIndexHead retrieves the hash of the latest block whose
+// transactions have been indexed by address.
+func ReadAddressIndexHead(db ethdb.KeyValueReader) common.Hash {
+	data, _ := db.Get(addressIndexHeadKey)
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteAddressIndexHead stores the hash of the latest block whose transactions
+// have been indexed by address.
+func WriteAddressIndexHead(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Put(addressIndexHeadKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store the address index head", "err", err)
+	}
+}
+
+// DeleteAddressIndexMarkers removes the markers of the indexed block range,
+// flagging that no block has been indexed by address.
+func DeleteAddressIndexMarkers(db ethdb.KeyValueWriter) {
+	if err := db.Delete(addressIndexTailKey); err != nil {
+		log.Crit("Failed to delete the address index tail", "err", err)
+	}
+	if err := db.Delete(addressIndexHeadKey); err != nil {
+		log.Crit("Failed to delete the address index head", "err", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReadLiveTrace retrieves the encoded traces of a block recorded by the live
+// tracer as it was imported.
+func ReadLiveTrace(db ethdb.KeyValueReader, hash common.Hash, number uint64) []byte {
+	data, _ := db.Get(liveTraceKey(number, hash))
+	return data
+}
+
+// WriteLiveTrace stores the encoded traces of a block recorded by the live
+// tracer.
+func WriteLiveTrace(db ethdb.KeyValueWriter, hash common.Hash, number uint64, data []byte) {
+	if err := db.Put(liveTraceKey(number, hash), data); err != nil {
+		log.Crit("Failed to store live trace", "err", err)
+	}
+}
+
+// DeleteLiveTrace removes the live traces of a block.
+func DeleteLiveTrace(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(liveTraceKey(number, hash)); err != nil {
+		log.Crit("Failed to delete live trace", "err", err)
+	}
+}
+
+// DeleteLiveTracesRange removes the live traces of all the blocks numbered in
+// the range [from, to), including the ones reorged out.
+func DeleteLiveTracesRange(db ethdb.KeyValueStore, from, to uint64) error {
+	var (
+		batch = db.NewBatch()
+		it    = db.NewIterator(LiveTracePrefix, encodeBlockNumber(from))
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(LiveTracePrefix):]
+		if len(key) != 8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key) >= to {
+			break
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// ReadLiveTraceTail retrieves the number of the oldest block whose live traces
+// may still be stored.
+func ReadLiveTraceTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(liveTraceTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteLiveTraceTail stores the number of the oldest block whose live traces
+// may still be stored.
+func WriteLiveTraceTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(liveTraceTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the live trace tail", "err", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Kinds of values indexed by the log index. Topics are indexed along with their
+// position within the log, the first topic being indexed as LogIndexTopic.
+const (
+	LogIndexAddress byte = 0
+	LogIndexTopic   byte = 1
+)
+
+// WriteLogIndexEntry adds a block to the posting list of the given log value.
+func WriteLogIndexEntry(db ethdb.KeyValueWriter, kind byte, value common.Hash, number uint64) {
+	if err := db.Put(logIndexKey(kind, value, number), nil); err != nil {
+		log.Crit("Failed to store log index entry", "err", err)
+	}
+}
+
+// DeleteLogIndexEntry removes a block from the posting list of the given log
+// value.
+func DeleteLogIndexEntry(db ethdb.KeyValueWriter, kind byte, value common.Hash, number uint64) {
+	if err := db.Delete(logIndexKey(kind, value, number)); err != nil {
+		log.Crit("Failed to delete log index entry", "err", err)
+	}
+}
+
+// ReadLogIndexBlocks retrieves the numbers of the blocks in the [begin, end]
+// range containing logs with the given value, in ascending order.
+func ReadLogIndexBlocks(db ethdb.Iteratee, kind byte, value common.Hash, begin, end uint64) []uint64 {
+	var (
+		prefix = logIndexKey(kind, value, 0)[:len(LogIndexPrefix)+1+common.HashLength]
+		start  = make([]byte, 8)
+	)
+	binary.BigEndian.PutUint64(start, begin)
+
+	it := db.NewIterator(prefix, start)
+	defer it.Release()
+
+	var numbers []uint64
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != 8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key)
+		if number > end {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// ReadLogIndexHead retrieves the hash of the latest block whose logs have been
+// indexed.
+func ReadLogIndexHead(db ethdb.KeyValueReader) common.Hash {
+	data, _ := db.Get(logIndexHeadKey)
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteLogIndexHead stores the hash of the latest block whose logs have been
+// indexed.
+func WriteLogIndexHead(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Put(logIndexHeadKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store the log index head", "err", err)
+	}
+}
+
+// DeleteLogIndex removes the entire log index.
+func DeleteLogIndex(db ethdb.KeyValueStore) error {
+	if err := deletePrefix(db, LogIndexPrefix); err != nil {
+		return err
+	}
+	return db.Delete(logIndexHeadKey)
+}
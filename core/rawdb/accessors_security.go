@@ -0,0 +1,132 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Kinds of persisted security policy entries. The kind is stored as a single
+// byte after SecurityPolicyPrefix, followed by the entry specific key.
+const (
+	SecurityWhitelistEntry   byte = 'w' // address -> empty
+	SecurityBlacklistEntry   byte = 'b' // address -> empty
+	SecurityFrozenEntry      byte = 'f' // address -> empty
+	SecurityContractEntry    byte = 'c' // owner + contract -> registration index (uint64 big endian)
+	SecurityRestrictionEntry byte = 'r' // address + scope -> RLP(restriction)
+)
+
+// SecurityEntry is a single persisted security policy entry, with the key
+// stripped of the prefix and kind.
+type SecurityEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// ReadSecuritySchemaVersion retrieves the layout version of the persisted
+// security policy, or nil if none was ever written.
+func ReadSecuritySchemaVersion(db ethdb.KeyValueReader) (*uint64, error) {
+	if has, err := db.Has(securitySchemaVersionKey); err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(securitySchemaVersionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 8 {
+		return nil, fmt.Errorf("invalid security schema version length %d", len(data))
+	}
+	version := binary.BigEndian.Uint64(data)
+	return &version, nil
+}
+
+// WriteSecuritySchemaVersion stores the layout version of the persisted
+// security policy.
+func WriteSecuritySchemaVersion(db ethdb.KeyValueWriter, version uint64) {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], version)
+	if err := db.Put(securitySchemaVersionKey, enc[:]); err != nil {
+		log.Crit("Failed to store security schema version", "err", err)
+	}
+}
+
+// ReadLegacySecurityConfig retrieves the RLP encoded security policy blob
+// written by schema version 0, or nil if there is none.
+func ReadLegacySecurityConfig(db ethdb.KeyValueReader) ([]byte, error) {
+	if has, err := db.Has(securityConfigLegacyKey); err != nil || !has {
+		return nil, err
+	}
+	return db.Get(securityConfigLegacyKey)
+}
+
+// DeleteLegacySecurityConfig removes the security policy blob written by
+// schema version 0.
+func DeleteLegacySecurityConfig(db ethdb.KeyValueWriter) {
+	if err := db.Delete(securityConfigLegacyKey); err != nil {
+		log.Crit("Failed to delete legacy security config", "err", err)
+	}
+}
+
+// WriteSecurityEntry stores a security policy entry of the given kind.
+func WriteSecurityEntry(db ethdb.KeyValueWriter, kind byte, key []byte, value []byte) {
+	if err := db.Put(securityPolicyKey(kind, key), value); err != nil {
+		log.Crit("Failed to store security policy entry", "err", err)
+	}
+}
+
+// DeleteSecurityEntry removes a security policy entry of the given kind.
+func DeleteSecurityEntry(db ethdb.KeyValueWriter, kind byte, key []byte) {
+	if err := db.Delete(securityPolicyKey(kind, key)); err != nil {
+		log.Crit("Failed to delete security policy entry", "err", err)
+	}
+}
+
+// ReadSecurityEntries retrieves all security policy entries of the given kind,
+// ordered by key.
+func ReadSecurityEntries(db ethdb.Iteratee, kind byte) ([]SecurityEntry, error) {
+	prefix := securityPolicyKey(kind, nil)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var entries []SecurityEntry
+	for it.Next() {
+		entries = append(entries, SecurityEntry{
+			Key:   bytes.Clone(it.Key()[len(prefix):]),
+			Value: bytes.Clone(it.Value()),
+		})
+	}
+	return entries, it.Error()
+}
+
+// DeleteAllSecurityEntries removes every persisted security policy entry of
+// any kind.
+func DeleteAllSecurityEntries(db ethdb.Iteratee, batch ethdb.KeyValueWriter) error {
+	it := db.NewIterator(SecurityPolicyPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Delete(bytes.Clone(it.Key())); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
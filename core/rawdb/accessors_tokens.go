@@ -0,0 +1,276 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Token standards whose transfers are indexed.
+const (
+	TokenERC20   uint16 = 20
+	TokenERC721  uint16 = 721
+	TokenERC1155 uint16 = 1155
+)
+
+// TokenTransferCursorLength is the length of the cursors pointing into the
+// transfer history of a token or a holder.
+const TokenTransferCursorLength = 16
+
+// TokenTransfer is a single token transfer emitted by a log. An ERC-1155 batch
+// transfer log emits one transfer per transferred token id.
+type TokenTransfer struct {
+	Token       common.Address
+	Standard    uint16
+	From        common.Address
+	To          common.Address
+	ID          *big.Int // Id of the transferred token, zero for ERC-20
+	Value       *big.Int // Amount transferred, one for ERC-721
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint32
+	BatchIndex  uint32 // Index of the transfer within an ERC-1155 batch transfer
+}
+
+// Position returns the position of the transfer within the chain, which is
+// also the cursor pointing to it.
+func (t *TokenTransfer) Position() []byte {
+	return tokenPosition(t.BlockNumber, t.LogIndex, t.BatchIndex)
+}
+
+// Holders returns the accounts whose transfer history includes the transfer.
+// The zero address minting and burning tokens is not tracked.
+func (t *TokenTransfer) Holders() []common.Address {
+	var holders []common.Address
+	if t.From != (common.Address{}) {
+		holders = append(holders, t.From)
+	}
+	if t.To != (common.Address{}) && t.To != t.From {
+		holders = append(holders, t.To)
+	}
+	return holders
+}
+
+// TokenHolder is an account holding a token, along with its balance. The
+// balance is the sum of all amounts transferred in and out of the account. The
+// ids of an ERC-1155 token are not fungible, so their balances are tracked per
+// id, whereas ERC-721 balances count the ids held.
+type TokenHolder struct {
+	Holder  common.Address
+	ID      *big.Int // Token id of ERC-1155 balances, nil otherwise
+	Balance *big.Int
+}
+
+// WriteTokenTransfer stores a token transfer, indexed both by the token and by
+// the holders involved.
+func WriteTokenTransfer(db ethdb.KeyValueWriter, transfer *TokenTransfer) {
+	data, err := rlp.EncodeToBytes(transfer)
+	if err != nil {
+		log.Crit("Failed to encode token transfer", "err", err)
+	}
+	position := transfer.Position()
+	if err := db.Put(tokenTransferKey(transfer.Token, position), data); err != nil {
+		log.Crit("Failed to store token transfer", "err", err)
+	}
+	for _, holder := range transfer.Holders() {
+		if err := db.Put(tokenHolderTransferKey(holder, position), data); err != nil {
+			log.Crit("Failed to store holder token transfer", "err", err)
+		}
+	}
+}
+
+// DeleteTokenTransfer removes a token transfer from all the indexes.
+func DeleteTokenTransfer(db ethdb.KeyValueWriter, transfer *TokenTransfer) {
+	position := transfer.Position()
+	if err := db.Delete(tokenTransferKey(transfer.Token, position)); err != nil {
+		log.Crit("Failed to delete token transfer", "err", err)
+	}
+	for _, holder := range transfer.Holders() {
+		if err := db.Delete(tokenHolderTransferKey(holder, position)); err != nil {
+			log.Crit("Failed to delete holder token transfer", "err", err)
+		}
+	}
+}
+
+// ReadTokenTransfers retrieves at most limit transfers of the given token,
+// starting from the most recent one at or before the given cursor. The cursor
+// of the next page is returned too, nil if there are no more transfers.
+func ReadTokenTransfers(db ethdb.Iteratee, token common.Address, cursor []byte, limit int) ([]*TokenTransfer, []byte) {
+	prefix := append(append([]byte{}, TokenTransferPrefix...), token.Bytes()...)
+	return readTokenTransfers(db, prefix, cursor, limit, nil)
+}
+
+// ReadHolderTokenTransfers retrieves at most limit transfers from or to the
+// given holder, optionally only of the given token, starting from the most
+// recent one at or before the given cursor. The cursor of the next page is
+// returned too, nil if there are no more transfers.
+func ReadHolderTokenTransfers(db ethdb.Iteratee, holder common.Address, token *common.Address, cursor []byte, limit int) ([]*TokenTransfer, []byte) {
+	prefix := append(append([]byte{}, TokenHolderTransferPrefix...), holder.Bytes()...)
+	return readTokenTransfers(db, prefix, cursor, limit, token)
+}
+
+func readTokenTransfers(db ethdb.Iteratee, prefix []byte, cursor []byte, limit int, token *common.Address) ([]*TokenTransfer, []byte) {
+	it := db.NewIterator(prefix, cursor)
+	defer it.Release()
+
+	var transfers []*TokenTransfer
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != TokenTransferCursorLength {
+			continue
+		}
+		transfer := new(TokenTransfer)
+		if err := rlp.DecodeBytes(it.Value(), transfer); err != nil {
+			log.Error("Invalid token transfer RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		if token != nil && transfer.Token != *token {
+			continue
+		}
+		if len(transfers) == limit {
+			return transfers, common.CopyBytes(key)
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+// ReadTokenBalance retrieves the balance of a holder of the given token, zero
+// if the holder is not known. The id is only set for ERC-1155 tokens.
+func ReadTokenBalance(db ethdb.KeyValueReader, token common.Address, holder common.Address, id *big.Int) *big.Int {
+	data, _ := db.Get(tokenBalanceKey(token, holder, id))
+	return decodeTokenBalance(data)
+}
+
+// WriteTokenBalance stores the balance of a holder of the given token. Holders
+// with a zero balance are removed. The id is only set for ERC-1155 tokens.
+func WriteTokenBalance(db ethdb.KeyValueWriter, token common.Address, holder common.Address, id *big.Int, balance *big.Int) {
+	if balance.Sign() == 0 {
+		if err := db.Delete(tokenBalanceKey(token, holder, id)); err != nil {
+			log.Crit("Failed to delete token balance", "err", err)
+		}
+		return
+	}
+	// Balances are encoded as a sign byte followed by the magnitude, as
+	// tokens minted without events may drive them negative.
+	enc := append([]byte{0}, balance.Bytes()...)
+	if balance.Sign() < 0 {
+		enc[0] = 1
+	}
+	if err := db.Put(tokenBalanceKey(token, holder, id), enc); err != nil {
+		log.Crit("Failed to store token balance", "err", err)
+	}
+}
+
+func decodeTokenBalance(data []byte) *big.Int {
+	if len(data) == 0 {
+		return new(big.Int)
+	}
+	balance := new(big.Int).SetBytes(data[1:])
+	if data[0] == 1 {
+		balance.Neg(balance)
+	}
+	return balance
+}
+
+// ReadTokenHolders retrieves at most limit holders of the given token with a
+// non-zero balance, starting at the given holder cursor. ERC-1155 holders are
+// returned once per id held. The cursor of the next page is returned too, nil
+// if there are no more holders.
+func ReadTokenHolders(db ethdb.Iteratee, token common.Address, cursor []byte, limit int) ([]*TokenHolder, []byte) {
+	prefix := append(append([]byte{}, TokenBalancePrefix...), token.Bytes()...)
+	it := db.NewIterator(prefix, cursor)
+	defer it.Release()
+
+	var holders []*TokenHolder
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != common.AddressLength && len(key) != common.AddressLength+common.HashLength {
+			continue
+		}
+		if len(holders) == limit {
+			return holders, common.CopyBytes(key)
+		}
+		holder := &TokenHolder{
+			Holder:  common.BytesToAddress(key[:common.AddressLength]),
+			Balance: decodeTokenBalance(it.Value()),
+		}
+		if len(key) > common.AddressLength {
+			holder.ID = new(big.Int).SetBytes(key[common.AddressLength:])
+		}
+		holders = append(holders, holder)
+	}
+	return holders, nil
+}
+
+// ReadTokenIndexHead retrieves the hash of the latest block whose token
+// transfers have been indexed.
+func ReadTokenIndexHead(db ethdb.KeyValueReader) common.Hash {
+	data, _ := db.Get(tokenIndexHeadKey)
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteTokenIndexHead stores the hash of the latest block whose token transfers
+// have been indexed.
+func WriteTokenIndexHead(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Put(tokenIndexHeadKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store the token index head", "err", err)
+	}
+}
+
+// DeleteTokenIndex removes the entire token transfer index.
+func DeleteTokenIndex(db ethdb.KeyValueStore) error {
+	for _, prefix := range [][]byte{TokenTransferPrefix, TokenHolderTransferPrefix, TokenBalancePrefix} {
+		if err := deletePrefix(db, prefix); err != nil {
+			return err
+		}
+	}
+	return db.Delete(tokenIndexHeadKey)
+}
+
+// deletePrefix removes all the entries with the given key prefix.
+func deletePrefix(db ethdb.KeyValueStore, prefix []byte) error {
+	var (
+		batch = db.NewBatch()
+		it    = db.NewIterator(prefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
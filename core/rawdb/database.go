@@ -479,6 +479,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		securityPolicy  stat
+		addressTxs      stat
+		tokenTransfers  stat
+		logIndex        stat
+		liveTraces      stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -541,6 +546,16 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, SecurityPolicyPrefix) || bytes.Equal(key, securityConfigLegacyKey):
+			securityPolicy.Add(size)
+		case bytes.HasPrefix(key, AddressTxPrefix) && len(key) == len(AddressTxPrefix)+common.AddressLength+12:
+			addressTxs.Add(size)
+		case bytes.HasPrefix(key, TokenTransferPrefix) || bytes.HasPrefix(key, TokenHolderTransferPrefix) || bytes.HasPrefix(key, TokenBalancePrefix):
+			tokenTransfers.Add(size)
+		case bytes.HasPrefix(key, LogIndexPrefix) && len(key) == len(LogIndexPrefix)+1+common.HashLength+8:
+			logIndex.Add(size)
+		case bytes.HasPrefix(key, LiveTracePrefix) && len(key) == len(LiveTracePrefix)+8+common.HashLength:
+			liveTraces.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -557,6 +572,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 				persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
+				securitySchemaVersionKey, addressIndexTailKey, addressIndexHeadKey,
+				tokenIndexHeadKey, logIndexHeadKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -594,6 +611,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Security policy", securityPolicy.Size(), securityPolicy.Count()},
+		{"Key-Value store", "Address transaction index", addressTxs.Size(), addressTxs.Count()},
+		{"Key-Value store", "Token transfer index", tokenTransfers.Size(), tokenTransfers.Count()},
+		{"Key-Value store", "Log index", logIndex.Size(), logIndex.Count()},
+		{"Key-Value store", "Live traces", liveTraces.Size(), liveTraces.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -20,6 +20,7 @@ package rawdb
 import (
 	"bytes"
 	"encoding/binary"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -96,6 +97,33 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// securitySchemaVersionKey tracks the layout version of the persisted security policy.
+	securitySchemaVersionKey = []byte("SecuritySchemaVersion")
+
+	// securityConfigLegacyKey tracks the single-blob security policy of schema version 0.
+	securityConfigLegacyKey = []byte("security-config")
+
+	// addressIndexTailKey tracks the oldest block whose transactions have been
+	// indexed by address. Unlike the other metadata keys, it must not start with
+	// an "A", which would be taken for an account trie node.
+	addressIndexTailKey = []byte("TxAddressIndexTail")
+
+	// addressIndexHeadKey tracks the hash of the latest block whose transactions
+	// have been indexed by address.
+	addressIndexHeadKey = []byte("TxAddressIndexHead")
+
+	// tokenIndexHeadKey tracks the hash of the latest block whose token transfers
+	// have been indexed.
+	tokenIndexHeadKey = []byte("TokenIndexHead")
+
+	// logIndexHeadKey tracks the hash of the latest block whose logs have been
+	// indexed.
+	logIndexHeadKey = []byte("LogIndexHead")
+
+	// liveTraceTailKey tracks the oldest block whose live traces may still be
+	// stored.
+	liveTraceTailKey = []byte("LiveTraceTail")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -134,6 +162,18 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 
+	SecurityPolicyPrefix = []byte("security-v1-") // SecurityPolicyPrefix + kind (1 byte) + entry key -> policy entry
+
+	AddressTxPrefix = []byte("addr-tx-") // AddressTxPrefix + address + ^num (uint64 big endian) + ^index (uint32 big endian) -> flags
+
+	TokenTransferPrefix       = []byte("token-tx-")  // TokenTransferPrefix + token + ^position -> RLP(TokenTransfer)
+	TokenHolderTransferPrefix = []byte("token-htx-") // TokenHolderTransferPrefix + holder + ^position -> RLP(TokenTransfer)
+	TokenBalancePrefix        = []byte("token-bal-") // TokenBalancePrefix + token + holder (+ id) -> signed balance
+
+	LogIndexPrefix = []byte("log-idx-") // LogIndexPrefix + kind + value + num (uint64 big endian) -> nil
+
+	LiveTracePrefix = []byte("live-trace-") // LiveTracePrefix + num (uint64 big endian) + hash -> block traces
+
 	BestUpdateKey         = []byte("update-")    // bigEndian64(syncPeriod) -> RLP(types.LightClientUpdate)  (nextCommittee only referenced by root hash)
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
 	SyncCommitteeKey      = []byte("committee-") // bigEndian64(syncPeriod) -> serialized committee
@@ -265,6 +305,74 @@ func stateIDKey(root common.Hash) []byte {
 	return append(stateIDPrefix, root.Bytes()...)
 }
 
+// securityPolicyKey = SecurityPolicyPrefix + kind + entry key
+func securityPolicyKey(kind byte, key []byte) []byte {
+	buf := make([]byte, len(SecurityPolicyPrefix)+1+len(key))
+	n := copy(buf, SecurityPolicyPrefix)
+	buf[n] = kind
+	copy(buf[n+1:], key)
+	return buf
+}
+
+// addressTxKey = AddressTxPrefix + address + ^num (uint64 big endian) + ^index (uint32 big endian)
+//
+// The block number and transaction index are inverted so that the transactions
+// of an address are iterated from the most recent one.
+func addressTxKey(address common.Address, number uint64, index uint32) []byte {
+	buf := make([]byte, len(AddressTxPrefix)+common.AddressLength+12)
+	n := copy(buf, AddressTxPrefix)
+	n += copy(buf[n:], address.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], ^number)
+	binary.BigEndian.PutUint32(buf[n+8:], ^index)
+	return buf
+}
+
+// tokenPosition = ^num (uint64 big endian) + ^log index (uint32 big endian) + ^batch index (uint32 big endian)
+//
+// The position is inverted so that token transfers are iterated from the most
+// recent one.
+func tokenPosition(number uint64, logIndex uint32, batchIndex uint32) []byte {
+	buf := make([]byte, TokenTransferCursorLength)
+	binary.BigEndian.PutUint64(buf, ^number)
+	binary.BigEndian.PutUint32(buf[8:], ^logIndex)
+	binary.BigEndian.PutUint32(buf[12:], ^batchIndex)
+	return buf
+}
+
+// tokenTransferKey = TokenTransferPrefix + token + position
+func tokenTransferKey(token common.Address, position []byte) []byte {
+	return append(append(append([]byte{}, TokenTransferPrefix...), token.Bytes()...), position...)
+}
+
+// tokenHolderTransferKey = TokenHolderTransferPrefix + holder + position
+func tokenHolderTransferKey(holder common.Address, position []byte) []byte {
+	return append(append(append([]byte{}, TokenHolderTransferPrefix...), holder.Bytes()...), position...)
+}
+
+// tokenBalanceKey = TokenBalancePrefix + token + holder (+ id)
+func tokenBalanceKey(token common.Address, holder common.Address, id *big.Int) []byte {
+	key := append(append(append([]byte{}, TokenBalancePrefix...), token.Bytes()...), holder.Bytes()...)
+	if id != nil {
+		key = append(key, common.BigToHash(id).Bytes()...)
+	}
+	return key
+}
+
+// logIndexKey = LogIndexPrefix + kind + value + num (uint64 big endian)
+func logIndexKey(kind byte, value common.Hash, number uint64) []byte {
+	key := make([]byte, len(LogIndexPrefix)+1+common.HashLength+8)
+	copy(key, LogIndexPrefix)
+	key[len(LogIndexPrefix)] = kind
+	copy(key[len(LogIndexPrefix)+1:], value.Bytes())
+	binary.BigEndian.PutUint64(key[len(LogIndexPrefix)+1+common.HashLength:], number)
+	return key
+}
+
+// liveTraceKey = LiveTracePrefix + num (uint64 big endian) + hash
+func liveTraceKey(number uint64, hash common.Hash) []byte {
+	return append(append(LiveTracePrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // accountTrieNodeKey = trieNodeAccountPrefix + nodePath.
 func accountTrieNodeKey(path []byte) []byte {
 	return append(trieNodeAccountPrefix, path...)
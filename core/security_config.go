@@ -17,12 +17,20 @@
 package core
 
 import (
+    "encoding/binary"
     "errors"
+    "fmt"
+    "sort"
     "sync"
+    "sync/atomic"
 
     "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/core/rawdb"
+    "github.com/ethereum/go-ethereum/core/types"
     "github.com/ethereum/go-ethereum/ethdb"
+    "github.com/ethereum/go-ethereum/event"
     "github.com/ethereum/go-ethereum/log"
+    "github.com/ethereum/go-ethereum/metrics"
     "github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -43,6 +51,62 @@ type SecurityConfig struct {
     // DeployedContracts - lưu trữ danh sách contract đã được triển khai bởi mỗi địa chỉ
     DeployedContracts     map[common.Address][]common.Address
     contractsMu          sync.RWMutex
+    
+    // Restrictions - các hạn chế có thời hạn và phạm vi áp dụng cho từng địa chỉ
+    Restrictions          map[common.Address][]*Restriction
+    restrictionsMu       sync.RWMutex
+    
+    // db là cơ sở dữ liệu mà mọi thay đổi được ghi ngay vào (nil nếu chỉ lưu trong bộ nhớ)
+    db                   ethdb.Database
+    
+    // Feeds thông báo thay đổi chính sách và giao dịch bị chặn. Sự kiện được
+    // chuyển qua notify tới một goroutine riêng, khởi động khi có subscriber
+    // đầu tiên, để subscriber chậm không chặn txpool hay việc import block.
+    policyFeed           event.Feed
+    rejectionFeed        event.Feed
+    notify               chan interface{}
+    notifyOnce           sync.Once
+    subscribed           atomic.Bool
+}
+
+// securityFeedBuffer là số sự kiện được đệm cho subscriber trước khi bắt đầu
+// bị bỏ qua
+const securityFeedBuffer = 1024
+
+// securityEventDropMeter đếm số sự kiện bảo mật bị bỏ qua do subscriber không
+// theo kịp
+var securityEventDropMeter = metrics.NewRegisteredMeter("security/events/dropped", nil)
+
+// Các hành động thay đổi chính sách được thông báo qua SecurityPolicyEvent
+const (
+    PolicyActionAddToWhitelist      = "addToWhitelist"
+    PolicyActionRemoveFromWhitelist = "removeFromWhitelist"
+    PolicyActionAddToBlacklist      = "addToBlacklist"
+    PolicyActionRemoveFromBlacklist = "removeFromBlacklist"
+    PolicyActionFreezeAccount       = "freezeAccount"
+    PolicyActionUnfreezeAccount     = "unfreezeAccount"
+    PolicyActionRegisterContract    = "registerDeployedContract"
+    PolicyActionAddRestriction      = "addRestriction"
+    PolicyActionRemoveRestriction   = "removeRestriction"
+)
+
+// Các nơi giao dịch có thể bị chặn, được thông báo qua SecurityRejectionEvent
+const (
+    RejectionPathPool  = "pool"  // Khi thêm vào txpool
+    RejectionPathRPC   = "rpc"   // Khi gửi qua JSON-RPC
+    RejectionPathBlock = "block" // Khi xử lý giao dịch trong block
+)
+
+// NewSecurityConfig tạo cấu hình bảo mật rỗng
+func NewSecurityConfig() *SecurityConfig {
+    return &SecurityConfig{
+        ContractDeployWhitelist: make(map[common.Address]bool),
+        BlacklistedAddresses:    make(map[common.Address]bool),
+        FrozenAccounts:          make(map[common.Address]bool),
+        DeployedContracts:       make(map[common.Address][]common.Address),
+        Restrictions:            make(map[common.Address][]*Restriction),
+        notify:                  make(chan interface{}, securityFeedBuffer),
+    }
 }
 
 // Biến global sử dụng singleton pattern
@@ -55,12 +119,7 @@ var (
 func GetSecurityConfig() *SecurityConfig {
     once.Do(func() {
         if securityConfigInstance == nil {
-            securityConfigInstance = &SecurityConfig{
-                ContractDeployWhitelist: make(map[common.Address]bool),
-                BlacklistedAddresses:    make(map[common.Address]bool),
-                FrozenAccounts:          make(map[common.Address]bool),
-                DeployedContracts:       make(map[common.Address][]common.Address),
-            }
+            securityConfigInstance = NewSecurityConfig()
             log.Info("Security config initialized")
         }
     })
@@ -79,10 +138,22 @@ func (sc *SecurityConfig) AddToWhitelist(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionAddToWhitelist, Address: addr})
+        }
+    }()
     sc.whitelistMu.Lock()
     defer sc.whitelistMu.Unlock()
     
+    if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityWhitelistEntry, addr.Bytes(), nil)
+    }); err != nil {
+        return err
+    }
     sc.ContractDeployWhitelist[addr] = true
+    changed = true
     log.Info("Address added to contract deployment whitelist", "address", addr.Hex())
     return nil
 }
@@ -93,11 +164,23 @@ func (sc *SecurityConfig) RemoveFromWhitelist(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionRemoveFromWhitelist, Address: addr})
+        }
+    }()
     sc.whitelistMu.Lock()
     defer sc.whitelistMu.Unlock()
     
     if _, exists := sc.ContractDeployWhitelist[addr]; exists {
+        if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+            rawdb.DeleteSecurityEntry(batch, rawdb.SecurityWhitelistEntry, addr.Bytes())
+        }); err != nil {
+            return err
+        }
         delete(sc.ContractDeployWhitelist, addr)
+        changed = true
         log.Info("Address removed from contract deployment whitelist", "address", addr.Hex())
     } else {
         log.Debug("Address not in whitelist, nothing to remove", "address", addr.Hex())
@@ -120,10 +203,22 @@ func (sc *SecurityConfig) AddToBlacklist(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionAddToBlacklist, Address: addr})
+        }
+    }()
     sc.blacklistMu.Lock()
     defer sc.blacklistMu.Unlock()
     
+    if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityBlacklistEntry, addr.Bytes(), nil)
+    }); err != nil {
+        return err
+    }
     sc.BlacklistedAddresses[addr] = true
+    changed = true
     log.Info("Address added to blacklist", "address", addr.Hex())
     return nil
 }
@@ -134,11 +229,23 @@ func (sc *SecurityConfig) RemoveFromBlacklist(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionRemoveFromBlacklist, Address: addr})
+        }
+    }()
     sc.blacklistMu.Lock()
     defer sc.blacklistMu.Unlock()
     
     if _, exists := sc.BlacklistedAddresses[addr]; exists {
+        if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+            rawdb.DeleteSecurityEntry(batch, rawdb.SecurityBlacklistEntry, addr.Bytes())
+        }); err != nil {
+            return err
+        }
         delete(sc.BlacklistedAddresses, addr)
+        changed = true
         log.Info("Address removed from blacklist", "address", addr.Hex())
     } else {
         log.Debug("Address not in blacklist, nothing to remove", "address", addr.Hex())
@@ -160,10 +267,22 @@ func (sc *SecurityConfig) FreezeAccount(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionFreezeAccount, Address: addr})
+        }
+    }()
     sc.frozenMu.Lock()
     defer sc.frozenMu.Unlock()
     
+    if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityFrozenEntry, addr.Bytes(), nil)
+    }); err != nil {
+        return err
+    }
     sc.FrozenAccounts[addr] = true
+    changed = true
     log.Info("Account frozen", "address", addr.Hex())
     return nil
 }
@@ -174,11 +293,23 @@ func (sc *SecurityConfig) UnfreezeAccount(addr common.Address) error {
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionUnfreezeAccount, Address: addr})
+        }
+    }()
     sc.frozenMu.Lock()
     defer sc.frozenMu.Unlock()
     
     if _, exists := sc.FrozenAccounts[addr]; exists {
+        if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+            rawdb.DeleteSecurityEntry(batch, rawdb.SecurityFrozenEntry, addr.Bytes())
+        }); err != nil {
+            return err
+        }
         delete(sc.FrozenAccounts, addr)
+        changed = true
         log.Info("Account unfrozen", "address", addr.Hex())
     } else {
         log.Debug("Account not frozen, nothing to unfreeze", "address", addr.Hex())
@@ -200,6 +331,12 @@ func (sc *SecurityConfig) RegisterDeployedContract(owner common.Address, contrac
         return errors.New("invalid address: zero address")
     }
     
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionRegisterContract, Address: owner, Contract: &contractAddr})
+        }
+    }()
     sc.contractsMu.Lock()
     defer sc.contractsMu.Unlock()
     
@@ -215,7 +352,14 @@ func (sc *SecurityConfig) RegisterDeployedContract(owner common.Address, contrac
         }
     }
     
+    index := uint64(len(sc.DeployedContracts[owner]))
+    if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityContractEntry, contractEntryKey(owner, contractAddr), binary.BigEndian.AppendUint64(nil, index))
+    }); err != nil {
+        return err
+    }
     sc.DeployedContracts[owner] = append(sc.DeployedContracts[owner], contractAddr)
+    changed = true
     log.Info("Contract registered successfully", "owner", owner.Hex(), "contract", contractAddr.Hex(), "total", len(sc.DeployedContracts[owner]))
     return nil
 }
@@ -269,6 +413,338 @@ func (sc *SecurityConfig) IsContractFrozen(contractAddr common.Address) bool {
     return sc.IsFrozen(owner)
 }
 
+// ============= CÁC HÀM HẠN CHẾ CÓ THỜI HẠN =============
+// Hạn chế cho phép chặn một địa chỉ theo phạm vi (gửi đi, nhận vào, deploy
+// contract hoặc tương tác với các contract cụ thể), kèm mã lý do và thời hạn
+// tính theo block hoặc timestamp. Khác với blacklist/freeze, hạn chế tự hết
+// hiệu lực khi tới hạn mà không cần gỡ thủ công.
+
+// RestrictionScope xác định phạm vi áp dụng của một hạn chế
+type RestrictionScope uint8
+
+const (
+    RestrictionAll       RestrictionScope = iota // Chặn cả giao dịch gửi đi lẫn nhận vào
+    RestrictionOutbound                          // Chặn giao dịch do địa chỉ gửi đi
+    RestrictionInbound                           // Chặn giao dịch gửi tới địa chỉ
+    RestrictionDeploy                            // Chặn deploy contract
+    RestrictionContracts                         // Chặn tương tác với các contract được liệt kê
+)
+
+var restrictionScopeNames = map[RestrictionScope]string{
+    RestrictionAll:       "all",
+    RestrictionOutbound:  "outbound",
+    RestrictionInbound:   "inbound",
+    RestrictionDeploy:    "deploy",
+    RestrictionContracts: "contracts",
+}
+
+// String trả về tên của phạm vi hạn chế
+func (s RestrictionScope) String() string {
+    if name, ok := restrictionScopeNames[s]; ok {
+        return name
+    }
+    return fmt.Sprintf("unknown(%d)", uint8(s))
+}
+
+// ParseRestrictionScope chuyển tên phạm vi thành RestrictionScope
+func ParseRestrictionScope(name string) (RestrictionScope, error) {
+    for scope, n := range restrictionScopeNames {
+        if n == name {
+            return scope, nil
+        }
+    }
+    return 0, fmt.Errorf("unknown restriction scope %q", name)
+}
+
+// Restriction mô tả một hạn chế áp dụng lên một địa chỉ
+type Restriction struct {
+    Address     common.Address
+    Scope       RestrictionScope
+    Reason      uint64           // Mã lý do do nhà vận hành quy định
+    Contracts   []common.Address // Chỉ dùng với RestrictionContracts
+    ExpiryBlock uint64           // Block hết hạn (0 = không giới hạn)
+    ExpiryTime  uint64           // Timestamp hết hạn (0 = không giới hạn)
+}
+
+// Active kiểm tra hạn chế còn hiệu lực tại block và thời điểm cho trước hay không
+func (r *Restriction) Active(number uint64, time uint64) bool {
+    if r.ExpiryBlock != 0 && number >= r.ExpiryBlock {
+        return false
+    }
+    if r.ExpiryTime != 0 && time >= r.ExpiryTime {
+        return false
+    }
+    return true
+}
+
+// copy trả về bản sao sâu của hạn chế
+func (r *Restriction) copy() *Restriction {
+    cpy := *r
+    cpy.Contracts = make([]common.Address, len(r.Contracts))
+    copy(cpy.Contracts, r.Contracts)
+    return &cpy
+}
+
+// AddRestriction thêm hạn chế cho một địa chỉ. Hạn chế cùng phạm vi đã tồn tại
+// sẽ bị thay thế.
+func (sc *SecurityConfig) AddRestriction(r *Restriction) error {
+    if r.Address == (common.Address{}) {
+        return errors.New("invalid address: zero address")
+    }
+    if _, ok := restrictionScopeNames[r.Scope]; !ok {
+        return fmt.Errorf("unknown restriction scope %d", r.Scope)
+    }
+    if r.Scope == RestrictionContracts && len(r.Contracts) == 0 {
+        return errors.New("contracts restriction requires at least one contract")
+    }
+    if r.Scope != RestrictionContracts && len(r.Contracts) > 0 {
+        return fmt.Errorf("contracts are only allowed with the %s scope", RestrictionContracts)
+    }
+    r = r.copy()
+    enc, err := rlp.EncodeToBytes(r)
+    if err != nil {
+        return err
+    }
+    
+    var changed bool
+    defer func() {
+        if changed {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionAddRestriction, Address: r.Address, Restriction: r.copy()})
+        }
+    }()
+    sc.restrictionsMu.Lock()
+    defer sc.restrictionsMu.Unlock()
+    
+    if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityRestrictionEntry, restrictionEntryKey(r.Address, r.Scope), enc)
+    }); err != nil {
+        return err
+    }
+    changed = true
+    
+    list := sc.Restrictions[r.Address]
+    for i, old := range list {
+        if old.Scope == r.Scope {
+            list[i] = r
+            log.Info("Address restriction updated", "address", r.Address.Hex(), "scope", r.Scope, "reason", r.Reason, "expiryBlock", r.ExpiryBlock, "expiryTime", r.ExpiryTime)
+            return nil
+        }
+    }
+    sc.Restrictions[r.Address] = append(list, r)
+    log.Info("Address restriction added", "address", r.Address.Hex(), "scope", r.Scope, "reason", r.Reason, "expiryBlock", r.ExpiryBlock, "expiryTime", r.ExpiryTime)
+    return nil
+}
+
+// RemoveRestriction xóa hạn chế theo phạm vi của một địa chỉ
+func (sc *SecurityConfig) RemoveRestriction(addr common.Address, scope RestrictionScope) error {
+    if addr == (common.Address{}) {
+        return errors.New("invalid address: zero address")
+    }
+    
+    var removed *Restriction
+    defer func() {
+        if removed != nil {
+            sc.notifyPolicyChange(SecurityPolicyEvent{Action: PolicyActionRemoveRestriction, Address: addr, Restriction: removed})
+        }
+    }()
+    sc.restrictionsMu.Lock()
+    defer sc.restrictionsMu.Unlock()
+    
+    list := sc.Restrictions[addr]
+    for i, r := range list {
+        if r.Scope == scope {
+            if err := sc.persist(func(batch ethdb.KeyValueWriter) {
+                rawdb.DeleteSecurityEntry(batch, rawdb.SecurityRestrictionEntry, restrictionEntryKey(addr, scope))
+            }); err != nil {
+                return err
+            }
+            removed = r.copy()
+            list = append(list[:i], list[i+1:]...)
+            if len(list) == 0 {
+                delete(sc.Restrictions, addr)
+            } else {
+                sc.Restrictions[addr] = list
+            }
+            log.Info("Address restriction removed", "address", addr.Hex(), "scope", scope)
+            return nil
+        }
+    }
+    log.Debug("Address restriction not found, nothing to remove", "address", addr.Hex(), "scope", scope)
+    return nil
+}
+
+// GetAddressRestrictions trả về bản sao các hạn chế của một địa chỉ, kể cả
+// những hạn chế đã hết hạn
+func (sc *SecurityConfig) GetAddressRestrictions(addr common.Address) []*Restriction {
+    sc.restrictionsMu.RLock()
+    defer sc.restrictionsMu.RUnlock()
+    
+    result := make([]*Restriction, 0, len(sc.Restrictions[addr]))
+    for _, r := range sc.Restrictions[addr] {
+        result = append(result, r.copy())
+    }
+    return result
+}
+
+// GetRestrictions trả về bản sao tất cả các hạn chế, sắp xếp theo địa chỉ và phạm vi
+func (sc *SecurityConfig) GetRestrictions() []*Restriction {
+    sc.restrictionsMu.RLock()
+    defer sc.restrictionsMu.RUnlock()
+    
+    result := make([]*Restriction, 0, len(sc.Restrictions))
+    for _, list := range sc.Restrictions {
+        for _, r := range list {
+            result = append(result, r.copy())
+        }
+    }
+    sort.Slice(result, func(i, j int) bool {
+        if c := result[i].Address.Cmp(result[j].Address); c != 0 {
+            return c < 0
+        }
+        return result[i].Scope < result[j].Scope
+    })
+    return result
+}
+
+// activeRestriction trả về hạn chế còn hiệu lực đầu tiên của địa chỉ thuộc một trong
+// các phạm vi cho trước, hoặc nil nếu không có. Caller phải giữ restrictionsMu.
+func (sc *SecurityConfig) activeRestriction(addr common.Address, number uint64, time uint64, scopes ...RestrictionScope) *Restriction {
+    for _, r := range sc.Restrictions[addr] {
+        if !r.Active(number, time) {
+            continue
+        }
+        for _, scope := range scopes {
+            if r.Scope == scope {
+                return r
+            }
+        }
+    }
+    return nil
+}
+
+// CheckRestrictions kiểm tra một giao dịch từ from tới to (nil nếu là deploy
+// contract) có bị chặn bởi hạn chế nào còn hiệu lực tại block và thời điểm cho
+// trước hay không
+func (sc *SecurityConfig) CheckRestrictions(from common.Address, to *common.Address, number uint64, time uint64) error {
+    sc.restrictionsMu.RLock()
+    defer sc.restrictionsMu.RUnlock()
+    
+    if len(sc.Restrictions) == 0 {
+        return nil
+    }
+    // Hạn chế phía người gửi
+    if r := sc.activeRestriction(from, number, time, RestrictionAll, RestrictionOutbound); r != nil {
+        return fmt.Errorf("%w: scope %s, reason %d", ErrRestrictedSender, r.Scope, r.Reason)
+    }
+    if to == nil {
+        if r := sc.activeRestriction(from, number, time, RestrictionDeploy); r != nil {
+            return fmt.Errorf("%w: reason %d", ErrRestrictedDeploy, r.Reason)
+        }
+        return nil
+    }
+    // Hạn chế phía người nhận
+    if r := sc.activeRestriction(*to, number, time, RestrictionAll, RestrictionInbound); r != nil {
+        return fmt.Errorf("%w: scope %s, reason %d", ErrRestrictedRecipient, r.Scope, r.Reason)
+    }
+    // Hạn chế tương tác với contract cụ thể
+    if r := sc.activeRestriction(from, number, time, RestrictionContracts); r != nil {
+        for _, contract := range r.Contracts {
+            if contract == *to {
+                return fmt.Errorf("%w: contract %s, reason %d", ErrRestrictedContractCall, to.Hex(), r.Reason)
+            }
+        }
+    }
+    return nil
+}
+
+// ============= KIỂM TRA GIAO DỊCH VÀ THÔNG BÁO =============
+
+// CheckTransaction kiểm tra một giao dịch từ from tới to (nil nếu là deploy
+// contract) theo toàn bộ chính sách bảo mật: blacklist, tài khoản và contract bị
+// đóng băng, whitelist deploy contract và các hạn chế có thời hạn
+func (sc *SecurityConfig) CheckTransaction(from common.Address, to *common.Address, data []byte, number uint64, time uint64) error {
+    // Kiểm tra blacklist
+    if sc.IsBlacklisted(from) {
+        return ErrBlacklistedAddress
+    }
+    // Kiểm tra frozen accounts
+    if sc.IsFrozen(from) {
+        return ErrFrozenAccount
+    }
+    // Kiểm tra contract destination nếu là frozen contract
+    if to != nil && sc.IsContractFrozen(*to) {
+        return ErrFrozenContract
+    }
+    // Kiểm tra whitelist cho contract deployment
+    if to == nil && len(data) > 0 && !sc.IsWhitelisted(from) {
+        return ErrNotWhitelistedForDeploy
+    }
+    // Kiểm tra các hạn chế có thời hạn
+    return sc.CheckRestrictions(from, to, number, time)
+}
+
+// CheckPendingTransaction kiểm tra một giao dịch theo chính sách bảo mật có hiệu
+// lực tại block kế tiếp head, block sớm nhất có thể chứa giao dịch. Thời gian của
+// block kế tiếp chưa biết nên lấy giá trị nhỏ nhất có thể là head.Time + 1
+func (sc *SecurityConfig) CheckPendingTransaction(from common.Address, to *common.Address, data []byte, head *types.Header) error {
+    return sc.CheckTransaction(from, to, data, head.Number.Uint64()+1, head.Time+1)
+}
+
+// notifyPolicyChange thông báo thay đổi chính sách tới các subscriber
+func (sc *SecurityConfig) notifyPolicyChange(ev SecurityPolicyEvent) {
+    sc.send(ev)
+}
+
+// NotifyRejection thông báo một giao dịch bị chặn bởi chính sách bảo mật
+func (sc *SecurityConfig) NotifyRejection(hash common.Hash, from common.Address, to *common.Address, err error, path string) {
+    sc.send(SecurityRejectionEvent{Hash: hash, From: from, To: to, Err: err, Path: path})
+}
+
+// send chuyển một sự kiện tới goroutine thông báo mà không chờ, bỏ qua sự
+// kiện nếu bộ đệm đã đầy hoặc chưa có subscriber nào
+func (sc *SecurityConfig) send(ev interface{}) {
+    if !sc.subscribed.Load() {
+        return
+    }
+    select {
+    case sc.notify <- ev:
+    default:
+        securityEventDropMeter.Mark(1)
+    }
+}
+
+// startNotifier khởi động goroutine chuyển sự kiện tới các feed, chạy suốt
+// vòng đời của cấu hình
+func (sc *SecurityConfig) startNotifier() {
+    sc.notifyOnce.Do(func() {
+        go func() {
+            for ev := range sc.notify {
+                switch ev := ev.(type) {
+                case SecurityPolicyEvent:
+                    sc.policyFeed.Send(ev)
+                case SecurityRejectionEvent:
+                    sc.rejectionFeed.Send(ev)
+                }
+            }
+        }()
+        sc.subscribed.Store(true)
+    })
+}
+
+// SubscribePolicyChanges đăng ký nhận thông báo khi chính sách bảo mật thay đổi
+func (sc *SecurityConfig) SubscribePolicyChanges(ch chan<- SecurityPolicyEvent) event.Subscription {
+    sub := sc.policyFeed.Subscribe(ch)
+    sc.startNotifier()
+    return sub
+}
+
+// SubscribeRejections đăng ký nhận thông báo khi giao dịch bị chặn
+func (sc *SecurityConfig) SubscribeRejections(ch chan<- SecurityRejectionEvent) event.Subscription {
+    sub := sc.rejectionFeed.Subscribe(ch)
+    sc.startNotifier()
+    return sub
+}
+
 // ============= HELPER FUNCTIONS =============
 // Helper functions để tránh expose mutex ra ngoài
 // =============================================
@@ -333,13 +809,49 @@ func (sc *SecurityConfig) GetAllDeployedContracts() map[common.Address][]common.
 
 // ============= PERSISTENCE FUNCTIONS =============
 // Các hàm lưu trữ và khôi phục cấu hình bảo mật
+//
+// Từ schema phiên bản 1, mỗi mục của cấu hình được lưu dưới một key riêng
+// (xem rawdb.SecurityPolicyPrefix) và mọi thay đổi được ghi ngay vào cơ sở dữ
+// liệu trong một batch nguyên tử, nên không mất dữ liệu khi node bị tắt đột
+// ngột. Schema phiên bản 0 lưu toàn bộ cấu hình trong một blob RLP và chỉ được
+// ghi khi node dừng; blob này được tự động chuyển đổi khi tải.
+
+// SecuritySchemaVersion là phiên bản layout lưu trữ hiện tại của cấu hình bảo mật
+const SecuritySchemaVersion = 1
+
+// persist ghi các thay đổi vào cơ sở dữ liệu trong một batch nguyên tử, kèm
+// phiên bản schema. Không làm gì nếu cấu hình chưa gắn với cơ sở dữ liệu.
+func (sc *SecurityConfig) persist(write func(batch ethdb.KeyValueWriter)) error {
+    if sc.db == nil {
+        return nil
+    }
+    batch := sc.db.NewBatch()
+    rawdb.WriteSecuritySchemaVersion(batch, SecuritySchemaVersion)
+    write(batch)
+    if err := batch.Write(); err != nil {
+        log.Error("Không thể lưu thay đổi cấu hình bảo mật", "lỗi", err)
+        return err
+    }
+    return nil
+}
+
+// contractEntryKey trả về key lưu trữ của một contract đã đăng ký
+func contractEntryKey(owner common.Address, contract common.Address) []byte {
+    return append(owner.Bytes(), contract.Bytes()...)
+}
+
+// restrictionEntryKey trả về key lưu trữ của một hạn chế
+func restrictionEntryKey(addr common.Address, scope RestrictionScope) []byte {
+    return append(addr.Bytes(), byte(scope))
+}
 
-// Cấu trúc dữ liệu để lưu trữ
+// Cấu trúc dữ liệu lưu trữ của schema phiên bản 0
 type securityConfigData struct {
     Whitelist  []common.Address
     Blacklist  []common.Address
     Frozen     []common.Address
     Contracts []contractOwnerMapping
+    Restrictions []*Restriction `rlp:"optional"`
 }
 // Thêm kiểu mới để lưu trữ mapping giữa owner và contracts
 type contractOwnerMapping struct {
@@ -347,99 +859,184 @@ type contractOwnerMapping struct {
     Contracts []common.Address
 }
 
-// SaveConfig lưu cấu hình bảo mật vào cơ sở dữ liệu
+// SaveConfig ghi lại toàn bộ cấu hình bảo mật vào cơ sở dữ liệu trong một batch
+// nguyên tử, thay thế mọi mục đã lưu trước đó. Các thay đổi thông thường đã được
+// ghi ngay khi thực hiện, hàm này dùng để sao chép cấu hình sang cơ sở dữ liệu
+// khác hoặc chuyển đổi từ schema cũ.
 func (sc *SecurityConfig) SaveConfig(db ethdb.Database) error {
     if db == nil {
         return errors.New("database connection is nil")
     }
     
-    // Tạo log để chỉ ra rằng chúng ta đang lưu
-    log.Info("Đang lưu cấu hình bảo mật vào cơ sở dữ liệu")
-    
-    // Thu thập dữ liệu
-    data := securityConfigData{
-        Whitelist: make([]common.Address, 0),
-        Blacklist: make([]common.Address, 0),
-        Frozen:    make([]common.Address, 0),
-        Contracts: make([]contractOwnerMapping, 0),
+    batch := db.NewBatch()
+    if err := rawdb.DeleteAllSecurityEntries(db, batch); err != nil {
+        log.Error("Không thể đọc cấu hình bảo mật hiện có", "lỗi", err)
+        return err
     }
     
-    // Lấy whitelist
-    sc.whitelistMu.RLock()
-    for addr := range sc.ContractDeployWhitelist {
-        data.Whitelist = append(data.Whitelist, addr)
+    // Ghi whitelist
+    whitelist := sc.GetWhitelistAddresses()
+    for addr := range whitelist {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityWhitelistEntry, addr.Bytes(), nil)
     }
-    sc.whitelistMu.RUnlock()
     
-    // Lấy blacklist
-    sc.blacklistMu.RLock()
-    for addr := range sc.BlacklistedAddresses {
-        data.Blacklist = append(data.Blacklist, addr)
+    // Ghi blacklist
+    blacklist := sc.GetBlacklistAddresses()
+    for addr := range blacklist {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityBlacklistEntry, addr.Bytes(), nil)
     }
-    sc.blacklistMu.RUnlock()
     
-    // Lấy frozen accounts
-    sc.frozenMu.RLock()
-    for addr := range sc.FrozenAccounts {
-        data.Frozen = append(data.Frozen, addr)
+    // Ghi frozen accounts
+    frozen := sc.GetFrozenAddresses()
+    for addr := range frozen {
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityFrozenEntry, addr.Bytes(), nil)
     }
-    sc.frozenMu.RUnlock()
     
-    // Lấy deployed contracts và chuyển đổi map thành slice
+    // Ghi deployed contracts, giữ nguyên thứ tự đăng ký
     sc.contractsMu.RLock()
     for owner, contracts := range sc.DeployedContracts {
-        contractsCopy := make([]common.Address, len(contracts))
-        copy(contractsCopy, contracts)
-        data.Contracts = append(data.Contracts, contractOwnerMapping{
-            Owner:     owner,
-            Contracts: contractsCopy,
-        })
+        for i, contract := range contracts {
+            rawdb.WriteSecurityEntry(batch, rawdb.SecurityContractEntry, contractEntryKey(owner, contract), binary.BigEndian.AppendUint64(nil, uint64(i)))
+        }
     }
+    owners := len(sc.DeployedContracts)
     sc.contractsMu.RUnlock()
     
-    // Encode và lưu
-    encoded, err := rlp.EncodeToBytes(data)
-    if err != nil {
-        log.Error("Không thể mã hóa cấu hình bảo mật", "lỗi", err)
-        return err
+    // Ghi các hạn chế
+    restrictions := sc.GetRestrictions()
+    for _, r := range restrictions {
+        enc, err := rlp.EncodeToBytes(r)
+        if err != nil {
+            log.Error("Không thể mã hóa hạn chế", "lỗi", err)
+            return err
+        }
+        rawdb.WriteSecurityEntry(batch, rawdb.SecurityRestrictionEntry, restrictionEntryKey(r.Address, r.Scope), enc)
     }
+    rawdb.WriteSecuritySchemaVersion(batch, SecuritySchemaVersion)
+    rawdb.DeleteLegacySecurityConfig(batch)
     
-    err = db.Put([]byte("security-config"), encoded)
-    if err != nil {
+    if err := batch.Write(); err != nil {
         log.Error("Không thể lưu cấu hình bảo mật vào cơ sở dữ liệu", "lỗi", err)
         return err
     }
     
     log.Info("Đã lưu cấu hình bảo mật vào cơ sở dữ liệu thành công", 
-             "whitelist", len(data.Whitelist), 
-             "blacklist", len(data.Blacklist), 
-             "frozen", len(data.Frozen), 
-             "contracts", len(data.Contracts))
+             "whitelist", len(whitelist), 
+             "blacklist", len(blacklist), 
+             "frozen", len(frozen), 
+             "contracts", owners,
+             "restrictions", len(restrictions))
     return nil
 }
 
-// LoadSecurityConfig tải cấu hình bảo mật từ cơ sở dữ liệu
+// LoadSecurityConfig tải cấu hình bảo mật từ cơ sở dữ liệu. Cấu hình trả về được
+// gắn với cơ sở dữ liệu, mọi thay đổi sau đó sẽ được ghi ngay vào db.
 func LoadSecurityConfig(db ethdb.Database) (*SecurityConfig, error) {
     if db == nil {
         return nil, errors.New("database connection is nil")
     }
     
-    encoded, err := db.Get([]byte("security-config"))
+    version, err := rawdb.ReadSecuritySchemaVersion(db)
     if err != nil {
-        // Kiểm tra lỗi "not found"
-        if err.Error() == "not found" || err.Error() == "leveldb: not found" {
-            log.Info("Không tìm thấy cấu hình bảo mật trong cơ sở dữ liệu, khởi tạo cấu hình trống")
-            return &SecurityConfig{
-                ContractDeployWhitelist: make(map[common.Address]bool),
-                BlacklistedAddresses:    make(map[common.Address]bool),
-                FrozenAccounts:          make(map[common.Address]bool),
-                DeployedContracts:       make(map[common.Address][]common.Address),
-            }, nil
+        return nil, fmt.Errorf("failed to read security schema version: %v", err)
+    }
+    if version != nil && *version > SecuritySchemaVersion {
+        return nil, fmt.Errorf("unsupported security schema version %d, max supported %d", *version, SecuritySchemaVersion)
+    }
+    if version == nil {
+        // Chưa có dữ liệu theo schema mới, chuyển đổi blob của schema 0 nếu có
+        legacy, err := rawdb.ReadLegacySecurityConfig(db)
+        if err != nil {
+            return nil, fmt.Errorf("failed to read legacy security config: %v", err)
+        }
+        if len(legacy) > 0 {
+            return migrateLegacySecurityConfig(db, legacy)
+        }
+        log.Info("Không tìm thấy cấu hình bảo mật trong cơ sở dữ liệu, khởi tạo cấu hình trống")
+        sc := NewSecurityConfig()
+        sc.db = db
+        return sc, nil
+    }
+    
+    sc := NewSecurityConfig()
+    
+    // Khôi phục whitelist, blacklist và frozen accounts
+    for _, list := range []struct {
+        kind byte
+        set  map[common.Address]bool
+    }{
+        {rawdb.SecurityWhitelistEntry, sc.ContractDeployWhitelist},
+        {rawdb.SecurityBlacklistEntry, sc.BlacklistedAddresses},
+        {rawdb.SecurityFrozenEntry, sc.FrozenAccounts},
+    } {
+        entries, err := rawdb.ReadSecurityEntries(db, list.kind)
+        if err != nil {
+            log.Error("Không thể tải cấu hình bảo mật từ cơ sở dữ liệu", "lỗi", err)
+            return nil, err
         }
+        for _, entry := range entries {
+            if len(entry.Key) != common.AddressLength {
+                log.Warn("Bỏ qua mục cấu hình bảo mật không hợp lệ", "kind", string(list.kind), "key", common.Bytes2Hex(entry.Key))
+                continue
+            }
+            list.set[common.BytesToAddress(entry.Key)] = true
+        }
+    }
+    
+    // Khôi phục deployed contracts theo thứ tự đăng ký
+    entries, err := rawdb.ReadSecurityEntries(db, rawdb.SecurityContractEntry)
+    if err != nil {
         log.Error("Không thể tải cấu hình bảo mật từ cơ sở dữ liệu", "lỗi", err)
         return nil, err
     }
+    indexes := make(map[common.Address]uint64)
+    for _, entry := range entries {
+        if len(entry.Key) != 2*common.AddressLength || len(entry.Value) != 8 {
+            log.Warn("Bỏ qua mục cấu hình bảo mật không hợp lệ", "kind", "contract", "key", common.Bytes2Hex(entry.Key))
+            continue
+        }
+        contract := common.BytesToAddress(entry.Key[common.AddressLength:])
+        owner := common.BytesToAddress(entry.Key[:common.AddressLength])
+        sc.DeployedContracts[owner] = append(sc.DeployedContracts[owner], contract)
+        indexes[contract] = binary.BigEndian.Uint64(entry.Value)
+    }
+    for _, contracts := range sc.DeployedContracts {
+        sort.SliceStable(contracts, func(i, j int) bool {
+            return indexes[contracts[i]] < indexes[contracts[j]]
+        })
+    }
     
+    // Khôi phục các hạn chế
+    entries, err = rawdb.ReadSecurityEntries(db, rawdb.SecurityRestrictionEntry)
+    if err != nil {
+        log.Error("Không thể tải cấu hình bảo mật từ cơ sở dữ liệu", "lỗi", err)
+        return nil, err
+    }
+    restrictions := 0
+    for _, entry := range entries {
+        r := new(Restriction)
+        if err := rlp.DecodeBytes(entry.Value, r); err != nil {
+            log.Warn("Bỏ qua hạn chế không hợp lệ", "key", common.Bytes2Hex(entry.Key), "lỗi", err)
+            continue
+        }
+        sc.Restrictions[r.Address] = append(sc.Restrictions[r.Address], r)
+        restrictions++
+    }
+    sc.db = db
+    
+    log.Info("Đã tải cấu hình bảo mật từ cơ sở dữ liệu", 
+             "whitelist", len(sc.ContractDeployWhitelist), 
+             "blacklist", len(sc.BlacklistedAddresses), 
+             "frozen", len(sc.FrozenAccounts), 
+             "contracts", len(sc.DeployedContracts),
+             "restrictions", restrictions)
+        
+    return sc, nil
+}
+
+// migrateLegacySecurityConfig giải mã blob cấu hình của schema phiên bản 0 và
+// ghi lại theo schema hiện tại
+func migrateLegacySecurityConfig(db ethdb.Database, encoded []byte) (*SecurityConfig, error) {
     var data securityConfigData
     if err := rlp.DecodeBytes(encoded, &data); err != nil {
         log.Error("Không thể giải mã cấu hình bảo mật", "lỗi", err)
@@ -447,12 +1044,7 @@ func LoadSecurityConfig(db ethdb.Database) (*SecurityConfig, error) {
     }
     
     // Tạo config mới
-    sc := &SecurityConfig{
-        ContractDeployWhitelist: make(map[common.Address]bool),
-        BlacklistedAddresses:    make(map[common.Address]bool),
-        FrozenAccounts:          make(map[common.Address]bool),
-        DeployedContracts:       make(map[common.Address][]common.Address),
-    }
+    sc := NewSecurityConfig()
     
     // Khôi phục whitelist
     for _, addr := range data.Whitelist {
@@ -479,11 +1071,16 @@ func LoadSecurityConfig(db ethdb.Database) (*SecurityConfig, error) {
         sc.DeployedContracts[owner] = contractsCopy
     }
     
-    log.Info("Đã tải cấu hình bảo mật từ cơ sở dữ liệu", 
-             "whitelist", len(data.Whitelist), 
-             "blacklist", len(data.Blacklist), 
-             "frozen", len(data.Frozen), 
-             "contracts", len(data.Contracts))
-        
+    // Khôi phục các hạn chế
+    for _, r := range data.Restrictions {
+        sc.Restrictions[r.Address] = append(sc.Restrictions[r.Address], r)
+    }
+    
+    log.Info("Chuyển đổi cấu hình bảo mật sang schema mới", "from", 0, "to", SecuritySchemaVersion)
+    if err := sc.SaveConfig(db); err != nil {
+        // Cơ sở dữ liệu có thể được mở ở chế độ chỉ đọc, vẫn trả về cấu hình đã giải mã
+        log.Warn("Không thể chuyển đổi cấu hình bảo mật", "lỗi", err)
+    }
+    sc.db = db
     return sc, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,384 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	testRestrictedA = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+	testRestrictedB = common.HexToAddress("0x000000000000000000000000000000000000bbbb")
+	testRestrictedC = common.HexToAddress("0x000000000000000000000000000000000000cccc")
+)
+
+func TestRestrictionScopes(t *testing.T) {
+	sc := NewSecurityConfig()
+
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionOutbound, Reason: 1}); err != nil {
+		t.Fatalf("failed to add restriction: %v", err)
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedB, Scope: RestrictionInbound, Reason: 2}); err != nil {
+		t.Fatalf("failed to add restriction: %v", err)
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedC, Scope: RestrictionDeploy, Reason: 3}); err != nil {
+		t.Fatalf("failed to add restriction: %v", err)
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedC, Scope: RestrictionContracts, Reason: 4, Contracts: []common.Address{testRestrictedA}}); err != nil {
+		t.Fatalf("failed to add restriction: %v", err)
+	}
+	tests := []struct {
+		from common.Address
+		to   *common.Address
+		want error
+	}{
+		{testRestrictedA, &testRestrictedC, ErrRestrictedSender},
+		{testRestrictedC, &testRestrictedB, ErrRestrictedRecipient},
+		{testRestrictedB, &testRestrictedA, nil},
+		{testRestrictedC, nil, ErrRestrictedDeploy},
+		{testRestrictedB, nil, nil},
+		{testRestrictedC, &testRestrictedA, ErrRestrictedContractCall},
+		{testRestrictedB, &testRestrictedC, nil},
+	}
+	for i, tt := range tests {
+		err := sc.CheckRestrictions(tt.from, tt.to, 1, 1)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
+
+func TestRestrictionExpiry(t *testing.T) {
+	sc := NewSecurityConfig()
+
+	sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionAll, ExpiryBlock: 10})
+	sc.AddRestriction(&Restriction{Address: testRestrictedB, Scope: RestrictionAll, ExpiryTime: 1000})
+
+	if err := sc.CheckRestrictions(testRestrictedA, nil, 9, 0); !errors.Is(err, ErrRestrictedSender) {
+		t.Errorf("restriction expired early: %v", err)
+	}
+	if err := sc.CheckRestrictions(testRestrictedA, nil, 10, 0); err != nil {
+		t.Errorf("restriction not expired at expiry block: %v", err)
+	}
+	if err := sc.CheckRestrictions(testRestrictedC, &testRestrictedB, 0, 999); !errors.Is(err, ErrRestrictedRecipient) {
+		t.Errorf("restriction expired early: %v", err)
+	}
+	if err := sc.CheckRestrictions(testRestrictedC, &testRestrictedB, 0, 1000); err != nil {
+		t.Errorf("restriction not expired at expiry time: %v", err)
+	}
+	// Re-adding a restriction with the same scope replaces the old one
+	sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionAll, ExpiryBlock: 20})
+	if have := len(sc.GetAddressRestrictions(testRestrictedA)); have != 1 {
+		t.Fatalf("restriction count mismatch: have %d, want 1", have)
+	}
+	if err := sc.CheckRestrictions(testRestrictedA, nil, 15, 0); !errors.Is(err, ErrRestrictedSender) {
+		t.Errorf("updated restriction not applied: %v", err)
+	}
+	sc.RemoveRestriction(testRestrictedA, RestrictionAll)
+	if err := sc.CheckRestrictions(testRestrictedA, nil, 15, 0); err != nil {
+		t.Errorf("removed restriction still applied: %v", err)
+	}
+}
+
+// Tests that pool admission evaluates restrictions against the pending block
+// rather than the current head.
+func TestCheckPendingTransaction(t *testing.T) {
+	sc := NewSecurityConfig()
+	sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionAll, ExpiryBlock: 10})
+
+	head := &types.Header{Number: big.NewInt(9), Time: 100}
+	if err := sc.CheckTransaction(testRestrictedA, nil, nil, head.Number.Uint64(), head.Time); !errors.Is(err, ErrRestrictedSender) {
+		t.Fatalf("restriction not applied at head: %v", err)
+	}
+	if err := sc.CheckPendingTransaction(testRestrictedA, nil, nil, head); err != nil {
+		t.Errorf("restriction expiring at the pending block still applied: %v", err)
+	}
+	head.Number = big.NewInt(8)
+	if err := sc.CheckPendingTransaction(testRestrictedA, nil, nil, head); !errors.Is(err, ErrRestrictedSender) {
+		t.Errorf("restriction not applied to the pending block: %v", err)
+	}
+}
+
+func TestRestrictionValidation(t *testing.T) {
+	sc := NewSecurityConfig()
+
+	if err := sc.AddRestriction(&Restriction{Scope: RestrictionAll}); err == nil {
+		t.Error("zero address accepted")
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionContracts}); err == nil {
+		t.Error("contracts restriction without contracts accepted")
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionOutbound, Contracts: []common.Address{testRestrictedB}}); err == nil {
+		t.Error("contracts accepted with non-contracts scope")
+	}
+	if err := sc.AddRestriction(&Restriction{Address: testRestrictedA, Scope: RestrictionScope(100)}); err == nil {
+		t.Error("unknown scope accepted")
+	}
+	for scope, name := range restrictionScopeNames {
+		parsed, err := ParseRestrictionScope(name)
+		if err != nil || parsed != scope {
+			t.Errorf("scope %s round trip failed: have %v, err %v", name, parsed, err)
+		}
+	}
+}
+
+func TestSecurityConfigPersistence(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	sc := NewSecurityConfig()
+	sc.AddToBlacklist(testRestrictedA)
+	sc.AddRestriction(&Restriction{Address: testRestrictedB, Scope: RestrictionContracts, Reason: 7, Contracts: []common.Address{testRestrictedC}, ExpiryBlock: 100})
+	if err := sc.SaveConfig(db); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	loaded, err := LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if !loaded.IsBlacklisted(testRestrictedA) {
+		t.Error("blacklist not restored")
+	}
+	restrictions := loaded.GetRestrictions()
+	if len(restrictions) != 1 {
+		t.Fatalf("restriction count mismatch: have %d, want 1", len(restrictions))
+	}
+	r := restrictions[0]
+	if r.Address != testRestrictedB || r.Scope != RestrictionContracts || r.Reason != 7 || r.ExpiryBlock != 100 || len(r.Contracts) != 1 || r.Contracts[0] != testRestrictedC {
+		t.Errorf("restriction mismatch: %+v", r)
+	}
+}
+
+// Tests that every mutation is written through to the database, so that the
+// policy survives a crash without an explicit save, on all database backends.
+func TestSecurityConfigWriteThrough(t *testing.T) {
+	t.Run("memorydb", func(t *testing.T) {
+		testSecurityConfigWriteThrough(t, func() ethdb.Database { return rawdb.NewMemoryDatabase() })
+	})
+	t.Run("leveldb", func(t *testing.T) {
+		testSecurityConfigWriteThrough(t, func() ethdb.Database {
+			db, err := rawdb.NewLevelDBDatabase(filepath.Join(t.TempDir(), "leveldb"), 16, 16, "", false)
+			if err != nil {
+				t.Fatalf("failed to create leveldb: %v", err)
+			}
+			return db
+		})
+	})
+	t.Run("pebble", func(t *testing.T) {
+		testSecurityConfigWriteThrough(t, func() ethdb.Database {
+			db, err := rawdb.NewPebbleDBDatabase(filepath.Join(t.TempDir(), "pebble"), 16, 16, "", false, true)
+			if err != nil {
+				t.Fatalf("failed to create pebble: %v", err)
+			}
+			return db
+		})
+	})
+}
+
+func testSecurityConfigWriteThrough(t *testing.T, newDB func() ethdb.Database) {
+	db := newDB()
+	defer db.Close()
+
+	// Loading from an empty database must not fail on any backend
+	sc, err := LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to load from empty database: %v", err)
+	}
+	sc.AddToWhitelist(testRestrictedA)
+	sc.AddToBlacklist(testRestrictedB)
+	sc.AddToBlacklist(testRestrictedC)
+	sc.RemoveFromBlacklist(testRestrictedC)
+	sc.FreezeAccount(testRestrictedC)
+	sc.RegisterDeployedContract(testRestrictedA, testRestrictedC)
+	sc.RegisterDeployedContract(testRestrictedA, testRestrictedB)
+	sc.AddRestriction(&Restriction{Address: testRestrictedB, Scope: RestrictionDeploy, Reason: 3})
+	sc.AddRestriction(&Restriction{Address: testRestrictedC, Scope: RestrictionInbound})
+	sc.RemoveRestriction(testRestrictedC, RestrictionInbound)
+
+	// Reload without saving, simulating a crash
+	loaded, err := LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	if !loaded.IsWhitelisted(testRestrictedA) {
+		t.Error("whitelist entry lost")
+	}
+	if !loaded.IsBlacklisted(testRestrictedB) || loaded.IsBlacklisted(testRestrictedC) {
+		t.Error("blacklist mismatch")
+	}
+	if !loaded.IsFrozen(testRestrictedC) {
+		t.Error("frozen entry lost")
+	}
+	contracts := loaded.GetDeployedContracts(testRestrictedA)
+	if len(contracts) != 2 || contracts[0] != testRestrictedC || contracts[1] != testRestrictedB {
+		t.Errorf("deployed contracts mismatch: %v", contracts)
+	}
+	restrictions := loaded.GetRestrictions()
+	if len(restrictions) != 1 || restrictions[0].Address != testRestrictedB || restrictions[0].Scope != RestrictionDeploy {
+		t.Errorf("restrictions mismatch: %v", restrictions)
+	}
+	if version, _ := rawdb.ReadSecuritySchemaVersion(db); version == nil || *version != SecuritySchemaVersion {
+		t.Errorf("schema version mismatch: have %v, want %d", version, SecuritySchemaVersion)
+	}
+}
+
+// Tests that the single-blob policy of schema version 0 is migrated on load.
+func TestSecurityConfigLegacyMigration(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	legacy := securityConfigData{
+		Whitelist: []common.Address{testRestrictedA},
+		Blacklist: []common.Address{testRestrictedB},
+		Contracts: []contractOwnerMapping{{Owner: testRestrictedA, Contracts: []common.Address{testRestrictedC}}},
+	}
+	enc, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatalf("failed to encode legacy config: %v", err)
+	}
+	db.Put([]byte("security-config"), enc)
+
+	sc, err := LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to load legacy config: %v", err)
+	}
+	if !sc.IsWhitelisted(testRestrictedA) || !sc.IsBlacklisted(testRestrictedB) || len(sc.GetDeployedContracts(testRestrictedA)) != 1 {
+		t.Fatal("legacy config not restored")
+	}
+	if data, _ := rawdb.ReadLegacySecurityConfig(db); len(data) != 0 {
+		t.Error("legacy config not removed after migration")
+	}
+	// The migrated config is persisted in the new layout and stays write-through
+	sc.AddToBlacklist(testRestrictedC)
+
+	loaded, err := LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to reload migrated config: %v", err)
+	}
+	if !loaded.IsWhitelisted(testRestrictedA) || !loaded.IsBlacklisted(testRestrictedB) || !loaded.IsBlacklisted(testRestrictedC) {
+		t.Error("migrated config not persisted")
+	}
+}
+
+// Tests that a policy written by a newer schema version is refused.
+func TestSecurityConfigFutureVersion(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	rawdb.WriteSecuritySchemaVersion(db, SecuritySchemaVersion+1)
+
+	if _, err := LoadSecurityConfig(db); err == nil {
+		t.Fatal("loaded config of unsupported schema version")
+	}
+}
+
+func TestSecurityPolicyEvents(t *testing.T) {
+	sc := NewSecurityConfig()
+
+	events := make(chan SecurityPolicyEvent, 16)
+	sub := sc.SubscribePolicyChanges(events)
+	defer sub.Unsubscribe()
+
+	sc.AddToBlacklist(testRestrictedA)
+	sc.RemoveFromBlacklist(testRestrictedB) // not blacklisted, no event
+	sc.RemoveFromBlacklist(testRestrictedA)
+	sc.RegisterDeployedContract(testRestrictedA, testRestrictedC)
+	sc.AddRestriction(&Restriction{Address: testRestrictedB, Scope: RestrictionOutbound, Reason: 9})
+	sc.RemoveRestriction(testRestrictedB, RestrictionOutbound)
+
+	want := []string{
+		PolicyActionAddToBlacklist,
+		PolicyActionRemoveFromBlacklist,
+		PolicyActionRegisterContract,
+		PolicyActionAddRestriction,
+		PolicyActionRemoveRestriction,
+	}
+	for i, action := range want {
+		select {
+		case ev := <-events:
+			if ev.Action != action {
+				t.Fatalf("event %d: action mismatch: have %s, want %s", i, ev.Action, action)
+			}
+			if action == PolicyActionRegisterContract && (ev.Contract == nil || *ev.Contract != testRestrictedC) {
+				t.Errorf("event %d: contract mismatch: %v", i, ev.Contract)
+			}
+			if action == PolicyActionRemoveRestriction && (ev.Restriction == nil || ev.Restriction.Reason != 9) {
+				t.Errorf("event %d: restriction mismatch: %v", i, ev.Restriction)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d: missing %s event", i, action)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Tests that a subscriber not reading its events doesn't block policy changes
+// nor rejection notifications, the events being dropped instead.
+func TestSecurityEventsStalledSubscriber(t *testing.T) {
+	sc := NewSecurityConfig()
+	sub := sc.SubscribeRejections(make(chan SecurityRejectionEvent))
+	defer sub.Unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 4*securityFeedBuffer; i++ {
+			sc.NotifyRejection(common.Hash{}, testRestrictedA, nil, ErrBlacklistedAddress, RejectionPathPool)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notifications blocked on stalled subscriber")
+	}
+}
+
+func TestSecurityCheckTransaction(t *testing.T) {
+	sc := NewSecurityConfig()
+	sc.AddToBlacklist(testRestrictedA)
+	sc.FreezeAccount(testRestrictedB)
+	sc.RegisterDeployedContract(testRestrictedB, testRestrictedC)
+
+	tests := []struct {
+		from common.Address
+		to   *common.Address
+		data []byte
+		want error
+	}{
+		{testRestrictedA, &testRestrictedC, nil, ErrBlacklistedAddress},
+		{testRestrictedB, &testRestrictedA, nil, ErrFrozenAccount},
+		{testRestrictedC, &testRestrictedC, nil, ErrFrozenContract},
+		{testRestrictedC, nil, []byte{0x60}, ErrNotWhitelistedForDeploy},
+		{testRestrictedC, nil, nil, nil},
+		{testRestrictedC, &testRestrictedA, nil, nil},
+	}
+	for i, tt := range tests {
+		if err := sc.CheckTransaction(tt.from, tt.to, tt.data, 0, 0); !errors.Is(err, tt.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+)
+
+// errHistoricState is returned when committing or iterating the tries of a
+// historical state, whose modifications are only kept in memory.
+var errHistoricState = errors.New("not supported by historical state")
+
+// historicDB is a state database serving a historical state of the path scheme
+// which has left the in-memory layers, reconstructed from the state histories.
+// The state can be read and modified in memory, but never committed.
+type historicDB struct {
+	Database
+	reader *pathdb.HistoricalStateReader
+}
+
+// NewHistoricDatabase creates a state database serving the historical state
+// with the given root from the state histories retained by the path scheme.
+// Contract codes are retrieved from the given database.
+func NewHistoricDatabase(db Database, root common.Hash) (Database, error) {
+	reader, err := db.TrieDB().HistoricReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return &historicDB{Database: db, reader: reader}, nil
+}
+
+// OpenTrie opens the account trie of the historical state.
+func (db *historicDB) OpenTrie(root common.Hash) (Trie, error) {
+	if types.TrieRootHash(root) != db.reader.Root() {
+		return nil, errors.New("state is not the historical one")
+	}
+	return &historicTrie{reader: db.reader, root: root}, nil
+}
+
+// OpenStorageTrie opens the storage trie of an account of the historical state.
+func (db *historicDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	return &historicTrie{reader: db.reader, root: root}, nil
+}
+
+// CopyTrie returns an independent copy of the given trie.
+func (db *historicDB) CopyTrie(t Trie) Trie {
+	return t.(*historicTrie).copy()
+}
+
+// historicTrie is a view of a trie of the historical state, either the account
+// trie or the storage trie of an account. The trie can be modified for the
+// state to be executed on, the modifications being kept in memory on top of
+// the historical values. They are not hashed though: the hash of the trie
+// remains the one of the historical state.
+type historicTrie struct {
+	reader   *pathdb.HistoricalStateReader
+	root     common.Hash
+	accounts map[common.Address]*types.StateAccount // Accounts modified in memory, nil if deleted
+	slots    map[common.Hash][]byte                 // Storage slots modified in memory by key hash, nil if deleted
+}
+
+// copy returns an independent copy of the trie, along with its modifications.
+func (t *historicTrie) copy() *historicTrie {
+	cpy := &historicTrie{reader: t.reader, root: t.root}
+	if t.accounts != nil {
+		cpy.accounts = make(map[common.Address]*types.StateAccount, len(t.accounts))
+		for addr, account := range t.accounts {
+			if account != nil {
+				account = account.Copy()
+			}
+			cpy.accounts[addr] = account
+		}
+	}
+	if t.slots != nil {
+		cpy.slots = make(map[common.Hash][]byte, len(t.slots))
+		for key, value := range t.slots {
+			cpy.slots[key] = common.CopyBytes(value)
+		}
+	}
+	return cpy
+}
+
+func (t *historicTrie) GetKey([]byte) []byte { return nil }
+
+func (t *historicTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	if account, ok := t.accounts[address]; ok {
+		if account == nil {
+			return nil, nil
+		}
+		return account.Copy(), nil
+	}
+	return t.reader.Account(address)
+}
+
+func (t *historicTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	hash := crypto.Keccak256Hash(key)
+	if value, ok := t.slots[hash]; ok {
+		return common.CopyBytes(value), nil
+	}
+	return t.reader.Storage(addr, hash)
+}
+
+func (t *historicTrie) UpdateAccount(address common.Address, account *types.StateAccount) error {
+	if t.accounts == nil {
+		t.accounts = make(map[common.Address]*types.StateAccount)
+	}
+	t.accounts[address] = account.Copy()
+	return nil
+}
+
+func (t *historicTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	if t.slots == nil {
+		t.slots = make(map[common.Hash][]byte)
+	}
+	t.slots[crypto.Keccak256Hash(key)] = common.CopyBytes(value)
+	return nil
+}
+
+func (t *historicTrie) DeleteAccount(address common.Address) error {
+	if t.accounts == nil {
+		t.accounts = make(map[common.Address]*types.StateAccount)
+	}
+	t.accounts[address] = nil
+	return nil
+}
+
+func (t *historicTrie) DeleteStorage(addr common.Address, key []byte) error {
+	if t.slots == nil {
+		t.slots = make(map[common.Hash][]byte)
+	}
+	t.slots[crypto.Keccak256Hash(key)] = nil
+	return nil
+}
+
+// UpdateContractCode does nothing, the codes being kept by the state objects
+// until committed.
+func (t *historicTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	return nil
+}
+
+func (t *historicTrie) Hash() common.Hash { return t.root }
+
+func (t *historicTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet, error) {
+	return common.Hash{}, nil, errHistoricState
+}
+
+func (t *historicTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errHistoricState
+}
+
+func (t *historicTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errHistoricState
+}
@@ -77,6 +77,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
 	)
+	tracer, _ := cfg.Tracer.(BlockTracer)
+	if tracer != nil {
+		tracer.OnBlockStart(block)
+	}
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
@@ -87,7 +91,13 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
 		statedb.SetTxContext(tx.Hash(), i)
+		if tracer != nil {
+			tracer.OnTxStart(tx, i, msg.From)
+		}
 		receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
+		if tracer != nil {
+			tracer.OnTxEnd(receipt, err)
+		}
 		if err != nil {
 			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
@@ -172,27 +182,9 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 
 
     // ============= THÊM ĐOẠN KIỂM TRA BẢO MẬT =============
-    // Kiểm tra blacklist
-	if GetSecurityConfig().IsBlacklisted(msg.From) {
-        return nil, ErrBlacklistedAddress
-    }
-    
-    // Kiểm tra frozen accounts
-    if GetSecurityConfig().IsFrozen(msg.From) {
-        return nil, ErrFrozenAccount
-    }
-    
-    // Kiểm tra contract destination nếu là frozen contract
-    if msg.To != nil && GetSecurityConfig().IsContractFrozen(*msg.To) {
-        return nil, ErrFrozenContract
-    }
-    
-    // Kiểm tra whitelist cho contract deployment
-    if msg.To == nil && len(msg.Data) > 0 {
-        // Đây là transaction tạo contract
-        if !GetSecurityConfig().IsWhitelisted(msg.From) {
-            return nil, ErrNotWhitelistedForDeploy
-        }
+    if err := GetSecurityConfig().CheckTransaction(msg.From, msg.To, msg.Data, header.Number.Uint64(), header.Time); err != nil {
+        GetSecurityConfig().NotifyRejection(tx.Hash(), msg.From, msg.To, err, RejectionPathBlock)
+        return nil, err
     }
     // ============= KẾT THÚC KIỂM TRA BẢO MẬT =============
     
@@ -18,6 +18,7 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -422,3 +423,92 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	}
 	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
 }
+
+// TestFeePayerTransaction tests that sponsored transactions charge the gas to
+// the fee payer and the value to the sender.
+func TestFeePayerTransaction(t *testing.T) {
+	var (
+		config = &params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			MuirGlacierBlock:    big.NewInt(0),
+			BerlinBlock:         big.NewInt(0),
+			LondonBlock:         big.NewInt(0),
+			FeePayerBlock:       big.NewInt(0),
+			Ethash:              new(params.EthashConfig),
+		}
+		signer       = types.LatestSigner(config)
+		senderKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		payerKey, _  = crypto.HexToECDSA("0202020202020202020202020202020202020202020202020202002020202020")
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+		payer        = crypto.PubkeyToAddress(payerKey.PublicKey)
+		recipient    = common.HexToAddress("0xdeadbeef")
+		value        = big.NewInt(1000)
+		feeCap       = big.NewInt(params.InitialBaseFee * 2)
+	)
+	gspec := &Genesis{
+		Config: config,
+		Alloc: types.GenesisAlloc{
+			sender: {Balance: value}, // enough for the value, not for any gas
+			payer:  {Balance: big.NewInt(1000000000000000000)},
+		},
+	}
+	mkTx := func(nonce uint64, payerKey *ecdsa.PrivateKey) *types.Transaction {
+		tx, err := types.SignNewTx(senderKey, signer, &types.FeePayerTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(0),
+			GasFeeCap: feeCap,
+			Gas:       params.TxGas,
+			To:        &recipient,
+			Value:     value,
+			FeePayer:  payer,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx, err = types.SignFeePayerTx(tx, signer, payerKey); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		b.AddTx(mkTx(0, payerKey))
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert sponsored block: %v", err)
+	}
+	state, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := state.GetBalance(sender); !have.IsZero() {
+		t.Errorf("sender balance mismatch: have %v, want 0", have)
+	}
+	if have := state.GetBalance(recipient); have.ToBig().Cmp(value) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want %v", have, value)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipts[0][0].GasUsed), receipts[0][0].EffectiveGasPrice)
+	want := new(big.Int).Sub(big.NewInt(1000000000000000000), fee)
+	if have := state.GetBalance(payer); have.ToBig().Cmp(want) != 0 {
+		t.Errorf("fee payer balance mismatch: have %v, want %v", have, want)
+	}
+	// A fee payer signature from the wrong account must invalidate the block
+	bad := GenerateBadBlock(blocks[0], ethash.NewFaker(), types.Transactions{mkTx(1, senderKey)}, config)
+	if _, err := chain.InsertChain(types.Blocks{bad}); !errors.Is(err, types.ErrInvalidFeePayer) {
+		t.Fatalf("wrong error for forged fee payer: have %v, want %v", err, types.ErrInvalidFeePayer)
+	}
+}
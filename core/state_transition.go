@@ -142,6 +142,7 @@ type Message struct {
     AccessList    types.AccessList
     BlobGasFeeCap *big.Int
     BlobHashes    []common.Hash
+    FeePayer      *common.Address // Account charged for the gas instead of the sender, if any
 
     // When SkipAccountChecks is true, the message nonce is not checked against the
     // account nonce in state. It also disables checking that the sender is an EOA.
@@ -171,7 +172,25 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
     }
     var err error
     msg.From, err = types.Sender(s, tx)
-    return msg, err
+    if err != nil {
+        return msg, err
+    }
+    if tx.Type() == types.FeePayerTxType {
+        payer, err := types.FeePayer(s, tx)
+        if err != nil {
+            return msg, err
+        }
+        msg.FeePayer = &payer
+    }
+    return msg, nil
+}
+
+// gasPayer returns the account charged for the gas of the message.
+func (m *Message) gasPayer() common.Address {
+    if m.FeePayer != nil {
+        return *m.FeePayer
+    }
+    return m.From
 }
 
 // ApplyMessage computes the new state by applying the given message
@@ -235,13 +254,19 @@ func (st *StateTransition) to() common.Address {
 }
 
 func (st *StateTransition) buyGas() error {
+    // The gas is charged to the fee payer if the message has one, the value
+    // transfer is always funded by the sender
+    payer := st.msg.gasPayer()
+
     mgval := new(big.Int).SetUint64(st.msg.GasLimit)
     mgval = mgval.Mul(mgval, st.msg.GasPrice)
     balanceCheck := new(big.Int).Set(mgval)
     if st.msg.GasFeeCap != nil {
         balanceCheck.SetUint64(st.msg.GasLimit)
         balanceCheck = balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
-        balanceCheck.Add(balanceCheck, st.msg.Value)
+        if payer == st.msg.From {
+            balanceCheck.Add(balanceCheck, st.msg.Value)
+        }
     }
     if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
         if blobGas := st.blobGasUsed(); blobGas > 0 {
@@ -257,10 +282,10 @@ func (st *StateTransition) buyGas() error {
     }
     balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
     if overflow {
-        return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
+        return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, payer.Hex())
     }
-    if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
-        return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
+    if have, want := st.state.GetBalance(payer), balanceCheckU256; have.Cmp(want) < 0 {
+        return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, payer.Hex(), have, want)
     }
     if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
         return err
@@ -269,7 +294,7 @@ func (st *StateTransition) buyGas() error {
 
     st.initialGas = st.msg.GasLimit
     mgvalU256, _ := uint256.FromBig(mgval)
-    st.state.SubBalance(st.msg.From, mgvalU256)
+    st.state.SubBalance(payer, mgvalU256)
     return nil
 }
 
@@ -314,8 +339,9 @@ func (st *StateTransition) preCheck() error {
                     msg.From.Hex(), msg.GasTipCap, msg.GasFeeCap)
             }
             // This will panic if baseFee is nil, but basefee presence is verified
-            // as part of header validation.
-            if msg.GasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 {
+            // as part of header validation. Fee exempt system accounts may
+            // transact below the base fee.
+            if msg.GasFeeCap.Cmp(st.evm.Context.BaseFee) < 0 && !st.evm.ChainConfig().IsFeeExempt(st.evm.Context.BlockNumber, msg.From) {
                 return fmt.Errorf("%w: address %v, maxFeePerGas: %s, baseFee: %s", ErrFeeCapTooLow,
                     msg.From.Hex(), msg.GasFeeCap, st.evm.Context.BaseFee)
             }
@@ -456,6 +482,10 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
     effectiveTip := msg.GasPrice
     if rules.IsLondon {
         effectiveTip = cmath.BigMin(msg.GasTipCap, new(big.Int).Sub(msg.GasFeeCap, st.evm.Context.BaseFee))
+        if effectiveTip.Sign() < 0 {
+            // Fee exempt transactions below the base fee don't tip the miner
+            effectiveTip = new(big.Int)
+        }
     }
     effectiveTipU256, _ := uint256.FromBig(effectiveTip)
 
@@ -488,7 +518,7 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
     // Return ETH for remaining gas, exchanged at the original rate.
     remaining := uint256.NewInt(st.gasRemaining)
     remaining = remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
-    st.state.AddBalance(st.msg.From, remaining)
+    st.state.AddBalance(st.msg.gasPayer(), remaining)
 
     // Also return remaining gas to the block gas counter so it is
     // available for the next transaction.
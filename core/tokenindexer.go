@@ -0,0 +1,321 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// transferEventTopic is the topic of the ERC-20 and ERC-721 Transfer events.
+	transferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	// transferSingleEventTopic is the topic of the ERC-1155 TransferSingle event.
+	transferSingleEventTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+
+	// transferBatchEventTopic is the topic of the ERC-1155 TransferBatch event.
+	transferBatchEventTopic = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// parseTokenTransfers extracts the token transfers emitted by a log, or nil if
+// the log is not a token transfer event.
+func parseTokenTransfers(l *types.Log) []*rawdb.TokenTransfer {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	transfer := func(standard uint16, from, to common.Hash, id, value *big.Int, batch int) *rawdb.TokenTransfer {
+		return &rawdb.TokenTransfer{
+			Token:       l.Address,
+			Standard:    standard,
+			From:        common.BytesToAddress(from[12:]),
+			To:          common.BytesToAddress(to[12:]),
+			ID:          id,
+			Value:       value,
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+			LogIndex:    uint32(l.Index),
+			BatchIndex:  uint32(batch),
+		}
+	}
+	switch l.Topics[0] {
+	case transferEventTopic:
+		// ERC-20 and ERC-721 share the event, only differing in the token id
+		// being indexed or the amount being in the data.
+		switch {
+		case len(l.Topics) == 3 && len(l.Data) == 32:
+			value := new(big.Int).SetBytes(l.Data)
+			return []*rawdb.TokenTransfer{transfer(rawdb.TokenERC20, l.Topics[1], l.Topics[2], new(big.Int), value, 0)}
+		case len(l.Topics) == 4 && len(l.Data) == 0:
+			id := new(big.Int).SetBytes(l.Topics[3][:])
+			return []*rawdb.TokenTransfer{transfer(rawdb.TokenERC721, l.Topics[1], l.Topics[2], id, big.NewInt(1), 0)}
+		}
+	case transferSingleEventTopic:
+		if len(l.Topics) == 4 && len(l.Data) == 64 {
+			id, value := new(big.Int).SetBytes(l.Data[:32]), new(big.Int).SetBytes(l.Data[32:])
+			return []*rawdb.TokenTransfer{transfer(rawdb.TokenERC1155, l.Topics[2], l.Topics[3], id, value, 0)}
+		}
+	case transferBatchEventTopic:
+		if len(l.Topics) != 4 || len(l.Data) < 64 {
+			return nil
+		}
+		ids, ok := decodeUint256Array(l.Data, l.Data[:32])
+		if !ok {
+			return nil
+		}
+		values, ok := decodeUint256Array(l.Data, l.Data[32:64])
+		if !ok || len(ids) != len(values) {
+			return nil
+		}
+		transfers := make([]*rawdb.TokenTransfer, len(ids))
+		for i := range ids {
+			transfers[i] = transfer(rawdb.TokenERC1155, l.Topics[2], l.Topics[3], ids[i], values[i], i)
+		}
+		return transfers
+	}
+	return nil
+}
+
+// decodeUint256Array decodes an ABI encoded dynamic uint256 array from the data,
+// located at the given offset.
+func decodeUint256Array(data []byte, offset []byte) ([]*big.Int, bool) {
+	start := new(big.Int).SetBytes(offset)
+	if !start.IsUint64() || start.Uint64() > uint64(len(data))-32 {
+		return nil, false
+	}
+	var (
+		pos    = start.Uint64()
+		length = new(big.Int).SetBytes(data[pos : pos+32])
+		avail  = (uint64(len(data)) - pos - 32) / 32
+	)
+	if !length.IsUint64() || length.Uint64() > avail {
+		return nil, false
+	}
+	result := make([]*big.Int, length.Uint64())
+	for i := range result {
+		elem := pos + 32 + uint64(i)*32
+		result[i] = new(big.Int).SetBytes(data[elem : elem+32])
+	}
+	return result, true
+}
+
+// tokenBalanceRef identifies a cached token balance. The id is only set for
+// ERC-1155 tokens, whose ids are tracked separately.
+type tokenBalanceRef struct {
+	token  common.Address
+	holder common.Address
+	id     common.Hash
+	multi  bool
+}
+
+// tokenIndexWriter accumulates the changes to the token transfer index, caching
+// the updated holder balances until they are flushed.
+type tokenIndexWriter struct {
+	db       ethdb.Database
+	batch    ethdb.Batch
+	balances map[tokenBalanceRef]*big.Int
+}
+
+func newTokenIndexWriter(db ethdb.Database) *tokenIndexWriter {
+	return &tokenIndexWriter{
+		db:       db,
+		batch:    db.NewBatch(),
+		balances: make(map[tokenBalanceRef]*big.Int),
+	}
+}
+
+// apply adds the token transfers emitted by the receipts to the index, or
+// removes them if revert is set.
+func (w *tokenIndexWriter) apply(receipts types.Receipts, revert bool) {
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			for _, transfer := range parseTokenTransfers(l) {
+				if revert {
+					rawdb.DeleteTokenTransfer(w.batch, transfer)
+				} else {
+					rawdb.WriteTokenTransfer(w.batch, transfer)
+				}
+				value := transfer.Value
+				if revert {
+					value = new(big.Int).Neg(value)
+				}
+				if transfer.From != (common.Address{}) {
+					balance := w.balance(transfer, transfer.From)
+					balance.Sub(balance, value)
+				}
+				if transfer.To != (common.Address{}) {
+					balance := w.balance(transfer, transfer.To)
+					balance.Add(balance, value)
+				}
+			}
+		}
+	}
+}
+
+// balance returns the cached balance of a holder of the transferred token,
+// loading it from the database if not yet cached.
+func (w *tokenIndexWriter) balance(transfer *rawdb.TokenTransfer, holder common.Address) *big.Int {
+	ref := tokenBalanceRef{token: transfer.Token, holder: holder}
+	if transfer.Standard == rawdb.TokenERC1155 {
+		ref.id, ref.multi = common.BigToHash(transfer.ID), true
+	}
+	balance, ok := w.balances[ref]
+	if !ok {
+		balance = rawdb.ReadTokenBalance(w.db, ref.token, ref.holder, ref.balanceID())
+		w.balances[ref] = balance
+	}
+	return balance
+}
+
+// balanceID returns the token id the balance is keyed by, nil if the token ids
+// are not tracked separately.
+func (ref tokenBalanceRef) balanceID() *big.Int {
+	if !ref.multi {
+		return nil
+	}
+	return ref.id.Big()
+}
+
+// flush writes out the accumulated index changes.
+func (w *tokenIndexWriter) flush() {
+	for ref, balance := range w.balances {
+		rawdb.WriteTokenBalance(w.batch, ref.token, ref.holder, ref.balanceID(), balance)
+	}
+	if err := w.batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	w.batch.Reset()
+	clear(w.balances)
+}
+
+// updateTokenIndex brings the token transfer index in line with the canonical
+// chain ending at the given head, unindexing the blocks which are not canonical
+// anymore. If the stop channel is closed, the update is interrupted. The number
+// of blocks indexed is returned.
+func updateTokenIndex(db ethdb.Database, config *params.ChainConfig, head uint64, stop chan struct{}) (int, error) {
+	var (
+		w    = newTokenIndexWriter(db)
+		next uint64
+	)
+	if hash := rawdb.ReadTokenIndexHead(db); hash != (common.Hash{}) {
+		number := rawdb.ReadHeaderNumber(db, hash)
+		if number == nil {
+			return 0, errors.New("token indexed block not found")
+		}
+		n := *number
+		for n > head || rawdb.ReadCanonicalHash(db, n) != hash {
+			header := rawdb.ReadHeader(db, hash, n)
+			if header == nil {
+				return 0, errors.New("token indexed block not found")
+			}
+			receipts := rawdb.ReadReceipts(db, hash, n, header.Time, config)
+			// Reverting without the receipts would leave the transfers and the
+			// balances of the block in the index, bail out instead.
+			if receipts == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+				w.flush()
+				return 0, fmt.Errorf("missing receipts of token indexed block %d (%x)", n, hash)
+			}
+			w.apply(receipts, true)
+			hash, n = header.ParentHash, n-1
+			rawdb.WriteTokenIndexHead(w.batch, hash)
+		}
+		next = n + 1
+	}
+	var indexed int
+	for n := next; n <= head; n++ {
+		select {
+		case <-stop:
+			w.flush()
+			return indexed, nil
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(db, n)
+		header := rawdb.ReadHeader(db, hash, n)
+		if header == nil {
+			break
+		}
+		receipts := rawdb.ReadReceipts(db, hash, n, header.Time, config)
+		if receipts == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+			log.Warn("Missing receipts for token indexing", "number", n, "hash", hash)
+			break
+		}
+		w.apply(receipts, false)
+		rawdb.WriteTokenIndexHead(w.batch, hash)
+		indexed++
+
+		if w.batch.ValueSize() > ethdb.IdealBatchSize {
+			w.flush()
+		}
+	}
+	w.flush()
+	return indexed, nil
+}
+
+// RebuildTokenIndex drops the token transfer index and indexes the canonical
+// chain from scratch. If the stop channel is closed, the rebuild is interrupted
+// and resumed by the token indexer of the node.
+func RebuildTokenIndex(db ethdb.Database, config *params.ChainConfig, stop chan struct{}) error {
+	return rebuildIndex(db, "token transfer",
+		func() error { return rawdb.DeleteTokenIndex(db) },
+		func(head uint64) (int, error) { return updateTokenIndex(db, config, head, stop) },
+		func() common.Hash { return rawdb.ReadTokenIndexHead(db) },
+	)
+}
+
+// tokenIndexer is the module responsible for maintaining the token transfer
+// index, following the canonical chain from genesis.
+type tokenIndexer struct {
+	*indexScheduler
+	db     ethdb.Database
+	config *params.ChainConfig
+}
+
+// newTokenIndexer initializes the token transfer indexer.
+func newTokenIndexer(chain *BlockChain) *tokenIndexer {
+	indexer := &tokenIndexer{
+		db:     chain.db,
+		config: chain.chainConfig,
+	}
+	indexer.indexScheduler = newIndexScheduler("token", chain, indexer.update)
+
+	log.Info("Initialized token transfer indexer")
+	return indexer
+}
+
+// update brings the token transfer index up to the given head. If the stop
+// channel is closed, the task should be terminated as soon as possible.
+func (indexer *tokenIndexer) update(head uint64, stop chan struct{}) {
+	start := time.Now()
+	indexed, err := updateTokenIndex(indexer.db, indexer.config, head, stop)
+	if err != nil {
+		log.Error("Failed to update token transfer index, rebuild it offline", "err", err)
+		return
+	}
+	if indexed > 0 {
+		log.Debug("Indexed token transfers", "blocks", indexed, "head", head, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that token transfers are extracted from the logs of all supported
+// standards and malformed events are ignored.
+func TestParseTokenTransfers(t *testing.T) {
+	var (
+		token  = common.Address{0x70}
+		from   = common.BytesToHash(common.Address{0x01}.Bytes())
+		to     = common.BytesToHash(common.Address{0x02}.Bytes())
+		word   = func(n int64) []byte { return common.BigToHash(big.NewInt(n)).Bytes() }
+		concat = func(words ...[]byte) []byte {
+			var data []byte
+			for _, w := range words {
+				data = append(data, w...)
+			}
+			return data
+		}
+	)
+	tests := []struct {
+		log  *types.Log
+		want []rawdb.TokenTransfer
+	}{
+		// ERC-20 transfer
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferEventTopic, from, to}, Data: word(100), Index: 3},
+			[]rawdb.TokenTransfer{{Standard: rawdb.TokenERC20, ID: big.NewInt(0), Value: big.NewInt(100), LogIndex: 3}},
+		},
+		// ERC-721 transfer
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferEventTopic, from, to, common.BigToHash(big.NewInt(7))}},
+			[]rawdb.TokenTransfer{{Standard: rawdb.TokenERC721, ID: big.NewInt(7), Value: big.NewInt(1)}},
+		},
+		// ERC-1155 single transfer
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferSingleEventTopic, {0xff}, from, to}, Data: concat(word(7), word(5))},
+			[]rawdb.TokenTransfer{{Standard: rawdb.TokenERC1155, ID: big.NewInt(7), Value: big.NewInt(5)}},
+		},
+		// ERC-1155 batch transfer
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferBatchEventTopic, {0xff}, from, to}, Data: concat(
+				word(64), word(160), word(2), word(7), word(8), word(2), word(5), word(6),
+			)},
+			[]rawdb.TokenTransfer{
+				{Standard: rawdb.TokenERC1155, ID: big.NewInt(7), Value: big.NewInt(5)},
+				{Standard: rawdb.TokenERC1155, ID: big.NewInt(8), Value: big.NewInt(6), BatchIndex: 1},
+			},
+		},
+		// ERC-1155 batch transfer with an out of bounds array
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferBatchEventTopic, {0xff}, from, to}, Data: concat(
+				word(64), word(160), word(2), word(7), word(8), word(3), word(5), word(6),
+			)},
+			nil,
+		},
+		// Transfer event with unexpected data
+		{
+			&types.Log{Address: token, Topics: []common.Hash{transferEventTopic, from, to}, Data: concat(word(1), word(2))},
+			nil,
+		},
+		// Unrelated event
+		{
+			&types.Log{Address: token, Topics: []common.Hash{{0x01}, from, to}, Data: word(100)},
+			nil,
+		},
+	}
+	for i, tt := range tests {
+		have := parseTokenTransfers(tt.log)
+		if len(have) != len(tt.want) {
+			t.Errorf("test %d: transfer count mismatch: have %d, want %d", i, len(have), len(tt.want))
+			continue
+		}
+		for j, want := range tt.want {
+			got := have[j]
+			if got.Token != token || got.From != (common.Address{0x01}) || got.To != (common.Address{0x02}) {
+				t.Errorf("test %d, transfer %d: participants mismatch: %+v", i, j, got)
+			}
+			if got.Standard != want.Standard || got.ID.Cmp(want.ID) != 0 || got.Value.Cmp(want.Value) != 0 ||
+				got.LogIndex != want.LogIndex || got.BatchIndex != want.BatchIndex {
+				t.Errorf("test %d, transfer %d: mismatch: have %+v, want %+v", i, j, got, want)
+			}
+		}
+	}
+}
+
+// Tests that the token transfer index follows the canonical chain through
+// reorgs and can be rebuilt from scratch.
+func TestTokenIndexer(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		// The token mints the amount in the second calldata word to the
+		// address in the first one, emitting an ERC-20 Transfer event.
+		token     = common.HexToAddress("0x70")
+		tokenCode = common.FromHex("6020356000526000356000" + "7f" + transferEventTopic.Hex()[2:] + "60206000a300")
+
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				testBankAddress: {Balance: testBankFunds},
+				token:           {Code: tokenCode},
+			},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer   = types.LatestSigner(gspec.Config)
+		engine   = ethash.NewFaker()
+		original = common.HexToAddress("0xdeadbeef")
+		reorged  = common.HexToAddress("0xcafebabe")
+	)
+	generate := func(n int, recipient common.Address, amount int64) []*types.Block {
+		_, blocks, _ := GenerateChainWithGenesis(gspec, engine, n, func(i int, gen *BlockGen) {
+			input := append(common.BytesToHash(recipient.Bytes()).Bytes(), common.BigToHash(big.NewInt(amount)).Bytes()...)
+			tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+				Nonce:    uint64(i),
+				To:       &token,
+				Gas:      100000,
+				GasPrice: big.NewInt(10 * params.InitialBaseFee),
+				Data:     input,
+			})
+			gen.AddTx(tx)
+		})
+		return blocks
+	}
+	config := *defaultCacheConfig
+	config.TokenIndex = true
+
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, &config, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+
+	insert := func(blocks []*types.Block) {
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("failed to insert chain: %v", err)
+		}
+		for i := 0; i < 100; i++ {
+			if rawdb.ReadTokenIndexHead(db) == chain.CurrentBlock().Hash() {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("token index not caught up with head %d", chain.CurrentBlock().Number)
+	}
+	verify := func(holder common.Address, transfers int, balance int64) {
+		t.Helper()
+		have, _ := rawdb.ReadTokenTransfers(db, token, nil, 100)
+		if len(have) != transfers {
+			t.Fatalf("token transfer count mismatch: have %d, want %d", len(have), transfers)
+		}
+		for i, transfer := range have {
+			if transfer.BlockNumber != uint64(transfers-i) || transfer.To != holder || transfer.Standard != rawdb.TokenERC20 {
+				t.Fatalf("transfer %d mismatch: %+v", i, transfer)
+			}
+		}
+		if have, _ := rawdb.ReadHolderTokenTransfers(db, holder, &token, nil, 100); len(have) != transfers {
+			t.Fatalf("holder transfer count mismatch: have %d, want %d", len(have), transfers)
+		}
+		holders, _ := rawdb.ReadTokenHolders(db, token, nil, 100)
+		if len(holders) != 1 || holders[0].Holder != holder || holders[0].Balance.Int64() != balance {
+			t.Fatalf("token holders mismatch: %+v", holders)
+		}
+	}
+	insert(generate(4, original, 100))
+	verify(original, 4, 400)
+
+	// Reorg to a longer chain and ensure the old transfers are reverted
+	insert(generate(5, reorged, 50))
+	verify(reorged, 5, 250)
+	if have, _ := rawdb.ReadHolderTokenTransfers(db, original, nil, nil, 100); len(have) != 0 {
+		t.Fatalf("reorged transfers retained: %d", len(have))
+	}
+	if balance := rawdb.ReadTokenBalance(db, token, original, nil); balance.Sign() != 0 {
+		t.Fatalf("reorged balance retained: %v", balance)
+	}
+	// Rebuild the index offline and ensure it's the same
+	chain.Stop()
+	if err := RebuildTokenIndex(db, gspec.Config, nil); err != nil {
+		t.Fatalf("failed to rebuild token index: %v", err)
+	}
+	verify(reorged, 5, 250)
+}
+
+// Tests that ERC-1155 balances are tracked per token id, and that transfers are
+// reverted from the balances they were applied to.
+func TestTokenIndexBalances(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		token  = common.Address{0x70}
+		holder = common.Address{0x02}
+		word   = func(n int64) []byte { return common.BigToHash(big.NewInt(n)).Bytes() }
+	)
+	var data []byte
+	for _, n := range []int64{64, 160, 2, 7, 8, 2, 5, 6} {
+		data = append(data, word(n)...)
+	}
+	receipts := types.Receipts{{Logs: []*types.Log{{
+		Address: token,
+		Topics:  []common.Hash{transferBatchEventTopic, {0xff}, {}, common.BytesToHash(holder.Bytes())},
+		Data:    data,
+	}}}}
+	w := newTokenIndexWriter(db)
+	w.apply(receipts, false)
+	w.flush()
+
+	holders, _ := rawdb.ReadTokenHolders(db, token, nil, 100)
+	if len(holders) != 2 {
+		t.Fatalf("token holders mismatch: %+v", holders)
+	}
+	for i, want := range []struct{ id, balance int64 }{{7, 5}, {8, 6}} {
+		if holders[i].Holder != holder || holders[i].ID.Int64() != want.id || holders[i].Balance.Int64() != want.balance {
+			t.Fatalf("holder %d mismatch: %+v", i, holders[i])
+		}
+	}
+	if balance := rawdb.ReadTokenBalance(db, token, holder, big.NewInt(8)); balance.Int64() != 6 {
+		t.Fatalf("token id balance mismatch: have %v, want 6", balance)
+	}
+	w.apply(receipts, true)
+	w.flush()
+	if holders, _ := rawdb.ReadTokenHolders(db, token, nil, 100); len(holders) != 0 {
+		t.Fatalf("reverted holders retained: %+v", holders)
+	}
+}
+
+// Tests that unindexing a reorged block without its receipts fails instead of
+// leaving its transfers in the index.
+func TestTokenIndexMissingReceipts(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	genesis := &types.Header{Number: big.NewInt(0), ReceiptHash: types.EmptyReceiptsHash}
+	rawdb.WriteHeader(db, genesis)
+	rawdb.WriteCanonicalHash(db, genesis.Hash(), 0)
+
+	reorged := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(1), ReceiptHash: common.Hash{0x01}}
+	rawdb.WriteHeader(db, reorged)
+	rawdb.WriteTokenIndexHead(db, reorged.Hash())
+
+	if _, err := updateTokenIndex(db, params.TestChainConfig, 0, nil); err == nil {
+		t.Fatal("expected missing receipts error")
+	}
+	if head := rawdb.ReadTokenIndexHead(db); head != reorged.Hash() {
+		t.Fatalf("token index head changed: have %x, want %x", head, reorged.Hash())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BlockTracer is an EVM logger tracing the blocks imported by the chain as
+// they are processed, when set as the tracer of the chain's VM config. Beside
+// the EVM events, it's notified of the blocks and transactions being processed.
+//
+// Only block imports are traced: the tracer is detached from the VM config
+// used for mining, prefetching and RPC calls. Blocks sealed by the local miner
+// are re-executed for the tracer when they are written to the chain.
+type BlockTracer interface {
+	vm.EVMLogger
+
+	// OnBlockStart is called before the transactions of a block are processed.
+	OnBlockStart(block *types.Block)
+
+	// OnTxStart is called before a transaction of the block being traced is
+	// processed.
+	OnTxStart(tx *types.Transaction, index int, from common.Address)
+
+	// OnTxEnd is called after a transaction has been processed, along with its
+	// receipt or the error it failed with.
+	OnTxEnd(receipt *types.Receipt, err error)
+
+	// OnBlockEnd is called once the block has been imported, or has failed to
+	// be with the given error. The block's traces are final only in the former
+	// case, but the block might still be reorged out later.
+	OnBlockEnd(err error)
+}
+
+// untracedConfig returns a copy of the VM config without the block tracer,
+// for the executions which are not part of a block import.
+func untracedConfig(cfg vm.Config) vm.Config {
+	if _, ok := cfg.Tracer.(BlockTracer); ok {
+		cfg.Tracer = nil
+	}
+	return cfg
+}
+
+// endBlockTrace notifies the block tracer of the chain, if any, that the block
+// being traced has been imported or has failed to be.
+func (bc *BlockChain) endBlockTrace(err error) {
+	if tracer, ok := bc.vmConfig.Tracer.(BlockTracer); ok {
+		tracer.OnBlockEnd(err)
+	}
+}
+
+// traceSealedBlock re-executes a block sealed by the local miner through the
+// block tracer of the chain, if any. Sealed blocks are assembled by the miner
+// and written without going through the state processor, so the tracer would
+// miss them otherwise. It reports whether the block trace has been started and
+// needs to be ended once the block is written.
+func (bc *BlockChain) traceSealedBlock(block *types.Block) bool {
+	if _, ok := bc.vmConfig.Tracer.(BlockTracer); !ok {
+		return false
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		log.Warn("Missing parent of sealed block, not tracing", "number", block.Number(), "hash", block.Hash())
+		return false
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		log.Warn("Missing parent state of sealed block, not tracing", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return false
+	}
+	if _, _, _, err := bc.processor.Process(block, statedb, bc.vmConfig); err != nil {
+		bc.endBlockTrace(err)
+		return false
+	}
+	return true
+}
@@ -314,6 +314,8 @@ type BlobPool struct {
 	discoverFeed event.Feed // Event feed to send out new tx events on pool discovery (reorg excluded)
 	insertFeed   event.Feed // Event feed to send out new tx events on pool inclusion (reorg included)
 
+	lifecycle *txpool.LifecycleTracker // Lifecycle of the recently seen transactions, nil if not tracked
+
 	lock sync.RWMutex // Mutex protecting the pool during reorg handling
 }
 
@@ -331,6 +333,8 @@ func New(config Config, chain BlockChain) *BlobPool {
 		lookup: make(map[common.Hash]uint64),
 		index:  make(map[common.Address][]*blobTxMeta),
 		spent:  make(map[common.Address]*uint256.Int),
+
+		lifecycle: txpool.NewLifecycleTracker(config.Lifecycle),
 	}
 }
 
@@ -435,6 +439,8 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserve txpool.Addres
 
 // Close closes down the underlying persistent store.
 func (p *BlobPool) Close() error {
+	p.lifecycle.Close()
+
 	var errs []error
 	if p.limbo != nil { // Close might be invoked due to error in constructor, before p,limbo is set
 		if err := p.limbo.Close(); err != nil {
@@ -533,6 +539,11 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
 
+			if gapped {
+				p.lifecycle.Record(txs[i].hash, txpool.TxStageEvicted, "nonce gap")
+			} else {
+				p.trackStale(txs[i].hash)
+			}
 			// Included transactions blobs need to be moved to the limbo
 			if filled && inclusions != nil {
 				p.offload(addr, txs[i].nonce, txs[i].id, inclusions)
@@ -573,6 +584,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[0].costCap)
 			p.stored -= uint64(txs[0].size)
 			delete(p.lookup, txs[0].hash)
+			p.trackStale(txs[0].hash)
 
 			// Included transactions blobs need to be moved to the limbo
 			if inclusions != nil {
@@ -629,6 +641,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 			p.stored -= uint64(txs[i].size)
 			delete(p.lookup, txs[i].hash)
+			p.lifecycle.Record(txs[i].hash, txpool.TxStageEvicted, "repeated nonce")
 
 			if err := p.store.Delete(id); err != nil {
 				log.Error("Failed to delete blob transaction", "from", addr, "id", id, "err", err)
@@ -651,6 +664,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[j].costCap)
 			p.stored -= uint64(txs[j].size)
 			delete(p.lookup, txs[j].hash)
+			p.lifecycle.Record(txs[j].hash, txpool.TxStageEvicted, "nonce gap")
 		}
 		txs = txs[:i]
 
@@ -689,6 +703,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.lifecycle.Record(last.hash, txpool.TxStageEvicted, "insufficient balance")
 		}
 		if len(txs) == 0 {
 			delete(p.index, addr)
@@ -729,6 +744,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.size)
 			delete(p.lookup, last.hash)
+			p.lifecycle.Record(last.hash, txpool.TxStageEvicted, "account limit exceeded")
 		}
 		p.index[addr] = txs
 
@@ -909,6 +925,7 @@ func (p *BlobPool) reorg(oldHead, newHead *types.Header) (map[common.Address][]*
 			included[from] = append(included[from], tx)
 			inclusions[tx.Hash()] = add.NumberU64()
 			transactors[from] = struct{}{}
+			p.lifecycle.Included(tx.Hash(), add.Hash(), add.NumberU64())
 		}
 		if add = p.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
 			log.Error("Unrooted new chain seen by blobpool", "block", newHead.Number, "hash", newHead.Hash())
@@ -932,6 +949,7 @@ func (p *BlobPool) reorg(oldHead, newHead *types.Header) (map[common.Address][]*
 			included[from] = append(included[from], tx)
 			inclusions[tx.Hash()] = add.NumberU64()
 			transactors[from] = struct{}{}
+			p.lifecycle.Included(tx.Hash(), add.Hash(), add.NumberU64())
 		}
 		if add = p.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
 			log.Error("Unrooted new chain seen by blobpool", "block", newHead.Number, "hash", newHead.Hash())
@@ -1034,6 +1052,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 					p.stored -= uint64(tx.size)
 					delete(p.lookup, tx.hash)
+					p.lifecycle.Record(tx.hash, txpool.TxStageEvicted, "underpriced")
 					txs[i] = nil
 
 					// Drop everything afterwards, no gaps allowed
@@ -1044,6 +1063,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 						p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], tx.costCap)
 						p.stored -= uint64(tx.size)
 						delete(p.lookup, tx.hash)
+						p.lifecycle.Record(tx.hash, txpool.TxStageEvicted, "underpriced")
 						txs[i+1+j] = nil
 					}
 					// Clear out the dropped transactions from the index
@@ -1209,7 +1229,14 @@ func (p *BlobPool) Add(txs []*types.Transaction, local bool, sync bool) []error
 		adds = make([]*types.Transaction, 0, len(txs))
 		errs = make([]error, len(txs))
 	)
+	source := "remote"
+	if local {
+		source = "local"
+	}
 	for i, tx := range txs {
+		if !p.Has(tx.Hash()) {
+			p.lifecycle.Received(tx.Hash(), source)
+		}
 		errs[i] = p.add(tx)
 		if errs[i] == nil {
 			adds = append(adds, tx.WithoutBlobTxSidecar())
@@ -1237,6 +1264,12 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		addtimeHist.Update(time.Since(start).Nanoseconds())
 	}(time.Now())
 
+	// Record any rejection in the lifecycle of the transaction
+	defer func() {
+		if err != nil && !errors.Is(err, txpool.ErrAlreadyKnown) {
+			p.lifecycle.Record(tx.Hash(), txpool.TxStageRejected, err.Error())
+		}
+	}()
 	// Ensure the transaction is valid from all perspectives
 	if err := p.validateTx(tx); err != nil {
 		log.Trace("Transaction validation failed", "hash", tx.Hash(), "err", err)
@@ -1261,6 +1294,8 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 	// If the address is not yet known, request exclusivity to track the account
 	// only by this subpool until all transactions are evicted
 	from, _ := types.Sender(p.signer, tx) // already validated above
+	p.lifecycle.Record(tx.Hash(), txpool.TxStageValidated, "")
+
 	if _, ok := p.index[from]; !ok {
 		if err := p.reserve(from, true); err != nil {
 			addNonExclusiveMeter.Mark(1)
@@ -1318,6 +1353,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		delete(p.lookup, prev.hash)
 		p.lookup[meta.hash] = meta.id
 		p.stored += uint64(meta.size) - uint64(prev.size)
+		p.lifecycle.Replaced(prev.hash, meta.hash)
 	} else {
 		// Transaction extends previously scheduled ones
 		p.index[from] = append(p.index[from], meta)
@@ -1376,6 +1412,8 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 			heap.Fix(p.evict, p.evict.index[from])
 		}
 	}
+	p.lifecycle.Record(meta.hash, txpool.TxStagePending, "")
+
 	// If the pool went over the allowed data limit, evict transactions until
 	// we're again below the threshold
 	for p.stored > p.config.Datacap {
@@ -1435,6 +1473,7 @@ func (p *BlobPool) drop() {
 	// Remove the transaction from the data store
 	log.Debug("Evicting overflown blob transaction", "from", from, "evicted", drop.nonce, "id", drop.id)
 	dropOverflownMeter.Mark(1)
+	p.lifecycle.Record(drop.hash, txpool.TxStageEvicted, "pool overflow")
 
 	if err := p.store.Delete(drop.id); err != nil {
 		log.Error("Failed to drop evicted transaction", "id", drop.id, "err", err)
@@ -1591,6 +1630,26 @@ func (p *BlobPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool
 	}
 }
 
+// Lifecycle implements txpool.TrackingPool, returning the recorded lifecycle
+// steps of a transaction.
+func (p *BlobPool) Lifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	return p.lifecycle.Lifecycle(hash)
+}
+
+// SubscribeLifecycle implements txpool.TrackingPool, subscribing to the lifecycle
+// steps of all the transactions handled by the pool.
+func (p *BlobPool) SubscribeLifecycle(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return p.lifecycle.Subscribe(ch)
+}
+
+// trackStale records the removal of a transaction whose nonce became too low,
+// unless it was already recorded as included.
+func (p *BlobPool) trackStale(hash common.Hash) {
+	if stage, _ := p.lifecycle.Stage(hash); stage != txpool.TxStageIncluded {
+		p.lifecycle.Record(hash, txpool.TxStageEvicted, "nonce too low")
+	}
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *BlobPool) Nonce(addr common.Address) uint64 {
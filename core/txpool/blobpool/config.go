@@ -25,6 +25,7 @@ type Config struct {
 	Datadir   string // Data directory containing the currently executable blobs
 	Datacap   uint64 // Soft-cap of database storage (hard cap is larger due to overhead)
 	PriceBump uint64 // Minimum price bump percentage to replace an already existing nonce
+	Lifecycle int    // Number of recently seen transactions whose lifecycle is tracked (0 = disabled)
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -32,6 +33,7 @@ var DefaultConfig = Config{
 	Datadir:   "blobpool",
 	Datacap:   10 * 1024 * 1024 * 1024 / 4, // TODO(karalabe): /4 handicap for rollout, gradually bump back up to 10GB
 	PriceBump: 100,                         // either have patience or be aggressive, no mushy ground
+	Lifecycle: 1024,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -46,5 +48,9 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid blobpool price bump", "provided", conf.PriceBump, "updated", DefaultConfig.PriceBump)
 		conf.PriceBump = DefaultConfig.PriceBump
 	}
+	if conf.Lifecycle < 0 {
+		log.Warn("Sanitizing invalid blobpool lifecycle tracking", "provided", conf.Lifecycle, "updated", 0)
+		conf.Lifecycle = 0
+	}
 	return conf
 }
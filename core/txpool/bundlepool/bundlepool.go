@@ -0,0 +1,395 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bundlepool implements the transaction pool for atomic bundles.
+package bundlepool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// txMaxSize is the maximum size a single bundled transaction can have, the
+	// same limit the legacy pool applies to plain transactions.
+	txMaxSize = 128 * 1024
+)
+
+var (
+	// ErrEmptyBundle is returned if a bundle does not contain any transactions.
+	ErrEmptyBundle = errors.New("empty bundle")
+
+	// ErrBundleTooLarge is returned if a bundle contains more transactions than
+	// the pool allows.
+	ErrBundleTooLarge = errors.New("bundle too large")
+
+	// ErrBundleExpired is returned if the last block a bundle targets is already
+	// part of the chain.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrInvalidBlockRange is returned if the target block range of a bundle is
+	// empty or reaches too far into the future.
+	ErrInvalidBlockRange = errors.New("invalid bundle block range")
+
+	// ErrUnknownRevertingTx is returned if a bundle allows a transaction to revert
+	// which is not part of the bundle.
+	ErrUnknownRevertingTx = errors.New("reverting transaction not in bundle")
+
+	// ErrPoolFull is returned if the pool reached its bundle limit.
+	ErrPoolFull = errors.New("bundle pool full")
+)
+
+var bundleGauge = metrics.NewRegisteredGauge("bundlepool/bundles", nil)
+
+// BlockChain defines the minimal set of methods needed to back a bundle pool
+// with a chain. Exists to allow mocking the live chain out of tests.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// BundlePool is the transaction pool dedicated to bundles: ordered lists of
+// transactions that need to be included into a block together or not at all.
+//
+// Bundled transactions are never announced to the network, nor are they exposed
+// to the miner as individual transactions.
+type BundlePool struct {
+	config Config     // Pool configuration
+	chain  BlockChain // Chain object to access the state through
+	signer types.Signer
+
+	head  *types.Header  // Current head of the chain
+	state *state.StateDB // Current state at the head of the chain
+
+	bundles map[common.Hash]*txpool.Bundle     // Bundles tracked by the pool, keyed by bundle hash
+	lookup  map[common.Hash]*types.Transaction // Bundled transactions, keyed by transaction hash
+	refs    map[common.Hash]int                // Number of bundles referencing a transaction
+
+	txFeed event.Feed // Never fed, bundled transactions are not announced
+	lock   sync.RWMutex
+}
+
+// New creates a new bundle pool to gather and order transaction bundles.
+func New(config Config, chain BlockChain) *BundlePool {
+	// Sanitize the input to ensure the pool limits are workable
+	config = (&config).sanitize()
+
+	return &BundlePool{
+		config:  config,
+		chain:   chain,
+		signer:  types.LatestSigner(chain.Config()),
+		bundles: make(map[common.Hash]*txpool.Bundle),
+		lookup:  make(map[common.Hash]*types.Transaction),
+		refs:    make(map[common.Hash]int),
+	}
+}
+
+// Filter returns whether the given transaction can be consumed by the bundle
+// pool. Plain transactions are never accepted, bundles are added via AddBundle.
+func (p *BundlePool) Filter(tx *types.Transaction) bool {
+	return false
+}
+
+// Init sets the chain head the bundles are validated against. The bundle pool
+// does not persist bundles, so there is nothing to load from disk.
+func (p *BundlePool) Init(gasTip uint64, head *types.Header, reserve txpool.AddressReserver) error {
+	// Initialize the state with head block, or fallback to empty one in
+	// case the head state is not available (might occur when node is not
+	// fully synced).
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		statedb, err = p.chain.StateAt(types.EmptyRootHash)
+	}
+	if err != nil {
+		return err
+	}
+	p.head, p.state = head, statedb
+	return nil
+}
+
+// Close terminates the bundle pool.
+func (p *BundlePool) Close() error {
+	return nil
+}
+
+// Reset implements txpool.SubPool, dropping all bundles which can no longer be
+// included on top of the new head: either because their block range passed or
+// because one of their transactions became stale.
+func (p *BundlePool) Reset(oldHead, newHead *types.Header) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	statedb, err := p.chain.StateAt(newHead.Root)
+	if err != nil {
+		log.Error("Failed to reset bundlepool state", "err", err)
+		return
+	}
+	p.head, p.state = newHead, statedb
+
+	next := newHead.Number.Uint64() + 1
+	for hash, bundle := range p.bundles {
+		if bundle.MaxBlock < next {
+			log.Trace("Dropping expired bundle", "hash", hash, "maxblock", bundle.MaxBlock)
+			p.remove(hash)
+			continue
+		}
+		for _, tx := range bundle.Txs {
+			from, _ := types.Sender(p.signer, tx) // already validated
+			if tx.Nonce() < p.state.GetNonce(from) {
+				log.Trace("Dropping stale bundle", "hash", hash, "tx", tx.Hash())
+				p.remove(hash)
+				break
+			}
+		}
+	}
+	bundleGauge.Update(int64(len(p.bundles)))
+}
+
+// SetGasTip implements txpool.SubPool. Bundles are priced by the miner upon
+// inclusion, so the pool does not enforce a minimum tip.
+func (p *BundlePool) SetGasTip(tip *big.Int) {}
+
+// AddBundle validates a bundle against the current head and inserts it into
+// the pool.
+func (p *BundlePool) AddBundle(bundle *txpool.Bundle) error {
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if len(bundle.Txs) > p.config.MaxBundleTxs {
+		return fmt.Errorf("%w: %d transactions, limit %d", ErrBundleTooLarge, len(bundle.Txs), p.config.MaxBundleTxs)
+	}
+	for _, hash := range bundle.RevertingTxs {
+		if !containsTx(bundle.Txs, hash) {
+			return fmt.Errorf("%w: %x", ErrUnknownRevertingTx, hash)
+		}
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// Resolve and check the target block range
+	next := p.head.Number.Uint64() + 1
+	if bundle.MaxBlock == 0 {
+		bundle.MaxBlock = next + p.config.MaxBlockRange - 1
+	}
+	switch {
+	case bundle.MaxBlock < next:
+		return fmt.Errorf("%w: max block %d, next block %d", ErrBundleExpired, bundle.MaxBlock, next)
+	case bundle.MinBlock > bundle.MaxBlock:
+		return fmt.Errorf("%w: min block %d after max block %d", ErrInvalidBlockRange, bundle.MinBlock, bundle.MaxBlock)
+	case bundle.MaxBlock >= next+p.config.MaxBlockRange:
+		return fmt.Errorf("%w: max block %d beyond %d", ErrInvalidBlockRange, bundle.MaxBlock, next+p.config.MaxBlockRange-1)
+	}
+	hash := bundle.Hash()
+	if _, ok := p.bundles[hash]; ok {
+		return txpool.ErrAlreadyKnown
+	}
+	if len(p.bundles) >= p.config.MaxBundles {
+		return ErrPoolFull
+	}
+	// Validate the individual transactions against the head
+	for i, tx := range bundle.Txs {
+		if err := p.validateTx(tx); err != nil {
+			return fmt.Errorf("transaction %d (%x): %w", i, tx.Hash(), err)
+		}
+	}
+	if bundle.Time.IsZero() {
+		bundle.Time = time.Now()
+	}
+	p.bundles[hash] = bundle
+	for _, tx := range bundle.Txs {
+		p.lookup[tx.Hash()] = tx
+		p.refs[tx.Hash()]++
+	}
+	bundleGauge.Update(int64(len(p.bundles)))
+	log.Debug("Added bundle to pool", "hash", hash, "txs", len(bundle.Txs), "minblock", bundle.MinBlock, "maxblock", bundle.MaxBlock)
+	return nil
+}
+
+// validateTx checks whether a bundled transaction is valid on top of the current
+// head. The caller must hold the pool lock.
+func (p *BundlePool) validateTx(tx *types.Transaction) error {
+	opts := &txpool.ValidationOptions{
+		Config: p.chain.Config(),
+		Accept: 0 |
+			1<<types.LegacyTxType |
+			1<<types.AccessListTxType |
+			1<<types.DynamicFeeTxType,
+		MaxSize: txMaxSize,
+		MinTip:  new(big.Int),
+	}
+	if err := txpool.ValidateTransaction(tx, p.head, p.signer, opts); err != nil {
+		return err
+	}
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return txpool.ErrInvalidSender
+	}
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), p.head); err != nil {
+		log.Debug("Rejected bundled transaction by security policy", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathPool)
+		return err
+	}
+	if next := p.state.GetNonce(from); tx.Nonce() < next {
+		return fmt.Errorf("%w: next nonce %v, tx nonce %v", core.ErrNonceTooLow, next, tx.Nonce())
+	}
+	return nil
+}
+
+// remove drops a bundle from the pool. The caller must hold the pool lock.
+func (p *BundlePool) remove(hash common.Hash) {
+	bundle, ok := p.bundles[hash]
+	if !ok {
+		return
+	}
+	delete(p.bundles, hash)
+	for _, tx := range bundle.Txs {
+		txhash := tx.Hash()
+		if p.refs[txhash]--; p.refs[txhash] <= 0 {
+			delete(p.refs, txhash)
+			delete(p.lookup, txhash)
+		}
+	}
+}
+
+// PendingBundles retrieves all bundles that may be included in the block with
+// the given number, ordered by arrival time.
+func (p *BundlePool) PendingBundles(number uint64) []*txpool.Bundle {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var bundles []*txpool.Bundle
+	for _, bundle := range p.bundles {
+		if bundle.Eligible(number) {
+			bundles = append(bundles, bundle)
+		}
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Time.Before(bundles[j].Time)
+	})
+	return bundles
+}
+
+// Has implements txpool.SubPool. Bundled transactions are private order flow, and
+// the pool-wide Has and Get serve the transaction exchange with peers, so they
+// are never reported as known.
+func (p *BundlePool) Has(hash common.Hash) bool {
+	return false
+}
+
+// Get implements txpool.SubPool. Bundled transactions are never handed out, see Has.
+func (p *BundlePool) Get(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+// get returns a bundled transaction if it is contained in the pool, or nil
+// otherwise.
+func (p *BundlePool) get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.lookup[hash]
+}
+
+// Add implements txpool.SubPool. Plain transactions are never routed to the
+// bundle pool, so all of them are rejected.
+func (p *BundlePool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range txs {
+		errs[i] = core.ErrTxTypeNotSupported
+	}
+	return errs
+}
+
+// Pending implements txpool.SubPool. Bundled transactions are not individually
+// executable, the miner retrieves them through PendingBundles instead.
+func (p *BundlePool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
+	return make(map[common.Address][]*txpool.LazyTransaction)
+}
+
+// SubscribeTransactions registers a subscription for new transaction events.
+// Bundled transactions are private, so no events are ever delivered.
+func (p *BundlePool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// Nonce returns the next nonce of an account at the current head. Bundles do
+// not advance the pool nonce of their senders.
+func (p *BundlePool) Nonce(addr common.Address) uint64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.state.GetNonce(addr)
+}
+
+// Stats implements txpool.SubPool. Bundled transactions are not part of the
+// public pending set and are not counted.
+func (p *BundlePool) Stats() (int, int) {
+	return 0, 0
+}
+
+// Content implements txpool.SubPool, bundled transactions are not exposed.
+func (p *BundlePool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return make(map[common.Address][]*types.Transaction), make(map[common.Address][]*types.Transaction)
+}
+
+// ContentFrom implements txpool.SubPool, bundled transactions are not exposed.
+func (p *BundlePool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return []*types.Transaction{}, []*types.Transaction{}
+}
+
+// Locals implements txpool.SubPool, the bundle pool does not track locals.
+func (p *BundlePool) Locals() []common.Address {
+	return []common.Address{}
+}
+
+// Status returns the known status (unknown/pending) of a transaction identified
+// by its hash. Bundled transactions are reported as pending.
+func (p *BundlePool) Status(hash common.Hash) txpool.TxStatus {
+	if p.get(hash) != nil {
+		return txpool.TxStatusPending
+	}
+	return txpool.TxStatusUnknown
+}
+
+// containsTx reports whether the transaction with the given hash is in txs.
+func containsTx(txs types.Transactions, hash common.Hash) bool {
+	for _, tx := range txs {
+		if tx.Hash() == hash {
+			return true
+		}
+	}
+	return false
+}
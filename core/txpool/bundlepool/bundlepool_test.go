@@ -0,0 +1,192 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	config  *params.ChainConfig
+	head    *types.Header
+	statedb *state.StateDB
+}
+
+func newTestBlockChain() *testBlockChain {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return &testBlockChain{
+		config:  params.TestChainConfig,
+		head:    &types.Header{Number: big.NewInt(10), GasLimit: 10_000_000},
+		statedb: statedb,
+	}
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig { return bc.config }
+
+func (bc *testBlockChain) CurrentBlock() *types.Header { return bc.head }
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) { return bc.statedb, nil }
+
+// setHead moves the mock chain to the given block number.
+func (bc *testBlockChain) setHead(number int64) *types.Header {
+	bc.head = &types.Header{Number: big.NewInt(number), GasLimit: bc.head.GasLimit}
+	return bc.head
+}
+
+func newTestPool(t *testing.T, config Config) (*BundlePool, *testBlockChain) {
+	chain := newTestBlockChain()
+	pool := New(config, chain)
+	if err := pool.Init(0, chain.head, nil); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	return pool, chain
+}
+
+func transfer(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	to := common.Address{0xbb}
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+}
+
+func TestAddBundleValidation(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool, chain := newTestPool(t, Config{MaxBundles: 2, MaxBundleTxs: 2, MaxBlockRange: 10})
+	chain.statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 5)
+
+	tests := []struct {
+		bundle *txpool.Bundle
+		err    error
+	}{
+		{&txpool.Bundle{}, ErrEmptyBundle},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key), transfer(6, key), transfer(7, key)}}, ErrBundleTooLarge},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, RevertingTxs: []common.Hash{{0x1}}}, ErrUnknownRevertingTx},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, MaxBlock: 10}, ErrBundleExpired},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, MinBlock: 15, MaxBlock: 14}, ErrInvalidBlockRange},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, MaxBlock: 21}, ErrInvalidBlockRange},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(4, key)}}, core.ErrNonceTooLow},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, MaxBlock: 20}, nil},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key)}, MaxBlock: 20}, txpool.ErrAlreadyKnown},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(5, key), transfer(6, key)}}, nil},
+		{&txpool.Bundle{Txs: types.Transactions{transfer(6, key)}}, ErrPoolFull},
+	}
+	for i, tt := range tests {
+		if err := pool.AddBundle(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Bundles without an explicit range stay eligible for the configured range
+	bundles := pool.PendingBundles(20)
+	if len(bundles) != 2 {
+		t.Fatalf("pending bundle count mismatch: have %d, want 2", len(bundles))
+	}
+	if bundles[1].MaxBlock != 20 {
+		t.Errorf("default max block mismatch: have %d, want 20", bundles[1].MaxBlock)
+	}
+}
+
+func TestBundleLifecycle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool, chain := newTestPool(t, DefaultConfig)
+
+	var (
+		shared = transfer(0, key)
+		first  = &txpool.Bundle{Txs: types.Transactions{shared}, MinBlock: 12, MaxBlock: 13}
+		second = &txpool.Bundle{Txs: types.Transactions{shared, transfer(1, key)}, MaxBlock: 20}
+	)
+	for _, bundle := range []*txpool.Bundle{first, second} {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	// Eligibility follows the target block range
+	if have := len(pool.PendingBundles(11)); have != 1 {
+		t.Errorf("bundles for block 11: have %d, want 1", have)
+	}
+	if have := len(pool.PendingBundles(12)); have != 2 {
+		t.Errorf("bundles for block 12: have %d, want 2", have)
+	}
+	// Bundled transactions are known to the pool, but never pending nor
+	// served to peers
+	if pool.get(shared.Hash()) == nil {
+		t.Error("bundled transaction missing from pool")
+	}
+	if pool.Has(shared.Hash()) || pool.Get(shared.Hash()) != nil {
+		t.Error("bundled transaction exposed through pool lookups")
+	}
+	if pool.Status(shared.Hash()) != txpool.TxStatusPending {
+		t.Error("bundled transaction not reported pending")
+	}
+	if pending := pool.Pending(txpool.PendingFilter{}); len(pending) != 0 {
+		t.Errorf("bundled transactions exposed as pending: %d accounts", len(pending))
+	}
+	if pool.Filter(shared) {
+		t.Error("bundle pool accepts plain transactions")
+	}
+	// Moving past the range of the first bundle drops it, but keeps the shared
+	// transaction around for the second one
+	pool.Reset(chain.head, chain.setHead(13))
+	if have := len(pool.PendingBundles(14)); have != 1 {
+		t.Fatalf("bundles after expiry: have %d, want 1", have)
+	}
+	if pool.get(shared.Hash()) == nil {
+		t.Error("shared transaction dropped with expired bundle")
+	}
+	// Including the shared transaction makes the remaining bundle stale
+	chain.statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 1)
+	pool.Reset(chain.head, chain.setHead(14))
+	if have := len(pool.PendingBundles(15)); have != 0 {
+		t.Fatalf("bundles after inclusion: have %d, want 0", have)
+	}
+	if pool.get(shared.Hash()) != nil {
+		t.Error("stale bundled transaction still tracked")
+	}
+}
+
+func TestBundlePoolSecurityPolicy(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := core.GetSecurityConfig()
+	if err := config.AddToBlacklist(sender); err != nil {
+		t.Fatal(err)
+	}
+	defer config.RemoveFromBlacklist(sender)
+
+	pool, chain := newTestPool(t, DefaultConfig)
+	chain.statedb.SetBalance(sender, uint256.NewInt(params.Ether))
+	if err := pool.AddBundle(&txpool.Bundle{Txs: types.Transactions{transfer(0, key)}}); err == nil {
+		t.Fatal("bundle from blacklisted sender accepted")
+	}
+}
@@ -0,0 +1,586 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package conditionalpool implements the transaction pool for conditional
+// transactions.
+package conditionalpool
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// txMaxSize is the maximum size a single conditional transaction can have,
+	// the same limit the legacy pool applies to plain transactions.
+	txMaxSize = 128 * 1024
+
+	// priceBump is the minimum price bump percentage needed to replace an already
+	// pooled conditional transaction.
+	priceBump = 10
+)
+
+var (
+	// ErrConditionsExpired is returned if the conditions of a transaction can no
+	// longer be met by any future block.
+	ErrConditionsExpired = errors.New("transaction conditions expired")
+
+	// ErrInvalidConditions is returned if the conditions of a transaction can
+	// never be met, or reach further than the pool retains transactions.
+	ErrInvalidConditions = errors.New("invalid transaction conditions")
+
+	// ErrPoolFull is returned if the pool reached its transaction limit.
+	ErrPoolFull = errors.New("conditional pool full")
+)
+
+var (
+	txGauge       = metrics.NewRegisteredGauge("conditionalpool/txs", nil)
+	expiredMeter  = metrics.NewRegisteredMeter("conditionalpool/expired", nil)
+	includedMeter = metrics.NewRegisteredMeter("conditionalpool/included", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back a conditional
+// pool with a chain. Exists to allow mocking the live chain out of tests.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// GetBlock retrieves a specific block, used to track inclusions on resets.
+	GetBlock(hash common.Hash, number uint64) *types.Block
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// conditionalTx is a pooled transaction along with its inclusion conditions.
+type conditionalTx struct {
+	tx         *types.Transaction
+	from       common.Address
+	conditions *txpool.TxConditions // Conditions with the block number max always set
+	time       time.Time
+}
+
+// ConditionalPool is the transaction pool dedicated to conditional transactions,
+// which are kept out of the pending set and only handed to the local miner once
+// the block being built satisfies their conditions. They are never announced to
+// the network, as peers would not know about the conditions.
+//
+// Accounts with conditional transactions are reserved by the pool: as long as
+// any of their transactions is conditional, plain ones are rejected by the other
+// pools.
+type ConditionalPool struct {
+	config  Config     // Pool configuration
+	chain   BlockChain // Chain object to access the state through
+	signer  types.Signer
+	reserve txpool.AddressReserver // Address reserver to ensure exclusivity across subpools
+
+	head  *types.Header  // Current head of the chain
+	state *state.StateDB // Current state at the head of the chain
+
+	index  map[common.Address][]*conditionalTx // Conditional transactions grouped by account, nonce sorted without gaps
+	lookup map[common.Hash]*conditionalTx      // Conditional transactions, keyed by transaction hash
+
+	lifecycle *txpool.LifecycleTracker // Lifecycle of the recently seen transactions, nil if not tracked
+
+	txFeed event.Feed // Never fed, conditional transactions are not announced
+	lock   sync.RWMutex
+}
+
+// New creates a new conditional pool to gather conditional transactions.
+func New(config Config, chain BlockChain) *ConditionalPool {
+	// Sanitize the input to ensure the pool limits are workable
+	config = (&config).sanitize()
+
+	return &ConditionalPool{
+		config:    config,
+		chain:     chain,
+		signer:    types.LatestSigner(chain.Config()),
+		index:     make(map[common.Address][]*conditionalTx),
+		lookup:    make(map[common.Hash]*conditionalTx),
+		lifecycle: txpool.NewLifecycleTracker(config.Lifecycle),
+	}
+}
+
+// Filter returns whether the given transaction can be consumed by the conditional
+// pool. Plain transactions are never accepted, use AddConditional instead.
+func (p *ConditionalPool) Filter(tx *types.Transaction) bool {
+	return false
+}
+
+// Init sets the chain head the transactions are validated against. Conditional
+// transactions are not persisted, so there is nothing to load from disk.
+func (p *ConditionalPool) Init(gasTip uint64, head *types.Header, reserve txpool.AddressReserver) error {
+	// Initialize the state with head block, or fallback to empty one in
+	// case the head state is not available (might occur when node is not
+	// fully synced).
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		statedb, err = p.chain.StateAt(types.EmptyRootHash)
+	}
+	if err != nil {
+		return err
+	}
+	p.head, p.state = head, statedb
+	p.reserve = reserve
+	return nil
+}
+
+// Close terminates the conditional pool, releasing all account reservations.
+func (p *ConditionalPool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for addr := range p.index {
+		p.reserve(addr, false)
+	}
+	p.lifecycle.Close()
+	return nil
+}
+
+// Reset implements txpool.SubPool, dropping all conditional transactions which
+// got included or whose conditions can no longer be met.
+func (p *ConditionalPool) Reset(oldHead, newHead *types.Header) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	statedb, err := p.chain.StateAt(newHead.Root)
+	if err != nil {
+		log.Error("Failed to reset conditionalpool state", "err", err)
+		return
+	}
+	p.head, p.state = newHead, statedb
+
+	for _, block := range p.newBlocks(oldHead, newHead) {
+		for _, tx := range block.Transactions() {
+			p.lifecycle.Included(tx.Hash(), block.Hash(), block.NumberU64())
+		}
+	}
+	for addr, txs := range p.index {
+		// Drop everything included (or otherwise made stale) by the new head
+		nonce := p.state.GetNonce(addr)
+		for len(txs) > 0 && txs[0].tx.Nonce() < nonce {
+			hash := txs[0].tx.Hash()
+			if stage, _ := p.lifecycle.Stage(hash); stage == txpool.TxStageIncluded {
+				log.Trace("Dropping included conditional transaction", "hash", hash, "from", addr)
+				includedMeter.Mark(1)
+			} else {
+				log.Trace("Dropping stale conditional transaction", "hash", hash, "from", addr)
+				p.lifecycle.Record(hash, txpool.TxStageEvicted, "nonce too low")
+			}
+			delete(p.lookup, hash)
+			txs = txs[1:]
+		}
+		// Drop the first expired transaction and all depending on it
+		for i, ctx := range txs {
+			if expired, reason := ctx.conditions.Expired(newHead); expired {
+				for j, dropped := range txs[i:] {
+					log.Debug("Dropping expired conditional transaction", "hash", dropped.tx.Hash(), "from", addr, "reason", reason)
+					if j == 0 {
+						p.lifecycle.Record(dropped.tx.Hash(), txpool.TxStageEvicted, reason)
+					} else {
+						p.lifecycle.Record(dropped.tx.Hash(), txpool.TxStageEvicted, "preceding transaction "+reason)
+					}
+					delete(p.lookup, dropped.tx.Hash())
+				}
+				expiredMeter.Mark(int64(len(txs) - i))
+				txs = txs[:i]
+				break
+			}
+		}
+		p.update(addr, txs)
+	}
+	txGauge.Update(int64(len(p.lookup)))
+}
+
+// newBlocks returns the blocks the chain gained moving from the old head to the
+// new one, walking back to their common ancestor on reorgs the same way the
+// legacy pool does. Reorgs too deep to be walked only yield the new head.
+func (p *ConditionalPool) newBlocks(oldHead, newHead *types.Header) []*types.Block {
+	add := p.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
+	if add == nil {
+		log.Warn("Conditionalpool reset with missing new head", "number", newHead.Number, "hash", newHead.Hash())
+		return nil
+	}
+	if oldHead == nil || oldHead.Hash() == newHead.ParentHash {
+		return []*types.Block{add}
+	}
+	oldNum, newNum := oldHead.Number.Uint64(), newHead.Number.Uint64()
+	if depth := uint64(math.Abs(float64(oldNum) - float64(newNum))); depth > 64 {
+		log.Debug("Skipping deep conditionalpool reorg", "depth", depth)
+		return []*types.Block{add}
+	}
+	rem := p.chain.GetBlock(oldHead.Hash(), oldNum)
+	if rem == nil {
+		// The old head was discarded by a setHead, nothing was added on top
+		log.Debug("Skipping conditionalpool reset caused by setHead", "old", oldHead.Hash(), "oldnum", oldNum, "new", newHead.Hash(), "newnum", newNum)
+		return nil
+	}
+	var blocks []*types.Block
+	for rem.NumberU64() > add.NumberU64() {
+		if rem = p.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
+			log.Error("Unrooted old chain seen by conditionalpool", "block", oldHead.Number, "hash", oldHead.Hash())
+			return blocks
+		}
+	}
+	for add.NumberU64() > rem.NumberU64() {
+		blocks = append(blocks, add)
+		if add = p.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
+			log.Error("Unrooted new chain seen by conditionalpool", "block", newHead.Number, "hash", newHead.Hash())
+			return blocks
+		}
+	}
+	for rem.Hash() != add.Hash() {
+		if rem = p.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
+			log.Error("Unrooted old chain seen by conditionalpool", "block", oldHead.Number, "hash", oldHead.Hash())
+			return blocks
+		}
+		blocks = append(blocks, add)
+		if add = p.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
+			log.Error("Unrooted new chain seen by conditionalpool", "block", newHead.Number, "hash", newHead.Hash())
+			return blocks
+		}
+	}
+	return blocks
+}
+
+// SetGasTip implements txpool.SubPool. Conditional transactions are submitted
+// over the local RPC, so like local transactions they are exempt from the minimum
+// tip.
+func (p *ConditionalPool) SetGasTip(tip *big.Int) {}
+
+// AddConditional validates a conditional transaction against the current head
+// and inserts it into the pool. Without a block number max, the transaction is
+// retained for as long as the pool allows.
+func (p *ConditionalPool) AddConditional(tx *types.Transaction, conditions *txpool.TxConditions) (err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	hash := tx.Hash()
+	if p.lookup[hash] != nil {
+		return txpool.ErrAlreadyKnown
+	}
+	// Record the reception and any rejection in the lifecycle of the transaction
+	p.lifecycle.Received(hash, "local")
+	defer func() {
+		if err != nil {
+			p.lifecycle.Record(hash, txpool.TxStageRejected, err.Error())
+		}
+	}()
+	conditions, err = p.validateConditions(conditions)
+	if err != nil {
+		return err
+	}
+	from, err := p.validateTx(tx)
+	if err != nil {
+		return err
+	}
+	p.lifecycle.Record(hash, txpool.TxStageValidated, "")
+
+	ctx := &conditionalTx{tx: tx, from: from, conditions: conditions, time: time.Now()}
+	txs := p.index[from]
+	if len(txs) > 0 && tx.Nonce() >= txs[0].tx.Nonce() && tx.Nonce() < txs[0].tx.Nonce()+uint64(len(txs)) {
+		// Replacement of an already pooled transaction, ensure it pays more
+		old := txs[tx.Nonce()-txs[0].tx.Nonce()]
+		if !bumped(old.tx, tx) {
+			return txpool.ErrReplaceUnderpriced
+		}
+		delete(p.lookup, old.tx.Hash())
+		txs[tx.Nonce()-txs[0].tx.Nonce()] = ctx
+		p.lookup[hash] = ctx
+		p.lifecycle.Replaced(old.tx.Hash(), hash)
+		p.lifecycle.Record(hash, txpool.TxStageQueued, "awaiting conditions")
+
+		log.Debug("Replaced conditional transaction", "hash", hash, "old", old.tx.Hash(), "from", from, "nonce", tx.Nonce())
+		return nil
+	}
+	if len(p.lookup) >= p.config.MaxTxs {
+		return ErrPoolFull
+	}
+	if len(txs) == 0 {
+		// Accounts are exclusive to a single subpool, claim the sender
+		if err := p.reserve(from, true); err != nil {
+			return err
+		}
+	}
+	p.index[from] = append(txs, ctx)
+	p.lookup[hash] = ctx
+	p.lifecycle.Record(hash, txpool.TxStageQueued, "awaiting conditions")
+	txGauge.Update(int64(len(p.lookup)))
+
+	log.Debug("Added conditional transaction to pool", "hash", hash, "from", from, "nonce", tx.Nonce(), "maxblock", *conditions.BlockNumberMax)
+	return nil
+}
+
+// validateConditions checks whether the conditions of a transaction can still
+// be met by a future block, returning a copy with the block number max resolved.
+// The caller must hold the pool lock.
+func (p *ConditionalPool) validateConditions(conditions *txpool.TxConditions) (*txpool.TxConditions, error) {
+	resolved := new(txpool.TxConditions)
+	if conditions != nil {
+		*resolved = *conditions
+	}
+	next := p.head.Number.Uint64() + 1
+	if resolved.BlockNumberMax == nil {
+		last := next + p.config.MaxBlockRange - 1
+		resolved.BlockNumberMax = &last
+	}
+	switch {
+	case resolved.BlockNumberMin != nil && *resolved.BlockNumberMin > *resolved.BlockNumberMax:
+		return nil, fmt.Errorf("%w: block number min %d after max %d", ErrInvalidConditions, *resolved.BlockNumberMin, *resolved.BlockNumberMax)
+	case resolved.TimestampMin != nil && resolved.TimestampMax != nil && *resolved.TimestampMin > *resolved.TimestampMax:
+		return nil, fmt.Errorf("%w: timestamp min %d after max %d", ErrInvalidConditions, *resolved.TimestampMin, *resolved.TimestampMax)
+	case *resolved.BlockNumberMax >= next+p.config.MaxBlockRange:
+		return nil, fmt.Errorf("%w: block number max %d beyond %d", ErrInvalidConditions, *resolved.BlockNumberMax, next+p.config.MaxBlockRange-1)
+	case resolved.Slots() > p.config.MaxSlots:
+		return nil, fmt.Errorf("%w: %d storage slots, limit %d", ErrInvalidConditions, resolved.Slots(), p.config.MaxSlots)
+	}
+	if expired, reason := resolved.Expired(p.head); expired {
+		return nil, fmt.Errorf("%w: %s", ErrConditionsExpired, reason)
+	}
+	return resolved, nil
+}
+
+// validateTx checks whether a conditional transaction is valid on top of the
+// current head and the already pooled transactions of its sender. The caller
+// must hold the pool lock.
+func (p *ConditionalPool) validateTx(tx *types.Transaction) (common.Address, error) {
+	opts := &txpool.ValidationOptions{
+		Config: p.chain.Config(),
+		Accept: 0 |
+			1<<types.LegacyTxType |
+			1<<types.AccessListTxType |
+			1<<types.DynamicFeeTxType,
+		MaxSize: txMaxSize,
+		MinTip:  new(big.Int),
+	}
+	if err := txpool.ValidateTransaction(tx, p.head, p.signer, opts); err != nil {
+		return common.Address{}, err
+	}
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return common.Address{}, txpool.ErrInvalidSender
+	}
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), p.head); err != nil {
+		log.Debug("Rejected conditional transaction by security policy", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathPool)
+		return common.Address{}, err
+	}
+	stateOpts := &txpool.ValidationOptionsWithState{
+		State: p.state,
+		FirstNonceGap: func(addr common.Address) uint64 {
+			if txs := p.index[addr]; len(txs) > 0 {
+				return txs[0].tx.Nonce() + uint64(len(txs))
+			}
+			return p.state.GetNonce(addr)
+		},
+		UsedAndLeftSlots: func(addr common.Address) (int, int) {
+			used := len(p.index[addr])
+			return used, p.config.AccountSlots - used
+		},
+		ExistingExpenditure: func(addr common.Address) *big.Int {
+			spent := new(big.Int)
+			for _, ctx := range p.index[addr] {
+				spent.Add(spent, ctx.tx.Cost())
+			}
+			return spent
+		},
+		ExistingCost: func(addr common.Address, nonce uint64) *big.Int {
+			for _, ctx := range p.index[addr] {
+				if ctx.tx.Nonce() == nonce {
+					return ctx.tx.Cost()
+				}
+			}
+			return nil
+		},
+	}
+	if err := txpool.ValidateTransactionWithState(tx, p.signer, stateOpts); err != nil {
+		return common.Address{}, err
+	}
+	return from, nil
+}
+
+// bumped reports whether the replacement transaction pays enough more than the
+// pooled one to replace it.
+func bumped(old, tx *types.Transaction) bool {
+	var (
+		oldFeeCap = new(big.Int).Mul(old.GasFeeCap(), big.NewInt(100+priceBump))
+		oldTip    = new(big.Int).Mul(old.GasTipCap(), big.NewInt(100+priceBump))
+		feeCap    = new(big.Int).Mul(tx.GasFeeCap(), big.NewInt(100))
+		tip       = new(big.Int).Mul(tx.GasTipCap(), big.NewInt(100))
+	)
+	return feeCap.Cmp(oldFeeCap) >= 0 && tip.Cmp(oldTip) >= 0
+}
+
+// update stores the remaining conditional transactions of an account, releasing
+// the account reservation if none are left. The caller must hold the pool lock.
+func (p *ConditionalPool) update(addr common.Address, txs []*conditionalTx) {
+	if len(txs) > 0 {
+		p.index[addr] = txs
+		return
+	}
+	delete(p.index, addr)
+	if err := p.reserve(addr, false); err != nil {
+		log.Error("Failed to release conditional account reservation", "address", addr, "err", err)
+	}
+}
+
+// PendingConditional retrieves all conditional transactions which may be
+// included in a block with the given header. The transactions of an account
+// are returned in nonce order up to the first one the block does not satisfy,
+// the accounts are ordered by the arrival of their first transaction.
+func (p *ConditionalPool) PendingConditional(header *types.Header) []*txpool.ConditionalTx {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var accounts [][]*conditionalTx
+	for _, txs := range p.index {
+		var eligible int
+		for eligible < len(txs) && txs[eligible].conditions.CheckBlock(header) == nil {
+			eligible++
+		}
+		if eligible > 0 {
+			accounts = append(accounts, txs[:eligible])
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i][0].time.Before(accounts[j][0].time)
+	})
+	var pending []*txpool.ConditionalTx
+	for _, txs := range accounts {
+		for _, ctx := range txs {
+			pending = append(pending, &txpool.ConditionalTx{Tx: ctx.tx, Conditions: ctx.conditions, Time: ctx.time})
+		}
+	}
+	return pending
+}
+
+// Has implements txpool.SubPool. Conditional transactions are private order flow, and
+// the pool-wide Has and Get serve the transaction exchange with peers, so they
+// are never reported as known.
+func (p *ConditionalPool) Has(hash common.Hash) bool {
+	return false
+}
+
+// Get implements txpool.SubPool. Conditional transactions are never handed out, see Has.
+func (p *ConditionalPool) Get(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+// get returns a conditional transaction if it is contained in the pool, or nil
+// otherwise.
+func (p *ConditionalPool) get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if ctx := p.lookup[hash]; ctx != nil {
+		return ctx.tx
+	}
+	return nil
+}
+
+// Add implements txpool.SubPool. Plain transactions are never routed to the
+// conditional pool, so all of them are rejected.
+func (p *ConditionalPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range txs {
+		errs[i] = core.ErrTxTypeNotSupported
+	}
+	return errs
+}
+
+// Pending implements txpool.SubPool. Conditional transactions are not executable
+// on any block, the miner retrieves them through PendingConditional instead.
+func (p *ConditionalPool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
+	return make(map[common.Address][]*txpool.LazyTransaction)
+}
+
+// SubscribeTransactions registers a subscription for new transaction events.
+// Conditional transactions are not announced, so no events are ever delivered.
+func (p *ConditionalPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// Lifecycle implements txpool.TrackingPool, returning the recorded lifecycle
+// steps of a transaction.
+func (p *ConditionalPool) Lifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	return p.lifecycle.Lifecycle(hash)
+}
+
+// SubscribeLifecycle implements txpool.TrackingPool, subscribing to the lifecycle
+// steps of all the transactions handled by the pool.
+func (p *ConditionalPool) SubscribeLifecycle(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return p.lifecycle.Subscribe(ch)
+}
+
+// Nonce returns the next nonce of an account, with all conditional transactions
+// of the account applied on top of the current head.
+func (p *ConditionalPool) Nonce(addr common.Address) uint64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if txs := p.index[addr]; len(txs) > 0 {
+		return txs[0].tx.Nonce() + uint64(len(txs))
+	}
+	return p.state.GetNonce(addr)
+}
+
+// Stats implements txpool.SubPool. Conditional transactions are held outside
+// of the pending and queued sets and are not counted.
+func (p *ConditionalPool) Stats() (int, int) {
+	return 0, 0
+}
+
+// Content implements txpool.SubPool, conditional transactions are not exposed.
+func (p *ConditionalPool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return make(map[common.Address][]*types.Transaction), make(map[common.Address][]*types.Transaction)
+}
+
+// ContentFrom implements txpool.SubPool, conditional transactions are not exposed.
+func (p *ConditionalPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return []*types.Transaction{}, []*types.Transaction{}
+}
+
+// Locals implements txpool.SubPool, the conditional pool does not track locals.
+func (p *ConditionalPool) Locals() []common.Address {
+	return []common.Address{}
+}
+
+// Status returns the known status (unknown/queued) of a transaction identified
+// by its hash. Conditional transactions are reported as queued until included.
+func (p *ConditionalPool) Status(hash common.Hash) txpool.TxStatus {
+	if p.get(hash) != nil {
+		return txpool.TxStatusQueued
+	}
+	return txpool.TxStatusUnknown
+}
@@ -0,0 +1,302 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package conditionalpool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	config  *params.ChainConfig
+	head    *types.Header
+	blocks  map[common.Hash]*types.Block
+	statedb *state.StateDB
+}
+
+func newTestBlockChain() *testBlockChain {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	head := &types.Header{Number: big.NewInt(10), Time: 100, GasLimit: 10_000_000}
+	return &testBlockChain{
+		config:  params.TestChainConfig,
+		head:    head,
+		blocks:  map[common.Hash]*types.Block{head.Hash(): types.NewBlockWithHeader(head)},
+		statedb: statedb,
+	}
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig { return bc.config }
+
+func (bc *testBlockChain) CurrentBlock() *types.Header { return bc.head }
+
+func (bc *testBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.blocks[hash]
+}
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) { return bc.statedb, nil }
+
+// setHead moves the mock chain to a block with the given number, timestamp and
+// transactions on top of the current head.
+func (bc *testBlockChain) setHead(number int64, time uint64, txs ...*types.Transaction) *types.Header {
+	header := &types.Header{ParentHash: bc.head.Hash(), Number: big.NewInt(number), Time: time, GasLimit: bc.head.GasLimit}
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+
+	bc.head = block.Header()
+	bc.blocks[bc.head.Hash()] = block
+	return bc.head
+}
+
+// testReserver is an address reserver tracking the accounts claimed by the pool.
+type testReserver struct {
+	owned map[common.Address]bool
+}
+
+func (r *testReserver) reserve(addr common.Address, reserve bool) error {
+	if reserve {
+		r.owned[addr] = true
+	} else {
+		delete(r.owned, addr)
+	}
+	return nil
+}
+
+func newTestPool(t *testing.T, config Config) (*ConditionalPool, *testBlockChain, *testReserver) {
+	var (
+		chain    = newTestBlockChain()
+		pool     = New(config, chain)
+		reserver = &testReserver{owned: make(map[common.Address]bool)}
+	)
+	if err := pool.Init(0, chain.head, reserver.reserve); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return pool, chain, reserver
+}
+
+func newAccount(chain *testBlockChain) (*ecdsa.PrivateKey, common.Address) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chain.statedb.SetBalance(addr, uint256.NewInt(params.Ether))
+	return key, addr
+}
+
+func transfer(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	to := common.Address{0xbb}
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+}
+
+func u64(n uint64) *uint64 { return &n }
+
+func TestAddConditionalValidation(t *testing.T) {
+	pool, chain, reserver := newTestPool(t, Config{MaxTxs: 4, AccountSlots: 4, MaxBlockRange: 10, MaxSlots: 1})
+
+	key, addr := newAccount(chain)
+	tests := []struct {
+		tx         *types.Transaction
+		conditions *txpool.TxConditions
+		err        error
+	}{
+		{transfer(0, key), &txpool.TxConditions{BlockNumberMax: u64(10)}, ErrConditionsExpired},
+		{transfer(0, key), &txpool.TxConditions{TimestampMax: u64(100)}, ErrConditionsExpired},
+		{transfer(0, key), &txpool.TxConditions{BlockNumberMax: u64(21)}, ErrInvalidConditions},
+		{transfer(0, key), &txpool.TxConditions{BlockNumberMin: u64(15), BlockNumberMax: u64(14)}, ErrInvalidConditions},
+		{transfer(0, key), &txpool.TxConditions{TimestampMin: u64(200), TimestampMax: u64(150)}, ErrInvalidConditions},
+		{transfer(0, key), &txpool.TxConditions{KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			{0x01}: {{0x01}: {}, {0x02}: {}},
+		}}, ErrInvalidConditions},
+		{transfer(1, key), nil, core.ErrNonceTooHigh},
+		{transfer(0, key), nil, nil},
+		{transfer(0, key), nil, txpool.ErrAlreadyKnown},
+		{transfer(1, key), &txpool.TxConditions{BlockNumberMin: u64(15), BlockNumberMax: u64(20)}, nil},
+	}
+	for i, tt := range tests {
+		if err := pool.AddConditional(tt.tx, tt.conditions); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if !reserver.owned[addr] {
+		t.Error("sender of conditional transactions not reserved")
+	}
+	if nonce := pool.Nonce(addr); nonce != 2 {
+		t.Errorf("pool nonce mismatch: have %d, want 2", nonce)
+	}
+	// Conditional transactions are kept out of the pending set and pool stats
+	if pending := pool.Pending(txpool.PendingFilter{}); len(pending) != 0 {
+		t.Errorf("conditional transactions exposed as pending: %v", pending)
+	}
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Errorf("conditional transactions counted: pending %d, queued %d", pending, queued)
+	}
+	if status := pool.Status(transfer(0, key).Hash()); status != txpool.TxStatusQueued {
+		t.Errorf("status mismatch: have %v, want %v", status, txpool.TxStatusQueued)
+	}
+	if hash := transfer(0, key).Hash(); pool.Has(hash) || pool.Get(hash) != nil {
+		t.Error("conditional transaction exposed through pool lookups")
+	}
+}
+
+func TestPendingConditional(t *testing.T) {
+	pool, chain, _ := newTestPool(t, DefaultConfig)
+
+	var (
+		key, _  = newAccount(chain)
+		key2, _ = newAccount(chain)
+	)
+	adds := []struct {
+		tx         *types.Transaction
+		conditions *txpool.TxConditions
+	}{
+		{transfer(0, key), nil},
+		{transfer(1, key), &txpool.TxConditions{BlockNumberMin: u64(13)}},
+		{transfer(2, key), nil},
+		{transfer(0, key2), &txpool.TxConditions{TimestampMin: u64(150)}},
+	}
+	for _, add := range adds {
+		if err := pool.AddConditional(add.tx, add.conditions); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	tests := []struct {
+		number uint64
+		time   uint64
+		want   []*types.Transaction
+	}{
+		// Transactions past a not yet satisfied one are not eligible either
+		{11, 110, []*types.Transaction{adds[0].tx}},
+		{13, 110, []*types.Transaction{adds[0].tx, adds[1].tx, adds[2].tx}},
+		{11, 150, []*types.Transaction{adds[0].tx, adds[3].tx}},
+	}
+	for i, tt := range tests {
+		header := &types.Header{Number: new(big.Int).SetUint64(tt.number), Time: tt.time}
+		pending := pool.PendingConditional(header)
+		if len(pending) != len(tt.want) {
+			t.Errorf("test %d: pending count mismatch: have %d, want %d", i, len(pending), len(tt.want))
+			continue
+		}
+		for j, ctx := range pending {
+			if ctx.Tx.Hash() != tt.want[j].Hash() {
+				t.Errorf("test %d: pending transaction %d mismatch: have %x, want %x", i, j, ctx.Tx.Hash(), tt.want[j].Hash())
+			}
+		}
+	}
+}
+
+// Tests that transactions included in any of the blocks between the old and the
+// new head are recognized as included, not only the ones in the new head.
+func TestConditionalTxInclusion(t *testing.T) {
+	pool, chain, _ := newTestPool(t, DefaultConfig)
+
+	key, addr := newAccount(chain)
+	var (
+		first  = transfer(0, key)
+		second = transfer(1, key)
+	)
+	if err := pool.AddConditional(first, nil); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.AddConditional(second, nil); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// Include the transactions in two consecutive blocks, but only notify the
+	// pool of the second one
+	oldHead := chain.head
+	chain.setHead(11, 110, first)
+	chain.setHead(12, 120, second)
+	chain.statedb.SetNonce(addr, 2)
+	pool.Reset(oldHead, chain.head)
+
+	for i, tx := range []*types.Transaction{first, second} {
+		if pool.get(tx.Hash()) != nil {
+			t.Errorf("transaction %d not dropped", i)
+		}
+		steps := pool.Lifecycle(tx.Hash())
+		if len(steps) == 0 {
+			t.Errorf("transaction %d: lifecycle not tracked", i)
+			continue
+		}
+		if last := steps[len(steps)-1]; last.Stage != txpool.TxStageIncluded || last.BlockNumber != uint64(11+i) {
+			t.Errorf("transaction %d: last step mismatch: have %v in #%d, want %v in #%d", i, last.Stage, last.BlockNumber, txpool.TxStageIncluded, 11+i)
+		}
+	}
+}
+
+func TestConditionalTxExpiry(t *testing.T) {
+	pool, chain, reserver := newTestPool(t, DefaultConfig)
+
+	key, addr := newAccount(chain)
+	var (
+		first  = transfer(0, key)
+		second = transfer(1, key)
+		third  = transfer(2, key)
+	)
+	if err := pool.AddConditional(first, nil); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.AddConditional(second, &txpool.TxConditions{TimestampMax: u64(120)}); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.AddConditional(third, nil); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// Moving past the first one's nonce drops it, moving past the deadline of
+	// the second one drops it along with all depending on it
+	chain.statedb.SetNonce(addr, 1)
+	pool.Reset(chain.head, chain.setHead(11, 120))
+
+	for _, tx := range []*types.Transaction{first, second, third} {
+		if pool.get(tx.Hash()) != nil {
+			t.Errorf("transaction %x not dropped", tx.Hash())
+		}
+	}
+	if reserver.owned[addr] {
+		t.Error("reservation not released after expiry")
+	}
+	// The reasons of the drops are retained in the lifecycle of the transactions
+	wants := map[common.Hash]string{
+		first.Hash():  "nonce too low",
+		second.Hash(): "expired: timestamp max 120 reached",
+		third.Hash():  "preceding transaction expired: timestamp max 120 reached",
+	}
+	for hash, want := range wants {
+		steps := pool.Lifecycle(hash)
+		if len(steps) == 0 {
+			t.Errorf("transaction %x: lifecycle not tracked", hash)
+			continue
+		}
+		if last := steps[len(steps)-1]; last.Stage != txpool.TxStageEvicted || last.Reason != want {
+			t.Errorf("transaction %x: last step mismatch: have %v %q, want %v %q", hash, last.Stage, last.Reason, txpool.TxStageEvicted, want)
+		}
+	}
+}
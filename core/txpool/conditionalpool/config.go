@@ -0,0 +1,66 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package conditionalpool
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config are the configuration parameters of the conditional transaction pool.
+type Config struct {
+	MaxTxs        int    // Maximum number of conditional transactions tracked by the pool
+	AccountSlots  int    // Maximum number of conditional transactions per account
+	MaxBlockRange uint64 // Maximum number of blocks a conditional transaction stays in the pool
+	MaxSlots      int    // Maximum number of storage slots the conditions of a transaction may check
+	Lifecycle     int    // Number of recently seen transactions whose lifecycle is tracked (0 = disabled)
+}
+
+// DefaultConfig contains the default configurations for the conditional pool.
+var DefaultConfig = Config{
+	MaxTxs:        1024,
+	AccountSlots:  16,
+	MaxBlockRange: 7200,
+	MaxSlots:      64,
+	Lifecycle:     1024,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.MaxTxs < 1 {
+		log.Warn("Sanitizing invalid conditionalpool transaction limit", "provided", conf.MaxTxs, "updated", DefaultConfig.MaxTxs)
+		conf.MaxTxs = DefaultConfig.MaxTxs
+	}
+	if conf.AccountSlots < 1 {
+		log.Warn("Sanitizing invalid conditionalpool account slots", "provided", conf.AccountSlots, "updated", DefaultConfig.AccountSlots)
+		conf.AccountSlots = DefaultConfig.AccountSlots
+	}
+	if conf.MaxBlockRange < 1 {
+		log.Warn("Sanitizing invalid conditionalpool block range", "provided", conf.MaxBlockRange, "updated", DefaultConfig.MaxBlockRange)
+		conf.MaxBlockRange = DefaultConfig.MaxBlockRange
+	}
+	if conf.MaxSlots < 0 {
+		log.Warn("Sanitizing invalid conditionalpool slot limit", "provided", conf.MaxSlots, "updated", DefaultConfig.MaxSlots)
+		conf.MaxSlots = DefaultConfig.MaxSlots
+	}
+	if conf.Lifecycle < 0 {
+		log.Warn("Sanitizing invalid conditionalpool lifecycle tracking", "provided", conf.Lifecycle, "updated", 0)
+		conf.Lifecycle = 0
+	}
+	return conf
+}
@@ -26,6 +26,10 @@ var (
 	// ErrInvalidSender is returned if the transaction contains an invalid signature.
 	ErrInvalidSender = errors.New("invalid sender")
 
+	// ErrInvalidFeePayer is returned if a sponsored transaction contains an
+	// invalid or mismatching fee payer signature.
+	ErrInvalidFeePayer = errors.New("invalid fee payer")
+
 	// ErrUnderpriced is returned if a transaction's gas price is below the minimum
 	// configured for the transaction pool.
 	ErrUnderpriced = errors.New("transaction underpriced")
@@ -60,4 +64,20 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrBundlesUnsupported is returned if a bundle is submitted to a pool that
+	// has no subpool accepting bundles.
+	ErrBundlesUnsupported = errors.New("transaction bundles not supported")
+
+	// ErrPrivateTxsUnsupported is returned if a private transaction is submitted
+	// to a pool that has no subpool accepting private transactions.
+	ErrPrivateTxsUnsupported = errors.New("private transactions not supported")
+
+	// ErrConditionalTxsUnsupported is returned if a conditional transaction is
+	// submitted to a pool that has no subpool accepting conditional transactions.
+	ErrConditionalTxsUnsupported = errors.New("conditional transactions not supported")
+
+	// ErrConditionsNotMet is returned if the block or state a conditional
+	// transaction is checked against does not satisfy its conditions.
+	ErrConditionsNotMet = errors.New("transaction conditions not met")
 )
@@ -44,17 +44,31 @@ func (*devNull) Close() error                      { return nil }
 // journal is a rotating log of transactions with the aim of storing locally
 // created transactions to allow non-executed ones to survive node restarts.
 type journal struct {
+	name   string         // Kind of transactions journaled, used for logging
 	path   string         // Filesystem path to store the transactions at
+	limit  uint64         // Maximum size of a regenerated journal in bytes (0 = unlimited)
 	writer io.WriteCloser // Output stream to write new transactions into
 }
 
 // newTxJournal creates a new transaction journal to
 func newTxJournal(path string) *journal {
 	return &journal{
+		name: "local",
 		path: path,
 	}
 }
 
+// newRemoteTxJournal creates a new journal for the remote transactions of the
+// pool. It is only ever regenerated, never appended to, and capped to the given
+// size in bytes.
+func newRemoteTxJournal(path string, limit uint64) *journal {
+	return &journal{
+		name:  "remote",
+		path:  path,
+		limit: limit,
+	}
+}
+
 // load parses a transaction journal dump from disk, loading its contents into
 // the specified pool.
 func (journal *journal) load(add func([]*types.Transaction) []error) error {
@@ -112,7 +126,7 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 			batch = batch[:0]
 		}
 	}
-	log.Info("Loaded local transaction journal", "transactions", total, "dropped", dropped)
+	log.Info("Loaded "+journal.name+" transaction journal", "transactions", total, "dropped", dropped)
 
 	return failure
 }
@@ -129,8 +143,9 @@ func (journal *journal) insert(tx *types.Transaction) error {
 }
 
 // rotate regenerates the transaction journal based on the current contents of
-// the transaction pool.
-func (journal *journal) rotate(all map[common.Address]types.Transactions) error {
+// the transaction pool. If the journal is size capped, the transaction sets are
+// written in the order given until the limit is reached, the rest is discarded.
+func (journal *journal) rotate(sets ...map[common.Address]types.Transactions) error {
 	// Close the current journal (if any is open)
 	if journal.writer != nil {
 		if err := journal.writer.Close(); err != nil {
@@ -143,15 +158,34 @@ func (journal *journal) rotate(all map[common.Address]types.Transactions) error
 	if err != nil {
 		return err
 	}
-	journaled := 0
-	for _, txs := range all {
-		for _, tx := range txs {
-			if err = rlp.Encode(replacement, tx); err != nil {
-				replacement.Close()
-				return err
+	var (
+		journaled, skipped int
+		accounts           = make(map[common.Address]struct{})
+		size               uint64
+	)
+	for _, all := range sets {
+		for addr, txs := range all {
+			for i, tx := range txs {
+				blob, err := rlp.EncodeToBytes(tx)
+				if err != nil {
+					replacement.Close()
+					return err
+				}
+				// Drop the rest of the account if the journal is full, a nonce
+				// gap would only make the subsequent transactions non-executable
+				if journal.limit > 0 && size+uint64(len(blob)) > journal.limit {
+					skipped += len(txs) - i
+					break
+				}
+				if _, err = replacement.Write(blob); err != nil {
+					replacement.Close()
+					return err
+				}
+				size += uint64(len(blob))
+				journaled++
+				accounts[addr] = struct{}{}
 			}
 		}
-		journaled += len(txs)
 	}
 	replacement.Close()
 
@@ -166,10 +200,14 @@ func (journal *journal) rotate(all map[common.Address]types.Transactions) error
 	journal.writer = sink
 
 	logger := log.Info
-	if len(all) == 0 {
+	if len(accounts) == 0 {
 		logger = log.Debug
 	}
-	logger("Regenerated local transaction journal", "transactions", journaled, "accounts", len(all))
+	if skipped > 0 {
+		logger("Regenerated "+journal.name+" transaction journal", "transactions", journaled, "accounts", len(accounts), "skipped", skipped, "size", common.StorageSize(size))
+	} else {
+		logger("Regenerated "+journal.name+" transaction journal", "transactions", journaled, "accounts", len(accounts))
+	}
 
 	return nil
 }
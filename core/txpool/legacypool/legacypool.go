@@ -52,12 +52,24 @@ const (
 	// more expensive to propagate; larger transactions also take more resources
 	// to validate whether they fit into the pool or not.
 	txMaxSize = 4 * txSlotSize // 128KB
+
+	// maxTrackedBlocks is the maximum number of blocks scanned on a head change
+	// for the inclusion of tracked transactions.
+	maxTrackedBlocks = 64
 )
 
 var (
 	// ErrTxPoolOverflow is returned if the transaction pool is full and can't accept
 	// another remote transaction.
 	ErrTxPoolOverflow = errors.New("txpool is full")
+
+	// ErrSenderRateLimited is returned if the sender of a remote transaction
+	// exceeded its admission rate.
+	ErrSenderRateLimited = errors.New("sender rate limit exceeded")
+
+	// ErrPeerRateLimited is returned if the peer relaying a remote transaction
+	// exceeded its admission rate.
+	ErrPeerRateLimited = errors.New("peer rate limit exceeded")
 )
 
 var (
@@ -89,6 +101,10 @@ var (
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
+	// senderRateLimitMeter and peerRateLimitMeter count how many transactions are rejected
+	// due to their sender or relaying peer exceeding the admission rate.
+	senderRateLimitMeter = metrics.NewRegisteredMeter("txpool/ratelimit/sender", nil)
+	peerRateLimitMeter   = metrics.NewRegisteredMeter("txpool/ratelimit/peer", nil)
 	// reorgDurationTimer measures how long time a txpool reorg takes.
 	reorgDurationTimer = metrics.NewRegisteredTimer("txpool/reorgtime", nil)
 	// dropBetweenReorgHistogram counts how many drops we experience between two reorg runs. It is expected
@@ -126,6 +142,10 @@ type Config struct {
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
+	JournalRemotes    bool   // Whether remote transactions should also be journaled to survive node restarts
+	RemoteJournal     string // Journal of remote transactions, both executable and queued
+	RemoteJournalSize uint64 // Maximum size in bytes of the remote transaction journal
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -135,6 +155,14 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	SenderTxRate  float64       // Remote transactions admitted per second from a single sender (0 = unlimited)
+	SenderGasRate uint64        // Remote transaction gas admitted per second from a single sender (0 = unlimited)
+	PeerTxRate    float64       // Transactions admitted per second relayed by a single peer (0 = unlimited)
+	PeerGasRate   uint64        // Transaction gas admitted per second relayed by a single peer (0 = unlimited)
+	RateBurst     time.Duration // Period of unused admission allowance a sender or peer may accumulate
+
+	Lifecycle int // Number of recently seen transactions whose lifecycle is tracked (0 = disabled)
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -142,6 +170,9 @@ var DefaultConfig = Config{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	RemoteJournal:     "remote_transactions.rlp",
+	RemoteJournalSize: 32 * 1024 * 1024,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -151,6 +182,10 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	RateBurst: 10 * time.Second,
+
+	Lifecycle: 4096,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -161,6 +196,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.JournalRemotes && conf.RemoteJournalSize < txMaxSize {
+		log.Warn("Sanitizing invalid txpool remote journal size", "provided", conf.RemoteJournalSize, "updated", DefaultConfig.RemoteJournalSize)
+		conf.RemoteJournalSize = DefaultConfig.RemoteJournalSize
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultConfig.PriceLimit)
 		conf.PriceLimit = DefaultConfig.PriceLimit
@@ -189,6 +228,22 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.SenderTxRate < 0 {
+		log.Warn("Sanitizing invalid txpool sender rate", "provided", conf.SenderTxRate, "updated", 0)
+		conf.SenderTxRate = 0
+	}
+	if conf.PeerTxRate < 0 {
+		log.Warn("Sanitizing invalid txpool peer rate", "provided", conf.PeerTxRate, "updated", 0)
+		conf.PeerTxRate = 0
+	}
+	if conf.RateBurst < time.Second {
+		log.Warn("Sanitizing invalid txpool rate burst", "provided", conf.RateBurst, "updated", time.Second)
+		conf.RateBurst = time.Second
+	}
+	if conf.Lifecycle < 0 {
+		log.Warn("Sanitizing invalid txpool lifecycle tracking", "provided", conf.Lifecycle, "updated", 0)
+		conf.Lifecycle = 0
+	}
 	return conf
 }
 
@@ -214,6 +269,12 @@ type LegacyPool struct {
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
+	remotes *journal    // Journal of remote transactions to back up to disk
+
+	senderLimiter *rateLimiter[common.Address] // Admission rate limits of remote senders, nil if unlimited
+	peerLimiter   *rateLimiter[string]         // Admission rate limits of relaying peers, nil if unlimited
+
+	lifecycle *txpool.LifecycleTracker // Lifecycle of the recently seen transactions, nil if not tracked
 
 	reserve txpool.AddressReserver       // Address reserver to ensure exclusivity across subpools
 	pending map[common.Address]*list     // All currently processable transactions
@@ -259,6 +320,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
 		initDoneCh:      make(chan struct{}),
+		lifecycle:       txpool.NewLifecycleTracker(config.Lifecycle),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -270,14 +332,18 @@ func New(config Config, chain BlockChain) *LegacyPool {
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 	}
+	if config.JournalRemotes && config.RemoteJournal != "" {
+		pool.remotes = newRemoteTxJournal(config.RemoteJournal, config.RemoteJournalSize)
+	}
 	return pool
 }
 
 // Filter returns whether the given transaction can be consumed by the legacy
-// pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
+// pool, specifically, whether it is a Legacy, AccessList, Dynamic or FeePayer
+// transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.FeePayerTxType:
 		return true
 	default:
 		return false
@@ -323,6 +389,21 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If remote journaling is enabled, restore the previous remote transactions
+	// too. They are re-validated against the current head like any new one.
+	if pool.remotes != nil {
+		if err := pool.remotes.load(pool.addRemotesSync); err != nil {
+			log.Warn("Failed to load remote transaction journal", "err", err)
+		}
+		if err := pool.remotes.rotate(pool.remote()); err != nil {
+			log.Warn("Failed to rotate remote transaction journal", "err", err)
+		}
+	}
+	// Enable the admission rate limits only after the journals were restored,
+	// the transactions in there were already admitted before the restart.
+	pool.senderLimiter = newRateLimiter[common.Address](pool.config.SenderTxRate, pool.config.SenderGasRate, pool.config.RateBurst)
+	pool.peerLimiter = newRateLimiter[string](pool.config.PeerTxRate, pool.config.PeerGasRate, pool.config.RateBurst)
+
 	pool.wg.Add(1)
 	go pool.loop()
 	return nil
@@ -378,6 +459,7 @@ func (pool *LegacyPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
+						pool.lifecycle.Record(tx.Hash(), txpool.TxStageEvicted, "queued for too long")
 						pool.removeTx(tx.Hash(), true, true)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
@@ -394,6 +476,13 @@ func (pool *LegacyPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.remotes != nil {
+				pool.mu.Lock()
+				if err := pool.remotes.rotate(pool.remote()); err != nil {
+					log.Warn("Failed to rotate remote tx journal", "err", err)
+				}
+				pool.mu.Unlock()
+			}
 		}
 	}
 }
@@ -403,10 +492,21 @@ func (pool *LegacyPool) Close() error {
 	// Terminate the pool reorger and return
 	close(pool.reorgShutdownCh)
 	pool.wg.Wait()
+	pool.lifecycle.Close()
 
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	// The remote journal is only regenerated periodically, so flush the latest
+	// pool contents before shutting down
+	if pool.remotes != nil {
+		pool.mu.Lock()
+		if err := pool.remotes.rotate(pool.remote()); err != nil {
+			log.Warn("Failed to rotate remote tx journal", "err", err)
+		}
+		pool.mu.Unlock()
+		pool.remotes.close()
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -428,6 +528,18 @@ func (pool *LegacyPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs
 	return pool.txFeed.Subscribe(ch)
 }
 
+// Lifecycle implements txpool.TrackingPool, returning the recorded lifecycle
+// steps of a transaction.
+func (pool *LegacyPool) Lifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	return pool.lifecycle.Lifecycle(hash)
+}
+
+// SubscribeLifecycle implements txpool.TrackingPool, subscribing to the lifecycle
+// steps of all the transactions handled by the pool.
+func (pool *LegacyPool) SubscribeLifecycle(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return pool.lifecycle.Subscribe(ch)
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *LegacyPool) SetGasTip(tip *big.Int) {
@@ -442,8 +554,14 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 	// If the min miner fee increased, remove transactions below the new threshold
 	if newTip.Cmp(old) > 0 {
 		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
-		drop := pool.all.RemotesBelowTip(tip)
+		var drop types.Transactions
+		for _, tx := range pool.all.RemotesBelowTip(tip) {
+			if !pool.isFeeExempt(tx) {
+				drop = append(drop, tx)
+			}
+		}
 		for _, tx := range drop {
+			pool.lifecycle.Record(tx.Hash(), txpool.TxStageEvicted, "underpriced")
 			pool.removeTx(tx.Hash(), false, true)
 		}
 		pool.priced.Removed(len(drop))
@@ -547,7 +665,7 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 		txs := list.Flatten()
 
 		// If the miner requests tip enforcement, cap the lists now
-		if minTipBig != nil && !pool.locals.contains(addr) {
+		if minTipBig != nil && !pool.locals.contains(addr) && !pool.isFeeExemptSender(addr) {
 			for i, tx := range txs {
 				if tx.EffectiveGasTipIntCmp(minTipBig, baseFeeBig) < 0 {
 					txs = txs[:i]
@@ -599,6 +717,26 @@ func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// remote retrieves all currently known remote transactions, grouped by origin
+// account and sorted by nonce. Executable transactions are returned in the first
+// set and queued ones in the second, so journals prefer the former if capped.
+// The returned transaction sets are copies and can be freely modified.
+func (pool *LegacyPool) remote() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	pending := make(map[common.Address]types.Transactions)
+	for addr, list := range pool.pending {
+		if !pool.locals.contains(addr) {
+			pending[addr] = list.Flatten()
+		}
+	}
+	queued := make(map[common.Address]types.Transactions)
+	for addr, list := range pool.queue {
+		if !pool.locals.contains(addr) {
+			queued[addr] = list.Flatten()
+		}
+	}
+	return pending, queued
+}
+
 // validateTxBasics checks whether a transaction is valid according to the consensus
 // rules, but does not check state-dependent validation such as sufficient balance.
 // This check is meant as an early check which only needs to be performed once,
@@ -609,11 +747,12 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType,
+			1<<types.DynamicFeeTxType |
+			1<<types.FeePayerTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
 	}
-	if local {
+	if local || pool.isFeeExempt(tx) {
 		opts.MinTip = new(big.Int)
 	}
 	if err := txpool.ValidateTransaction(tx, pool.currentHead.Load(), pool.signer, opts); err != nil {
@@ -622,6 +761,24 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 	return nil
 }
 
+// isFeeExempt reports whether the transaction was sent by a system account the
+// chain config exempts from transaction fees. Such transactions are accepted
+// regardless of the pool's price limit, the same way local ones are.
+func (pool *LegacyPool) isFeeExempt(tx *types.Transaction) bool {
+	if pool.chainconfig.FeeExempt == nil {
+		return false
+	}
+	from, err := types.Sender(pool.signer, tx)
+	return err == nil && pool.isFeeExemptSender(from)
+}
+
+// isFeeExemptSender reports whether the chain config exempts the account from
+// transaction fees in the block following the current head.
+func (pool *LegacyPool) isFeeExemptSender(addr common.Address) bool {
+	next := new(big.Int).Add(pool.currentHead.Load().Number, common.Big1)
+	return pool.chainconfig.IsFeeExempt(next, addr)
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
@@ -648,11 +805,25 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 		ExistingCost: func(addr common.Address, nonce uint64) *big.Int {
 			if list := pool.pending[addr]; list != nil {
 				if tx := list.txs.Get(nonce); tx != nil {
-					return tx.Cost()
+					return tx.SenderCost()
 				}
 			}
 			return nil
 		},
+		ExistingSponsorship: func(payer common.Address, from common.Address, nonce uint64) *big.Int {
+			sponsored := pool.all.Sponsored(payer)
+			for _, txs := range []*list{pool.pending[from], pool.queue[from]} {
+				if txs == nil {
+					continue
+				}
+				if old := txs.txs.Get(nonce); old != nil {
+					if oldPayer := old.FeePayer(); oldPayer != nil && *oldPayer == payer {
+						sponsored.Sub(sponsored, sponsoredFee(old))
+					}
+				}
+			}
+			return sponsored
+		},
 	}
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
@@ -682,6 +853,16 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		knownTxMeter.Mark(1)
 		return false, txpool.ErrAlreadyKnown
 	}
+	// Record any rejection in the lifecycle of the transaction
+	var reason string
+	defer func() {
+		if err != nil {
+			if reason == "" {
+				reason = err.Error()
+			}
+			pool.lifecycle.Record(hash, txpool.TxStageRejected, reason)
+		}
+	}()
 	
 	// Lấy sender của transaction
 	from, err := types.Sender(pool.signer, tx)
@@ -690,31 +871,12 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 	}
 	
 	// ============= THÊM KIỂM TRA BẢO MẬT =============
-	// Kiểm tra blacklist
-	if core.GetSecurityConfig().IsBlacklisted(from) {
-		log.Debug("Rejected blacklisted transaction", "hash", hash, "from", from)
-		return false, core.ErrBlacklistedAddress
-	}
-	
-	// Kiểm tra frozen accounts
-	if core.GetSecurityConfig().IsFrozen(from) {
-		log.Debug("Rejected transaction from frozen account", "hash", hash, "from", from)
-		return false, core.ErrFrozenAccount
-	}
-	
-	// Kiểm tra contract destination nếu là frozen contract
-	if tx.To() != nil && core.GetSecurityConfig().IsContractFrozen(*tx.To()) {
-		log.Debug("Rejected transaction to frozen contract", "hash", hash, "from", from, "to", tx.To())
-		return false, core.ErrFrozenContract
-	}
-	
-	// Kiểm tra whitelist cho contract deployment
-	if tx.To() == nil && len(tx.Data()) > 0 {
-		// Đây là transaction tạo contract
-		if !core.GetSecurityConfig().IsWhitelisted(from) {
-			log.Debug("Rejected contract deployment from non-whitelisted address", "hash", hash, "from", from)
-			return false, core.ErrNotWhitelistedForDeploy
-		}
+	head := pool.currentHead.Load()
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), head); err != nil {
+		log.Debug("Rejected transaction by security policy", "hash", hash, "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(hash, from, tx.To(), err, core.RejectionPathPool)
+		reason = "security policy: " + err.Error()
+		return false, err
 	}
 	// ============= KẾT THÚC KIỂM TRA BẢO MẬT =============
 	
@@ -730,6 +892,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 	}
 	// already validated by this point
 	from, _ = types.Sender(pool.signer, tx)
+	pool.lifecycle.Record(hash, txpool.TxStageValidated, "")
 
 	// If the address is not yet known, request exclusivity to track the account
 	// only by this subpool until all transactions are evicted
@@ -809,6 +972,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 			underpricedTxMeter.Mark(1)
 
 			sender, _ := types.Sender(pool.signer, tx)
+			pool.lifecycle.Record(tx.Hash(), txpool.TxStageEvicted, "underpriced, pool full")
 			dropped := pool.removeTx(tx.Hash(), false, sender != from) // Don't unreserve the sender of the tx being added if last from the acc
 
 			pool.changesSinceReorg += dropped
@@ -828,7 +992,9 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.lifecycle.Replaced(old.Hash(), hash)
 		}
+		pool.lifecycle.Record(hash, txpool.TxStagePending, "")
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
@@ -840,10 +1006,16 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		return old != nil, nil
 	}
 	// New transaction isn't replacing a pending one, push into queue
+	reason = "awaiting promotion"
+	if tx.Nonce() > pool.pendingNonces.get(from) {
+		reason = "nonce gap"
+	}
 	replaced, err = pool.enqueueTx(hash, tx, isLocal, true)
 	if err != nil {
+		reason = ""
 		return false, err
 	}
+	pool.lifecycle.Record(hash, txpool.TxStageQueued, reason)
 	// Mark local addresses and journal local transactions
 	if local && !pool.locals.contains(from) {
 		log.Info("Setting new local account", "address", from)
@@ -903,6 +1075,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.lifecycle.Replaced(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -952,6 +1125,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.lifecycle.Record(hash, txpool.TxStageEvicted, "replacement underpriced")
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -959,12 +1133,14 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.lifecycle.Replaced(old.Hash(), hash)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
 	pool.pendingNonces.set(addr, tx.Nonce()+1)
+	pool.lifecycle.Record(hash, txpool.TxStagePending, "")
 
 	// Successful promotion, bump the heartbeat
 	pool.beats[addr] = time.Now()
@@ -1001,7 +1177,8 @@ func (pool *LegacyPool) addRemote(tx *types.Transaction) error {
 	return pool.addRemotes([]*types.Transaction{tx})[0]
 }
 
-// addRemotesSync is like addRemotes, but waits for pool reorganization. Tests use this method.
+// addRemotesSync is like addRemotes, but waits for pool reorganization. Tests and
+// the remote transaction journal use this method.
 func (pool *LegacyPool) addRemotesSync(txs []*types.Transaction) []error {
 	return pool.Add(txs, false, true)
 }
@@ -1017,6 +1194,18 @@ func (pool *LegacyPool) addRemoteSync(tx *types.Transaction) error {
 // If sync is set, the method will block until all internal maintenance related
 // to the add is finished. Only use this during tests for determinism!
 func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error {
+	return pool.addTxs(txs, "", local, sync)
+}
+
+// AddFromPeer enqueues a batch of remote transactions relayed by the given peer,
+// charging them to the admission rate of the peer.
+func (pool *LegacyPool) AddFromPeer(peer string, txs []*types.Transaction) []error {
+	return pool.addTxs(txs, peer, false, false)
+}
+
+// addTxs enqueues a batch of transactions into the pool, optionally relayed by
+// the given remote peer.
+func (pool *LegacyPool) addTxs(txs []*types.Transaction, peer string, local, sync bool) []error {
 	// Do not treat as local if local transactions have been disabled
 	local = local && !pool.config.NoLocals
 
@@ -1025,6 +1214,12 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 		errs = make([]error, len(txs))
 		news = make([]*types.Transaction, 0, len(txs))
 	)
+	source := "remote"
+	if peer != "" {
+		source = peer
+	} else if local {
+		source = "local"
+	}
 	for i, tx := range txs {
 		// If the transaction is known, pre-set the error slot
 		if pool.all.Get(tx.Hash()) != nil {
@@ -1032,6 +1227,8 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 			knownTxMeter.Mark(1)
 			continue
 		}
+		pool.lifecycle.Received(tx.Hash(), source)
+
 		// Exclude transactions with basic errors, e.g invalid signatures and
 		// insufficient intrinsic gas as soon as possible and cache senders
 		// in transactions before obtaining lock
@@ -1039,6 +1236,7 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 			errs[i] = err
 			log.Trace("Discarding invalid transaction", "hash", tx.Hash(), "err", err)
 			invalidTxMeter.Mark(1)
+			pool.lifecycle.Record(tx.Hash(), txpool.TxStageRejected, err.Error())
 			continue
 		}
 		// Accumulate all unknown transactions for deeper processing
@@ -1050,6 +1248,7 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
+	news = pool.admitTxsLocked(news, peer, local, errs)
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
 	pool.mu.Unlock()
 
@@ -1085,6 +1284,70 @@ func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction, local bool) ([]er
 	return errs, dirty
 }
 
+// admitTxsLocked charges a batch of new transactions to the admission rates
+// of their senders and of the peer relaying them, if any. The errors of the
+// throttled transactions are set in the first free slots of errs, the admitted
+// transactions are returned. The transaction pool lock must be held.
+func (pool *LegacyPool) admitTxsLocked(txs []*types.Transaction, peer string, local bool, errs []error) []*types.Transaction {
+	if pool.senderLimiter == nil && (peer == "" || pool.peerLimiter == nil) {
+		return txs
+	}
+	var (
+		admitted = make([]*types.Transaction, 0, len(txs))
+		slot     = 0
+	)
+	for _, tx := range txs {
+		for errs[slot] != nil {
+			slot++
+		}
+		if err := pool.admit(tx, peer, local); err != nil {
+			errs[slot] = err
+			pool.lifecycle.Record(tx.Hash(), txpool.TxStageRejected, err.Error())
+		} else {
+			admitted = append(admitted, tx)
+		}
+		slot++
+	}
+	return admitted
+}
+
+// admit charges a single transaction to the admission rates of its sender and
+// of the relaying peer. The transaction pool lock must be held.
+func (pool *LegacyPool) admit(tx *types.Transaction, peer string, local bool) error {
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	if pool.rateExempt(from, local) {
+		return nil
+	}
+	if pool.senderLimiter != nil {
+		if ok, first := pool.senderLimiter.allow(from, tx.Gas()); !ok {
+			if first {
+				log.Info("Throttling transaction sender", "sender", from, "txrate", pool.config.SenderTxRate, "gasrate", pool.config.SenderGasRate)
+			}
+			log.Trace("Discarding rate limited transaction", "hash", tx.Hash(), "sender", from)
+			senderRateLimitMeter.Mark(1)
+			return ErrSenderRateLimited
+		}
+	}
+	if peer != "" && pool.peerLimiter != nil {
+		if ok, first := pool.peerLimiter.allow(peer, tx.Gas()); !ok {
+			if first {
+				log.Info("Throttling transaction peer", "peer", peer, "txrate", pool.config.PeerTxRate, "gasrate", pool.config.PeerGasRate)
+			}
+			log.Trace("Discarding rate limited transaction", "hash", tx.Hash(), "peer", peer)
+			peerRateLimitMeter.Mark(1)
+			return ErrPeerRateLimited
+		}
+	}
+	return nil
+}
+
+// rateExempt reports whether transactions of the given sender are exempt from
+// admission rate limits: local ones and those of accounts whitelisted by the
+// security policy. The transaction pool lock must be held.
+func (pool *LegacyPool) rateExempt(from common.Address, local bool) bool {
+	return local || pool.locals.contains(from) || core.GetSecurityConfig().IsWhitelisted(from)
+}
+
 // Status returns the status (unknown/pending/queued) of a batch of transactions
 // identified by their hashes.
 func (pool *LegacyPool) Status(hash common.Hash) txpool.TxStatus {
@@ -1317,6 +1580,7 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	if reset != nil {
 		// Reset from the old head to the new, rescheduling any reorged transactions
 		pool.reset(reset.oldHead, reset.newHead)
+		pool.trackIncluded(reset.oldHead, reset.newHead)
 
 		// Nonces were reset, discard any events that became stale
 		for addr := range events {
@@ -1498,6 +1762,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range forwards {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.trackStale(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
@@ -1505,6 +1770,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.lifecycle.Record(hash, txpool.TxStageEvicted, "insufficient balance or gas limit exceeded")
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1527,6 +1793,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			for _, tx := range caps {
 				hash := tx.Hash()
 				pool.all.Remove(hash)
+				pool.lifecycle.Record(hash, txpool.TxStageEvicted, "account queue limit exceeded")
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1595,6 +1862,7 @@ func (pool *LegacyPool) truncatePending() {
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
+						pool.lifecycle.Record(hash, txpool.TxStageEvicted, "pending pool overflow")
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
 					pool.priced.Removed(len(caps))
@@ -1622,6 +1890,7 @@ func (pool *LegacyPool) truncatePending() {
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
+					pool.lifecycle.Record(hash, txpool.TxStageEvicted, "pending pool overflow")
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
 				pool.priced.Removed(len(caps))
@@ -1665,6 +1934,7 @@ func (pool *LegacyPool) truncateQueue() {
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
+				pool.lifecycle.Record(tx.Hash(), txpool.TxStageEvicted, "queue overflow")
 				pool.removeTx(tx.Hash(), true, true)
 			}
 			drop -= size
@@ -1674,6 +1944,7 @@ func (pool *LegacyPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
+			pool.lifecycle.Record(txs[i].Hash(), txpool.TxStageEvicted, "queue overflow")
 			pool.removeTx(txs[i].Hash(), true, true)
 			drop--
 			queuedRateLimitMeter.Mark(1)
@@ -1699,6 +1970,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.trackStale(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
@@ -1707,6 +1979,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			pool.lifecycle.Record(hash, txpool.TxStageEvicted, "insufficient balance or gas limit exceeded")
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
@@ -1716,6 +1989,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
+			pool.lifecycle.Record(hash, txpool.TxStageQueued, "nonce gap")
 		}
 		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
 		if pool.locals.contains(addr) {
@@ -1730,6 +2004,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(hash, tx, false, false)
+				pool.lifecycle.Record(hash, txpool.TxStageQueued, "nonce gap")
 			}
 			pendingGauge.Dec(int64(len(gapped)))
 		}
@@ -1743,6 +2018,41 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// trackIncluded records the inclusion of the tracked transactions in the blocks
+// between the old and the new head, up to maxTrackedBlocks deep.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) trackIncluded(oldHead, newHead *types.Header) {
+	if pool.lifecycle == nil || newHead == nil {
+		return
+	}
+	var stop uint64
+	if newHead.Number.Uint64() > 0 {
+		stop = newHead.Number.Uint64() - 1
+	}
+	if oldHead != nil && oldHead.Number.Uint64() < stop {
+		stop = oldHead.Number.Uint64()
+	}
+	if newHead.Number.Uint64()-stop > maxTrackedBlocks {
+		stop = newHead.Number.Uint64() - maxTrackedBlocks
+	}
+	block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
+	for block != nil && block.NumberU64() > stop {
+		for _, tx := range block.Transactions() {
+			pool.lifecycle.Included(tx.Hash(), block.Hash(), block.NumberU64())
+		}
+		block = pool.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	}
+}
+
+// trackStale records the removal of a transaction whose nonce became too low,
+// unless it was already recorded as included.
+func (pool *LegacyPool) trackStale(hash common.Hash) {
+	if stage, _ := pool.lifecycle.Stage(hash); stage != txpool.TxStageIncluded {
+		pool.lifecycle.Record(hash, txpool.TxStageEvicted, "nonce too low")
+	}
+}
+
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
 type addressByHeartbeat struct {
 	address   common.Address
@@ -1838,17 +2148,19 @@ func (as *accountSet) merge(other *accountSet) {
 // This lookup set combines the notion of "local transactions", which is useful
 // to build upper-level structure.
 type lookup struct {
-	slots   int
-	lock    sync.RWMutex
-	locals  map[common.Hash]*types.Transaction
-	remotes map[common.Hash]*types.Transaction
+	slots     int
+	lock      sync.RWMutex
+	locals    map[common.Hash]*types.Transaction
+	remotes   map[common.Hash]*types.Transaction
+	sponsored map[common.Address]*big.Int // Cumulative fees owed by each fee payer
 }
 
 // newLookup returns a new lookup structure.
 func newLookup() *lookup {
 	return &lookup{
-		locals:  make(map[common.Hash]*types.Transaction),
-		remotes: make(map[common.Hash]*types.Transaction),
+		locals:    make(map[common.Hash]*types.Transaction),
+		remotes:   make(map[common.Hash]*types.Transaction),
+		sponsored: make(map[common.Address]*big.Int),
 	}
 }
 
@@ -1934,6 +2246,18 @@ func (t *lookup) Slots() int {
 	return t.slots
 }
 
+// Sponsored returns the cumulative fees of the transactions in the lookup paid
+// for by the given fee payer. The returned value is a copy.
+func (t *lookup) Sponsored(payer common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if fees := t.sponsored[payer]; fees != nil {
+		return new(big.Int).Set(fees)
+	}
+	return new(big.Int)
+}
+
 // Add adds a transaction to the lookup.
 func (t *lookup) Add(tx *types.Transaction, local bool) {
 	t.lock.Lock()
@@ -1942,6 +2266,14 @@ func (t *lookup) Add(tx *types.Transaction, local bool) {
 	t.slots += numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if payer := tx.FeePayer(); payer != nil {
+		fees := t.sponsored[*payer]
+		if fees == nil {
+			fees = new(big.Int)
+			t.sponsored[*payer] = fees
+		}
+		fees.Add(fees, sponsoredFee(tx))
+	}
 	if local {
 		t.locals[tx.Hash()] = tx
 	} else {
@@ -1965,6 +2297,13 @@ func (t *lookup) Remove(hash common.Hash) {
 	t.slots -= numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if payer := tx.FeePayer(); payer != nil {
+		if fees := t.sponsored[*payer]; fees != nil {
+			if fees.Sub(fees, sponsoredFee(tx)).Sign() <= 0 {
+				delete(t.sponsored, *payer)
+			}
+		}
+	}
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
 }
@@ -2002,3 +2341,9 @@ func (t *lookup) RemotesBelowTip(threshold *big.Int) types.Transactions {
 func numSlots(tx *types.Transaction) int {
 	return int((tx.Size() + txSlotSize - 1) / txSlotSize)
 }
+
+// sponsoredFee calculates the part of a transaction's cost charged to its fee
+// payer instead of the sender.
+func sponsoredFee(tx *types.Transaction) *big.Int {
+	return new(big.Int).Sub(tx.Cost(), tx.SenderCost())
+}
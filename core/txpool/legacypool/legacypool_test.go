@@ -24,6 +24,8 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -38,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
 )
@@ -182,6 +185,25 @@ func validatePoolInternals(pool *LegacyPool) error {
 	if total := pool.all.Count(); total != pending+queued {
 		return fmt.Errorf("total transaction count %d != %d pending + %d queued", total, pending, queued)
 	}
+	// Ensure the fees tracked for each fee payer match the pooled transactions
+	sponsored := make(map[common.Address]*big.Int)
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
+		if payer := tx.FeePayer(); payer != nil {
+			if sponsored[*payer] == nil {
+				sponsored[*payer] = new(big.Int)
+			}
+			sponsored[*payer].Add(sponsored[*payer], sponsoredFee(tx))
+		}
+		return true
+	}, true, true)
+	if len(sponsored) != len(pool.all.sponsored) {
+		return fmt.Errorf("sponsoring fee payer count %d != %d", len(pool.all.sponsored), len(sponsored))
+	}
+	for payer, fees := range sponsored {
+		if have := pool.all.Sponsored(payer); have.Cmp(fees) != 0 {
+			return fmt.Errorf("fee payer %x sponsored fees %v != %v", payer, have, fees)
+		}
+	}
 	pool.priced.Reheap()
 	priced, remote := pool.priced.urgent.Len()+pool.priced.floating.Len(), pool.all.RemoteCount()
 	if priced != remote {
@@ -1534,6 +1556,112 @@ func TestMinGasPriceEnforced(t *testing.T) {
 	}
 }
 
+// Tests that fee exempt system accounts can transact with zero fees below the
+// pool's price limit, while everyone else is still held to it.
+func TestFeeExemptAccounts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		exemptKey, _ = crypto.GenerateKey()
+		otherKey, _  = crypto.GenerateKey()
+		exempt       = crypto.PubkeyToAddress(exemptKey.PublicKey)
+		other        = crypto.PubkeyToAddress(otherKey.PublicKey)
+	)
+	config := *eip1559Config
+	config.FeeExemptBlock = common.Big0
+	config.FeeExempt = &params.FeeExemptConfig{Accounts: []common.Address{exempt}}
+
+	pool, _ := setupPoolWithConfig(&config)
+	defer pool.Close()
+
+	testAddBalance(pool, exempt, big.NewInt(1000000))
+	testAddBalance(pool, other, big.NewInt(1000000))
+
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(0), big.NewInt(0), otherKey)); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("zero fee transaction error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(0), big.NewInt(0), exemptKey)); err != nil {
+		t.Fatalf("failed to add fee exempt transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(pricedTransaction(1, 100000, big.NewInt(0), exemptKey)); err != nil {
+		t.Fatalf("failed to add fee exempt legacy transaction: %v", err)
+	}
+	// Raising the price limit must not evict the exempt transactions
+	pool.SetGasTip(big.NewInt(10))
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	// Tip enforcement for the miner must let the exempt transactions through
+	pending := pool.Pending(txpool.PendingFilter{MinTip: uint256.NewInt(10), BaseFee: uint256.NewInt(10)})
+	if len(pending[exempt]) != 2 {
+		t.Fatalf("pending exempt transactions mismatched: have %d, want %d", len(pending[exempt]), 2)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the fees of all pooled transactions sponsored by a fee payer are
+// checked against its balance, not just the fee of each one in isolation.
+func TestFeePayerSponsorship(t *testing.T) {
+	t.Parallel()
+
+	var (
+		payerKey, _ = crypto.GenerateKey()
+		keys        = make([]*ecdsa.PrivateKey, 3)
+		payer       = crypto.PubkeyToAddress(payerKey.PublicKey)
+	)
+	config := *eip1559Config
+	config.FeePayerBlock = common.Big0
+	signer := types.LatestSigner(&config)
+
+	pool, _ := setupPoolWithConfig(&config)
+	defer pool.Close()
+
+	sponsoredTx := func(nonce uint64, fee int64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx := types.MustSignNewTx(key, signer, &types.FeePayerTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(fee),
+			GasFeeCap: big.NewInt(fee),
+			Gas:       params.TxGas,
+			To:        &common.Address{},
+			Value:     big.NewInt(0),
+			FeePayer:  payer,
+		})
+		tx, err := types.SignFeePayerTx(tx, signer, payerKey)
+		if err != nil {
+			t.Fatalf("failed to sign as fee payer: %v", err)
+		}
+		return tx
+	}
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	// Fund the payer for two transactions only
+	testAddBalance(pool, payer, big.NewInt(int64(2*params.TxGas*10+params.TxGas)))
+
+	if err := pool.addRemoteSync(sponsoredTx(0, 10, keys[0])); err != nil {
+		t.Fatalf("failed to add first sponsored transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(sponsoredTx(0, 10, keys[1])); err != nil {
+		t.Fatalf("failed to add second sponsored transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(sponsoredTx(0, 10, keys[2])); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("overdrafting sponsored transaction error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+	// Replacing a sponsored transaction only accounts for the fee difference
+	if err := pool.addRemoteSync(sponsoredTx(0, 11, keys[0])); err != nil {
+		t.Fatalf("failed to replace sponsored transaction: %v", err)
+	}
+	if have, want := pool.all.Sponsored(payer), big.NewInt(int64(params.TxGas*21)); have.Cmp(want) != 0 {
+		t.Fatalf("sponsored fees mismatch: have %v, want %v", have, want)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that setting the transaction pool gas price to a higher value correctly
 // discards everything cheaper (legacy & dynamic fee) than that and moves any
 // gapped transactions back from the pending pool to the queue.
@@ -2457,6 +2585,165 @@ func testJournaling(t *testing.T, nolocals bool) {
 	pool.Close()
 }
 
+// Tests that remote transactions, both executable and queued, are journaled to
+// disk if enabled and re-validated against the new head upon restart.
+func TestRemoteJournaling(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.JournalRemotes = true
+	config.RemoteJournal = filepath.Join(t.TempDir(), "remotes.rlp")
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	remote, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	// Add two executable and a queued remote transaction
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), remote),
+		pricedTransaction(1, 100000, big.NewInt(1), remote),
+		pricedTransaction(3, 100000, big.NewInt(1), remote),
+	}
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	// Terminate the pool, include the first transaction and ensure the rest survive
+	pool.Close()
+	statedb.SetNonce(crypto.PubkeyToAddress(remote.PublicKey), 1)
+	blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	pending, queued := pool.Stats()
+	if pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	pool.Close()
+
+	// Disabling remote journaling drops them on restart
+	config.JournalRemotes = false
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("remote transactions restored with journaling disabled: %d pending, %d queued", pending, queued)
+	}
+}
+
+// Tests that a size capped journal prefers the earlier transaction sets and
+// doesn't leave nonce gaps within an account.
+func TestJournalSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		size, _ = rlp.EncodeToBytes(transaction(0, 100000, key1))
+	)
+	pending := map[common.Address]types.Transactions{
+		addr1: {transaction(0, 100000, key1), transaction(1, 100000, key1)},
+	}
+	queued := map[common.Address]types.Transactions{
+		addr2: {transaction(5, 100000, key2), transaction(6, 100000, key2)},
+	}
+	journal := newRemoteTxJournal(filepath.Join(t.TempDir(), "remotes.rlp"), uint64(3*len(size)))
+	if err := journal.rotate(pending, queued); err != nil {
+		t.Fatalf("failed to rotate journal: %v", err)
+	}
+	journal.close()
+
+	var loaded []*types.Transaction
+	journal.load(func(txs []*types.Transaction) []error {
+		loaded = append(loaded, txs...)
+		return make([]error, len(txs))
+	})
+	if len(loaded) != 3 {
+		t.Fatalf("journaled transactions mismatch: have %d, want %d", len(loaded), 3)
+	}
+	for i, tx := range loaded[:2] {
+		if tx.Hash() != pending[addr1][i].Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, tx.Hash(), pending[addr1][i].Hash())
+		}
+	}
+	if loaded[2].Hash() != queued[addr2][0].Hash() {
+		t.Errorf("queued transaction mismatch: have %x, want %x", loaded[2].Hash(), queued[addr2][0].Hash())
+	}
+}
+
+// Tests that the lifecycle of the transactions is tracked through queueing,
+// promotion, replacement and rejection.
+func TestTransactionLifecycle(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	var (
+		gapped   = pricedTransaction(1, 100000, big.NewInt(1), key)
+		first    = pricedTransaction(0, 100000, big.NewInt(1), key)
+		replacer = pricedTransaction(0, 100000, big.NewInt(2), key)
+		cheap    = pricedTransaction(2, 100000, big.NewInt(0), key)
+	)
+	if err := pool.addRemoteSync(gapped); err != nil {
+		t.Fatalf("failed to add gapped transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(first); err != nil {
+		t.Fatalf("failed to add first transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(replacer); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(cheap); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("underpriced transaction error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	stages := func(hash common.Hash) []txpool.TxStage {
+		var stages []txpool.TxStage
+		for _, ev := range pool.Lifecycle(hash) {
+			stages = append(stages, ev.Stage)
+		}
+		return stages
+	}
+	tests := []struct {
+		tx   *types.Transaction
+		want []txpool.TxStage
+	}{
+		{gapped, []txpool.TxStage{txpool.TxStageReceived, txpool.TxStageValidated, txpool.TxStageQueued, txpool.TxStagePending}},
+		{first, []txpool.TxStage{txpool.TxStageReceived, txpool.TxStageValidated, txpool.TxStageQueued, txpool.TxStagePending, txpool.TxStageReplaced}},
+		{replacer, []txpool.TxStage{txpool.TxStageReceived, txpool.TxStageValidated, txpool.TxStagePending}},
+		{cheap, []txpool.TxStage{txpool.TxStageReceived, txpool.TxStageRejected}},
+	}
+	for i, tt := range tests {
+		if have := stages(tt.tx.Hash()); !slices.Equal(have, tt.want) {
+			t.Errorf("test %d: lifecycle mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+	if steps := pool.Lifecycle(gapped.Hash()); steps[2].Reason != "nonce gap" {
+		t.Errorf("queue reason mismatch: have %q, want %q", steps[2].Reason, "nonce gap")
+	}
+	if steps := pool.Lifecycle(first.Hash()); steps[len(steps)-1].ReplacedBy != replacer.Hash() {
+		t.Errorf("replacement mismatch: have %x, want %x", steps[len(steps)-1].ReplacedBy, replacer.Hash())
+	}
+}
+
 // TestStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestStatusCheck(t *testing.T) {
@@ -0,0 +1,116 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRateBuckets is the number of token buckets a rate limiter tracks before
+// it starts dropping the ones which refilled completely.
+const maxRateBuckets = 4096
+
+// tokenBucket tracks the admission allowance left for a single sender or peer,
+// both in number of transactions and in gas.
+type tokenBucket struct {
+	txs       float64   // Transactions that can still be admitted
+	gas       float64   // Gas that can still be admitted
+	updated   time.Time // Last time the allowance was refilled
+	throttled bool      // Whether the last admission was denied
+}
+
+// rateLimiter is a set of token buckets enforcing a transaction and a gas rate
+// per key. The buckets can accumulate up to burst worth of allowance, spending
+// it all at once if needed.
+type rateLimiter[K comparable] struct {
+	txRate  float64 // Transactions admitted per second, 0 if unlimited
+	gasRate float64 // Gas admitted per second, 0 if unlimited
+	burst   float64 // Seconds worth of allowance a bucket can hold
+
+	buckets map[K]*tokenBucket
+	now     func() time.Time // Clock, overridable for tests
+	lock    sync.Mutex
+}
+
+// newRateLimiter creates a rate limiter with the given per-key limits. If no
+// limits are set, nil is returned, admitting everything.
+func newRateLimiter[K comparable](txRate float64, gasRate uint64, burst time.Duration) *rateLimiter[K] {
+	if txRate <= 0 && gasRate == 0 {
+		return nil
+	}
+	return &rateLimiter[K]{
+		txRate:  txRate,
+		gasRate: float64(gasRate),
+		burst:   burst.Seconds(),
+		buckets: make(map[K]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow charges a transaction with the given gas to the bucket of the key. It
+// returns whether the transaction is admitted and, if not, whether the key just
+// got throttled after previously being admitted.
+func (l *rateLimiter[K]) allow(key K, gas uint64) (bool, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	bucket := l.buckets[key]
+	if bucket == nil {
+		if len(l.buckets) >= maxRateBuckets {
+			l.prune(now)
+		}
+		bucket = &tokenBucket{txs: l.txRate * l.burst, gas: l.gasRate * l.burst, updated: now}
+		l.buckets[key] = bucket
+	} else {
+		l.refill(bucket, now)
+	}
+	// Transactions are only admitted if both allowances cover them
+	if (l.txRate > 0 && bucket.txs < 1) || (l.gasRate > 0 && bucket.gas < float64(gas)) {
+		first := !bucket.throttled
+		bucket.throttled = true
+		return false, first
+	}
+	bucket.txs--
+	bucket.gas -= float64(gas)
+	bucket.throttled = false
+	return true, false
+}
+
+// refill tops up the allowance of a bucket for the time passed since its last
+// update, capped at the burst size.
+func (l *rateLimiter[K]) refill(bucket *tokenBucket, now time.Time) {
+	elapsed := now.Sub(bucket.updated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	bucket.txs = min(bucket.txs+elapsed*l.txRate, l.txRate*l.burst)
+	bucket.gas = min(bucket.gas+elapsed*l.gasRate, l.gasRate*l.burst)
+	bucket.updated = now
+}
+
+// prune drops all buckets which refilled completely, as those are equivalent
+// to fresh ones. The caller must hold the limiter lock.
+func (l *rateLimiter[K]) prune(now time.Time) {
+	for key, bucket := range l.buckets {
+		l.refill(bucket, now)
+		if bucket.txs >= l.txRate*l.burst && bucket.gas >= l.gasRate*l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
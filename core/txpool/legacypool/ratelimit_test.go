@@ -0,0 +1,153 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that token buckets enforce both the transaction and the gas rate, and
+// refill over time up to the burst allowance.
+func TestRateLimiter(t *testing.T) {
+	if newRateLimiter[string](0, 0, time.Second) != nil {
+		t.Fatal("limiter created without limits")
+	}
+	var (
+		now     = time.Unix(0, 0)
+		limiter = newRateLimiter[string](2, 100_000, 2*time.Second)
+	)
+	limiter.now = func() time.Time { return now }
+
+	// Gas runs out before the transaction allowance (200k gas, 4 txs)
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a", 90_000); !ok {
+			t.Fatalf("transaction %d throttled", i)
+		}
+	}
+	if ok, first := limiter.allow("a", 90_000); ok || !first {
+		t.Fatalf("gas allowance not enforced: admitted %v, first %v", ok, first)
+	}
+	if ok, first := limiter.allow("a", 90_000); ok || first {
+		t.Fatalf("throttling reported twice: admitted %v, first %v", ok, first)
+	}
+	// Other keys are unaffected
+	if ok, _ := limiter.allow("b", 21_000); !ok {
+		t.Fatal("independent key throttled")
+	}
+	// Allowance refills with time, but never beyond the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		if ok, _ := limiter.allow("a", 21_000); !ok {
+			t.Fatalf("transaction %d throttled after refill", i)
+		}
+	}
+	if ok, _ := limiter.allow("a", 21_000); ok {
+		t.Fatal("transaction allowance not enforced")
+	}
+	// Fully refilled buckets are pruned once the limiter is full
+	now = now.Add(time.Hour)
+	for i := 0; i < maxRateBuckets; i++ {
+		limiter.allow(string(rune(i+'c')), 21_000)
+	}
+	if len(limiter.buckets) > maxRateBuckets {
+		t.Fatalf("buckets not pruned: %d", len(limiter.buckets))
+	}
+}
+
+// Tests that remote transactions are throttled per sender and per peer, while
+// local and whitelisted senders are exempt.
+func TestAdmissionRateLimits(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.SenderTxRate = 1
+	config.PeerTxRate = 1
+	config.RateBurst = 3 * time.Second
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	now := time.Now()
+	pool.senderLimiter.now = func() time.Time { return now }
+	pool.peerLimiter.now = func() time.Time { return now }
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	// A single sender may only burst up to its allowance
+	txs := []*types.Transaction{transaction(0, 100000, keys[0]), transaction(1, 100000, keys[0]), transaction(2, 100000, keys[0]), transaction(3, 100000, keys[0])}
+	errs := pool.Add(txs, false, true)
+	for i := 0; i < 3; i++ {
+		if errs[i] != nil {
+			t.Fatalf("transaction %d rejected: %v", i, errs[i])
+		}
+	}
+	if !errors.Is(errs[3], ErrSenderRateLimited) {
+		t.Fatalf("sender limit error mismatch: have %v, want %v", errs[3], ErrSenderRateLimited)
+	}
+	// Local transactions bypass the limit
+	if err := pool.addLocal(transaction(3, 100000, keys[0])); err != nil {
+		t.Fatalf("local transaction rejected: %v", err)
+	}
+	// A single peer may only relay up to its allowance, even across senders
+	txs = []*types.Transaction{transaction(0, 100000, keys[1]), transaction(0, 100000, keys[2]), transaction(1, 100000, keys[1]), transaction(1, 100000, keys[2])}
+	errs = pool.AddFromPeer("peer", txs)
+	for i := 0; i < 3; i++ {
+		if errs[i] != nil {
+			t.Fatalf("relayed transaction %d rejected: %v", i, errs[i])
+		}
+	}
+	if !errors.Is(errs[3], ErrPeerRateLimited) {
+		t.Fatalf("peer limit error mismatch: have %v, want %v", errs[3], ErrPeerRateLimited)
+	}
+	// Whitelisted senders are exempt from both limits
+	whitelisted := crypto.PubkeyToAddress(keys[3].PublicKey)
+	if err := core.GetSecurityConfig().AddToWhitelist(whitelisted); err != nil {
+		t.Fatal(err)
+	}
+	defer core.GetSecurityConfig().RemoveFromWhitelist(whitelisted)
+
+	txs = []*types.Transaction{transaction(0, 100000, keys[3]), transaction(1, 100000, keys[3]), transaction(2, 100000, keys[3]), transaction(3, 100000, keys[3])}
+	for i, err := range pool.AddFromPeer("peer", txs) {
+		if err != nil {
+			t.Fatalf("whitelisted transaction %d rejected: %v", i, err)
+		}
+	}
+	// Allowance recovers with time
+	now = now.Add(time.Second)
+	if err := pool.AddFromPeer("peer", []*types.Transaction{transaction(1, 100000, keys[2])})[0]; err != nil {
+		t.Fatalf("transaction rejected after refill: %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// TxStage is a step in the lifecycle of a transaction handled by the pool.
+type TxStage string
+
+const (
+	TxStageReceived  TxStage = "received"  // Transaction arrived from the RPC or from a peer
+	TxStageRejected  TxStage = "rejected"  // Transaction was refused admission into the pool
+	TxStageValidated TxStage = "validated" // Transaction passed validation against the pool state
+	TxStagePending   TxStage = "pending"   // Transaction became executable
+	TxStageQueued    TxStage = "queued"    // Transaction is waiting to become executable
+	TxStageReplaced  TxStage = "replaced"  // Transaction was replaced by another with the same nonce
+	TxStageEvicted   TxStage = "evicted"   // Transaction was dropped from the pool
+	TxStageIncluded  TxStage = "included"  // Transaction was included in a block
+)
+
+const (
+	// maxLifecycleSteps is the number of steps retained per transaction. Once
+	// exceeded, the oldest steps (except for the reception) are forgotten.
+	maxLifecycleSteps = 32
+
+	// lifecycleFeedBuffer is the number of steps buffered for subscribers before
+	// notifications start being dropped.
+	lifecycleFeedBuffer = 1024
+)
+
+// lifecycleDropMeter counts the lifecycle notifications dropped because the
+// subscribers could not keep up.
+var lifecycleDropMeter = metrics.NewRegisteredMeter("txpool/lifecycle/dropped", nil)
+
+// TxLifecycleEvent is a single recorded step in the lifecycle of a transaction.
+type TxLifecycleEvent struct {
+	Hash  common.Hash // Hash of the transaction
+	Stage TxStage     // Step the transaction reached
+	Time  time.Time   // Time the step was reached
+
+	Source      string      // Origin of a received transaction: "local", "remote" or the relaying peer
+	Reason      string      // Why the transaction was queued, rejected or evicted
+	ReplacedBy  common.Hash // Transaction replacing a replaced one
+	BlockHash   common.Hash // Block including an included transaction
+	BlockNumber uint64      // Number of the block including an included transaction
+}
+
+// LifecycleTracker records the lifecycle of the most recently seen transactions
+// in a bounded ring, and feeds every recorded step to its subscribers.
+//
+// All methods are safe to call on a nil tracker, which records nothing.
+type LifecycleTracker struct {
+	limit int                                // Maximum number of transactions tracked
+	steps map[common.Hash][]TxLifecycleEvent // Recorded steps of the tracked transactions
+	ring  []common.Hash                      // Tracked transactions in insertion order
+	next  int                                // Ring slot to be overwritten by the next transaction
+
+	notify chan TxLifecycleEvent // Steps waiting to be fed to subscribers
+	feed   event.Feed
+	scope  event.SubscriptionScope
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	lock   sync.RWMutex
+}
+
+// NewLifecycleTracker creates a tracker retaining the lifecycle of the given
+// number of transactions, or nil if tracking is disabled.
+func NewLifecycleTracker(limit int) *LifecycleTracker {
+	if limit <= 0 {
+		return nil
+	}
+	t := &LifecycleTracker{
+		limit:  limit,
+		steps:  make(map[common.Hash][]TxLifecycleEvent),
+		ring:   make([]common.Hash, 0, limit),
+		notify: make(chan TxLifecycleEvent, lifecycleFeedBuffer),
+		quit:   make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.loop()
+	return t
+}
+
+// loop feeds the recorded steps to the subscribers, outside of any pool lock.
+func (t *LifecycleTracker) loop() {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case ev := <-t.notify:
+			t.feed.Send(ev)
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// Close stops feeding steps to the subscribers and terminates all subscriptions.
+func (t *LifecycleTracker) Close() {
+	if t == nil {
+		return
+	}
+	close(t.quit)
+	t.wg.Wait()
+	t.scope.Close()
+}
+
+// Received records the arrival of a transaction from the given source.
+func (t *LifecycleTracker) Received(hash common.Hash, source string) {
+	t.record(TxLifecycleEvent{Hash: hash, Stage: TxStageReceived, Source: source})
+}
+
+// Record records a transaction reaching the given stage, with an optional reason.
+func (t *LifecycleTracker) Record(hash common.Hash, stage TxStage, reason string) {
+	t.record(TxLifecycleEvent{Hash: hash, Stage: stage, Reason: reason})
+}
+
+// Replaced records a transaction being replaced by another one.
+func (t *LifecycleTracker) Replaced(hash common.Hash, by common.Hash) {
+	t.record(TxLifecycleEvent{Hash: hash, Stage: TxStageReplaced, ReplacedBy: by})
+}
+
+// Included records a tracked transaction being included in the given block.
+// Untracked transactions are ignored, so that the transactions of every block
+// can be fed to the tracker without flushing the ring.
+func (t *LifecycleTracker) Included(hash common.Hash, blockHash common.Hash, number uint64) {
+	if t == nil {
+		return
+	}
+	if stage, ok := t.Stage(hash); !ok || stage == TxStageIncluded {
+		return
+	}
+	t.record(TxLifecycleEvent{Hash: hash, Stage: TxStageIncluded, BlockHash: blockHash, BlockNumber: number})
+}
+
+// record appends a step to the lifecycle of a transaction, starting to track it
+// if unknown, and notifies the subscribers.
+func (t *LifecycleTracker) record(ev TxLifecycleEvent) {
+	if t == nil {
+		return
+	}
+	ev.Time = time.Now()
+
+	t.lock.Lock()
+	steps, ok := t.steps[ev.Hash]
+	if !ok {
+		// New transaction, overwrite the oldest one if the ring is full
+		if len(t.ring) < t.limit {
+			t.ring = append(t.ring, ev.Hash)
+		} else {
+			delete(t.steps, t.ring[t.next])
+			t.ring[t.next] = ev.Hash
+			t.next = (t.next + 1) % t.limit
+		}
+	}
+	if len(steps) >= maxLifecycleSteps {
+		// Too many steps, forget the oldest ones but keep the reception
+		steps = append(steps[:1], steps[len(steps)-maxLifecycleSteps+2:]...)
+	}
+	t.steps[ev.Hash] = append(steps, ev)
+	t.lock.Unlock()
+
+	select {
+	case t.notify <- ev:
+	default:
+		lifecycleDropMeter.Mark(1)
+	}
+}
+
+// Lifecycle returns the recorded steps of a transaction, oldest first, or nil
+// if the transaction is not tracked.
+func (t *LifecycleTracker) Lifecycle(hash common.Hash) []TxLifecycleEvent {
+	if t == nil {
+		return nil
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	steps := t.steps[hash]
+	if steps == nil {
+		return nil
+	}
+	return append([]TxLifecycleEvent(nil), steps...)
+}
+
+// Stage returns the last stage reached by a transaction, and whether it is
+// tracked at all.
+func (t *LifecycleTracker) Stage(hash common.Hash) (TxStage, bool) {
+	if t == nil {
+		return "", false
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	steps := t.steps[hash]
+	if len(steps) == 0 {
+		return "", false
+	}
+	return steps[len(steps)-1].Stage, true
+}
+
+// Subscribe subscribes to the lifecycle steps recorded by the tracker.
+func (t *LifecycleTracker) Subscribe(ch chan<- TxLifecycleEvent) event.Subscription {
+	if t == nil {
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			<-quit
+			return nil
+		})
+	}
+	return t.scope.Track(t.feed.Subscribe(ch))
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the lifecycle tracker forgets the oldest transactions once full,
+// caps the steps retained per transaction and notifies its subscribers.
+func TestLifecycleTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewLifecycleTracker(2)
+	defer tracker.Close()
+
+	events := make(chan TxLifecycleEvent, 16)
+	sub := tracker.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	var (
+		a = common.Hash{0x01}
+		b = common.Hash{0x02}
+		c = common.Hash{0x03}
+	)
+	tracker.Received(a, "local")
+	tracker.Received(b, "remote")
+	tracker.Included(c, common.Hash{0xff}, 1) // untracked, must be ignored
+
+	if _, ok := tracker.Stage(c); ok {
+		t.Fatalf("untracked transaction recorded as included")
+	}
+	tracker.Received(c, "peer")
+	if steps := tracker.Lifecycle(a); steps != nil {
+		t.Fatalf("oldest transaction not forgotten: %v", steps)
+	}
+	if stage, ok := tracker.Stage(b); !ok || stage != TxStageReceived {
+		t.Fatalf("stage mismatch: have %v (tracked %v), want %v", stage, ok, TxStageReceived)
+	}
+	// Overflow the steps of a transaction and ensure the reception is retained
+	for i := 0; i < 2*maxLifecycleSteps; i++ {
+		tracker.Record(c, TxStageQueued, "nonce gap")
+	}
+	tracker.Included(c, common.Hash{0xff}, 1)
+
+	steps := tracker.Lifecycle(c)
+	if len(steps) > maxLifecycleSteps {
+		t.Fatalf("step count mismatch: have %d, want at most %d", len(steps), maxLifecycleSteps)
+	}
+	if steps[0].Stage != TxStageReceived || steps[0].Source != "peer" {
+		t.Fatalf("reception not retained: %+v", steps[0])
+	}
+	if last := steps[len(steps)-1]; last.Stage != TxStageIncluded || last.BlockNumber != 1 {
+		t.Fatalf("inclusion mismatch: %+v", last)
+	}
+	// Ensure the subscriber was fed the recorded steps in order
+	for i, want := range []common.Hash{a, b, c} {
+		select {
+		case ev := <-events:
+			if ev.Hash != want {
+				t.Fatalf("event %d: hash mismatch: have %x, want %x", i, ev.Hash, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d: notification timeout", i)
+		}
+	}
+}
+
+// Tests that a disabled tracker can be used without recording anything.
+func TestLifecycleTrackerDisabled(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewLifecycleTracker(0)
+	defer tracker.Close()
+
+	tracker.Received(common.Hash{0x01}, "local")
+	if steps := tracker.Lifecycle(common.Hash{0x01}); steps != nil {
+		t.Fatalf("disabled tracker recorded steps: %v", steps)
+	}
+	sub := tracker.Subscribe(make(chan TxLifecycleEvent))
+	sub.Unsubscribe()
+}
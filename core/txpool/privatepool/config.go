@@ -0,0 +1,55 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package privatepool
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config are the configuration parameters of the private transaction pool.
+type Config struct {
+	MaxTxs       int      // Maximum number of private transactions tracked by the pool
+	AccountSlots int      // Maximum number of private transactions per account
+	MaxBlocks    uint64   // Maximum number of blocks a private transaction stays in the pool
+	Validators   []string `toml:",omitempty"` // Validator RPC endpoints private transactions are forwarded to
+}
+
+// DefaultConfig contains the default configurations for the private pool.
+var DefaultConfig = Config{
+	MaxTxs:       1024,
+	AccountSlots: 16,
+	MaxBlocks:    25,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.MaxTxs < 1 {
+		log.Warn("Sanitizing invalid privatepool transaction limit", "provided", conf.MaxTxs, "updated", DefaultConfig.MaxTxs)
+		conf.MaxTxs = DefaultConfig.MaxTxs
+	}
+	if conf.AccountSlots < 1 {
+		log.Warn("Sanitizing invalid privatepool account slots", "provided", conf.AccountSlots, "updated", DefaultConfig.AccountSlots)
+		conf.AccountSlots = DefaultConfig.AccountSlots
+	}
+	if conf.MaxBlocks < 1 {
+		log.Warn("Sanitizing invalid privatepool block lifetime", "provided", conf.MaxBlocks, "updated", DefaultConfig.MaxBlocks)
+		conf.MaxBlocks = DefaultConfig.MaxBlocks
+	}
+	return conf
+}
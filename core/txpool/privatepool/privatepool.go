@@ -0,0 +1,493 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package privatepool implements the transaction pool for private transactions.
+package privatepool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+const (
+	// txMaxSize is the maximum size a single private transaction can have, the
+	// same limit the legacy pool applies to plain transactions.
+	txMaxSize = 128 * 1024
+
+	// priceBump is the minimum price bump percentage needed to replace an already
+	// pooled private transaction.
+	priceBump = 10
+)
+
+var (
+	// ErrTxExpired is returned if the last block a private transaction targets
+	// is already part of the chain.
+	ErrTxExpired = errors.New("private transaction expired")
+
+	// ErrInvalidMaxBlock is returned if the last block a private transaction
+	// targets reaches further than the pool retains transactions.
+	ErrInvalidMaxBlock = errors.New("private transaction max block too far")
+
+	// ErrUnknownTx is returned if a private transaction to cancel is not in the
+	// pool.
+	ErrUnknownTx = errors.New("unknown private transaction")
+
+	// ErrCancelNotSender is returned if a private transaction is attempted to be
+	// cancelled by anyone other than its sender.
+	ErrCancelNotSender = errors.New("private transaction cancelled by non-sender")
+
+	// ErrPoolFull is returned if the pool reached its transaction limit.
+	ErrPoolFull = errors.New("private pool full")
+)
+
+var (
+	txGauge       = metrics.NewRegisteredGauge("privatepool/txs", nil)
+	expiredMeter  = metrics.NewRegisteredMeter("privatepool/expired", nil)
+	cancelMeter   = metrics.NewRegisteredMeter("privatepool/cancelled", nil)
+	includedMeter = metrics.NewRegisteredMeter("privatepool/included", nil)
+)
+
+// BlockChain defines the minimal set of methods needed to back a private pool
+// with a chain. Exists to allow mocking the live chain out of tests.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// privateTx is a pooled private transaction along with its inclusion deadline.
+type privateTx struct {
+	tx       *types.Transaction
+	from     common.Address
+	maxBlock uint64 // Last block number the transaction may be included in
+}
+
+// PrivatePool is the transaction pool dedicated to private transactions, which
+// are handed to the local miner like any other executable transaction, but are
+// never announced to the network.
+//
+// Accounts with private transactions are reserved by the pool: as long as any
+// of their transactions is private, public ones are rejected by the other pools.
+type PrivatePool struct {
+	config  Config     // Pool configuration
+	chain   BlockChain // Chain object to access the state through
+	signer  types.Signer
+	reserve txpool.AddressReserver // Address reserver to ensure exclusivity across subpools
+
+	head  *types.Header  // Current head of the chain
+	state *state.StateDB // Current state at the head of the chain
+
+	index  map[common.Address][]*privateTx // Private transactions grouped by account, nonce sorted without gaps
+	lookup map[common.Hash]*privateTx      // Private transactions, keyed by transaction hash
+
+	txFeed event.Feed // Never fed, private transactions are not announced
+	lock   sync.RWMutex
+}
+
+// New creates a new private pool to gather private transactions.
+func New(config Config, chain BlockChain) *PrivatePool {
+	// Sanitize the input to ensure the pool limits are workable
+	config = (&config).sanitize()
+
+	return &PrivatePool{
+		config: config,
+		chain:  chain,
+		signer: types.LatestSigner(chain.Config()),
+		index:  make(map[common.Address][]*privateTx),
+		lookup: make(map[common.Hash]*privateTx),
+	}
+}
+
+// Filter returns whether the given transaction can be consumed by the private
+// pool. Public transactions are never accepted, use AddPrivate instead.
+func (p *PrivatePool) Filter(tx *types.Transaction) bool {
+	return false
+}
+
+// Init sets the chain head the transactions are validated against. Private
+// transactions are not persisted, so there is nothing to load from disk.
+func (p *PrivatePool) Init(gasTip uint64, head *types.Header, reserve txpool.AddressReserver) error {
+	// Initialize the state with head block, or fallback to empty one in
+	// case the head state is not available (might occur when node is not
+	// fully synced).
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		statedb, err = p.chain.StateAt(types.EmptyRootHash)
+	}
+	if err != nil {
+		return err
+	}
+	p.head, p.state = head, statedb
+	p.reserve = reserve
+	return nil
+}
+
+// Close terminates the private pool, releasing all account reservations.
+func (p *PrivatePool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for addr := range p.index {
+		p.reserve(addr, false)
+	}
+	return nil
+}
+
+// Reset implements txpool.SubPool, dropping all private transactions which got
+// included or which can no longer be included until their deadline.
+func (p *PrivatePool) Reset(oldHead, newHead *types.Header) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	statedb, err := p.chain.StateAt(newHead.Root)
+	if err != nil {
+		log.Error("Failed to reset privatepool state", "err", err)
+		return
+	}
+	p.head, p.state = newHead, statedb
+
+	next := newHead.Number.Uint64() + 1
+	for addr, txs := range p.index {
+		// Drop everything included (or otherwise made stale) by the new head
+		nonce := p.state.GetNonce(addr)
+		for len(txs) > 0 && txs[0].tx.Nonce() < nonce {
+			log.Trace("Dropping included private transaction", "hash", txs[0].tx.Hash(), "from", addr)
+			delete(p.lookup, txs[0].tx.Hash())
+			includedMeter.Mark(1)
+			txs = txs[1:]
+		}
+		// Drop the first expired transaction and all depending on it
+		for i, ptx := range txs {
+			if ptx.maxBlock < next {
+				for _, dropped := range txs[i:] {
+					log.Debug("Dropping expired private transaction", "hash", dropped.tx.Hash(), "from", addr, "maxblock", dropped.maxBlock)
+					delete(p.lookup, dropped.tx.Hash())
+				}
+				expiredMeter.Mark(int64(len(txs) - i))
+				txs = txs[:i]
+				break
+			}
+		}
+		p.update(addr, txs)
+	}
+	txGauge.Update(int64(len(p.lookup)))
+}
+
+// SetGasTip implements txpool.SubPool. Private transactions are submitted over
+// the local RPC, so like local transactions they are exempt from the minimum tip.
+func (p *PrivatePool) SetGasTip(tip *big.Int) {}
+
+// AddPrivate validates a private transaction against the current head and
+// inserts it into the pool. A zero maxBlock retains the transaction for as long
+// as the pool allows.
+func (p *PrivatePool) AddPrivate(tx *types.Transaction, maxBlock uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// Resolve and check the inclusion deadline
+	next := p.head.Number.Uint64() + 1
+	if maxBlock == 0 {
+		maxBlock = next + p.config.MaxBlocks - 1
+	}
+	switch {
+	case maxBlock < next:
+		return fmt.Errorf("%w: max block %d, next block %d", ErrTxExpired, maxBlock, next)
+	case maxBlock >= next+p.config.MaxBlocks:
+		return fmt.Errorf("%w: max block %d beyond %d", ErrInvalidMaxBlock, maxBlock, next+p.config.MaxBlocks-1)
+	}
+	if p.lookup[tx.Hash()] != nil {
+		return txpool.ErrAlreadyKnown
+	}
+	from, err := p.validateTx(tx)
+	if err != nil {
+		return err
+	}
+	txs := p.index[from]
+	if len(txs) > 0 && tx.Nonce() >= txs[0].tx.Nonce() && tx.Nonce() < txs[0].tx.Nonce()+uint64(len(txs)) {
+		// Replacement of an already pooled transaction, ensure it pays more
+		old := txs[tx.Nonce()-txs[0].tx.Nonce()]
+		if !bumped(old.tx, tx) {
+			return txpool.ErrReplaceUnderpriced
+		}
+		delete(p.lookup, old.tx.Hash())
+		ptx := &privateTx{tx: tx, from: from, maxBlock: maxBlock}
+		txs[tx.Nonce()-txs[0].tx.Nonce()] = ptx
+		p.lookup[tx.Hash()] = ptx
+
+		log.Debug("Replaced private transaction", "hash", tx.Hash(), "old", old.tx.Hash(), "from", from, "nonce", tx.Nonce())
+		return nil
+	}
+	if len(p.lookup) >= p.config.MaxTxs {
+		return ErrPoolFull
+	}
+	if len(txs) == 0 {
+		// Accounts are exclusive to a single subpool, claim the sender
+		if err := p.reserve(from, true); err != nil {
+			return err
+		}
+	}
+	ptx := &privateTx{tx: tx, from: from, maxBlock: maxBlock}
+	p.index[from] = append(txs, ptx)
+	p.lookup[tx.Hash()] = ptx
+	txGauge.Update(int64(len(p.lookup)))
+
+	log.Debug("Added private transaction to pool", "hash", tx.Hash(), "from", from, "nonce", tx.Nonce(), "maxblock", maxBlock)
+	return nil
+}
+
+// validateTx checks whether a private transaction is valid on top of the current
+// head and the already pooled transactions of its sender. The caller must hold
+// the pool lock.
+func (p *PrivatePool) validateTx(tx *types.Transaction) (common.Address, error) {
+	opts := &txpool.ValidationOptions{
+		Config: p.chain.Config(),
+		Accept: 0 |
+			1<<types.LegacyTxType |
+			1<<types.AccessListTxType |
+			1<<types.DynamicFeeTxType,
+		MaxSize: txMaxSize,
+		MinTip:  new(big.Int),
+	}
+	if err := txpool.ValidateTransaction(tx, p.head, p.signer, opts); err != nil {
+		return common.Address{}, err
+	}
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return common.Address{}, txpool.ErrInvalidSender
+	}
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), p.head); err != nil {
+		log.Debug("Rejected private transaction by security policy", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathPool)
+		return common.Address{}, err
+	}
+	stateOpts := &txpool.ValidationOptionsWithState{
+		State: p.state,
+		FirstNonceGap: func(addr common.Address) uint64 {
+			if txs := p.index[addr]; len(txs) > 0 {
+				return txs[0].tx.Nonce() + uint64(len(txs))
+			}
+			return p.state.GetNonce(addr)
+		},
+		UsedAndLeftSlots: func(addr common.Address) (int, int) {
+			used := len(p.index[addr])
+			return used, p.config.AccountSlots - used
+		},
+		ExistingExpenditure: func(addr common.Address) *big.Int {
+			spent := new(big.Int)
+			for _, ptx := range p.index[addr] {
+				spent.Add(spent, ptx.tx.Cost())
+			}
+			return spent
+		},
+		ExistingCost: func(addr common.Address, nonce uint64) *big.Int {
+			for _, ptx := range p.index[addr] {
+				if ptx.tx.Nonce() == nonce {
+					return ptx.tx.Cost()
+				}
+			}
+			return nil
+		},
+	}
+	if err := txpool.ValidateTransactionWithState(tx, p.signer, stateOpts); err != nil {
+		return common.Address{}, err
+	}
+	return from, nil
+}
+
+// bumped reports whether the replacement transaction pays enough more than the
+// pooled one to replace it.
+func bumped(old, tx *types.Transaction) bool {
+	var (
+		oldFeeCap = new(big.Int).Mul(old.GasFeeCap(), big.NewInt(100+priceBump))
+		oldTip    = new(big.Int).Mul(old.GasTipCap(), big.NewInt(100+priceBump))
+		feeCap    = new(big.Int).Mul(tx.GasFeeCap(), big.NewInt(100))
+		tip       = new(big.Int).Mul(tx.GasTipCap(), big.NewInt(100))
+	)
+	return feeCap.Cmp(oldFeeCap) >= 0 && tip.Cmp(oldTip) >= 0
+}
+
+// CancelPrivate drops a private transaction from the pool, together with all
+// the subsequent transactions of the same sender which can no longer execute.
+// Only the sender of the transaction is allowed to cancel it.
+func (p *PrivatePool) CancelPrivate(hash common.Hash, from common.Address) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ptx := p.lookup[hash]
+	if ptx == nil {
+		return ErrUnknownTx
+	}
+	if sender, err := types.Sender(p.signer, ptx.tx); err != nil || sender != from {
+		return ErrCancelNotSender
+	}
+	txs := p.index[ptx.from]
+	for i, pooled := range txs {
+		if pooled.tx.Hash() != hash {
+			continue
+		}
+		for _, dropped := range txs[i:] {
+			log.Debug("Cancelled private transaction", "hash", dropped.tx.Hash(), "from", ptx.from, "nonce", dropped.tx.Nonce())
+			delete(p.lookup, dropped.tx.Hash())
+		}
+		cancelMeter.Mark(int64(len(txs) - i))
+		p.update(ptx.from, txs[:i])
+		break
+	}
+	txGauge.Update(int64(len(p.lookup)))
+	return nil
+}
+
+// update stores the remaining private transactions of an account, releasing
+// the account reservation if none are left. The caller must hold the pool lock.
+func (p *PrivatePool) update(addr common.Address, txs []*privateTx) {
+	if len(txs) > 0 {
+		p.index[addr] = txs
+		return
+	}
+	delete(p.index, addr)
+	if err := p.reserve(addr, false); err != nil {
+		log.Error("Failed to release private account reservation", "address", addr, "err", err)
+	}
+}
+
+// Has implements txpool.SubPool. Private transactions must not leak to the
+// network, and the pool-wide Has and Get serve the transaction exchange with
+// peers, so they are never reported as known.
+func (p *PrivatePool) Has(hash common.Hash) bool {
+	return false
+}
+
+// Get implements txpool.SubPool. Private transactions are never handed out, see Has.
+func (p *PrivatePool) Get(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+// get returns a private transaction if it is contained in the pool, or nil
+// otherwise.
+func (p *PrivatePool) get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if ptx := p.lookup[hash]; ptx != nil {
+		return ptx.tx
+	}
+	return nil
+}
+
+// Add implements txpool.SubPool. Public transactions are never routed to the
+// private pool, so all of them are rejected.
+func (p *PrivatePool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range txs {
+		errs[i] = core.ErrTxTypeNotSupported
+	}
+	return errs
+}
+
+// Pending retrieves all currently executable private transactions, grouped by
+// origin account and sorted by nonce. Private transactions are only returned if
+// the filter allows them to be, they must never leak into announcements.
+func (p *PrivatePool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
+	if filter.OnlyBlobTxs || filter.NoPrivateTxs {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	pending := make(map[common.Address][]*txpool.LazyTransaction, len(p.index))
+	for addr, txs := range p.index {
+		lazies := make([]*txpool.LazyTransaction, len(txs))
+		for i, ptx := range txs {
+			lazies[i] = &txpool.LazyTransaction{
+				Pool:      p,
+				Hash:      ptx.tx.Hash(),
+				Tx:        ptx.tx,
+				Time:      ptx.tx.Time(),
+				GasFeeCap: uint256.MustFromBig(ptx.tx.GasFeeCap()),
+				GasTipCap: uint256.MustFromBig(ptx.tx.GasTipCap()),
+				Gas:       ptx.tx.Gas(),
+			}
+		}
+		pending[addr] = lazies
+	}
+	return pending
+}
+
+// SubscribeTransactions registers a subscription for new transaction events.
+// Private transactions are never announced, so no events are ever delivered.
+func (p *PrivatePool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// Nonce returns the next nonce of an account, with all private transactions
+// of the account applied on top of the current head.
+func (p *PrivatePool) Nonce(addr common.Address) uint64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if txs := p.index[addr]; len(txs) > 0 {
+		return txs[0].tx.Nonce() + uint64(len(txs))
+	}
+	return p.state.GetNonce(addr)
+}
+
+// Stats implements txpool.SubPool. Private transactions are not part of the
+// public pending set and are not counted.
+func (p *PrivatePool) Stats() (int, int) {
+	return 0, 0
+}
+
+// Content implements txpool.SubPool, private transactions are not exposed.
+func (p *PrivatePool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return make(map[common.Address][]*types.Transaction), make(map[common.Address][]*types.Transaction)
+}
+
+// ContentFrom implements txpool.SubPool, private transactions are not exposed.
+func (p *PrivatePool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return []*types.Transaction{}, []*types.Transaction{}
+}
+
+// Locals implements txpool.SubPool, the private pool does not track locals.
+func (p *PrivatePool) Locals() []common.Address {
+	return []common.Address{}
+}
+
+// Status returns the known status (unknown/pending) of a transaction identified
+// by its hash. Private transactions are reported as pending.
+func (p *PrivatePool) Status(hash common.Hash) txpool.TxStatus {
+	if p.get(hash) != nil {
+		return txpool.TxStatusPending
+	}
+	return txpool.TxStatusUnknown
+}
@@ -0,0 +1,257 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package privatepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	config  *params.ChainConfig
+	head    *types.Header
+	statedb *state.StateDB
+}
+
+func newTestBlockChain() *testBlockChain {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return &testBlockChain{
+		config:  params.TestChainConfig,
+		head:    &types.Header{Number: big.NewInt(10), GasLimit: 10_000_000},
+		statedb: statedb,
+	}
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig { return bc.config }
+
+func (bc *testBlockChain) CurrentBlock() *types.Header { return bc.head }
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) { return bc.statedb, nil }
+
+// setHead moves the mock chain to the given block number.
+func (bc *testBlockChain) setHead(number int64) *types.Header {
+	bc.head = &types.Header{Number: big.NewInt(number), GasLimit: bc.head.GasLimit}
+	return bc.head
+}
+
+// testReserver is an address reserver tracking the accounts claimed by the pool,
+// with the option of simulating accounts owned by some other subpool.
+type testReserver struct {
+	owned map[common.Address]bool // Accounts reserved by the pool under test
+	taken map[common.Address]bool // Accounts reserved by other subpools
+}
+
+func (r *testReserver) reserve(addr common.Address, reserve bool) error {
+	if r.taken[addr] {
+		return txpool.ErrAlreadyReserved
+	}
+	if reserve {
+		r.owned[addr] = true
+	} else {
+		delete(r.owned, addr)
+	}
+	return nil
+}
+
+func newTestPool(t *testing.T, config Config) (*PrivatePool, *testBlockChain, *testReserver) {
+	var (
+		chain    = newTestBlockChain()
+		pool     = New(config, chain)
+		reserver = &testReserver{owned: make(map[common.Address]bool), taken: make(map[common.Address]bool)}
+	)
+	if err := pool.Init(0, chain.head, reserver.reserve); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	return pool, chain, reserver
+}
+
+func newAccount(chain *testBlockChain) (*ecdsa.PrivateKey, common.Address) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chain.statedb.SetBalance(addr, uint256.NewInt(params.Ether))
+	return key, addr
+}
+
+func pricedTransfer(nonce uint64, gasPrice int64, key *ecdsa.PrivateKey) *types.Transaction {
+	to := common.Address{0xbb}
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(gasPrice),
+	})
+}
+
+func transfer(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	return pricedTransfer(nonce, params.InitialBaseFee, key)
+}
+
+func TestAddPrivateValidation(t *testing.T) {
+	pool, chain, reserver := newTestPool(t, Config{MaxTxs: 4, AccountSlots: 3, MaxBlocks: 10})
+
+	key, addr := newAccount(chain)
+	poor, _ := crypto.GenerateKey()
+	other, otherAddr := newAccount(chain)
+	chain.statedb.SetNonce(addr, 5)
+	reserver.taken[otherAddr] = true
+
+	tests := []struct {
+		tx       *types.Transaction
+		maxBlock uint64
+		err      error
+	}{
+		{transfer(5, key), 10, ErrTxExpired},
+		{transfer(5, key), 21, ErrInvalidMaxBlock},
+		{transfer(4, key), 0, core.ErrNonceTooLow},
+		{transfer(6, key), 0, core.ErrNonceTooHigh},
+		{transfer(0, poor), 0, core.ErrInsufficientFunds},
+		{transfer(0, other), 0, txpool.ErrAlreadyReserved},
+		{transfer(5, key), 20, nil},
+		{transfer(5, key), 20, txpool.ErrAlreadyKnown},
+		{pricedTransfer(5, params.InitialBaseFee+1, key), 0, txpool.ErrReplaceUnderpriced},
+		{pricedTransfer(5, 2*params.InitialBaseFee, key), 0, nil},
+		{transfer(6, key), 0, nil},
+		{transfer(7, key), 0, nil},
+		{transfer(8, key), 0, txpool.ErrAccountLimitExceeded},
+	}
+	for i, tt := range tests {
+		if err := pool.AddPrivate(tt.tx, tt.maxBlock); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if !reserver.owned[addr] {
+		t.Error("sender of private transactions not reserved")
+	}
+	if nonce := pool.Nonce(addr); nonce != 8 {
+		t.Errorf("pool nonce mismatch: have %d, want 8", nonce)
+	}
+	// The pool limit is shared by all accounts
+	key2, _ := newAccount(chain)
+	if err := pool.AddPrivate(transfer(0, key2), 0); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.AddPrivate(transfer(1, key2), 0); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("pool limit error mismatch: have %v, want %v", err, ErrPoolFull)
+	}
+}
+
+func TestPrivateTxsNotAnnounced(t *testing.T) {
+	pool, chain, _ := newTestPool(t, DefaultConfig)
+
+	key, addr := newAccount(chain)
+	tx := transfer(0, key)
+	if err := pool.AddPrivate(tx, 0); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// Private transactions are handed to the miner...
+	if pending := pool.Pending(txpool.PendingFilter{}); len(pending[addr]) != 1 {
+		t.Errorf("private transaction not executable: %v", pending)
+	}
+	if pool.get(tx.Hash()) == nil || pool.Status(tx.Hash()) != txpool.TxStatusPending {
+		t.Error("private transaction not tracked")
+	}
+	// ...but never to anything announcing or listing them
+	if pending := pool.Pending(txpool.PendingFilter{OnlyPlainTxs: true, NoPrivateTxs: true}); len(pending) != 0 {
+		t.Errorf("private transaction exposed for announcement: %v", pending)
+	}
+	if pending, queued := pool.Content(); len(pending) != 0 || len(queued) != 0 {
+		t.Error("private transaction exposed in pool content")
+	}
+	if pool.Has(tx.Hash()) || pool.Get(tx.Hash()) != nil {
+		t.Error("private transaction exposed through pool lookups")
+	}
+	if pool.Filter(tx) {
+		t.Error("private pool accepts public transactions")
+	}
+	if errs := pool.Add([]*types.Transaction{tx}, true, true); errs[0] == nil {
+		t.Error("private pool accepted public transaction")
+	}
+}
+
+func TestPrivateTxLifecycle(t *testing.T) {
+	pool, chain, reserver := newTestPool(t, DefaultConfig)
+
+	var (
+		key, addr   = newAccount(chain)
+		key2, addr2 = newAccount(chain)
+	)
+	for _, add := range []struct {
+		tx       *types.Transaction
+		maxBlock uint64
+	}{
+		{transfer(0, key), 0}, {transfer(1, key), 12}, {transfer(2, key), 0},
+		{transfer(0, key2), 0}, {transfer(1, key2), 0}, {transfer(2, key2), 0},
+	} {
+		if err := pool.AddPrivate(add.tx, add.maxBlock); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	// Including the first transaction drops it from the pool
+	chain.statedb.SetNonce(addr, 1)
+	pool.Reset(chain.head, chain.setHead(11))
+	if have := len(pool.Pending(txpool.PendingFilter{})[addr]); have != 2 {
+		t.Fatalf("pending transactions after inclusion: have %d, want 2", have)
+	}
+	// Passing the deadline of the next one also drops all depending on it
+	pool.Reset(chain.head, chain.setHead(12))
+	if pending := pool.Pending(txpool.PendingFilter{}); len(pending[addr]) != 0 {
+		t.Fatalf("pending transactions after expiry: have %d, want 0", len(pending[addr]))
+	}
+	if reserver.owned[addr] {
+		t.Error("reservation not released after expiry")
+	}
+	// Only the sender may cancel a transaction
+	second := transfer(1, key2)
+	if err := pool.CancelPrivate(second.Hash(), addr); !errors.Is(err, ErrCancelNotSender) {
+		t.Fatalf("foreign cancel error mismatch: have %v, want %v", err, ErrCancelNotSender)
+	}
+	if have := len(pool.Pending(txpool.PendingFilter{})[addr2]); have != 3 {
+		t.Fatalf("pending transactions after foreign cancel: have %d, want 3", have)
+	}
+	// Cancelling a transaction drops all depending on it
+	if err := pool.CancelPrivate(second.Hash(), addr2); err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if have := len(pool.Pending(txpool.PendingFilter{})[addr2]); have != 1 {
+		t.Fatalf("pending transactions after cancel: have %d, want 1", have)
+	}
+	if nonce := pool.Nonce(addr2); nonce != 1 {
+		t.Errorf("pool nonce after cancel: have %d, want 1", nonce)
+	}
+	if err := pool.CancelPrivate(second.Hash(), addr2); !errors.Is(err, ErrUnknownTx) {
+		t.Errorf("repeated cancel error mismatch: have %v, want %v", err, ErrUnknownTx)
+	}
+	if err := pool.CancelPrivate(transfer(0, key2).Hash(), addr2); err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if len(reserver.owned) != 0 {
+		t.Errorf("reservations left after cancelling everything: %v", reserver.owned)
+	}
+}
@@ -17,12 +17,15 @@
 package txpool
 
 import (
+	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/holiman/uint256"
 )
@@ -83,6 +86,7 @@ type PendingFilter struct {
 
 	OnlyPlainTxs bool // Return only plain EVM transactions (peer-join announces, block space filling)
 	OnlyBlobTxs  bool // Return only blob transactions (block blob-space filling)
+	NoPrivateTxs bool // Skip private transactions which must never be announced (peer-join announces, RPC listings)
 }
 
 // SubPool represents a specialized transaction pool that lives on its own (e.g.
@@ -163,3 +167,180 @@ type SubPool interface {
 	// identified by their hashes.
 	Status(hash common.Hash) TxStatus
 }
+
+// PeerLimitedPool is a subpool which limits the rate transactions are admitted
+// at from individual remote peers.
+type PeerLimitedPool interface {
+	SubPool
+
+	// AddFromPeer enqueues a batch of remote transactions relayed by the given
+	// peer if they are valid and the peer did not exceed its admission rate.
+	AddFromPeer(peer string, txs []*types.Transaction) []error
+}
+
+// Bundle is an ordered list of transactions which must be included into a block
+// together, in the given order, or not at all.
+type Bundle struct {
+	Txs          types.Transactions // Transactions of the bundle, in execution order
+	MinBlock     uint64             // First block number the bundle may be included in (0 = no lower bound)
+	MaxBlock     uint64             // Last block number the bundle may be included in
+	RevertingTxs []common.Hash      // Transactions allowed to revert without invalidating the bundle
+
+	Time time.Time // Time when the bundle was first seen
+}
+
+// Hash returns the identifier of the bundle, the hash of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// Eligible returns whether the bundle may be included in the block with the given
+// number.
+func (b *Bundle) Eligible(number uint64) bool {
+	return number >= b.MinBlock && number <= b.MaxBlock
+}
+
+// CanRevert returns whether the transaction with the given hash is allowed to
+// revert without invalidating the bundle.
+func (b *Bundle) CanRevert(hash common.Hash) bool {
+	for _, h := range b.RevertingTxs {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// BundlePool is a subpool which, besides plain transactions, also maintains
+// bundles of transactions that need to be included atomically.
+type BundlePool interface {
+	SubPool
+
+	// AddBundle enqueues a bundle into the pool if it is valid.
+	AddBundle(bundle *Bundle) error
+
+	// PendingBundles retrieves all bundles that may be included in the block with
+	// the given number, ordered by arrival time.
+	PendingBundles(number uint64) []*Bundle
+}
+
+// TxConditions are the constraints a conditional transaction places on the block
+// including it. Unset bounds are not checked.
+type TxConditions struct {
+	BlockNumberMin *uint64 // First block number the transaction may be included in
+	BlockNumberMax *uint64 // Last block number the transaction may be included in
+	TimestampMin   *uint64 // Earliest block timestamp the transaction may be included at
+	TimestampMax   *uint64 // Latest block timestamp the transaction may be included at
+
+	// KnownAccounts are the storage slot values the state must hold right before
+	// the transaction is executed.
+	KnownAccounts map[common.Address]map[common.Hash]common.Hash
+}
+
+// Slots returns the number of storage slots the conditions check.
+func (c *TxConditions) Slots() int {
+	var slots int
+	for _, storage := range c.KnownAccounts {
+		slots += len(storage)
+	}
+	return slots
+}
+
+// CheckBlock returns whether a transaction with the conditions may be included
+// in a block with the given header.
+func (c *TxConditions) CheckBlock(header *types.Header) error {
+	number := header.Number.Uint64()
+	switch {
+	case c.BlockNumberMin != nil && number < *c.BlockNumberMin:
+		return fmt.Errorf("%w: block number %d before %d", ErrConditionsNotMet, number, *c.BlockNumberMin)
+	case c.BlockNumberMax != nil && number > *c.BlockNumberMax:
+		return fmt.Errorf("%w: block number %d after %d", ErrConditionsNotMet, number, *c.BlockNumberMax)
+	case c.TimestampMin != nil && header.Time < *c.TimestampMin:
+		return fmt.Errorf("%w: timestamp %d before %d", ErrConditionsNotMet, header.Time, *c.TimestampMin)
+	case c.TimestampMax != nil && header.Time > *c.TimestampMax:
+		return fmt.Errorf("%w: timestamp %d after %d", ErrConditionsNotMet, header.Time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckState returns whether the given state holds all the storage slot values
+// the conditions require.
+func (c *TxConditions) CheckState(statedb *state.StateDB) error {
+	for addr, storage := range c.KnownAccounts {
+		for slot, want := range storage {
+			if have := statedb.GetState(addr, slot); have != want {
+				return fmt.Errorf("%w: account %v slot %v is %v, want %v", ErrConditionsNotMet, addr, slot, have, want)
+			}
+		}
+	}
+	return nil
+}
+
+// Expired returns whether a transaction with the conditions can no longer be
+// included in any block after the given head, and why.
+func (c *TxConditions) Expired(head *types.Header) (bool, string) {
+	if c.BlockNumberMax != nil && *c.BlockNumberMax <= head.Number.Uint64() {
+		return true, fmt.Sprintf("expired: block number max %d reached", *c.BlockNumberMax)
+	}
+	if c.TimestampMax != nil && *c.TimestampMax <= head.Time {
+		return true, fmt.Sprintf("expired: timestamp max %d reached", *c.TimestampMax)
+	}
+	return false, ""
+}
+
+// ConditionalTx is a transaction along with the conditions of its inclusion.
+type ConditionalTx struct {
+	Tx         *types.Transaction
+	Conditions *TxConditions
+
+	Time time.Time // Time when the transaction was first seen
+}
+
+// PrivatePool is a subpool maintaining private transactions: transactions which
+// are only ever handed to the local miner and to trusted validators, but never
+// announced to the network.
+type PrivatePool interface {
+	SubPool
+
+	// AddPrivate enqueues a private transaction into the pool if it is valid. The
+	// transaction is dropped if not included until the given block number.
+	AddPrivate(tx *types.Transaction, maxBlock uint64) error
+
+	// CancelPrivate drops a private transaction, along with all the transactions
+	// of the same sender depending on it, from the pool. The cancellation must be
+	// issued by the sender of the transaction.
+	CancelPrivate(hash common.Hash, from common.Address) error
+}
+
+// TrackingPool is a subpool recording the lifecycle of the transactions it
+// handles, from reception until inclusion or removal.
+type TrackingPool interface {
+	SubPool
+
+	// Lifecycle returns the recorded lifecycle steps of a transaction, oldest
+	// first, or nil if the transaction is unknown.
+	Lifecycle(hash common.Hash) []TxLifecycleEvent
+
+	// SubscribeLifecycle subscribes to the lifecycle steps of all transactions.
+	SubscribeLifecycle(ch chan<- TxLifecycleEvent) event.Subscription
+}
+
+// ConditionalPool is a subpool maintaining conditional transactions: transactions
+// which may only be included in blocks, and on top of states, satisfying their
+// conditions. They are kept out of the pending set and checked by the miner.
+type ConditionalPool interface {
+	SubPool
+
+	// AddConditional enqueues a conditional transaction into the pool if it is
+	// valid and its conditions can still be met.
+	AddConditional(tx *types.Transaction, conditions *TxConditions) error
+
+	// PendingConditional retrieves all conditional transactions which may be
+	// included in a block with the given header, sorted by nonce per account.
+	// The state conditions are left for the caller to check.
+	PendingConditional(header *types.Header) []*ConditionalTx
+}
@@ -309,6 +309,26 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
 func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	return p.add(txs, func(subpool SubPool, txs []*types.Transaction) []error {
+		return subpool.Add(txs, local, sync)
+	})
+}
+
+// AddFromPeer enqueues a batch of remote transactions relayed by the given peer.
+// Subpools limiting the admission rate of peers charge the transactions to it,
+// the others treat them as any other remote transactions.
+func (p *TxPool) AddFromPeer(peer string, txs []*types.Transaction) []error {
+	return p.add(txs, func(subpool SubPool, txs []*types.Transaction) []error {
+		if pool, ok := subpool.(PeerLimitedPool); ok {
+			return pool.AddFromPeer(peer, txs)
+		}
+		return subpool.Add(txs, false, false)
+	})
+}
+
+// add splits a batch of transactions between the subpools accepting them and
+// inserts them using the given method.
+func (p *TxPool) add(txs []*types.Transaction, insert func(SubPool, []*types.Transaction) []error) []error {
 	// Split the input transactions between the subpools. It shouldn't really
 	// happen that we receive merged batches, but better graceful than strange
 	// errors.
@@ -335,7 +355,7 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	// back the errors into the original sort order.
 	errsets := make([][]error, len(p.subpools))
 	for i := 0; i < len(p.subpools); i++ {
-		errsets[i] = p.subpools[i].Add(txsets[i], local, sync)
+		errsets[i] = insert(p.subpools[i], txsets[i])
 	}
 	errs := make([]error, len(txs))
 	for i, split := range splits {
@@ -351,6 +371,104 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	return errs
 }
 
+// AddBundle enqueues a transaction bundle into the subpool handling bundles.
+func (p *TxPool) AddBundle(bundle *Bundle) error {
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(BundlePool); ok {
+			return pool.AddBundle(bundle)
+		}
+	}
+	return ErrBundlesUnsupported
+}
+
+// PendingBundles retrieves all bundles that may be included in the block with
+// the given number.
+func (p *TxPool) PendingBundles(number uint64) []*Bundle {
+	var bundles []*Bundle
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(BundlePool); ok {
+			bundles = append(bundles, pool.PendingBundles(number)...)
+		}
+	}
+	return bundles
+}
+
+// AddPrivate enqueues a private transaction into the subpool handling private
+// transactions. Transactions already known publicly are rejected.
+func (p *TxPool) AddPrivate(tx *types.Transaction, maxBlock uint64) error {
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(PrivatePool); ok {
+			if p.Has(tx.Hash()) {
+				return ErrAlreadyKnown
+			}
+			return pool.AddPrivate(tx, maxBlock)
+		}
+	}
+	return ErrPrivateTxsUnsupported
+}
+
+// CancelPrivate drops a private transaction on behalf of its sender from the
+// subpool handling private transactions.
+func (p *TxPool) CancelPrivate(hash common.Hash, from common.Address) error {
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(PrivatePool); ok {
+			return pool.CancelPrivate(hash, from)
+		}
+	}
+	return ErrPrivateTxsUnsupported
+}
+
+// AddConditional enqueues a conditional transaction into the subpool handling
+// conditional transactions. Transactions already known are rejected.
+func (p *TxPool) AddConditional(tx *types.Transaction, conditions *TxConditions) error {
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(ConditionalPool); ok {
+			if p.Has(tx.Hash()) {
+				return ErrAlreadyKnown
+			}
+			return pool.AddConditional(tx, conditions)
+		}
+	}
+	return ErrConditionalTxsUnsupported
+}
+
+// PendingConditional retrieves all conditional transactions which may be
+// included in a block with the given header.
+func (p *TxPool) PendingConditional(header *types.Header) []*ConditionalTx {
+	var txs []*ConditionalTx
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(ConditionalPool); ok {
+			txs = append(txs, pool.PendingConditional(header)...)
+		}
+	}
+	return txs
+}
+
+// Lifecycle returns the recorded lifecycle steps of a transaction, oldest first,
+// from the subpool which handled it.
+func (p *TxPool) Lifecycle(hash common.Hash) []TxLifecycleEvent {
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(TrackingPool); ok {
+			if steps := pool.Lifecycle(hash); len(steps) > 0 {
+				return steps
+			}
+		}
+	}
+	return nil
+}
+
+// SubscribeLifecycle registers a subscription for the lifecycle steps of the
+// transactions handled by all the tracking subpools.
+func (p *TxPool) SubscribeLifecycle(ch chan<- TxLifecycleEvent) event.Subscription {
+	var subs []event.Subscription
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(TrackingPool); ok {
+			subs = append(subs, pool.SubscribeLifecycle(ch))
+		}
+	}
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
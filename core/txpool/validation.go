@@ -72,6 +72,9 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !opts.Config.IsCancun(head.Number, head.Time) && tx.Type() == types.BlobTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Cancun", core.ErrTxTypeNotSupported, tx.Type())
 	}
+	if !opts.Config.IsFeePayer(head.Number) && tx.Type() == types.FeePayerTxType {
+		return fmt.Errorf("%w: type %d rejected, pool not yet in fee payer fork", core.ErrTxTypeNotSupported, tx.Type())
+	}
 	// Check whether the init code size has been exceeded
 	if opts.Config.IsShanghai(head.Number, head.Time) && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
 		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
@@ -100,6 +103,11 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if _, err := types.Sender(signer, tx); err != nil {
 		return ErrInvalidSender
 	}
+	if tx.Type() == types.FeePayerTxType {
+		if _, err := types.FeePayer(signer, tx); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidFeePayer, err)
+		}
+	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
 	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, opts.Config.IsIstanbul(head.Number), opts.Config.IsShanghai(head.Number, head.Time))
@@ -191,6 +199,13 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	// ExistingSponsorship is an optional callback to retrieve the cumulative
+	// fees of the already pooled transactions paid for by a fee payer, minus
+	// the fee of the sender's transaction with the given nonce, if that one is
+	// being replaced and sponsored by the same payer. If this method is not set,
+	// only the fee of the transaction itself is checked against the payer.
+	ExistingSponsorship func(payer common.Address, from common.Address, nonce uint64) *big.Int
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -216,10 +231,28 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 			return fmt.Errorf("%w: tx nonce %v, gapped nonce %v", core.ErrNonceTooHigh, tx.Nonce(), gap)
 		}
 	}
+	// Ensure the fee payer, if any, has enough funds to cover the gas of this
+	// and all other pooled transactions it sponsors
+	if payer := tx.FeePayer(); payer != nil {
+		var (
+			balance = opts.State.GetBalance(*payer).ToBig()
+			fee     = new(big.Int).Sub(tx.Cost(), tx.SenderCost())
+		)
+		if balance.Cmp(fee) < 0 {
+			return fmt.Errorf("%w: fee payer %v balance %v, tx fee %v, overshot %v", core.ErrInsufficientFunds, *payer, balance, fee, new(big.Int).Sub(fee, balance))
+		}
+		if opts.ExistingSponsorship != nil {
+			sponsored := opts.ExistingSponsorship(*payer, from, tx.Nonce())
+			need := new(big.Int).Add(sponsored, fee)
+			if balance.Cmp(need) < 0 {
+				return fmt.Errorf("%w: fee payer %v balance %v, sponsored fees %v, tx fee %v, overshot %v", core.ErrInsufficientFunds, *payer, balance, sponsored, fee, new(big.Int).Sub(need, balance))
+			}
+		}
+	}
 	// Ensure the transactor has enough funds to cover the transaction costs
 	var (
 		balance = opts.State.GetBalance(from).ToBig()
-		cost    = tx.Cost()
+		cost    = tx.SenderCost()
 	)
 	if balance.Cmp(cost) < 0 {
 		return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
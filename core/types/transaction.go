@@ -33,6 +33,7 @@ import (
 
 var (
 	ErrInvalidSig           = errors.New("invalid transaction v, r, s values")
+	ErrInvalidFeePayer      = errors.New("fee payer signature does not match fee payer")
 	ErrUnexpectedProtection = errors.New("transaction type does not supported EIP-155 protected signatures")
 	ErrInvalidTxType        = errors.New("transaction type not valid in this context")
 	ErrTxTypeNotSupported   = errors.New("transaction type not supported")
@@ -49,6 +50,7 @@ const (
 	AccessListTxType = 0x01
 	DynamicFeeTxType = 0x02
 	BlobTxType       = 0x03
+	FeePayerTxType   = 0x07
 )
 
 // Transaction is an Ethereum transaction.
@@ -58,8 +60,9 @@ type Transaction struct {
 
 	// caches
 	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	size  atomic.Value
+	from  atomic.Value
+	payer atomic.Value
 }
 
 // NewTx creates a new transaction.
@@ -71,7 +74,7 @@ func NewTx(inner TxData) *Transaction {
 
 // TxData is the underlying data of a transaction.
 //
-// This is implemented by DynamicFeeTx, LegacyTx and AccessListTx.
+// This is implemented by DynamicFeeTx, LegacyTx, AccessListTx, BlobTx and FeePayerTx.
 type TxData interface {
 	txType() byte // returns the type ID
 	copy() TxData // creates a deep copy and initializes all fields
@@ -206,6 +209,8 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(DynamicFeeTx)
 	case BlobTxType:
 		inner = new(BlobTx)
+	case FeePayerTxType:
+		inner = new(FeePayerTx)
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -319,6 +324,34 @@ func (tx *Transaction) Cost() *big.Int {
 	return total
 }
 
+// SenderCost returns the part of Cost charged to the sender. For fee payer
+// transactions this is only the value, the gas being charged to the fee payer.
+func (tx *Transaction) SenderCost() *big.Int {
+	if tx.Type() == FeePayerTxType {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
+// FeePayer returns the account charged for the gas of fee payer transactions,
+// nil for all other transactions.
+func (tx *Transaction) FeePayer() *common.Address {
+	if fptx, ok := tx.inner.(*FeePayerTx); ok {
+		return copyAddressPtr(&fptx.FeePayer)
+	}
+	return nil
+}
+
+// RawFeePayerSignatureValues returns the V, R, S signature values of the fee
+// payer for fee payer transactions, nil for all other transactions.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RawFeePayerSignatureValues() (v, r, s *big.Int) {
+	if fptx, ok := tx.inner.(*FeePayerTx); ok {
+		return fptx.rawFeePayerSignatureValues()
+	}
+	return nil, nil, nil
+}
+
 // RawSignatureValues returns the V, R, S signature values of the transaction.
 // The return values should not be modified by the caller.
 // The return values may be nil or zero, if the transaction is unsigned.
@@ -518,6 +551,23 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	return &Transaction{inner: cpy, time: tx.time}, nil
 }
 
+// WithFeePayerSignature returns a new fee payer transaction with the given fee
+// payer signature. This signature needs to be in the [R || S || V] format where
+// V is 0 or 1. The sender must have signed the transaction beforehand.
+func (tx *Transaction) WithFeePayerSignature(signer Signer, sig []byte) (*Transaction, error) {
+	fs, ok := signer.(feePayerSigner)
+	if !ok || tx.Type() != FeePayerTxType {
+		return nil, ErrTxTypeNotSupported
+	}
+	r, s, v, err := fs.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, err
+	}
+	cpy := tx.inner.copy().(*FeePayerTx)
+	cpy.setFeePayerSignatureValues(v, r, s)
+	return &Transaction{inner: cpy, time: tx.time}, nil
+}
+
 // Transactions implements DerivableList for transactions.
 type Transactions []*Transaction
 
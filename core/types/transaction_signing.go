@@ -53,6 +53,9 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 	default:
 		signer = FrontierSigner{}
 	}
+	if config.ChainID != nil && config.IsFeePayer(blockNumber) {
+		signer = NewFeePayerSigner(signer)
+	}
 	return signer
 }
 
@@ -64,6 +67,16 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 // Use this in transaction-handling code where the current block number is unknown. If you
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config *params.ChainConfig) Signer {
+	signer := latestForkSigner(config)
+	if config.ChainID != nil && config.FeePayerBlock != nil {
+		signer = NewFeePayerSigner(signer)
+	}
+	return signer
+}
+
+// latestForkSigner returns the 'most permissive' Signer of the upstream forks
+// scheduled in the given chain configuration.
+func latestForkSigner(config *params.ChainConfig) Signer {
 	if config.ChainID != nil {
 		if config.CancunTime != nil {
 			return NewCancunSigner(config.ChainID)
@@ -92,7 +105,7 @@ func LatestSignerForChainID(chainID *big.Int) Signer {
 	if chainID == nil {
 		return HomesteadSigner{}
 	}
-	return NewCancunSigner(chainID)
+	return NewFeePayerSigner(NewCancunSigner(chainID))
 }
 
 // SignTx signs the transaction using the given signer and private key.
@@ -126,6 +139,61 @@ func MustSignNewTx(prv *ecdsa.PrivateKey, s Signer, txdata TxData) *Transaction
 	return tx
 }
 
+// SignFeePayerTx signs the transaction as its fee payer using the given signer
+// and private key. The transaction must already be signed by the sender.
+func SignFeePayerTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h, err := FeePayerHash(s, tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithFeePayerSignature(s, sig)
+}
+
+// FeePayerHash returns the hash to be signed by the fee payer of a transaction,
+// covering the sender signed payload along with the sender signature.
+func FeePayerHash(signer Signer, tx *Transaction) (common.Hash, error) {
+	fs, ok := signer.(feePayerSigner)
+	if !ok || tx.Type() != FeePayerTxType {
+		return common.Hash{}, ErrTxTypeNotSupported
+	}
+	return fs.feePayerHash(tx), nil
+}
+
+// FeePayer returns the address derived from the fee payer signature of a fee
+// payer transaction. An error is returned if the signature is invalid or if it
+// was not made by the fee payer declared in the transaction.
+//
+// FeePayer may cache the address, the same way Sender does.
+func FeePayer(signer Signer, tx *Transaction) (common.Address, error) {
+	if sc := tx.payer.Load(); sc != nil {
+		sigCache := sc.(sigCache)
+		if sigCache.signer.Equal(signer) {
+			return sigCache.from, nil
+		}
+	}
+	fs, ok := signer.(feePayerSigner)
+	if !ok || tx.Type() != FeePayerTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	V, R, S := tx.RawFeePayerSignatureValues()
+	// Fee payer signatures use 0 and 1 as their recovery id, add 27 to become
+	// equivalent to unprotected Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	addr, err := recoverPlain(fs.feePayerHash(tx), R, S, V, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if addr != *tx.FeePayer() {
+		return common.Address{}, fmt.Errorf("%w: have %x want %x", ErrInvalidFeePayer, addr, *tx.FeePayer())
+	}
+	tx.payer.Store(sigCache{signer: signer, from: addr})
+	return addr, nil
+}
+
 // Sender returns the address derived from the signature (V, R, S) using secp256k1
 // elliptic curve and an error if it failed deriving or upon an incorrect
 // signature.
@@ -175,6 +243,91 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+type feePayerSigner struct{ Signer }
+
+// NewFeePayerSigner returns a signer that accepts fee payer transactions on top
+// of all the transactions accepted by the given replay protected signer.
+func NewFeePayerSigner(signer Signer) Signer {
+	return feePayerSigner{signer}
+}
+
+func (s feePayerSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != FeePayerTxType {
+		return s.Signer.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Fee payer txs are defined to use 0 and 1 as their recovery
+	// id, add 27 to become equivalent to unprotected Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	if tx.ChainId().Cmp(s.ChainID()) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.ChainID())
+	}
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+func (s feePayerSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(feePayerSigner)
+	return ok && x.Signer.Equal(s.Signer)
+}
+
+func (s feePayerSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*FeePayerTx)
+	if !ok {
+		return s.Signer.SignatureValues(tx, sig)
+	}
+	// Check that chain ID of tx matches the signer. We also accept ID zero here,
+	// because it indicates that the chain ID was not specified in the tx.
+	if txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(s.ChainID()) != 0 {
+		return nil, nil, nil, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, txdata.ChainID, s.ChainID())
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s feePayerSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != FeePayerTxType {
+		return s.Signer.Hash(tx)
+	}
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.ChainID(),
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			tx.FeePayer(),
+		})
+}
+
+// feePayerHash returns the hash to be signed by the fee payer, committing to
+// the sender signature too. It does not uniquely identify the transaction.
+func (s feePayerSigner) feePayerHash(tx *Transaction) common.Hash {
+	V, R, S := tx.RawSignatureValues()
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.ChainID(),
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			tx.FeePayer(),
+			V, R, S,
+		})
+}
+
 type cancunSigner struct{ londonSigner }
 
 // NewCancunSigner returns a signer that accepts
@@ -188,3 +188,92 @@ func createTestLegacyTxInner() *LegacyTx {
 		Data:     nil,
 	}
 }
+
+func TestFeePayerSigning(t *testing.T) {
+	var (
+		senderKey, _ = crypto.GenerateKey()
+		payerKey, _  = crypto.GenerateKey()
+		otherKey, _  = crypto.GenerateKey()
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+		payer        = crypto.PubkeyToAddress(payerKey.PublicKey)
+		to           = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		signer       = LatestSignerForChainID(big.NewInt(18))
+	)
+	tx, err := SignNewTx(senderKey, signer, &FeePayerTx{
+		ChainID:   big.NewInt(18),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(5),
+		FeePayer:  payer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a fee payer signature, the payer must not be recoverable
+	if _, err := FeePayer(signer, tx); err == nil {
+		t.Fatal("expected error for missing fee payer signature")
+	}
+	// A signature from anyone but the declared payer must be rejected
+	forged, err := SignFeePayerTx(tx, signer, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FeePayer(signer, forged); !errors.Is(err, ErrInvalidFeePayer) {
+		t.Fatalf("wrong error for forged payer: have %v, want %v", err, ErrInvalidFeePayer)
+	}
+	signed, err := SignFeePayerTx(tx, signer, payerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(name string, tx *Transaction) {
+		t.Helper()
+		if from, err := Sender(signer, tx); err != nil || from != sender {
+			t.Fatalf("%s: sender mismatch: have %x (%v), want %x", name, from, err, sender)
+		}
+		if have, err := FeePayer(signer, tx); err != nil || have != payer {
+			t.Fatalf("%s: fee payer mismatch: have %x (%v), want %x", name, have, err, payer)
+		}
+		if have := tx.SenderCost(); have.Cmp(big.NewInt(5)) != 0 {
+			t.Fatalf("%s: sender cost mismatch: have %v, want 5", name, have)
+		}
+		if have, want := tx.Cost(), big.NewInt(21000*10+5); have.Cmp(want) != 0 {
+			t.Fatalf("%s: cost mismatch: have %v, want %v", name, have, want)
+		}
+	}
+	check("signed", signed)
+
+	// The fee payer signature must survive both the binary and JSON encodings
+	blob, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Transaction
+	if err := dec.UnmarshalBinary(blob); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != signed.Hash() {
+		t.Fatalf("binary hash mismatch: have %x, want %x", dec.Hash(), signed.Hash())
+	}
+	check("binary", &dec)
+
+	js, err := signed.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decJSON Transaction
+	if err := decJSON.UnmarshalJSON(js); err != nil {
+		t.Fatal(err)
+	}
+	if decJSON.Hash() != signed.Hash() {
+		t.Fatalf("json hash mismatch: have %x, want %x", decJSON.Hash(), signed.Hash())
+	}
+	check("json", &decJSON)
+
+	// Signers without fee payer support must reject the transaction type
+	if _, err := Sender(NewCancunSigner(big.NewInt(18)), &dec); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("wrong error from plain signer: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+}
@@ -203,7 +203,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.stateAt(header.Root)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -225,7 +225,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if blockNrOrHash.RequireCanonical && b.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != hash {
 			return nil, nil, errors.New("hash is not currently canonical")
 		}
-		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+		stateDb, err := b.stateAt(header.Root)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -234,6 +234,19 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// stateAt returns the state with the given root. With the path scheme, states
+// which have left the in-memory layers are served from the state histories.
+func (b *EthAPIBackend) stateAt(root common.Hash) (*state.StateDB, error) {
+	stateDb, err := b.eth.BlockChain().StateAt(root)
+	if err == nil || b.eth.BlockChain().TrieDB().Scheme() != rawdb.PathScheme {
+		return stateDb, err
+	}
+	if historic, herr := b.eth.BlockChain().HistoricState(root); herr == nil {
+		return historic, nil
+	}
+	return nil, err
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
@@ -301,31 +314,11 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	}
 	
 	// ============= THÊM KIỂM TRA BẢO MẬT =============
-	// Kiểm tra blacklist
-	if core.GetSecurityConfig().IsBlacklisted(from) {
-		log.Debug("Rejected blacklisted transaction via API", "hash", signedTx.Hash(), "from", from)
-		return core.ErrBlacklistedAddress
-	}
-	
-	// Kiểm tra frozen accounts
-	if core.GetSecurityConfig().IsFrozen(from) {
-		log.Debug("Rejected transaction from frozen account via API", "hash", signedTx.Hash(), "from", from)
-		return core.ErrFrozenAccount
-	}
-	
-	// Kiểm tra contract destination nếu là frozen contract
-	if signedTx.To() != nil && core.GetSecurityConfig().IsContractFrozen(*signedTx.To()) {
-		log.Debug("Rejected transaction to frozen contract via API", "hash", signedTx.Hash(), "from", from, "to", signedTx.To())
-		return core.ErrFrozenContract
-	}
-	
-	// Kiểm tra whitelist cho contract deployment
-	if signedTx.To() == nil && len(signedTx.Data()) > 0 {
-		// Đây là transaction tạo contract
-		if !core.GetSecurityConfig().IsWhitelisted(from) {
-			log.Debug("Rejected contract deployment from non-whitelisted address via API", "hash", signedTx.Hash(), "from", from)
-			return core.ErrNotWhitelistedForDeploy
-		}
+	head := b.CurrentHeader()
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, signedTx.To(), signedTx.Data(), head); err != nil {
+		log.Debug("Rejected transaction via API", "hash", signedTx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(signedTx.Hash(), from, signedTx.To(), err, core.RejectionPathRPC)
+		return err
 	}
 	// ============= KẾT THÚC KIỂM TRA BẢO MẬT =============
 	
@@ -333,8 +326,36 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
 }
 
+func (b *EthAPIBackend) SendBundle(ctx context.Context, bundle *txpool.Bundle) error {
+	return b.eth.txPool.AddBundle(bundle)
+}
+
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, tx *types.Transaction, maxBlock uint64) error {
+	if err := b.eth.txPool.AddPrivate(tx, maxBlock); err != nil {
+		return err
+	}
+	if b.eth.privateTxs != nil {
+		b.eth.privateTxs.send(tx, maxBlock)
+	}
+	return nil
+}
+
+func (b *EthAPIBackend) SendConditionalTx(ctx context.Context, tx *types.Transaction, conditions *txpool.TxConditions) error {
+	return b.eth.txPool.AddConditional(tx, conditions)
+}
+
+func (b *EthAPIBackend) CancelPrivateTx(ctx context.Context, hash common.Hash, from common.Address, sig []byte) error {
+	if err := b.eth.txPool.CancelPrivate(hash, from); err != nil {
+		return err
+	}
+	if b.eth.privateTxs != nil {
+		b.eth.privateTxs.cancel(hash, sig)
+	}
+	return nil
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
-	pending := b.eth.txPool.Pending(txpool.PendingFilter{})
+	pending := b.eth.txPool.Pending(txpool.PendingFilter{NoPrivateTxs: true})
 	var txs types.Transactions
 	for _, batch := range pending {
 		for _, lazy := range batch {
@@ -371,6 +392,10 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	return true, tx, lookup.BlockHash, lookup.BlockIndex, lookup.Index, nil
 }
 
+func (b *EthAPIBackend) AddressTransactions(ctx context.Context, addr common.Address, cursor []byte, limit int) ([]rawdb.AddressTxEntry, []byte, error) {
+	return b.eth.blockchain.AddressTransactions(addr, cursor, limit)
+}
+
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return b.eth.txPool.Nonce(addr), nil
 }
@@ -395,6 +420,14 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.txPool.SubscribeTransactions(ch, true)
 }
 
+func (b *EthAPIBackend) TxLifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	return b.eth.txPool.Lifecycle(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxLifecycleEvent(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return b.eth.txPool.SubscribeLifecycle(ch)
+}
+
 func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 	prog := b.eth.Downloader().Progress()
 	if txProg, err := b.eth.blockchain.TxIndexProgress(); err == nil {
@@ -455,6 +488,14 @@ func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 	}
 }
 
+func (b *EthAPIBackend) LogIndexStatus() (uint64, bool) {
+	return b.eth.blockchain.LogIndexHead()
+}
+
+func (b *EthAPIBackend) LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	return b.eth.blockchain.LogIndexBlocks(addresses, topics, begin, end)
+}
+
 func (b *EthAPIBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
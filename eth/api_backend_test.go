@@ -0,0 +1,112 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+)
+
+// Tests that with the path scheme, the states which have left the in-memory
+// layers are served from the state histories, and can be executed on across
+// blocks as eth_simulateV1 does.
+func TestStateAndHeaderByNumberHistoric(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.Address{0xaa}
+		gspec     = &core.Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, core.TriesInMemory+8, func(i int, b *core.BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &recipient,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		})
+		b.AddTx(tx)
+	})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.PathScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert chain: %v", n, err)
+	}
+	backend := &EthAPIBackend{eth: &Ethereum{blockchain: chain}}
+
+	// The state of the first block isn't held by the in-memory layers anymore
+	if _, err := chain.StateAt(blocks[0].Root()); err == nil {
+		t.Fatal("state of the first block unexpectedly available")
+	}
+	statedb, header, err := backend.StateAndHeaderByNumber(context.Background(), rpc.BlockNumber(1))
+	if err != nil {
+		t.Fatalf("failed to retrieve historical state: %v", err)
+	}
+	if header.Hash() != blocks[0].Hash() {
+		t.Fatalf("header mismatch: have %x, want %x", header.Hash(), blocks[0].Hash())
+	}
+	if balance := statedb.GetBalance(recipient); balance.Uint64() != 1000 {
+		t.Fatalf("historical balance mismatch: have %v, want 1000", balance)
+	}
+	// Execute on top of the historical state across blocks, which hashes the
+	// state in between
+	statedb.AddBalance(recipient, uint256.NewInt(1))
+	statedb.SetState(recipient, common.Hash{0x01}, common.Hash{0x02})
+	statedb.IntermediateRoot(true)
+
+	statedb.AddBalance(recipient, uint256.NewInt(1))
+	statedb.IntermediateRoot(true)
+	if err := statedb.Error(); err != nil {
+		t.Fatalf("failed to execute on historical state: %v", err)
+	}
+	if balance := statedb.GetBalance(recipient); balance.Uint64() != 1002 {
+		t.Fatalf("modified balance mismatch: have %v, want 1002", balance)
+	}
+	if value := statedb.GetState(recipient, common.Hash{0x01}); value != (common.Hash{0x02}) {
+		t.Fatalf("modified slot mismatch: have %x, want %x", value, common.Hash{0x02})
+	}
+	if _, err := statedb.Commit(1, true); err == nil {
+		t.Fatal("historical state unexpectedly committed")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+const (
+	// defaultTokenPageLimit is the number of entries returned per page of token
+	// transfers or holders if no limit is given.
+	defaultTokenPageLimit = 100
+
+	// maxTokenPageLimit is the maximum number of entries returned per page of
+	// token transfers or holders.
+	maxTokenPageLimit = 1000
+)
+
+// TobeAPI provides an API to access the chain data indexed by this node
+// beyond what the standard eth namespace offers.
+type TobeAPI struct {
+	e *Ethereum
+}
+
+// NewTobeAPI creates a new TobeAPI instance.
+func NewTobeAPI(e *Ethereum) *TobeAPI {
+	return &TobeAPI{e}
+}
+
+// TokenTransferFilter selects the token transfers to retrieve: those of a
+// token, those from or to a holder, or those of a token from or to a holder.
+type TokenTransferFilter struct {
+	Token  *common.Address `json:"token"`
+	Holder *common.Address `json:"holder"`
+}
+
+// Page selects a page of a paginated result: the cursor returned along with
+// the previous page, and the maximum number of entries to return.
+type Page struct {
+	Cursor *hexutil.Bytes  `json:"cursor"`
+	Limit  *hexutil.Uint64 `json:"limit"`
+}
+
+// cursor returns the cursor and limit selected by the page, validating them.
+func (p *Page) cursor() ([]byte, int, error) {
+	if p == nil {
+		return nil, defaultTokenPageLimit, nil
+	}
+	limit := defaultTokenPageLimit
+	if p.Limit != nil {
+		if *p.Limit == 0 || *p.Limit > maxTokenPageLimit {
+			return nil, 0, fmt.Errorf("limit must be between 1 and %d", maxTokenPageLimit)
+		}
+		limit = int(*p.Limit)
+	}
+	var cursor []byte
+	if p.Cursor != nil {
+		cursor = *p.Cursor
+	}
+	return cursor, limit, nil
+}
+
+// TokenTransfer is a single token transfer as returned over RPC.
+type TokenTransfer struct {
+	Token       common.Address `json:"token"`
+	Standard    string         `json:"standard"`
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	ID          *hexutil.Big   `json:"id"`
+	Value       *hexutil.Big   `json:"value"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+	BatchIndex  hexutil.Uint   `json:"batchIndex"`
+}
+
+// TokenTransfers is a page of token transfers, with the cursor of the next page
+// if there are more transfers.
+type TokenTransfers struct {
+	Transfers []*TokenTransfer `json:"transfers"`
+	Next      *hexutil.Bytes   `json:"next"`
+}
+
+// TokenHolder is an account holding a token, along with its balance. ERC-1155
+// balances are reported per token id.
+type TokenHolder struct {
+	Holder  common.Address `json:"holder"`
+	ID      *hexutil.Big   `json:"id,omitempty"`
+	Balance *hexutil.Big   `json:"balance"`
+}
+
+// TokenHolders is a page of token holders, with the cursor of the next page if
+// there are more holders.
+type TokenHolders struct {
+	Holders []*TokenHolder `json:"holders"`
+	Next    *hexutil.Bytes `json:"next"`
+}
+
+// standardNames maps the indexed token standards to their names.
+var standardNames = map[uint16]string{
+	rawdb.TokenERC20:   "ERC20",
+	rawdb.TokenERC721:  "ERC721",
+	rawdb.TokenERC1155: "ERC1155",
+}
+
+// GetTokenTransfers returns the token transfers matching the filter, from the
+// most recent one. The results are paginated: the returned cursor is to be
+// passed in the page to retrieve the next one.
+func (api *TobeAPI) GetTokenTransfers(filter TokenTransferFilter, page *Page) (*TokenTransfers, error) {
+	if filter.Token == nil && filter.Holder == nil {
+		return nil, errors.New("token or holder required")
+	}
+	cursor, limit, err := page.cursor()
+	if err != nil {
+		return nil, err
+	}
+	transfers, next, err := api.e.blockchain.TokenTransfers(filter.Token, filter.Holder, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &TokenTransfers{Transfers: make([]*TokenTransfer, 0, len(transfers))}
+	for _, transfer := range transfers {
+		result.Transfers = append(result.Transfers, &TokenTransfer{
+			Token:       transfer.Token,
+			Standard:    standardNames[transfer.Standard],
+			From:        transfer.From,
+			To:          transfer.To,
+			ID:          (*hexutil.Big)(transfer.ID),
+			Value:       (*hexutil.Big)(transfer.Value),
+			BlockNumber: hexutil.Uint64(transfer.BlockNumber),
+			TxHash:      transfer.TxHash,
+			LogIndex:    hexutil.Uint(transfer.LogIndex),
+			BatchIndex:  hexutil.Uint(transfer.BatchIndex),
+		})
+	}
+	if next != nil {
+		enc := hexutil.Bytes(next)
+		result.Next = &enc
+	}
+	return result, nil
+}
+
+// GetTokenHolders returns the accounts holding the given token along with their
+// balances, as derived from the indexed transfers. The results are paginated:
+// the returned cursor is to be passed in the page to retrieve the next one.
+func (api *TobeAPI) GetTokenHolders(token common.Address, page *Page) (*TokenHolders, error) {
+	cursor, limit, err := page.cursor()
+	if err != nil {
+		return nil, err
+	}
+	holders, next, err := api.e.blockchain.TokenHolders(token, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &TokenHolders{Holders: make([]*TokenHolder, 0, len(holders))}
+	for _, holder := range holders {
+		result.Holders = append(result.Holders, &TokenHolder{
+			Holder:  holder.Holder,
+			ID:      (*hexutil.Big)(holder.ID),
+			Balance: (*hexutil.Big)(holder.Balance),
+		})
+	}
+	if next != nil {
+		enc := hexutil.Bytes(next)
+		result.Next = &enc
+	}
+	return result, nil
+}
@@ -18,6 +18,7 @@
 package eth
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -34,7 +35,10 @@ import (
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
+	"github.com/ethereum/go-ethereum/core/txpool/conditionalpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/txpool/privatepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
@@ -42,6 +46,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -66,7 +71,8 @@ type Ethereum struct {
 	config *ethconfig.Config
 
 	// Handlers
-	txPool *txpool.TxPool
+	txPool     *txpool.TxPool
+	privateTxs *privateTxForwarder // Relays private transactions to validators, nil if none configured
 
 	blockchain         *core.BlockChain
 	handler            *handler
@@ -85,6 +91,8 @@ type Ethereum struct {
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
 
+	liveTracer *tracers.LiveTracer // Tracer of the imported blocks, nil if live tracing is disabled
+
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
@@ -173,15 +181,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	// Tải cấu hình bảo mật từ cơ sở dữ liệu
+	// Không khởi động với chính sách rỗng nếu không tải được, tránh âm thầm
+	// bỏ qua blacklist và các tài khoản bị đóng băng
 	securityConfig, err := core.LoadSecurityConfig(chainDb)
 	if err != nil {
-		log.Warn("Không thể tải cấu hình bảo mật, sử dụng cấu hình mặc định", "lỗi", err)
-		// Tiếp tục với cấu hình mặc định thay vì trả về lỗi
-	} else {
-		log.Info("Đã tải cấu hình bảo mật từ cơ sở dữ liệu")
-		// Đặt instance toàn cục thành cấu hình đã tải
-		core.SetSecurityConfig(securityConfig)
+		return nil, fmt.Errorf("failed to load security config: %v", err)
 	}
+	// Đặt instance toàn cục thành cấu hình đã tải
+	core.SetSecurityConfig(securityConfig)
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
 	var dbVer = "<nil>"
 	if bcVersion != nil {
@@ -199,6 +206,22 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			rawdb.WriteDatabaseVersion(chainDb, core.BlockChainVersion)
 		}
 	}
+	if config.VMTrace != "" {
+		sink := tracers.NewDatabaseSink(chainDb, config.VMTraceHistory)
+		if config.VMTraceOutput != "" {
+			if sink, err = tracers.NewFileSink(config.VMTraceOutput); err != nil {
+				return nil, fmt.Errorf("failed to open live trace output: %v", err)
+			}
+		}
+		var traceConfig json.RawMessage
+		if config.VMTraceJsonConfig != "" {
+			traceConfig = json.RawMessage(config.VMTraceJsonConfig)
+		}
+		if eth.liveTracer, err = tracers.NewLiveTracer(config.VMTrace, traceConfig, sink); err != nil {
+			return nil, fmt.Errorf("failed to create live tracer: %v", err)
+		}
+		log.Info("Tracing imported blocks", "tracer", config.VMTrace, "output", config.VMTraceOutput)
+	}
 	var (
 		vmConfig = vm.Config{
 			EnablePreimageRecording: config.EnablePreimageRecording,
@@ -213,8 +236,15 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
 			StateScheme:         scheme,
+			AddressIndex:        config.AddressIndex,
+			AddressHistory:      config.AddressHistory,
+			TokenIndex:          config.TokenIndex,
+			LogIndex:            config.LogIndex,
 		}
 	)
+	if eth.liveTracer != nil {
+		vmConfig.Tracer = eth.liveTracer
+	}
 	// Override the chain config with provided settings.
 	var overrides core.ChainOverrides
 	if config.OverrideCancun != nil {
@@ -238,12 +268,21 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.RemoteJournal != "" {
+		config.TxPool.RemoteJournal = stack.ResolvePath(config.TxPool.RemoteJournal)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	bundlePool := bundlepool.New(config.BundlePool, eth.blockchain)
+	privatePool := privatepool.New(config.PrivateTxPool, eth.blockchain)
+	conditionalPool := conditionalpool.New(config.ConditionalPool, eth.blockchain)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool, bundlePool, privatePool, conditionalPool})
 	if err != nil {
 		return nil, err
 	}
+	if len(config.PrivateTxPool.Validators) > 0 {
+		eth.privateTxs = newPrivateTxForwarder(config.PrivateTxPool.Validators)
+	}
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -331,6 +370,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "tobe",
+			Service:   NewTobeAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.blockchain, s.eventMux),
@@ -557,18 +599,21 @@ func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Close()
+	if s.privateTxs != nil {
+		s.privateTxs.close()
+	}
 	s.miner.Close()
 	s.blockchain.Stop()
+	if s.liveTracer != nil {
+		if err := s.liveTracer.Close(); err != nil {
+			log.Error("Failed to close live tracer", "err", err)
+		}
+	}
 	s.engine.Close()
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
 
-    // Lưu cấu hình bảo mật vào cơ sở dữ liệu
-    log.Info("Đang lưu cấu hình bảo mật vào cơ sở dữ liệu")
-    if err := core.GetSecurityConfig().SaveConfig(s.chainDb); err != nil {
-        log.Error("Không thể lưu cấu hình bảo mật", "lỗi", err)
-        // Không trả về lỗi để đảm bảo quá trình tắt tiếp tục
-    }
+    // Cấu hình bảo mật được ghi ngay vào cơ sở dữ liệu khi thay đổi, không cần lưu lại ở đây
 
 	s.chainDb.Close()
 	s.eventMux.Stop()
@@ -29,7 +29,10 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
+	"github.com/ethereum/go-ethereum/core/txpool/conditionalpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/txpool/privatepool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -53,6 +56,7 @@ var Defaults = Config{
 	NetworkId:          0, // enable auto configuration of networkID == chainID
 	TxLookupLimit:      2350000,
 	TransactionHistory: 2350000,
+	VMTraceHistory:     90000,
 	StateHistory:       params.FullImmutabilityThreshold,
 	LightPeers:         100,
 	DatabaseCache:      512,
@@ -64,6 +68,9 @@ var Defaults = Config{
 	Miner:              miner.DefaultConfig,
 	TxPool:             legacypool.DefaultConfig,
 	BlobPool:           blobpool.DefaultConfig,
+	BundlePool:         bundlepool.DefaultConfig,
+	PrivateTxPool:      privatepool.DefaultConfig,
+	ConditionalPool:    conditionalpool.DefaultConfig,
 	RPCGasCap:          50000000,
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
@@ -95,6 +102,10 @@ type Config struct {
 	TxLookupLimit      uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	AddressIndex       bool   `toml:",omitempty"` // Whether to index transactions by the addresses they touch.
+	AddressHistory     uint64 `toml:",omitempty"` // The maximum number of blocks from head whose transactions are indexed by address (0 = entire chain).
+	TokenIndex         bool   `toml:",omitempty"` // Whether to index ERC-20/721/1155 token transfers by token and by holder.
+	LogIndex           bool   `toml:",omitempty"` // Whether to index logs by address and topic, serving eth_getLogs in place of the bloombits.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -133,8 +144,11 @@ type Config struct {
 	Miner miner.Config
 
 	// Transaction pool options
-	TxPool   legacypool.Config
-	BlobPool blobpool.Config
+	TxPool          legacypool.Config
+	BlobPool        blobpool.Config
+	BundlePool      bundlepool.Config
+	PrivateTxPool   privatepool.Config
+	ConditionalPool conditionalpool.Config
 
 	// Gas Price Oracle options
 	GPO gasprice.Config
@@ -142,6 +156,15 @@ type Config struct {
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// Live tracing options: the tracer to trace the imported blocks with, its
+	// JSON config, the directory to write the traces to as JSON lines instead
+	// of the database, and the number of recent blocks whose traces are kept
+	// in the database (0 = entire chain).
+	VMTrace           string `toml:",omitempty"`
+	VMTraceJsonConfig string `toml:",omitempty"`
+	VMTraceOutput     string `toml:",omitempty"`
+	VMTraceHistory    uint64 `toml:",omitempty"`
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
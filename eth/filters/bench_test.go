@@ -19,16 +19,23 @@ package filters
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
 )
 
 func BenchmarkBloomBits512(b *testing.B) {
@@ -187,3 +194,126 @@ func BenchmarkNoBloomBits(b *testing.B) {
 	b.Log(" ", d, "total  ", d*time.Duration(1000000)/time.Duration(*headNum+1), "per million blocks")
 	db.Close()
 }
+
+const (
+	logBenchSections = 4    // Number of bloombits sections in the log benchmark chain
+	logBenchInterval = 1024 // Number of blocks between the logs searched for
+	logBenchLogs     = 64   // Number of logs per block, filling the block blooms
+	logBenchPool     = 2048 // Number of addresses and topics the logs are drawn from
+)
+
+var (
+	logBenchAddress = common.HexToAddress("0xbeef")
+	logBenchTopic   = common.HexToHash("0xbeef")
+
+	logBenchOnce     sync.Once
+	logBenchGenesis  *core.Genesis
+	logBenchChain    []*types.Block
+	logBenchReceipts []types.Receipts
+)
+
+// logBenchDatabase returns a chain whose blocks are full of logs with random
+// addresses and topics, which contains a log with the searched address and
+// topic every logBenchInterval blocks. Both the bloombits and the log index
+// are generated for the chain.
+func logBenchDatabase(b *testing.B) ethdb.Database {
+	logBenchOnce.Do(func() {
+		var (
+			blocks = logBenchSections * params.BloomBitsBlocks
+			rnd    = rand.New(rand.NewSource(1))
+		)
+		logBenchGenesis = &core.Genesis{
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Config:  params.TestChainConfig,
+		}
+		_, logBenchChain, logBenchReceipts = core.GenerateChainWithGenesis(logBenchGenesis, ethash.NewFaker(), int(blocks)-1, func(i int, gen *core.BlockGen) {
+			receipt := types.NewReceipt(nil, false, 0)
+			for j := 0; j < logBenchLogs; j++ {
+				receipt.Logs = append(receipt.Logs, &types.Log{
+					Address: common.BigToAddress(big.NewInt(rnd.Int63n(logBenchPool) + 1)),
+					Topics:  []common.Hash{common.BigToHash(big.NewInt(rnd.Int63n(logBenchPool) + 1))},
+				})
+			}
+			if (i+1)%logBenchInterval == 0 {
+				receipt.Logs = append(receipt.Logs, &types.Log{Address: logBenchAddress, Topics: []common.Hash{logBenchTopic}})
+			}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		})
+	})
+	db, err := rawdb.NewLevelDBDatabase(b.TempDir(), 128, 1024, "", false)
+	if err != nil {
+		b.Fatalf("failed to create database: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	logBenchGenesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range logBenchChain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), logBenchReceipts[i])
+	}
+	for section := uint64(0); section < logBenchSections; section++ {
+		gen, err := bloombits.NewGenerator(uint(params.BloomBitsBlocks))
+		if err != nil {
+			b.Fatalf("failed to create generator: %v", err)
+		}
+		for i := uint64(0); i < params.BloomBitsBlocks; i++ {
+			number := section*params.BloomBitsBlocks + i
+			header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, number), number)
+			gen.AddBloom(uint(i), header.Bloom)
+		}
+		head := rawdb.ReadCanonicalHash(db, (section+1)*params.BloomBitsBlocks-1)
+		for bit := 0; bit < types.BloomBitLength; bit++ {
+			data, err := gen.Bitset(uint(bit))
+			if err != nil {
+				b.Fatalf("failed to retrieve bitset: %v", err)
+			}
+			rawdb.WriteBloomBits(db, uint(bit), section, head, bitutil.CompressBytes(data))
+		}
+	}
+	if err := core.RebuildLogIndex(db, nil); err != nil {
+		b.Fatalf("failed to build log index: %v", err)
+	}
+	return db
+}
+
+func BenchmarkLogIndexAddress(b *testing.B) {
+	benchmarkLogFilter(b, true, []common.Address{logBenchAddress}, nil)
+}
+
+func BenchmarkBloomBitsAddress(b *testing.B) {
+	benchmarkLogFilter(b, false, []common.Address{logBenchAddress}, nil)
+}
+
+func BenchmarkLogIndexTopic(b *testing.B) {
+	benchmarkLogFilter(b, true, nil, [][]common.Hash{{logBenchTopic}})
+}
+
+func BenchmarkBloomBitsTopic(b *testing.B) {
+	benchmarkLogFilter(b, false, nil, [][]common.Hash{{logBenchTopic}})
+}
+
+// benchmarkLogFilter measures retrieving the rare logs of the benchmark chain
+// matching the given criteria, either through the log index or the bloombits.
+func benchmarkLogFilter(b *testing.B, logIndex bool, addresses []common.Address, topics [][]common.Hash) {
+	var (
+		db      = logBenchDatabase(b)
+		end     = int64(logBenchSections*params.BloomBitsBlocks - 1)
+		backend = &testBackend{db: db, sections: logBenchSections, logIndex: logIndex}
+	)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Use a fresh filter system to not serve the logs from the cache
+		sys := NewFilterSystem(backend, Config{})
+		logs, err := sys.NewRangeFilter(0, end, addresses, topics).Logs(context.Background())
+		if err != nil {
+			b.Fatalf("failed to filter logs: %v", err)
+		}
+		if len(logs) != int(end)/logBenchInterval {
+			b.Fatalf("log count mismatch: have %d, want %d", len(logs), int(end)/logBenchInterval)
+		}
+	}
+}
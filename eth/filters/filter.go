@@ -192,13 +192,23 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			close(logChan)
 		}()
 
-		// Gather all indexed logs, and finish with non indexed ones
+		// Gather the logs covered by the log index, continue with the ones
+		// covered by the bloombits and finish with non indexed ones
 		var (
 			end            = uint64(f.end)
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
-		if indexed := sections * size; indexed > uint64(f.begin) {
+		if head, ok := f.sys.backend.LogIndexStatus(); ok && f.logIndexable() && head >= uint64(f.begin) {
+			if head > end {
+				head = end
+			}
+			if err = f.logIndexLogs(ctx, head, logChan); err != nil {
+				errChan <- err
+				return
+			}
+		}
+		if indexed := sections * size; indexed > uint64(f.begin) && uint64(f.begin) <= end {
 			if indexed > end {
 				indexed = end + 1
 			}
@@ -265,6 +275,63 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 	}
 }
 
+// logIndexWindow is the number of blocks whose posting lists are matched at once
+// when retrieving logs from the log index.
+const logIndexWindow = 65536
+
+// logIndexable returns whether the filter has any address or topic criterion the
+// log index can narrow the searched blocks by.
+func (f *Filter) logIndexable() bool {
+	if len(f.addresses) > 0 {
+		return true
+	}
+	for _, sub := range f.topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// logIndexLogs returns the logs matching the filter criteria based on the
+// address and topic posting lists of the log index.
+func (f *Filter) logIndexLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
+	for uint64(f.begin) <= end {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		last := uint64(f.begin) + logIndexWindow - 1
+		if last > end {
+			last = end
+		}
+		numbers, err := f.sys.backend.LogIndexBlocks(ctx, f.addresses, f.topics, uint64(f.begin), last)
+		if err != nil {
+			return err
+		}
+		for _, number := range numbers {
+			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return err
+			}
+			found, err := f.checkMatches(ctx, header)
+			if err != nil {
+				return err
+			}
+			for _, log := range found {
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		f.begin = int64(last) + 1
+	}
+	return nil
+}
+
 // unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
 func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
@@ -74,6 +74,9 @@ type Backend interface {
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+
+	LogIndexStatus() (uint64, bool)
+	LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error)
 }
 
 // FilterSystem holds resources shared by all filters.
@@ -29,6 +29,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
@@ -45,6 +46,7 @@ import (
 type testBackend struct {
 	db              ethdb.Database
 	sections        uint64
+	logIndex        bool
 	txFeed          event.Feed
 	logsFeed        event.Feed
 	rmLogsFeed      event.Feed
@@ -171,7 +173,8 @@ func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.Matc
 				for i, section := range task.Sections {
 					if rand.Int()%4 != 0 { // Handle occasional missing deliveries
 						head := rawdb.ReadCanonicalHash(b.db, (section+1)*params.BloomBitsBlocks-1)
-						task.Bitsets[i], _ = rawdb.ReadBloomBits(b.db, task.Bit, section, head)
+						comp, _ := rawdb.ReadBloomBits(b.db, task.Bit, section, head)
+						task.Bitsets[i], _ = bitutil.DecompressBytes(comp, int(params.BloomBitsBlocks/8))
 					}
 				}
 				request <- task
@@ -180,6 +183,21 @@ func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.Matc
 	}()
 }
 
+func (b *testBackend) LogIndexStatus() (uint64, bool) {
+	if !b.logIndex {
+		return 0, false
+	}
+	number := rawdb.ReadHeaderNumber(b.db, rawdb.ReadLogIndexHead(b.db))
+	if number == nil {
+		return 0, false
+	}
+	return *number, true
+}
+
+func (b *testBackend) LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	return core.MatchLogIndex(b.db, addresses, topics, begin, end)
+}
+
 func newTestFilterSystem(t testing.TB, db ethdb.Database, cfg Config) (*testBackend, *FilterSystem) {
 	backend := &testBackend{db: db}
 	sys := NewFilterSystem(backend, cfg)
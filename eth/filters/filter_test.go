@@ -388,3 +388,89 @@ func TestFilters(t *testing.T) {
 		}
 	})
 }
+
+// TestLogIndexFilters tests that range filters served from a log index covering
+// part of the chain return the same logs as the ones iterating the blocks.
+func TestLogIndexFilters(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		addr1        = common.Address{0x01}
+		addr2        = common.Address{0x02}
+		topic1       = common.Hash{0x01}
+		topic2       = common.Hash{0x02}
+
+		gspec = &core.Genesis{
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Config:  params.TestChainConfig,
+		}
+	)
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 300, func(i int, gen *core.BlockGen) {
+		var logs []*types.Log
+		if i%3 == 0 {
+			logs = append(logs, &types.Log{Address: addr1, Topics: []common.Hash{topic1}})
+		}
+		if i%5 == 0 {
+			logs = append(logs, &types.Log{Address: addr2, Topics: []common.Hash{topic2, topic1}})
+		}
+		if len(logs) == 0 {
+			return
+		}
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = logs
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+	})
+	// The test txs are not properly signed, can't simply create a chain
+	// and then import blocks.
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+
+	write := func(from, to int) {
+		for i := from; i < to; i++ {
+			rawdb.WriteBlock(db, chain[i])
+			rawdb.WriteCanonicalHash(db, chain[i].Hash(), chain[i].NumberU64())
+			rawdb.WriteHeadBlockHash(db, chain[i].Hash())
+			rawdb.WriteReceipts(db, chain[i].Hash(), chain[i].NumberU64(), receipts[i])
+		}
+	}
+	// Index the first 200 blocks only, leaving the rest to be iterated
+	write(0, 200)
+	if err := core.RebuildLogIndex(db, nil); err != nil {
+		t.Fatalf("failed to build log index: %v", err)
+	}
+	write(200, len(chain))
+
+	for i, tc := range []struct {
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		want       int
+	}{
+		{0, -1, []common.Address{addr1}, nil, 100},
+		{0, -1, nil, [][]common.Hash{{topic2}}, 60},
+		{0, -1, nil, [][]common.Hash{nil, {topic1}}, 60},
+		{10, 250, []common.Address{addr1, addr2}, [][]common.Hash{{topic1, topic2}}, 129},
+		{0, -1, []common.Address{addr1}, [][]common.Hash{{topic2}}, 0},
+		{250, 299, nil, [][]common.Hash{{topic1}}, 17},
+	} {
+		backend.logIndex = false
+		want, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to filter logs: %v", i, err)
+		}
+		backend.logIndex = true
+		have, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: failed to filter indexed logs: %v", i, err)
+		}
+		if len(want) != tc.want {
+			t.Fatalf("test %d: log count mismatch: have %d, want %d", i, len(want), tc.want)
+		}
+		haveJSON, _ := json.Marshal(have)
+		wantJSON, _ := json.Marshal(want)
+		if string(haveJSON) != string(wantJSON) {
+			t.Fatalf("test %d: indexed logs mismatch:\nhave %s\nwant %s", i, haveJSON, wantJSON)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// privateTxForwardTimeout is the maximum time allowed to relay a private
+// transaction to a single validator.
+const privateTxForwardTimeout = 5 * time.Second
+
+// privateTxForwarder relays private transactions to the configured validator
+// endpoints, the only parties besides the local node that ever get to see them.
+//
+// Validators receive the transactions through their own private transaction
+// API, so they don't gossip them either. Relaying stops at nodes which already
+// know a transaction, which prevents forwarding loops between validators.
+type privateTxForwarder struct {
+	endpoints []string
+	clients   map[string]*rpc.Client
+	lock      sync.Mutex
+}
+
+// newPrivateTxForwarder creates a forwarder relaying to the given endpoints.
+// Connections are established lazily upon the first relayed transaction.
+func newPrivateTxForwarder(endpoints []string) *privateTxForwarder {
+	return &privateTxForwarder{
+		endpoints: endpoints,
+		clients:   make(map[string]*rpc.Client),
+	}
+}
+
+// send relays a private transaction to all validators in the background.
+func (f *privateTxForwarder) send(tx *types.Transaction, maxBlock uint64) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		log.Error("Failed to encode private transaction", "hash", tx.Hash(), "err", err)
+		return
+	}
+	args := ethapi.PrivateTransactionArgs{Tx: raw}
+	if maxBlock != 0 {
+		args.MaxBlockNumber = (*hexutil.Uint64)(&maxBlock)
+	}
+	f.forward("eth_sendPrivateTransaction", tx.Hash(), args)
+}
+
+// cancel relays a sender signed cancellation of a private transaction to all
+// validators in the background. The signature is relayed as is, so validators
+// verify the sender themselves.
+func (f *privateTxForwarder) cancel(hash common.Hash, sig []byte) {
+	f.forward("eth_cancelPrivateTransaction", hash, hash, hexutil.Bytes(sig))
+}
+
+// forward invokes the given method on every validator endpoint concurrently.
+func (f *privateTxForwarder) forward(method string, hash common.Hash, args ...interface{}) {
+	for _, endpoint := range f.endpoints {
+		go func(endpoint string) {
+			ctx, cancel := context.WithTimeout(context.Background(), privateTxForwardTimeout)
+			defer cancel()
+
+			client, err := f.client(ctx, endpoint)
+			if err == nil {
+				err = client.CallContext(ctx, nil, method, args...)
+			}
+			if err != nil {
+				log.Warn("Failed to forward private transaction", "method", method, "hash", hash, "endpoint", endpoint, "err", err)
+				return
+			}
+			log.Debug("Forwarded private transaction", "method", method, "hash", hash, "endpoint", endpoint)
+		}(endpoint)
+	}
+}
+
+// client returns the connection to the given endpoint, dialing it if needed.
+func (f *privateTxForwarder) client(ctx context.Context, endpoint string) (*rpc.Client, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if client, ok := f.clients[endpoint]; ok {
+		return client, nil
+	}
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	f.clients[endpoint] = client
+	return client, nil
+}
+
+// close terminates all validator connections.
+func (f *privateTxForwarder) close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for endpoint, client := range f.clients {
+		client.Close()
+		delete(f.clients, endpoint)
+	}
+}
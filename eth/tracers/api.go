@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"runtime"
 	"sync"
@@ -65,6 +66,10 @@ const (
 	// for tracing. The creation of trace state will be paused if the unused
 	// trace states exceed this limit.
 	maximumPendingTraceStates = 128
+
+	// maxTraceBundleCalls is the maximum number of calls that can be traced
+	// across all the bundles of a single TraceCallMany request.
+	maxTraceBundleCalls = 1024
 )
 
 var errTxNotFound = errors.New("transaction not found")
@@ -156,6 +161,10 @@ type TraceConfig struct {
 	// Config specific to given tracer. Note struct logger
 	// config are historically embedded in main object.
 	TracerConfig json.RawMessage
+	// Rewards appends a trace of the block rewards, with an empty transaction
+	// hash, to the transaction traces of a block. Only honoured by block and
+	// chain tracing with tracers reporting rewards.
+	Rewards bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -206,7 +215,8 @@ type txTraceTask struct {
 }
 
 // TraceChain returns the structured logs created during the execution of EVM
-// between two blocks (excluding start) and returns them as a JSON object.
+// between two blocks (excluding start) and returns them as a JSON object. The
+// block rewards are traced too by the tracers reporting them.
 func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) { // Fetch the block interval that we want to trace
 	from, err := api.blockByNumber(ctx, start)
 	if err != nil {
@@ -238,7 +248,8 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 // traceChain configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The tracing chain range includes
 // the end block but excludes the start one. The return value will be one item per
-// transaction, dependent on the requested tracer.
+// transaction, dependent on the requested tracer, followed by the block rewards
+// for the tracers reporting them.
 // The tracing procedure should be aborted in case the closed signal is received.
 func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed <-chan interface{}) chan *blockTraceResult {
 	reexec := defaultTraceReexec
@@ -287,6 +298,9 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 					task.statedb.Finalise(api.backend.ChainConfig().IsEIP158(task.block.Number()))
 					task.results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
 				}
+				if res := api.traceRewards(task.block, config); res != nil {
+					task.results = append(task.results, res)
+				}
 				// Tracing state is used up, queue it for de-referencing. Note the
 				// state is the parent state of trace block, use block.number-1 as
 				// the state number.
@@ -566,7 +580,8 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 
 // traceBlock configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
-// per transaction, dependent on the requested tracer.
+// per transaction, dependent on the requested tracer, followed by the block rewards
+// for the tracers reporting them.
 func (api *API) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
 	if block.NumberU64() == 0 {
 		return nil, errors.New("genesis is not traceable")
@@ -621,9 +636,46 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 		statedb.Finalise(is158)
 	}
+	if res := api.traceRewards(block, config); res != nil {
+		results = append(results, res)
+	}
 	return results, nil
 }
 
+// traceRewards traces the rewards credited by the consensus engine when
+// finalizing the block, which aren't part of any transaction. The result has
+// an empty transaction hash, and is nil if the rewards weren't requested, the
+// tracer doesn't report rewards or the engine didn't credit any.
+func (api *API) traceRewards(block *types.Block, config *TraceConfig) *txTraceResult {
+	// JS tracers can't report rewards, avoid their costly construction
+	if config == nil || !config.Rewards || config.Tracer == nil || *config.Tracer == "" || DefaultDirectory.IsJS(*config.Tracer) {
+		return nil
+	}
+	rewarder, ok := api.backend.Engine().(consensus.Rewarder)
+	if !ok {
+		return nil
+	}
+	rewards := rewarder.BlockRewards(api.backend.ChainConfig(), block.Header(), block.Uncles(), block.Withdrawals())
+	if len(rewards) == 0 {
+		return nil
+	}
+	txctx := &Context{BlockHash: block.Hash(), BlockNumber: block.Number(), TxIndex: len(block.Transactions())}
+	tracer, err := DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
+	if err != nil {
+		return &txTraceResult{Error: err.Error()}
+	}
+	rewardTracer, ok := tracer.(RewardTracer)
+	if !ok {
+		return nil
+	}
+	rewardTracer.CaptureRewards(rewards)
+	res, err := rewardTracer.GetResult()
+	if err != nil {
+		return &txTraceResult{Error: err.Error()}
+	}
+	return &txTraceResult{Result: res}
+}
+
 // traceBlockParallel is for tracers that have a high overhead (read JS tracers). One thread
 // runs along and executes txes without tracing enabled to generate their prestate.
 // Worker threads take the tasks and the prestate and trace them.
@@ -869,6 +921,137 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 // the trace will be conducted on the state after executing the specified transaction
 // within the specified block.
 func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	block, statedb, release, err := api.callState(ctx, blockNrOrHash, config)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	// Apply the customization rules if required.
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		config.BlockOverrides.Apply(&vmctx)
+	}
+	// Execute the trace
+	msg, err := args.ToMessage(api.backend.RPCGasCap(), vmctx.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	var traceConfig *TraceConfig
+	if config != nil {
+		traceConfig = &config.TraceConfig
+	}
+	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
+}
+
+// BundleCall is a call of a bundle traced by TraceCallMany, along with the
+// state overrides to apply right before it.
+type BundleCall struct {
+	ethapi.TransactionArgs
+	StateOverrides *ethapi.StateOverride `json:"stateOverrides"`
+}
+
+// Bundle is a list of calls traced by TraceCallMany in the same block.
+type Bundle struct {
+	Calls          []BundleCall           `json:"calls"`
+	BlockOverrides *ethapi.BlockOverrides `json:"blockOverrides"`
+}
+
+// callTraceResult is the trace of a call of a bundle, or the reason it failed.
+type callTraceResult struct {
+	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// TraceCallMany lets you trace a list of bundles of calls, executed one after
+// the other on top of the provided block, each call seeing the state changes
+// made by the previous ones. The first bundle is traced in the context of the
+// provided block as TraceCall does, the following ones in the context of the
+// next blocks, each advancing the block number and time by one unless
+// overridden. The results are returned per call, a call failing to execute
+// leaving the state unchanged for the following ones. The gas used by all the
+// calls is limited by the RPC gas cap.
+func (api *API) TraceCallMany(ctx context.Context, bundles []Bundle, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) ([][]*callTraceResult, error) {
+	if len(bundles) == 0 {
+		return nil, errors.New("empty bundle list")
+	}
+	var calls int
+	for _, bundle := range bundles {
+		calls += len(bundle.Calls)
+	}
+	if calls > maxTraceBundleCalls {
+		return nil, fmt.Errorf("too many calls: %d > %d", calls, maxTraceBundleCalls)
+	}
+	block, statedb, release, err := api.callState(ctx, blockNrOrHash, config)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	base := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	// Apply the customization rules if required.
+	var traceConfig *TraceConfig
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		config.BlockOverrides.Apply(&base)
+		traceConfig = &config.TraceConfig
+	}
+	var (
+		chainConfig = api.backend.ChainConfig()
+		results     = make([][]*callTraceResult, len(bundles))
+		budget      = api.backend.RPCGasCap() // Gas left for the remaining calls, unlimited if zero
+	)
+	for i, bundle := range bundles {
+		vmctx := base
+		vmctx.BlockNumber = new(big.Int).Add(base.BlockNumber, big.NewInt(int64(i)))
+		vmctx.Time = base.Time + uint64(i)
+		bundle.BlockOverrides.Apply(&vmctx)
+
+		results[i] = make([]*callTraceResult, len(bundle.Calls))
+		for j, call := range bundle.Calls {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := call.StateOverrides.Apply(statedb); err != nil {
+				return nil, fmt.Errorf("call %d of bundle %d: %w", j, i, err)
+			}
+			if api.backend.RPCGasCap() > 0 && budget == 0 {
+				return nil, fmt.Errorf("call %d of bundle %d: RPC gas cap exhausted", j, i)
+			}
+			msg, err := call.ToMessage(budget, vmctx.BaseFee)
+			if err != nil {
+				return nil, fmt.Errorf("call %d of bundle %d: %w", j, i, err)
+			}
+			var (
+				txctx = &Context{BlockNumber: vmctx.BlockNumber, TxIndex: j}
+				snap  = statedb.Snapshot()
+			)
+			res, used, err := api.traceTxGas(ctx, msg, txctx, vmctx, statedb, traceConfig)
+			if err != nil {
+				statedb.RevertToSnapshot(snap)
+				results[i][j] = &callTraceResult{Error: err.Error()}
+				continue
+			}
+			if budget > 0 {
+				budget -= min(used, budget)
+			}
+			// Finalize the state so any modifications are written to the trie
+			statedb.Finalise(chainConfig.IsEIP158(vmctx.BlockNumber))
+			results[i][j] = &callTraceResult{Result: res}
+		}
+	}
+	return results, nil
+}
+
+// callState retrieves the block to trace calls on top of, along with its state
+// or the state within it at the transaction index given in the config.
+func (api *API) callState(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (*types.Block, *state.StateDB, StateReleaseFunc, error) {
 	// Try to retrieve the specified block
 	var (
 		err     error
@@ -885,14 +1068,14 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 			// more flexibility and stability than trying to trace on 'pending', since
 			// the contents of 'pending' is unstable and probably not a true representation
 			// of what the next actual block is likely to contain.
-			return nil, errors.New("tracing on top of pending is not supported")
+			return nil, nil, nil, errors.New("tracing on top of pending is not supported")
 		}
 		block, err = api.blockByNumber(ctx, number)
 	} else {
-		return nil, errors.New("invalid arguments; neither block nor hash specified")
+		return nil, nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	// try to recompute the state
 	reexec := defaultTraceReexec
@@ -906,35 +1089,21 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 		statedb, release, err = api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-	defer release()
-
-	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
-	// Apply the customization rules if required.
-	if config != nil {
-		if err := config.StateOverrides.Apply(statedb); err != nil {
-			return nil, err
-		}
-		config.BlockOverrides.Apply(&vmctx)
-	}
-	// Execute the trace
-	msg, err := args.ToMessage(api.backend.RPCGasCap(), vmctx.BaseFee)
-	if err != nil {
-		return nil, err
-	}
-
-	var traceConfig *TraceConfig
-	if config != nil {
-		traceConfig = &config.TraceConfig
-	}
-	return api.traceTx(ctx, msg, new(Context), vmctx, statedb, traceConfig)
+	return block, statedb, release, nil
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	res, _, err := api.traceTxGas(ctx, message, txctx, vmctx, statedb, config)
+	return res, err
+}
+
+// traceTxGas is traceTx, also returning the gas used by the message.
+func (api *API) traceTxGas(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, uint64, error) {
 	var (
 		tracer    Tracer
 		err       error
@@ -949,7 +1118,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	if config.Tracer != nil {
 		tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
@@ -957,7 +1126,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	// Define a meaningful timeout of a single transaction trace
 	if config.Timeout != nil {
 		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
 	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -973,10 +1142,12 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 
 	// Call Prepare to clear out the statedb access list
 	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
-	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit)); err != nil {
-		return nil, fmt.Errorf("tracing failed: %w", err)
+	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("tracing failed: %w", err)
 	}
-	return tracer.GetResult()
+	res, err := tracer.GetResult()
+	return res, result.UsedGas, err
 }
 
 // APIs return the collection of RPC services the tracer package offers.
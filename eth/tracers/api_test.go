@@ -403,6 +403,120 @@ func TestTraceCall(t *testing.T) {
 	}
 }
 
+func TestTraceCallMany(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	genBlocks := 2
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &accounts[1].addr,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+			Data:     nil}),
+			signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	var (
+		recipient = common.HexToAddress("0x1234")
+		// balancer returns the balance of the recipient, numberer the number
+		// of the block it's called in.
+		balancer     = common.HexToAddress("0xba1a")
+		balancerCode = hexutil.Bytes(append(append([]byte{byte(vm.PUSH20)}, recipient.Bytes()...), common.FromHex("3160005260206000f3")...))
+		numberer     = common.HexToAddress("0x4e4e")
+		numbererCode = hexutil.Bytes(common.FromHex("4360005260206000f3"))
+		number       = hexutil.Big(*big.NewInt(100))
+		latest       = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	results, err := api.TraceCallMany(context.Background(), []Bundle{
+		{
+			Calls: []BundleCall{
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &recipient, Value: (*hexutil.Big)(big.NewInt(1000))}},
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &balancer}, StateOverrides: &ethapi.StateOverride{balancer: ethapi.OverrideAccount{Code: &balancerCode}}},
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[1].addr, To: &recipient, Value: (*hexutil.Big)(big.NewInt(params.Ether))}},
+			},
+		},
+		{
+			Calls: []BundleCall{
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &recipient, Value: (*hexutil.Big)(big.NewInt(1000))}},
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &balancer}},
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &numberer}, StateOverrides: &ethapi.StateOverride{numberer: ethapi.OverrideAccount{Code: &numbererCode}}},
+			},
+		},
+		{
+			Calls: []BundleCall{
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &numberer}},
+			},
+			BlockOverrides: &ethapi.BlockOverrides{Number: &number},
+		},
+	}, latest, nil)
+	if err != nil {
+		t.Fatalf("failed to trace call bundles: %v", err)
+	}
+	returned := func(i, j int) uint64 {
+		t.Helper()
+		res := results[i][j]
+		if res.Error != "" {
+			t.Fatalf("call %d of bundle %d failed: %v", j, i, res.Error)
+		}
+		var have *logger.ExecutionResult
+		if err := json.Unmarshal(res.Result.(json.RawMessage), &have); err != nil {
+			t.Fatalf("call %d of bundle %d: failed to unmarshal result %v", j, i, err)
+		}
+		return new(big.Int).SetBytes(common.FromHex(have.ReturnValue)).Uint64()
+	}
+	if have := returned(0, 1); have != 1000 {
+		t.Errorf("wrong balance after first transfer: have %d, want %d", have, 1000)
+	}
+	if results[0][2].Error == "" {
+		t.Errorf("expected unfunded transfer to fail")
+	}
+	if have := returned(1, 1); have != 2000 {
+		t.Errorf("wrong balance after second transfer: have %d, want %d", have, 2000)
+	}
+	if have, want := returned(1, 2), uint64(genBlocks+1); have != want {
+		t.Errorf("wrong number of second bundle: have %d, want %d", have, want)
+	}
+	if have := returned(2, 0); have != 100 {
+		t.Errorf("wrong overridden number of third bundle: have %d, want %d", have, 100)
+	}
+	if _, err := api.TraceCallMany(context.Background(), nil, latest, nil); err == nil {
+		t.Errorf("expected error for empty bundle list")
+	}
+	if _, err := api.TraceCallMany(context.Background(), []Bundle{{Calls: make([]BundleCall, maxTraceBundleCalls+1)}}, latest, nil); err == nil {
+		t.Errorf("expected error for too many calls")
+	}
+	// Ensure the gas used by all the calls is limited by the RPC gas cap
+	var (
+		burner     = common.HexToAddress("0x1001")
+		burnerCode = hexutil.Bytes(common.FromHex("fe"))
+	)
+	_, err = api.TraceCallMany(context.Background(), []Bundle{
+		{
+			Calls: []BundleCall{
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &burner}, StateOverrides: &ethapi.StateOverride{burner: ethapi.OverrideAccount{Code: &burnerCode}}},
+				{TransactionArgs: ethapi.TransactionArgs{From: &accounts[0].addr, To: &recipient}},
+			},
+		},
+	}, latest, nil)
+	if err == nil {
+		t.Errorf("expected error for exhausted gas cap")
+	}
+}
+
 func TestTraceTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -548,6 +662,93 @@ func TestTraceBlock(t *testing.T) {
 	}
 }
 
+// rewardTestTracer is a struct logger which also reports the block rewards.
+type rewardTestTracer struct {
+	*logger.StructLogger
+	rewards []consensus.Reward
+}
+
+func init() {
+	DefaultDirectory.Register("rewardTestTracer", func(ctx *Context, cfg json.RawMessage) (Tracer, error) {
+		return &rewardTestTracer{StructLogger: logger.NewStructLogger(nil)}, nil
+	}, false)
+}
+
+func (t *rewardTestTracer) CaptureRewards(rewards []consensus.Reward) {
+	t.rewards = rewards
+}
+
+func (t *rewardTestTracer) GetResult() (json.RawMessage, error) {
+	if t.rewards == nil {
+		return t.StructLogger.GetResult()
+	}
+	return json.Marshal(t.rewards)
+}
+
+// Tests that the block rewards are traced after the transactions by the tracers
+// reporting them.
+func TestTraceBlockRewards(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	coinbase := common.HexToAddress("0xc0ffee")
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(coinbase)
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &accounts[1].addr,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	// Rewards are only traced on request, keeping one result per transaction
+	tracer := "rewardTestTracer"
+	results, err := api.TraceBlockByNumber(context.Background(), 1, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("result count mismatch: have %d, want 1", len(results))
+	}
+	results, err = api.TraceBlockByNumber(context.Background(), 1, &TraceConfig{Tracer: &tracer, Rewards: true})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("result count mismatch: have %d, want 2", len(results))
+	}
+	if results[1].TxHash != (common.Hash{}) || results[1].Error != "" {
+		t.Fatalf("unexpected reward result: %+v", results[1])
+	}
+	var rewards []consensus.Reward
+	if err := json.Unmarshal(results[1].Result.(json.RawMessage), &rewards); err != nil {
+		t.Fatalf("failed to decode rewards: %v", err)
+	}
+	want := new(big.Int).Mul(big.NewInt(2), big.NewInt(params.Ether))
+	if len(rewards) != 1 || rewards[0].Beneficiary != coinbase || rewards[0].Kind != consensus.RewardBlock || rewards[0].Amount.Cmp(want) != 0 {
+		t.Fatalf("rewards mismatch: %+v", rewards)
+	}
+	// Tracers not reporting rewards only trace the transactions
+	results, err = api.TraceBlockByNumber(context.Background(), 1, &TraceConfig{Rewards: true})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("result count mismatch: have %d, want 1", len(results))
+	}
+}
+
 func TestTracingWithOverrides(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
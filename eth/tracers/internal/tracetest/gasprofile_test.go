@@ -0,0 +1,145 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/google/pprof/profile"
+)
+
+// Tests that the gas profiler accounts all the gas used by a transaction to
+// the stacks of the frames spending it, in both output formats.
+func TestGasProfiler(t *testing.T) {
+	var (
+		to     = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		callee = common.HexToAddress("0x00000000000000000000000000000000000000fe")
+		origin = common.HexToAddress("0x00000000000000000000000000000000feed")
+		code   = []byte{
+			// Call the identity precompile
+			byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1),
+			byte(vm.PUSH1), 0x4, byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+			// Call the callee with the 0x12345678 selector
+			byte(vm.PUSH4), 0x12, 0x34, 0x56, 0x78, byte(vm.PUSH1), 0xe0, byte(vm.SHL), byte(vm.PUSH1), 0x0, byte(vm.MSTORE),
+			byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.PUSH1), 0x4, byte(vm.PUSH1), 0x0, byte(vm.DUP1),
+			byte(vm.PUSH1), 0xfe, byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+		}
+		context = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: new(big.Int).SetUint64(8000000),
+			Time:        5,
+			Difficulty:  big.NewInt(0x30000),
+			GasLimit:    uint64(6000000),
+		}
+	)
+	for _, format := range []string{"folded", "pprof"} {
+		state := tests.MakePreState(rawdb.NewMemoryDatabase(),
+			types.GenesisAlloc{
+				to:     types.Account{Code: code},
+				callee: types.Account{Code: []byte{byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE)}},
+				origin: types.Account{Balance: big.NewInt(500000000000000)},
+			}, false, rawdb.HashScheme)
+		defer state.Close()
+
+		tracer, err := tracers.DefaultDirectory.New("gasProfiler", nil, json.RawMessage(`{"format":"`+format+`"}`))
+		if err != nil {
+			t.Fatalf("%s: failed to create gas profiler: %v", format, err)
+		}
+		evm := vm.NewEVM(context, vm.TxContext{Origin: origin, GasPrice: big.NewInt(1)}, state.StateDB, params.MainnetChainConfig, vm.Config{Tracer: tracer})
+		msg := &core.Message{
+			To:        &to,
+			From:      origin,
+			Value:     big.NewInt(0),
+			GasLimit:  100000,
+			GasPrice:  big.NewInt(1),
+			GasFeeCap: big.NewInt(1),
+			GasTipCap: big.NewInt(1),
+		}
+		st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		result, err := st.TransitionDb()
+		if err != nil {
+			t.Fatalf("%s: failed to execute transaction: %v", format, err)
+		}
+		res, err := tracer.GetResult()
+		if err != nil {
+			t.Fatalf("%s: failed to retrieve gas profile: %v", format, err)
+		}
+		// Collect the gas of the stacks, whatever the format
+		stacks := make(map[string]uint64)
+		if format == "folded" {
+			var folded string
+			if err := json.Unmarshal(res, &folded); err != nil {
+				t.Fatalf("failed to decode folded stacks: %v", err)
+			}
+			for _, line := range strings.Split(strings.TrimSpace(folded), "\n") {
+				i := strings.LastIndexByte(line, ' ')
+				gas, err := strconv.ParseUint(line[i+1:], 10, 64)
+				if err != nil {
+					t.Fatalf("invalid folded line %q: %v", line, err)
+				}
+				stacks[line[:i]] += gas
+			}
+		} else {
+			var enc []byte
+			if err := json.Unmarshal(res, &enc); err != nil {
+				t.Fatalf("failed to decode pprof profile: %v", err)
+			}
+			p, err := profile.Parse(bytes.NewReader(enc))
+			if err != nil {
+				t.Fatalf("failed to parse pprof profile: %v", err)
+			}
+			for _, sample := range p.Sample {
+				var names []string
+				for i := len(sample.Location) - 1; i >= 0; i-- {
+					names = append(names, sample.Location[i].Line[0].Function.Name)
+				}
+				stacks[strings.Join(names, ";")] += uint64(sample.Value[0])
+			}
+		}
+		var total uint64
+		for _, gas := range stacks {
+			total += gas
+		}
+		// Refunds are not deducted from the profile
+		if want := result.UsedGas + result.RefundedGas; total != want {
+			t.Errorf("%s: total gas mismatch: have %d, want %d", format, total, want)
+		}
+		for stack, want := range map[string]uint64{
+			to.Hex() + ";(intrinsic)": params.TxGas,
+			to.Hex() + ";" + common.BytesToAddress([]byte{4}).Hex() + ";(precompile)": params.IdentityBaseGas,
+			to.Hex() + ";" + callee.Hex() + ":0x12345678;SSTORE":                      params.SstoreSetGas,
+		} {
+			if stacks[stack] != want {
+				t.Errorf("%s: stack %s gas mismatch: have %d, want %d", format, stack, stacks[stack], want)
+			}
+		}
+	}
+}
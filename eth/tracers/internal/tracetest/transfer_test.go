@@ -0,0 +1,305 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+// Tests that the transfer tracer reports the value moved by the calls which
+// succeeded and the fees, but not the value of the reverted calls.
+func TestTransferTracer(t *testing.T) {
+	var (
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000000fe")
+		origin   = common.HexToAddress("0x00000000000000000000000000000000feed")
+		coinbase = common.HexToAddress("0x00000000000000000000000000000000c0ffee")
+		code     = []byte{
+			// Send 5 wei to 0xff, which succeeds
+			byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1),
+			byte(vm.PUSH1), 0x5, byte(vm.PUSH1), 0xff, byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+			// Send 7 wei to 0xfe, which reverts
+			byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1),
+			byte(vm.PUSH1), 0x7, byte(vm.PUSH1), 0xfe, byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+		}
+		context = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			Coinbase:    coinbase,
+			BlockNumber: new(big.Int).SetUint64(8000000),
+			Time:        5,
+			Difficulty:  big.NewInt(0x30000),
+			GasLimit:    uint64(6000000),
+		}
+	)
+	state := tests.MakePreState(rawdb.NewMemoryDatabase(),
+		types.GenesisAlloc{
+			to:       types.Account{Code: code, Balance: big.NewInt(100)},
+			reverter: types.Account{Code: []byte{byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.REVERT)}},
+			origin:   types.Account{Balance: big.NewInt(500000000000000)},
+		}, false, rawdb.HashScheme)
+	defer state.Close()
+
+	tracer, err := tracers.DefaultDirectory.New("transferTracer", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create transfer tracer: %v", err)
+	}
+	evm := vm.NewEVM(context, vm.TxContext{Origin: origin, GasPrice: big.NewInt(2)}, state.StateDB, params.MainnetChainConfig, vm.Config{Tracer: tracer})
+	msg := &core.Message{
+		To:        &to,
+		From:      origin,
+		Value:     big.NewInt(1000),
+		GasLimit:  80000,
+		GasPrice:  big.NewInt(2),
+		GasFeeCap: big.NewInt(2),
+		GasTipCap: big.NewInt(2),
+	}
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(msg.GasLimit))
+	if _, err := st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	want := `{"transfers":[{"from":"0x000000000000000000000000000000000000feed","to":"0x00000000000000000000000000000000deadbeef","value":"0x3e8","type":"call","depth":0},{"from":"0x00000000000000000000000000000000deadbeef","to":"0x00000000000000000000000000000000000000ff","value":"0x5","type":"call","depth":1},{"from":"0x000000000000000000000000000000000000feed","to":"0x0000000000000000000000000000000000c0ffee","value":"0x1db64","type":"fee","depth":0}],"balanceDeltas":{"0x00000000000000000000000000000000000000ff":"0x5","0x000000000000000000000000000000000000feed":"-0x1df4c","0x0000000000000000000000000000000000c0ffee":"0x1db64","0x00000000000000000000000000000000deadbeef":"0x3e3"}}`
+	if string(res) != want {
+		t.Errorf("trace mismatch\n have: %v\n want: %v\n", string(res), want)
+	}
+	// Cross-check the fee with the balance credited to the coinbase
+	if have := state.StateDB.GetBalance(coinbase); have.Uint64() != 0x1db64 {
+		t.Errorf("coinbase balance mismatch: have %v, want %v", have, 0x1db64)
+	}
+}
+
+// transferTrace is the decoded output of the transfer tracer.
+type transferTrace struct {
+	Transfers []struct {
+		From  common.Address `json:"from"`
+		To    common.Address `json:"to"`
+		Value *hexutil.Big   `json:"value"`
+		Type  string         `json:"type"`
+		Depth int            `json:"depth"`
+	} `json:"transfers"`
+	BalanceDeltas map[common.Address]string `json:"balanceDeltas"` // Signed, so not decodable as hexutil.Big
+}
+
+// hexDelta encodes a balance delta as the transfer tracer does.
+func hexDelta(delta int64) string {
+	return (*hexutil.Big)(big.NewInt(delta)).String()
+}
+
+// traceTransfers executes the message with the transfer tracer and returns the
+// decoded trace along with the resulting state.
+func traceTransfers(t *testing.T, config *params.ChainConfig, context vm.BlockContext, alloc types.GenesisAlloc, msg *core.Message) (*transferTrace, *state.StateDB) {
+	t.Helper()
+
+	state := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false, rawdb.HashScheme)
+	t.Cleanup(func() { state.Close() })
+
+	tracer, err := tracers.DefaultDirectory.New("transferTracer", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create transfer tracer: %v", err)
+	}
+	context.CanTransfer, context.Transfer = core.CanTransfer, core.Transfer
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), state.StateDB, config, vm.Config{Tracer: tracer})
+	if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	trace := new(transferTrace)
+	if err := json.Unmarshal(res, trace); err != nil {
+		t.Fatalf("failed to decode trace result: %v", err)
+	}
+	return trace, state.StateDB
+}
+
+// Tests that the transfer tracer reports the tip to the coinbase and the burnt
+// base fee after London, both paid by the fee payer of a sponsored transaction.
+func TestTransferTracerFeePayer(t *testing.T) {
+	var (
+		origin   = common.HexToAddress("0x00000000000000000000000000000000feed")
+		payer    = common.HexToAddress("0x000000000000000000000000000000000000fa")
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		coinbase = common.HexToAddress("0x00000000000000000000000000000000c0ffee")
+		context  = vm.BlockContext{
+			Coinbase:    coinbase,
+			BlockNumber: big.NewInt(1),
+			Time:        5,
+			Difficulty:  big.NewInt(0x30000),
+			GasLimit:    uint64(6000000),
+			BaseFee:     big.NewInt(10),
+		}
+		msg = &core.Message{
+			To:        &to,
+			From:      origin,
+			FeePayer:  &payer,
+			Value:     big.NewInt(1000),
+			GasLimit:  params.TxGas,
+			GasPrice:  big.NewInt(12),
+			GasFeeCap: big.NewInt(12),
+			GasTipCap: big.NewInt(2),
+		}
+	)
+	trace, statedb := traceTransfers(t, params.TestChainConfig, context, types.GenesisAlloc{
+		origin: {Balance: big.NewInt(1000)},
+		payer:  {Balance: big.NewInt(params.Ether)},
+	}, msg)
+
+	want := []struct {
+		from, to common.Address
+		value    int64
+		typ      string
+	}{
+		{origin, to, 1000, "call"},
+		{payer, coinbase, 2 * int64(params.TxGas), "fee"},
+		{payer, common.Address{}, 10 * int64(params.TxGas), "burn"},
+	}
+	if len(trace.Transfers) != len(want) {
+		t.Fatalf("transfer count mismatch: have %d, want %d", len(trace.Transfers), len(want))
+	}
+	for i, w := range want {
+		have := trace.Transfers[i]
+		if have.From != w.from || have.To != w.to || have.Value.ToInt().Int64() != w.value || have.Type != w.typ {
+			t.Errorf("transfer %d mismatch: have %+v, want %+v", i, have, w)
+		}
+	}
+	deltas := map[common.Address]int64{
+		origin:   -1000,
+		to:       1000,
+		coinbase: 2 * int64(params.TxGas),
+		payer:    -12 * int64(params.TxGas),
+	}
+	if len(trace.BalanceDeltas) != len(deltas) {
+		t.Fatalf("balance delta count mismatch: have %v, want %v", trace.BalanceDeltas, deltas)
+	}
+	for addr, delta := range deltas {
+		if have := trace.BalanceDeltas[addr]; have != hexDelta(delta) {
+			t.Errorf("balance delta mismatch of %x: have %v, want %d", addr, have, delta)
+		}
+	}
+	// Cross-check the fees with the balances of the payer and the coinbase
+	if have := statedb.GetBalance(payer).ToBig(); new(big.Int).Sub(big.NewInt(params.Ether), have).Int64() != 12*int64(params.TxGas) {
+		t.Errorf("payer balance mismatch: have %v", have)
+	}
+	if have := statedb.GetBalance(coinbase); have.Uint64() != 2*params.TxGas {
+		t.Errorf("coinbase balance mismatch: have %v, want %v", have, 2*params.TxGas)
+	}
+}
+
+// Tests that the transfer tracer reports the balance sent to the beneficiary
+// of a self-destruct.
+func TestTransferTracerSelfDestruct(t *testing.T) {
+	var (
+		origin      = common.HexToAddress("0x00000000000000000000000000000000feed")
+		destructed  = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		beneficiary = common.HexToAddress("0x000000000000000000000000000000000000be")
+		code        = append(append([]byte{byte(vm.PUSH20)}, beneficiary.Bytes()...), byte(vm.SELFDESTRUCT))
+		context     = vm.BlockContext{
+			BlockNumber: new(big.Int).SetUint64(8000000),
+			Time:        5,
+			Difficulty:  big.NewInt(0x30000),
+			GasLimit:    uint64(6000000),
+		}
+		msg = &core.Message{
+			To:        &destructed,
+			From:      origin,
+			Value:     big.NewInt(0),
+			GasLimit:  80000,
+			GasPrice:  big.NewInt(0),
+			GasFeeCap: big.NewInt(0),
+			GasTipCap: big.NewInt(0),
+		}
+	)
+	trace, _ := traceTransfers(t, params.MainnetChainConfig, context, types.GenesisAlloc{
+		origin:     {Balance: big.NewInt(1000)},
+		destructed: {Code: code, Balance: big.NewInt(50)},
+	}, msg)
+
+	if len(trace.Transfers) != 1 {
+		t.Fatalf("transfer count mismatch: have %+v, want 1", trace.Transfers)
+	}
+	have := trace.Transfers[0]
+	if have.From != destructed || have.To != beneficiary || have.Value.ToInt().Int64() != 50 || have.Type != "selfdestruct" || have.Depth != 1 {
+		t.Errorf("transfer mismatch: %+v", have)
+	}
+	if delta := trace.BalanceDeltas[beneficiary]; delta != hexDelta(50) {
+		t.Errorf("beneficiary delta mismatch: have %v, want 50", delta)
+	}
+	if delta := trace.BalanceDeltas[destructed]; delta != hexDelta(-50) {
+		t.Errorf("destructed delta mismatch: have %v, want -50", delta)
+	}
+}
+
+// Tests that the transfer tracer reports the block and uncle rewards as minted
+// transfers, not debiting the zero address.
+func TestTransferTracerRewards(t *testing.T) {
+	var (
+		coinbase = common.HexToAddress("0x00000000000000000000000000000000c0ffee")
+		uncle    = &types.Header{Number: big.NewInt(7999999), Coinbase: common.HexToAddress("0x000000000000000000000000000000000000ac")}
+		header   = &types.Header{Number: big.NewInt(8000000), Coinbase: coinbase}
+		rewards  = ethash.NewFaker().BlockRewards(params.MainnetChainConfig, header, []*types.Header{uncle}, nil)
+	)
+	tracer, err := tracers.DefaultDirectory.New("transferTracer", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create transfer tracer: %v", err)
+	}
+	tracer.(tracers.RewardTracer).CaptureRewards(rewards)
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var trace transferTrace
+	if err := json.Unmarshal(res, &trace); err != nil {
+		t.Fatalf("failed to decode trace result: %v", err)
+	}
+	// The uncle is one block behind, earning 7/8 of the 2 ether reward, while
+	// the miner earns an extra 1/32 for including it.
+	var (
+		uncleReward = new(big.Int).Mul(big.NewInt(175), big.NewInt(params.Ether/100))
+		blockReward = new(big.Int).Mul(big.NewInt(20625), big.NewInt(params.Ether/10000))
+	)
+	if len(trace.Transfers) != 2 {
+		t.Fatalf("transfer count mismatch: have %+v, want 2", trace.Transfers)
+	}
+	if have := trace.Transfers[0]; have.From != (common.Address{}) || have.To != uncle.Coinbase || have.Value.ToInt().Cmp(uncleReward) != 0 || have.Type != "uncleReward" {
+		t.Errorf("uncle reward mismatch: %+v", have)
+	}
+	if have := trace.Transfers[1]; have.From != (common.Address{}) || have.To != coinbase || have.Value.ToInt().Cmp(blockReward) != 0 || have.Type != "blockReward" {
+		t.Errorf("block reward mismatch: %+v", have)
+	}
+	if _, ok := trace.BalanceDeltas[common.Address{}]; ok || len(trace.BalanceDeltas) != 2 {
+		t.Errorf("balance deltas mismatch: %v", trace.BalanceDeltas)
+	}
+}
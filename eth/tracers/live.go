@@ -0,0 +1,338 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// liveTraceFileSpan is the number of blocks whose traces are written to the
+// same file by the file sink.
+const liveTraceFileSpan = 100000
+
+// LiveTxTrace is the trace of a transaction recorded by the live tracer.
+type LiveTxTrace struct {
+	TxHash common.Hash     `json:"txHash"`           // transaction hash
+	Result json.RawMessage `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string          `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// LiveBlockTrace is the traces of the transactions of an imported block.
+type LiveBlockTrace struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Traces     []*LiveTxTrace `json:"traces"`
+}
+
+// LiveSink is the destination of the traces recorded by the live tracer.
+type LiveSink interface {
+	// WriteBlockTrace records the traces of an imported block.
+	WriteBlockTrace(trace *LiveBlockTrace) error
+
+	// Close releases the resources held by the sink.
+	Close() error
+}
+
+// LiveTracer is a block tracer attached to the chain, tracing the transactions
+// of the blocks as they are imported or sealed locally with a tracer of the
+// default directory and handing the results over to a sink.
+type LiveTracer struct {
+	name   string
+	config json.RawMessage
+	sink   LiveSink
+
+	block   *LiveBlockTrace // Traces of the block being processed
+	txctx   *Context        // Context of the transaction being processed
+	current Tracer          // Tracer of the transaction being processed
+}
+
+// NewLiveTracer creates a live tracer, tracing each transaction with a new
+// instance of the named tracer.
+func NewLiveTracer(name string, config json.RawMessage, sink LiveSink) (*LiveTracer, error) {
+	if _, ok := DefaultDirectory.elems[name]; !ok && DefaultDirectory.jsEval == nil {
+		return nil, fmt.Errorf("unknown tracer %q", name)
+	}
+	// Instantiate the tracer once to validate its config early
+	if _, err := DefaultDirectory.New(name, new(Context), config); err != nil {
+		return nil, err
+	}
+	return &LiveTracer{name: name, config: config, sink: sink}, nil
+}
+
+// Close closes the sink of the tracer.
+func (t *LiveTracer) Close() error {
+	return t.sink.Close()
+}
+
+// OnBlockStart implements core.BlockTracer, starting to record the traces of
+// the block.
+func (t *LiveTracer) OnBlockStart(block *types.Block) {
+	t.block = &LiveBlockTrace{
+		Number:     hexutil.Uint64(block.NumberU64()),
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+		Traces:     make([]*LiveTxTrace, 0, len(block.Transactions())),
+	}
+	t.txctx, t.current = nil, nil
+}
+
+// OnTxStart implements core.BlockTracer, creating the tracer of the transaction.
+func (t *LiveTracer) OnTxStart(tx *types.Transaction, index int, from common.Address) {
+	if t.block == nil {
+		return
+	}
+	t.txctx = &Context{
+		BlockHash:   t.block.Hash,
+		BlockNumber: new(big.Int).SetUint64(uint64(t.block.Number)),
+		TxIndex:     index,
+		TxHash:      tx.Hash(),
+	}
+	tracer, err := DefaultDirectory.New(t.name, t.txctx, t.config)
+	if err != nil {
+		log.Warn("Failed to create live tracer", "tracer", t.name, "err", err)
+		return
+	}
+	t.current = tracer
+}
+
+// OnTxEnd implements core.BlockTracer, recording the result of the tracer of
+// the transaction.
+func (t *LiveTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	if t.block == nil || t.txctx == nil {
+		return
+	}
+	trace := &LiveTxTrace{TxHash: t.txctx.TxHash}
+	switch {
+	case err != nil:
+		trace.Error = err.Error()
+	case t.current == nil:
+		trace.Error = "tracer not created"
+	default:
+		if trace.Result, err = t.current.GetResult(); err != nil {
+			trace.Error = err.Error()
+		}
+	}
+	t.block.Traces = append(t.block.Traces, trace)
+	t.txctx, t.current = nil, nil
+}
+
+// OnBlockEnd implements core.BlockTracer, handing the traces of the block over
+// to the sink if it was imported.
+func (t *LiveTracer) OnBlockEnd(err error) {
+	block := t.block
+	t.block, t.txctx, t.current = nil, nil, nil
+
+	if block == nil || err != nil {
+		return
+	}
+	if err := t.sink.WriteBlockTrace(block); err != nil {
+		log.Error("Failed to write live trace", "number", uint64(block.Number), "hash", block.Hash, "err", err)
+	}
+}
+
+func (t *LiveTracer) CaptureTxStart(gasLimit uint64) {
+	if t.current != nil {
+		t.current.CaptureTxStart(gasLimit)
+	}
+}
+
+func (t *LiveTracer) CaptureTxEnd(restGas uint64) {
+	if t.current != nil {
+		t.current.CaptureTxEnd(restGas)
+	}
+}
+
+func (t *LiveTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	if t.current != nil {
+		t.current.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+func (t *LiveTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if t.current != nil {
+		t.current.CaptureEnd(output, gasUsed, err)
+	}
+}
+
+func (t *LiveTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.current != nil {
+		t.current.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *LiveTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.current != nil {
+		t.current.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *LiveTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.current != nil {
+		t.current.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *LiveTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.current != nil {
+		t.current.CaptureFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+var _ core.BlockTracer = (*LiveTracer)(nil)
+
+// LiveTraces returns the traces of a block recorded by the live tracer as it
+// was imported, if the traces are stored in the database.
+func (api *API) LiveTraces(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*LiveTxTrace, error) {
+	var (
+		header *types.Header
+		err    error
+	)
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header, err = api.backend.HeaderByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		header, err = api.backend.HeaderByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("block not found")
+	}
+	enc := rawdb.ReadLiveTrace(api.backend.ChainDb(), header.Hash(), header.Number.Uint64())
+	if enc == nil {
+		return nil, fmt.Errorf("no live traces recorded for block #%d", header.Number)
+	}
+	var traces []*LiveTxTrace
+	if err := json.Unmarshal(enc, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// databaseSink is a live sink storing the traces of each block in the chain
+// database, next to its receipts. Only the traces of the recent blocks are
+// kept, the ones falling out of the history window being deleted as new
+// blocks are traced, starting from a persisted tail marker.
+type databaseSink struct {
+	db      ethdb.KeyValueStore
+	history uint64 // Number of recent blocks to keep the traces of, 0 for all
+}
+
+// NewDatabaseSink creates a live sink storing the traces in the database, for
+// the given number of recent blocks (0 = entire chain).
+func NewDatabaseSink(db ethdb.KeyValueStore, history uint64) LiveSink {
+	return &databaseSink{db: db, history: history}
+}
+
+func (s *databaseSink) WriteBlockTrace(trace *LiveBlockTrace) error {
+	enc, err := json.Marshal(trace.Traces)
+	if err != nil {
+		return err
+	}
+	number := uint64(trace.Number)
+	rawdb.WriteLiveTrace(s.db, trace.Hash, number, enc)
+
+	// Track the oldest block with stored traces across restarts, rewinds and
+	// changes of the window, and delete everything from it up to the window.
+	// Without a tail, the traces of any earlier run are swept once.
+	var tail uint64
+	if stored := rawdb.ReadLiveTraceTail(s.db); stored != nil {
+		tail = *stored
+	}
+	if number < tail {
+		tail = number
+		rawdb.WriteLiveTraceTail(s.db, tail)
+	}
+	if s.history > 0 && number >= s.history {
+		if limit := number - s.history + 1; tail < limit {
+			if err := rawdb.DeleteLiveTracesRange(s.db, tail, limit); err != nil {
+				return err
+			}
+			rawdb.WriteLiveTraceTail(s.db, limit)
+		}
+	}
+	return nil
+}
+
+func (s *databaseSink) Close() error { return nil }
+
+// fileSink is a live sink appending the traces of each block as a JSON line to
+// files in a directory, each file holding a span of blocks. The traces of the
+// blocks reorged out are not removed: the latest line of a block number is the
+// one of the canonical block.
+type fileSink struct {
+	dir  string
+	span uint64   // Span of the blocks written to the open file
+	file *os.File // File of the span, nil if none is open
+}
+
+// NewFileSink creates a live sink writing the traces as JSON lines to files in
+// the given directory.
+func NewFileSink(dir string) (LiveSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileSink{dir: dir}, nil
+}
+
+func (s *fileSink) WriteBlockTrace(trace *LiveBlockTrace) error {
+	enc, err := json.Marshal(trace)
+	if err != nil {
+		return err
+	}
+	if span := uint64(trace.Number) / liveTraceFileSpan; s.file == nil || span != s.span {
+		if err := s.Close(); err != nil {
+			return err
+		}
+		name := filepath.Join(s.dir, fmt.Sprintf("traces-%09d.jsonl", span*liveTraceFileSpan))
+		file, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		s.file, s.span = file, span
+	}
+	_, err = s.file.Write(append(enc, '\n'))
+	return err
+}
+
+func (s *fileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,273 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	DefaultDirectory.Register("liveTestTracer", func(ctx *Context, cfg json.RawMessage) (Tracer, error) {
+		return logger.NewStructLogger(&logger.Config{DisableStack: true}), nil
+	}, false)
+}
+
+// Tests that the live tracer traces the transactions of the imported blocks
+// into the database and JSONL files.
+func TestLiveTracer(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	signer := types.HomesteadSigner{}
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 4, func(i int, b *core.BlockGen) {
+		// Blocks hold as many transfers as their number, minus one
+		for j := 0; j < i; j++ {
+			tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    b.TxNonce(accounts[0].addr),
+				To:       &accounts[1].addr,
+				Value:    big.NewInt(1000),
+				Gas:      params.TxGas,
+				GasPrice: b.BaseFee(),
+			}), signer, accounts[0].key)
+			b.AddTx(tx)
+		}
+	})
+	if _, err := NewLiveTracer("unknownTracer", nil, NewDatabaseSink(rawdb.NewMemoryDatabase(), 0)); err == nil {
+		t.Fatal("expected error for unknown tracer")
+	}
+	dir := t.TempDir()
+	fileSink, err := NewFileSink(dir)
+	if err != nil {
+		t.Fatalf("failed to create file sink: %v", err)
+	}
+	for _, output := range []string{"database", "file"} {
+		db := rawdb.NewMemoryDatabase()
+		sink := NewDatabaseSink(db, 0)
+		if output == "file" {
+			sink = fileSink
+		}
+		tracer, err := NewLiveTracer("liveTestTracer", nil, sink)
+		if err != nil {
+			t.Fatalf("failed to create live tracer: %v", err)
+		}
+		chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{Tracer: tracer}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create chain: %v", err)
+		}
+		if n, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+		}
+		chain.Stop()
+		if err := tracer.Close(); err != nil {
+			t.Fatalf("failed to close live tracer: %v", err)
+		}
+		if output == "file" {
+			continue
+		}
+		api := NewAPI(&testBackend{chainConfig: genesis.Config, engine: chain.Engine(), chaindb: db, chain: chain})
+		for _, block := range blocks {
+			traces, err := api.LiveTraces(context.Background(), rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+			if err != nil {
+				t.Fatalf("block %d: failed to retrieve live traces: %v", block.NumberU64(), err)
+			}
+			checkLiveTraces(t, block, traces)
+		}
+		if _, err := api.LiveTraces(context.Background(), rpc.BlockNumberOrHashWithNumber(0)); err == nil {
+			t.Fatal("expected error for untraced block")
+		}
+	}
+	file, err := os.Open(filepath.Join(dir, "traces-000000000.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open trace file: %v", err)
+	}
+	defer file.Close()
+
+	var lines int
+	for scanner := bufio.NewScanner(file); scanner.Scan(); lines++ {
+		var trace LiveBlockTrace
+		if err := json.Unmarshal(scanner.Bytes(), &trace); err != nil {
+			t.Fatalf("line %d: failed to decode trace: %v", lines, err)
+		}
+		block := blocks[lines]
+		if uint64(trace.Number) != block.NumberU64() || trace.Hash != block.Hash() || trace.ParentHash != block.ParentHash() {
+			t.Errorf("line %d: wrong block: have #%d %x, want #%d %x", lines, trace.Number, trace.Hash, block.NumberU64(), block.Hash())
+		}
+		checkLiveTraces(t, block, trace.Traces)
+	}
+	if lines != len(blocks) {
+		t.Errorf("wrong number of traced blocks: have %d, want %d", lines, len(blocks))
+	}
+}
+
+// Tests that the live tracer traces the blocks sealed by the local miner, which
+// are written to the chain without being imported.
+func TestLiveTracerSealedBlocks(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 1, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    b.TxNonce(accounts[0].addr),
+			To:       &accounts[1].addr,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+	})
+	db := rawdb.NewMemoryDatabase()
+	tracer, err := NewLiveTracer("liveTestTracer", nil, NewDatabaseSink(db, 0))
+	if err != nil {
+		t.Fatalf("failed to create live tracer: %v", err)
+	}
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{Tracer: tracer}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Execute the block the way the miner does, without the tracer
+	block := blocks[0]
+	statedb, err := chain.StateAt(chain.Genesis().Root())
+	if err != nil {
+		t.Fatalf("failed to retrieve genesis state: %v", err)
+	}
+	receipts, logs, _, err := chain.Processor().Process(block, statedb, vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to process block: %v", err)
+	}
+	if _, err := chain.WriteBlockAndSetHead(block, receipts, logs, statedb, false); err != nil {
+		t.Fatalf("failed to write sealed block: %v", err)
+	}
+	api := NewAPI(&testBackend{chainConfig: genesis.Config, engine: chain.Engine(), chaindb: db, chain: chain})
+	traces, err := api.LiveTraces(context.Background(), rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		t.Fatalf("failed to retrieve live traces of sealed block: %v", err)
+	}
+	checkLiveTraces(t, block, traces)
+}
+
+// Tests that the database sink only keeps the traces of the recent blocks,
+// including the ones of the blocks reorged out.
+func TestDatabaseSinkHistory(t *testing.T) {
+	var (
+		db   = rawdb.NewMemoryDatabase()
+		sink = NewDatabaseSink(db, 2)
+	)
+	write := func(number uint64, hash common.Hash) {
+		t.Helper()
+		if err := sink.WriteBlockTrace(&LiveBlockTrace{Number: hexutil.Uint64(number), Hash: hash}); err != nil {
+			t.Fatalf("block %d: failed to write trace: %v", number, err)
+		}
+	}
+	write(1, common.Hash{0x01})
+	write(1, common.Hash{0x02})
+	for number := uint64(2); number <= 4; number++ {
+		write(number, common.Hash{byte(number)})
+	}
+	for _, hash := range []common.Hash{{0x01}, {0x02}} {
+		if rawdb.ReadLiveTrace(db, hash, 1) != nil {
+			t.Errorf("trace of block 1 %x retained", hash)
+		}
+	}
+	if rawdb.ReadLiveTrace(db, common.Hash{0x02}, 2) != nil {
+		t.Errorf("trace of block 2 retained")
+	}
+	for number := uint64(3); number <= 4; number++ {
+		if rawdb.ReadLiveTrace(db, common.Hash{byte(number)}, number) == nil {
+			t.Errorf("trace of block %d missing", number)
+		}
+	}
+	check := func(number uint64, retained bool) {
+		t.Helper()
+		if have := rawdb.ReadLiveTrace(db, common.Hash{byte(number)}, number) != nil; have != retained {
+			t.Errorf("trace of block %d retained mismatch: have %v, want %v", number, have, retained)
+		}
+	}
+	// Traces stored by an earlier run without pruning are deleted from the tail
+	// on restart, not just the block leaving the window
+	for number := uint64(5); number <= 8; number++ {
+		rawdb.WriteLiveTrace(db, common.Hash{byte(number)}, number, []byte("[]"))
+	}
+	sink = NewDatabaseSink(db, 2)
+	write(9, common.Hash{0x09})
+	for number := uint64(3); number <= 7; number++ {
+		check(number, false)
+	}
+	check(8, true)
+	check(9, true)
+
+	// Blocks traced again after a rewind below the tail are pruned too
+	write(5, common.Hash{0x05})
+	write(6, common.Hash{0x06})
+	write(10, common.Hash{0x0a})
+	for number := uint64(5); number <= 8; number++ {
+		check(number, false)
+	}
+	check(9, true)
+	check(10, true)
+}
+
+func checkLiveTraces(t *testing.T, block *types.Block, traces []*LiveTxTrace) {
+	t.Helper()
+
+	if len(traces) != len(block.Transactions()) {
+		t.Fatalf("block %d: wrong number of traces: have %d, want %d", block.NumberU64(), len(traces), len(block.Transactions()))
+	}
+	for i, trace := range traces {
+		if trace.TxHash != block.Transactions()[i].Hash() || trace.Error != "" {
+			t.Errorf("block %d, tx %d: wrong trace: %+v", block.NumberU64(), i, trace)
+			continue
+		}
+		var result logger.ExecutionResult
+		if err := json.Unmarshal(trace.Result, &result); err != nil {
+			t.Fatalf("block %d, tx %d: failed to decode result: %v", block.NumberU64(), i, err)
+		}
+		if result.Gas != params.TxGas || result.Failed {
+			t.Errorf("block %d, tx %d: wrong result: %+v", block.NumberU64(), i, result)
+		}
+	}
+}
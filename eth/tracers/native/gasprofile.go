@@ -0,0 +1,307 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/google/pprof/profile"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("gasProfiler", newGasProfiler, false)
+}
+
+// Output formats of the gas profiler.
+const (
+	GasProfileFolded = "folded" // Folded stacks, as consumed by flamegraph tools
+	GasProfilePprof  = "pprof"  // Gzipped pprof protobuf
+)
+
+// Leaves of the gas spent outside of the opcodes of a call frame.
+const (
+	gasLeafIntrinsic  = "(intrinsic)"  // Intrinsic gas of the transaction
+	gasLeafPrecompile = "(precompile)" // Gas used by a precompiled contract
+)
+
+// gasSample identifies the gas spent by an opcode in a call stack.
+type gasSample struct {
+	stack string // Labels of the call frames, outermost first, separated by ';'
+	leaf  string // Opcode, or the source of the gas spent outside of opcodes
+}
+
+// gasFrame is a call frame being profiled.
+type gasFrame struct {
+	stack string // Labels of the frame and its parents
+	gas   uint64 // Gas available to the frame on entry
+	ops   bool   // Whether the frame executed any opcode
+
+	pending    string // Opcode being executed, whose gas isn't settled yet
+	pendingGas uint64 // Gas available before the pending opcode
+	childUsed  uint64 // Gas used by the frames entered by the pending opcode
+}
+
+type gasProfilerConfig struct {
+	Format string `json:"format"` // Output format, folded (default) or pprof
+}
+
+// gasProfiler aggregates the gas spent by a transaction per call stack, each
+// call frame labelled by its address and the 4-byte selector of its input, and
+// per opcode. The gas of an opcode excludes the gas used by the frames it
+// enters, which is accounted to their own stacks; refunds are not deducted.
+//
+// The result is either the folded stacks as a string, one "stack gas" line per
+// stack, or a gzipped pprof profile encoded as base64 JSON string.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x214e...", {tracer: "gasProfiler"})
+//	"0x7156...17F7;(intrinsic) 21000\n0x7156...17F7;PUSH1 9\n0x7156...17F7;0x7f0d...A842:0xa9059cbb;SSTORE 20000\n..."
+type gasProfiler struct {
+	noopTracer
+	config    gasProfilerConfig
+	samples   map[gasSample]uint64
+	frames    []*gasFrame
+	gasLimit  uint64
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newGasProfiler returns a native go tracer which profiles the gas used by a
+// tx, and implements vm.EVMLogger.
+func newGasProfiler(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config gasProfilerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	switch config.Format {
+	case "":
+		config.Format = GasProfileFolded
+	case GasProfileFolded, GasProfilePprof:
+	default:
+		return nil, fmt.Errorf("unknown gas profile format %q", config.Format)
+	}
+	return &gasProfiler{
+		config:  config,
+		samples: make(map[gasSample]uint64),
+	}, nil
+}
+
+// frameLabel returns the label of a call frame in the stacks: its address,
+// followed by the selector of its input if any.
+func frameLabel(addr common.Address, input []byte, create bool) string {
+	switch {
+	case create:
+		return addr.Hex() + ":create"
+	case len(input) >= 4:
+		return addr.Hex() + ":" + hexutil.Encode(input[:4])
+	default:
+		return addr.Hex()
+	}
+}
+
+// enter opens a call frame with the given gas.
+func (t *gasProfiler) enter(label string, gas uint64) {
+	stack := label
+	if len(t.frames) > 0 {
+		stack = t.frames[len(t.frames)-1].stack + ";" + label
+	}
+	t.frames = append(t.frames, &gasFrame{stack: stack, gas: gas})
+}
+
+// settle accounts the gas spent by the pending opcode of a frame, given the
+// gas left after it.
+func (t *gasProfiler) settle(frame *gasFrame, gasLeft uint64) {
+	if frame.pending == "" {
+		return
+	}
+	// Gas handed over to children is returned minus what they used
+	if spent := int64(frame.pendingGas) - int64(gasLeft) - int64(frame.childUsed); spent > 0 {
+		t.samples[gasSample{frame.stack, frame.pending}] += uint64(spent)
+	}
+	frame.pending, frame.childUsed = "", 0
+}
+
+// exit closes the innermost call frame, accounting the gas it used.
+func (t *gasProfiler) exit(gasUsed uint64) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+
+	if gasUsed > frame.gas {
+		gasUsed = frame.gas
+	}
+	if !frame.ops && gasUsed > 0 {
+		t.samples[gasSample{frame.stack, gasLeafPrecompile}] += gasUsed
+	}
+	t.settle(frame, frame.gas-gasUsed)
+
+	if len(t.frames) > 0 {
+		t.frames[len(t.frames)-1].childUsed += gasUsed
+	}
+}
+
+func (t *gasProfiler) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *gasProfiler) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.enter(frameLabel(to, input, create), gas)
+	if t.gasLimit > gas {
+		t.samples[gasSample{t.frames[0].stack, gasLeafIntrinsic}] += t.gasLimit - gas
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *gasProfiler) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.exit(gasUsed)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *gasProfiler) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.settle(frame, gas)
+
+	frame.ops = true
+	frame.pending, frame.pendingGas = op.String(), gas
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *gasProfiler) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.enter(frameLabel(to, input, typ == vm.CREATE || typ == vm.CREATE2), gas)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *gasProfiler) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.exit(gasUsed)
+}
+
+// sortedSamples returns the samples ordered by stack and leaf.
+func (t *gasProfiler) sortedSamples() []gasSample {
+	samples := make([]gasSample, 0, len(t.samples))
+	for sample := range t.samples {
+		samples = append(samples, sample)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].stack != samples[j].stack {
+			return samples[i].stack < samples[j].stack
+		}
+		return samples[i].leaf < samples[j].leaf
+	})
+	return samples
+}
+
+// folded renders the samples as folded stacks.
+func (t *gasProfiler) folded() string {
+	var b strings.Builder
+	for _, sample := range t.sortedSamples() {
+		fmt.Fprintf(&b, "%s;%s %d\n", sample.stack, sample.leaf, t.samples[sample])
+	}
+	return b.String()
+}
+
+// pprof renders the samples as a gzipped pprof profile, each element of the
+// stacks being a function.
+func (t *gasProfiler) pprof() ([]byte, error) {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "gas", Unit: "gas"}},
+		PeriodType: &profile.ValueType{Type: "gas", Unit: "gas"},
+		Period:     1,
+	}
+	locations := make(map[string]*profile.Location)
+	location := func(name string) *profile.Location {
+		if loc, ok := locations[name]; ok {
+			return loc
+		}
+		fn := &profile.Function{ID: uint64(len(p.Function) + 1), Name: name, SystemName: name}
+		loc := &profile.Location{ID: uint64(len(p.Location) + 1), Line: []profile.Line{{Function: fn}}}
+		p.Function = append(p.Function, fn)
+		p.Location = append(p.Location, loc)
+		locations[name] = loc
+		return loc
+	}
+	for _, sample := range t.sortedSamples() {
+		// Locations of a sample are ordered from the leaf to the root
+		names := append(strings.Split(sample.stack, ";"), sample.leaf)
+		locs := make([]*profile.Location, len(names))
+		for i, name := range names {
+			locs[len(names)-1-i] = location(name)
+		}
+		p.Sample = append(p.Sample, &profile.Sample{
+			Location: locs,
+			Value:    []int64{int64(t.samples[sample])},
+		})
+	}
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetResult returns the json-encoded gas profile in the configured format, and
+// any error arising from the encoding or forceful termination (via `Stop`).
+func (t *gasProfiler) GetResult() (json.RawMessage, error) {
+	var (
+		res []byte
+		err error
+	)
+	if t.config.Format == GasProfilePprof {
+		var enc []byte
+		if enc, err = t.pprof(); err != nil {
+			return nil, err
+		}
+		res, err = json.Marshal(enc)
+	} else {
+		res, err = json.Marshal(t.folded())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *gasProfiler) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
@@ -0,0 +1,272 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("transferTracer", newTransferTracer, false)
+}
+
+// Types of the value transfers reported by the transfer tracer.
+const (
+	transferCall         = "call"         // Value sent along with a call
+	transferCreate       = "create"       // Value endowed to a created contract
+	transferSelfDestruct = "selfdestruct" // Balance sent to the beneficiary of a self-destruct
+	transferFee          = "fee"          // Priority fee paid to the coinbase
+	transferBurn         = "burn"         // Base and blob fees burnt, sent to the zero address
+)
+
+// valueTransfer is a movement of ether between two accounts.
+type valueTransfer struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+	Type  string         `json:"type"`
+	Depth int            `json:"depth"`
+}
+
+// transferResult is the output of the transfer tracer.
+type transferResult struct {
+	Transfers     []*valueTransfer                `json:"transfers"`
+	BalanceDeltas map[common.Address]*hexutil.Big `json:"balanceDeltas"`
+}
+
+// transferTracer collects the flat list of the value transfers made by a
+// transaction: those of its calls, creations and self-destructs which weren't
+// reverted, and the payment of its fees. Along with them, it sums up the
+// resulting balance change of every account involved.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x214e...", {tracer: "transferTracer"})
+//	{
+//	  transfers: [{
+//	      from: "0x71562b71999873db5b286df957af199ec94617f7",
+//	      to: "0x7f0d15c7faae65896648c8273b6d7e43f58fa842",
+//	      value: "0xde0b6b3a7640000",
+//	      type: "call",
+//	      depth: 0
+//	  }, ...],
+//	  balanceDeltas: {
+//	    0x71562b71999873db5b286df957af199ec94617f7: "-0xde0d8d7e8c15000",
+//	    ...
+//	  }
+//	}
+//
+// Burnt fees are reported as transfers to the zero address, which isn't
+// credited in the balance deltas. Block rewards aren't part of any transaction:
+// when tracing blocks with the rewards option set, they are reported by a
+// dedicated trace following the ones of the transactions, as transfers from the
+// zero address which isn't debited in the balance deltas.
+type transferTracer struct {
+	noopTracer
+	env       *vm.EVM
+	frames    [][]*valueTransfer // Transfers of the active call frames, innermost last
+	transfers []*valueTransfer   // Transfers of the frames which completed successfully
+	minted    bool               // Whether the transfers are block rewards minted from the zero address
+	gasLimit  uint64
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newTransferTracer returns a native go tracer which collects the value
+// transfers of a tx, and implements vm.EVMLogger.
+func newTransferTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &transferTracer{}, nil
+}
+
+var _ tracers.RewardTracer = (*transferTracer)(nil)
+
+// enter opens a call frame, along with the transfer it makes if any.
+func (t *transferTracer) enter(typ string, from, to common.Address, value *big.Int) {
+	var frame []*valueTransfer
+	if value != nil && value.Sign() > 0 {
+		frame = append(frame, &valueTransfer{
+			From:  from,
+			To:    to,
+			Value: (*hexutil.Big)(new(big.Int).Set(value)),
+			Type:  typ,
+			Depth: len(t.frames),
+		})
+	}
+	t.frames = append(t.frames, frame)
+}
+
+// exit closes the innermost call frame, merging its transfers into the parent
+// frame unless it failed.
+func (t *transferTracer) exit(err error) []*valueTransfer {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if err != nil {
+		return nil
+	}
+	if len(t.frames) > 0 {
+		t.frames[len(t.frames)-1] = append(t.frames[len(t.frames)-1], frame...)
+	}
+	return frame
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+// CaptureTxEnd reports the fees paid by the transaction, once the unused gas
+// has been refunded.
+func (t *transferTracer) CaptureTxEnd(restGas uint64) {
+	if t.env == nil || t.env.TxContext.GasPrice == nil {
+		return
+	}
+	var (
+		ctx   = t.env.Context
+		payer = t.env.TxContext.GasPayer
+		paid  = new(big.Int).SetUint64(t.gasLimit - restGas)
+		tip   = new(big.Int)
+	)
+	if payer == (common.Address{}) {
+		payer = t.env.TxContext.Origin
+	}
+	paid.Mul(paid, t.env.TxContext.GasPrice)
+	if !t.env.ChainConfig().IsLondon(ctx.BlockNumber) || ctx.BaseFee == nil {
+		tip.Set(paid)
+	} else if price := new(big.Int).Sub(t.env.TxContext.GasPrice, ctx.BaseFee); price.Sign() > 0 {
+		// Transactions priced below the base fee don't tip the coinbase
+		tip.SetUint64(t.gasLimit - restGas)
+		tip.Mul(tip, price)
+	}
+	burnt := new(big.Int).Sub(paid, tip)
+	if blobs := len(t.env.TxContext.BlobHashes); blobs > 0 && ctx.BlobBaseFee != nil {
+		blobFee := new(big.Int).SetUint64(uint64(blobs) * params.BlobTxBlobGasPerBlob)
+		burnt.Add(burnt, blobFee.Mul(blobFee, ctx.BlobBaseFee))
+	}
+	if tip.Sign() > 0 {
+		t.transfers = append(t.transfers, &valueTransfer{From: payer, To: ctx.Coinbase, Value: (*hexutil.Big)(tip), Type: transferFee})
+	}
+	if burnt.Sign() > 0 {
+		t.transfers = append(t.transfers, &valueTransfer{From: payer, Value: (*hexutil.Big)(burnt), Type: transferBurn})
+	}
+}
+
+// CaptureRewards implements the tracers.RewardTracer interface, reporting the
+// rewards credited when finalizing a block.
+func (t *transferTracer) CaptureRewards(rewards []consensus.Reward) {
+	t.minted = true
+	for _, reward := range rewards {
+		if reward.Amount.Sign() > 0 {
+			t.transfers = append(t.transfers, &valueTransfer{To: reward.Beneficiary, Value: (*hexutil.Big)(new(big.Int).Set(reward.Amount)), Type: reward.Kind})
+		}
+	}
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	if create {
+		t.enter(transferCreate, from, to, value)
+	} else {
+		t.enter(transferCall, from, to, value)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.transfers = append(t.transfers, t.exit(err)...)
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	switch typ {
+	case vm.CALL:
+		t.enter(transferCall, from, to, value)
+	case vm.CREATE, vm.CREATE2:
+		t.enter(transferCreate, from, to, value)
+	case vm.SELFDESTRUCT:
+		t.enter(transferSelfDestruct, from, to, value)
+	default:
+		// Delegated and static calls don't move any value
+		t.enter("", from, to, nil)
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.exit(err)
+}
+
+// GetResult returns the json-encoded list of value transfers along with the
+// balance deltas, and any error arising from the encoding or forceful
+// termination (via `Stop`).
+func (t *transferTracer) GetResult() (json.RawMessage, error) {
+	result := transferResult{
+		Transfers:     t.transfers,
+		BalanceDeltas: make(map[common.Address]*hexutil.Big),
+	}
+	if result.Transfers == nil {
+		result.Transfers = []*valueTransfer{}
+	}
+	deltas := make(map[common.Address]*big.Int)
+	credit := func(addr common.Address, value *big.Int) {
+		if deltas[addr] == nil {
+			deltas[addr] = new(big.Int)
+		}
+		deltas[addr].Add(deltas[addr], value)
+	}
+	for _, transfer := range t.transfers {
+		if !t.minted {
+			credit(transfer.From, new(big.Int).Neg(transfer.Value.ToInt()))
+		}
+		if transfer.Type != transferBurn {
+			credit(transfer.To, transfer.Value.ToInt())
+		}
+	}
+	for addr, delta := range deltas {
+		result.BalanceDeltas[addr] = (*hexutil.Big)(delta)
+	}
+	res, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *transferTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
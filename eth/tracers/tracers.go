@@ -24,6 +24,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
 
@@ -45,6 +46,15 @@ type Tracer interface {
 	Stop(err error)
 }
 
+// RewardTracer is a tracer which also reports the rewards credited by the
+// consensus engine when finalizing a block, outside of any transaction.
+type RewardTracer interface {
+	Tracer
+	// CaptureRewards is called on a fresh tracer with the rewards of a block,
+	// once its transactions have been traced.
+	CaptureRewards(rewards []consensus.Reward)
+}
+
 type ctorFn func(*Context, json.RawMessage) (Tracer, error)
 type jsCtorFn func(string, *Context, json.RawMessage) (Tracer, error)
 
@@ -277,6 +277,16 @@ func (ec *Client) TransactionSender(ctx context.Context, tx *types.Transaction,
 	return meta.From, nil
 }
 
+// TransactionFeePayer returns the account charged for the gas of the given transaction.
+// For sponsored transactions this is the fee payer declared (and countersigned) in
+// the transaction itself, otherwise it's the sender as returned by TransactionSender.
+func (ec *Client) TransactionFeePayer(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	if payer := tx.FeePayer(); payer != nil {
+		return *payer, nil
+	}
+	return ec.TransactionSender(ctx, tx, block, index)
+}
+
 // TransactionCount returns the total number of transactions in the given block.
 func (ec *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
 	var num hexutil.Uint
@@ -18,16 +18,20 @@ package graphql
 
 import (
 	"context"
+	crand "crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
@@ -40,6 +44,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -430,6 +435,118 @@ func TestWithdrawals(t *testing.T) {
 	}
 }
 
+// Tests the PoI and security fields, and that the security policy is only
+// served to requests authorized by the authenticated endpoints' tokens.
+func TestGraphQLPoIAndSecurity(t *testing.T) {
+	var secret [32]byte
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatalf("failed to create jwt secret: %v", err)
+	}
+	jwtPath := filepath.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatalf("failed to prepare jwt secret file: %v", err)
+	}
+	stack, err := node.New(&node.Config{
+		HTTPHost:     "127.0.0.1",
+		HTTPPort:     0,
+		AuthAddr:     "127.0.0.1",
+		AuthPort:     0,
+		JWTSecret:    jwtPath,
+		RPCRoles:     map[string][]string{"auditor": {"security_getWhitelist"}},
+		HTTPTimeouts: node.DefaultConfig.HTTPTimeouts,
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer stack.Close()
+
+	sealer := common.HexToAddress("0x00000000000000000000000000000000005ea1e4")
+	genesis := &core.Genesis{
+		Config:     params.AllEthashProtocolChanges,
+		GasLimit:   11500000,
+		Difficulty: common.Big1,
+	}
+	newGQLService(t, stack, true, genesis, 1, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(sealer)
+	})
+	// Serve a fresh in-memory policy
+	defer core.SetSecurityConfig(core.GetSecurityConfig())
+	policy := core.NewSecurityConfig()
+	policy.AddToWhitelist(common.HexToAddress("0x1"))
+	policy.AddToBlacklist(common.HexToAddress("0x2"))
+	core.SetSecurityConfig(policy)
+
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	for i, tt := range []struct {
+		body string
+		auth rpc.HTTPAuth
+		want string
+		code int
+	}{
+		{
+			body: `{"query": "{block{sealer poiScore}}"}`,
+			want: `{"data":{"block":{"sealer":"0x00000000000000000000000000000000005ea1e4","poiScore":null}}}`,
+			code: 200,
+		},
+		{
+			body: `{"query": "{validators{address}}"}`,
+			want: `{"errors":[{"message":"chain is not running PoI consensus","path":["validators"]}],"data":null}`,
+			code: 400,
+		},
+		{
+			body: `{"query": "{securityPolicy{whitelist}}"}`,
+			want: `{"errors":[{"message":"missing token","path":["securityPolicy","whitelist"]}],"data":null}`,
+			code: 400,
+		},
+		{
+			body: `{"query": "{securityPolicy{whitelist blacklist restrictions{address}}}"}`,
+			auth: node.NewJWTAuth(secret),
+			want: `{"data":{"securityPolicy":{"whitelist":["0x0000000000000000000000000000000000000001"],"blacklist":["0x0000000000000000000000000000000000000002"],"restrictions":[]}}}`,
+			code: 200,
+		},
+		{
+			body: `{"query": "{securityPolicy{whitelist}}"}`,
+			auth: node.NewJWTRoleAuth(secret, "auditor"),
+			want: `{"data":{"securityPolicy":{"whitelist":["0x0000000000000000000000000000000000000001"]}}}`,
+			code: 200,
+		},
+		{
+			body: `{"query": "{securityPolicy{blacklist}}"}`,
+			auth: node.NewJWTRoleAuth(secret, "auditor"),
+			want: `{"errors":[{"message":"not permitted for role \"auditor\"","path":["securityPolicy","blacklist"]}],"data":null}`,
+			code: 400,
+		},
+	} {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatalf("could not create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if tt.auth != nil {
+			if err := tt.auth(req.Header); err != nil {
+				t.Fatalf("could not authenticate request: %v", err)
+			}
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("could not post: %v", err)
+		}
+		bodyBytes, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("could not read from response body: %v", err)
+		}
+		if have := string(bodyBytes); have != tt.want {
+			t.Errorf("testcase %d %s,\nhave:\n%v\nwant:\n%v", i, tt.body, have, tt.want)
+		}
+		if tt.code != resp.StatusCode {
+			t.Errorf("testcase %d %s,\nwrong statuscode, have: %v, want: %v", i, tt.body, resp.StatusCode, tt.code)
+		}
+	}
+}
+
 func createNode(t *testing.T) *node.Node {
 	stack, err := node.New(&node.Config{
 		HTTPHost:     "127.0.0.1",
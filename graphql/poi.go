@@ -0,0 +1,164 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/poi"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+var errNoPoI = errors.New("chain is not running PoI consensus")
+
+// poiAPI returns the PoI API registered on the node, nil if the chain doesn't
+// run PoI consensus.
+func poiAPI(stack *node.Node) *poi.API {
+	for _, api := range stack.RegisteredAPIs() {
+		if service, ok := api.Service.(*poi.API); ok {
+			return service
+		}
+	}
+	return nil
+}
+
+// Validator represents a validator of the PoI consensus.
+type Validator struct {
+	info *poi.ValidatorFullInfo
+	rank int
+}
+
+func (v *Validator) Address(ctx context.Context) common.Address {
+	return v.info.Address
+}
+
+func (v *Validator) Rank(ctx context.Context) int32 {
+	return int32(v.rank)
+}
+
+func (v *Validator) PoiScore(ctx context.Context) float64 {
+	return v.info.PoIScore
+}
+
+func (v *Validator) Reputation(ctx context.Context) float64 {
+	return v.info.Reputation
+}
+
+func (v *Validator) Performance(ctx context.Context) float64 {
+	return v.info.Performance
+}
+
+func (v *Validator) BlocksProduced(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.BlocksProduced)
+}
+
+func (v *Validator) LastActiveBlock(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.LastActiveBlock)
+}
+
+func (v *Validator) ConsecutiveBlocks(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.ConsecutiveBlocks)
+}
+
+func (v *Validator) CooldownUntilBlock(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.CooldownUntil)
+}
+
+func (v *Validator) UpTime(ctx context.Context) string {
+	return v.info.UpTime
+}
+
+func (v *Validator) TotalTransactions(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.TotalTx)
+}
+
+func (v *Validator) SuccessfulTransactions(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.SuccessfulTx)
+}
+
+func (v *Validator) Penalties(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(v.info.Penalties)
+}
+
+func (v *Validator) Active(ctx context.Context) bool {
+	return v.info.IsActive
+}
+
+// validators returns the validators of the PoI consensus ordered by rank.
+func (r *Resolver) validators() ([]*Validator, error) {
+	if r.poi == nil {
+		return nil, errNoPoI
+	}
+	rankings, err := r.poi.GetValidatorRanking()
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]*Validator, 0, len(rankings))
+	for _, ranking := range rankings {
+		info, err := r.poi.GetValidatorFullInfo(ranking.Validator)
+		if err != nil {
+			// The validator may have been dropped since the ranking
+			continue
+		}
+		validators = append(validators, &Validator{info: info, rank: ranking.Rank})
+	}
+	return validators, nil
+}
+
+func (r *Resolver) Validators(ctx context.Context) ([]*Validator, error) {
+	return r.validators()
+}
+
+func (r *Resolver) Validator(ctx context.Context, args struct{ Address common.Address }) (*Validator, error) {
+	validators, err := r.validators()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range validators {
+		if v.info.Address == args.Address {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *Block) Sealer(ctx context.Context) (common.Address, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return b.r.backend.Engine().Author(header)
+}
+
+func (b *Block) PoiScore(ctx context.Context) (*float64, error) {
+	if b.r.poi == nil {
+		return nil, nil
+	}
+	sealer, err := b.Sealer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := b.r.poi.GetValidatorFullInfo(sealer)
+	if err != nil {
+		// The sealer is not a known validator
+		return nil, nil
+	}
+	return &info.PoIScore, nil
+}
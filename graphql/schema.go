@@ -279,6 +279,103 @@ const schema string = `
         blobGasUsed: Long
         # ExcessBlobGas is a running total of blob gas consumed in excess of the target, prior to the block.
         excessBlobGas: Long
+        # Sealer is the address of the validator that sealed this block, as
+        # recovered by the consensus engine.
+        sealer: Address!
+        # PoIScore is the current PoI score of the sealer of this block. Scores are
+        # not retained per block. This field will be null if the chain does not
+        # run PoI consensus, or if the sealer is not a known validator.
+        poiScore: Float
+    }
+
+    # Validator is a validator of the PoI consensus, with its current standing.
+    type Validator {
+        # Address is the address the validator seals blocks with.
+        address: Address!
+        # Rank is the position of the validator when ordered by PoI score,
+        # starting at 1.
+        rank: Int!
+        # PoIScore is the score blending reputation and performance which
+        # validators are selected by.
+        poiScore: Float!
+        # Reputation is the validator's reputation, decaying over time.
+        reputation: Float!
+        # Performance is the validator's performance, from its latency,
+        # throughput, availability and bandwidth.
+        performance: Float!
+        # BlocksProduced is the number of blocks sealed by the validator.
+        blocksProduced: Long!
+        # LastActiveBlock is the number of the last block sealed by the validator.
+        lastActiveBlock: Long!
+        # ConsecutiveBlocks is the number of blocks the validator sealed in a row.
+        consecutiveBlocks: Long!
+        # CooldownUntilBlock is the block number until which the validator may
+        # not be selected.
+        cooldownUntilBlock: Long!
+        # UpTime is how long the validator has been up, as a duration such as "1h2m3s".
+        upTime: String!
+        # TotalTransactions is the number of transactions in the blocks sealed
+        # by the validator.
+        totalTransactions: Long!
+        # SuccessfulTransactions is the number of those transactions which succeeded.
+        successfulTransactions: Long!
+        # Penalties is the number of penalties the validator incurred.
+        penalties: Long!
+        # Active is whether the validator takes part in the selection.
+        active: Boolean!
+    }
+
+    # SecurityPolicy is the set of security lists enforced by the node. The
+    # lists are served to requests bearing a JWT token of the authenticated RPC
+    # endpoints in the Authorization header. A token selecting a role must be
+    # permitted the security method matching each list, named below.
+    type SecurityPolicy {
+        # Whitelist is the addresses allowed to deploy contracts
+        # (security_getWhitelist).
+        whitelist: [Address!]!
+        # Blacklist is the addresses whose transactions are rejected
+        # (security_getBlacklist).
+        blacklist: [Address!]!
+        # FrozenAccounts is the addresses whose assets and contracts are frozen
+        # (security_getFrozenAccounts).
+        frozenAccounts: [Address!]!
+        # DeployedContracts is the contracts deployed by each address
+        # (security_getAllDeployedContracts).
+        deployedContracts: [DeployedContracts!]!
+        # Restrictions is the time-bounded restrictions of addresses
+        # (security_getRestrictions).
+        restrictions: [Restriction!]!
+    }
+
+    # DeployedContracts is the list of contracts deployed by an address.
+    type DeployedContracts {
+        # Owner is the address that deployed the contracts.
+        owner: Address!
+        # Contracts is the addresses of the deployed contracts.
+        contracts: [Address!]!
+    }
+
+    # Restriction is a restriction of the transactions of an address, in a
+    # scope and until an expiry.
+    type Restriction {
+        # Address is the restricted address.
+        address: Address!
+        # Scope is the restricted transactions: all, outbound, inbound, deploy
+        # or contracts.
+        scope: String!
+        # Reason is the operator-defined reason code.
+        reason: Long!
+        # Contracts is the contracts interactions with are restricted, for the
+        # contracts scope.
+        contracts: [Address!]!
+        # ExpiryBlock is the block number the restriction expires at, null if
+        # it doesn't expire by block.
+        expiryBlock: Long
+        # ExpiryTime is the timestamp the restriction expires at, null if it
+        # doesn't expire by time.
+        expiryTime: Long
+        # Active is whether the restriction is in effect at the current block.
+        active: Boolean!
     }
 
     # CallData represents the data associated with a local contract call.
@@ -385,6 +482,14 @@ const schema string = `
         syncing: SyncState
         # ChainID returns the current chain ID for transaction replay protection.
         chainID: BigInt!
+        # Validators returns the validators of the PoI consensus, ordered by rank.
+        validators: [Validator!]!
+        # Validator returns the PoI validator with the given address, or null if
+        # there is no such validator.
+        validator(address: Address!): Validator
+        # SecurityPolicy returns the security lists enforced by the node, which
+        # require authentication.
+        securityPolicy: SecurityPolicy!
     }
 
     type Mutation {
@@ -0,0 +1,181 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// requestKey is the context key of the HTTP request being served.
+type requestKey struct{}
+
+// authorize checks that the request being served may access the data of the
+// given authenticated RPC method.
+func (r *Resolver) authorize(ctx context.Context, method string) error {
+	req, ok := ctx.Value(requestKey{}).(*http.Request)
+	if !ok || r.auth == nil {
+		return errors.New("authentication required")
+	}
+	return r.auth(req, method)
+}
+
+// sortedAddresses returns the addresses of a set in ascending order.
+func sortedAddresses(set map[common.Address]bool) []common.Address {
+	addrs := make([]common.Address, 0, len(set))
+	for addr := range set {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs
+}
+
+// SecurityPolicy represents the security policy lists enforced by the node.
+// Each list requires the request to be authorized for the security RPC method
+// serving it.
+type SecurityPolicy struct {
+	r *Resolver
+}
+
+func (r *Resolver) SecurityPolicy(ctx context.Context) *SecurityPolicy {
+	return &SecurityPolicy{r}
+}
+
+func (p *SecurityPolicy) Whitelist(ctx context.Context) ([]common.Address, error) {
+	if err := p.r.authorize(ctx, "security_getWhitelist"); err != nil {
+		return nil, err
+	}
+	return sortedAddresses(core.GetSecurityConfig().GetWhitelistAddresses()), nil
+}
+
+func (p *SecurityPolicy) Blacklist(ctx context.Context) ([]common.Address, error) {
+	if err := p.r.authorize(ctx, "security_getBlacklist"); err != nil {
+		return nil, err
+	}
+	return sortedAddresses(core.GetSecurityConfig().GetBlacklistAddresses()), nil
+}
+
+func (p *SecurityPolicy) FrozenAccounts(ctx context.Context) ([]common.Address, error) {
+	if err := p.r.authorize(ctx, "security_getFrozenAccounts"); err != nil {
+		return nil, err
+	}
+	return sortedAddresses(core.GetSecurityConfig().GetFrozenAddresses()), nil
+}
+
+func (p *SecurityPolicy) DeployedContracts(ctx context.Context) ([]*DeployedContracts, error) {
+	if err := p.r.authorize(ctx, "security_getAllDeployedContracts"); err != nil {
+		return nil, err
+	}
+	var (
+		all    = core.GetSecurityConfig().GetAllDeployedContracts()
+		owners = make(map[common.Address]bool, len(all))
+	)
+	for owner := range all {
+		owners[owner] = true
+	}
+	result := make([]*DeployedContracts, 0, len(all))
+	for _, owner := range sortedAddresses(owners) {
+		result = append(result, &DeployedContracts{owner: owner, contracts: all[owner]})
+	}
+	return result, nil
+}
+
+func (p *SecurityPolicy) Restrictions(ctx context.Context) ([]*Restriction, error) {
+	if err := p.r.authorize(ctx, "security_getRestrictions"); err != nil {
+		return nil, err
+	}
+	var (
+		head         = p.r.backend.CurrentHeader()
+		restrictions = core.GetSecurityConfig().GetRestrictions()
+		result       = make([]*Restriction, 0, len(restrictions))
+	)
+	for _, restriction := range restrictions {
+		result = append(result, &Restriction{
+			restriction: restriction,
+			active:      restriction.Active(head.Number.Uint64(), head.Time),
+		})
+	}
+	return result, nil
+}
+
+// DeployedContracts represents the contracts deployed by an address.
+type DeployedContracts struct {
+	owner     common.Address
+	contracts []common.Address
+}
+
+func (d *DeployedContracts) Owner(ctx context.Context) common.Address {
+	return d.owner
+}
+
+func (d *DeployedContracts) Contracts(ctx context.Context) []common.Address {
+	return d.contracts
+}
+
+// Restriction represents a time-bounded restriction of an address.
+type Restriction struct {
+	restriction *core.Restriction
+	active      bool
+}
+
+func (r *Restriction) Address(ctx context.Context) common.Address {
+	return r.restriction.Address
+}
+
+func (r *Restriction) Scope(ctx context.Context) string {
+	return r.restriction.Scope.String()
+}
+
+func (r *Restriction) Reason(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(r.restriction.Reason)
+}
+
+func (r *Restriction) Contracts(ctx context.Context) []common.Address {
+	if r.restriction.Contracts == nil {
+		return []common.Address{}
+	}
+	return r.restriction.Contracts
+}
+
+func (r *Restriction) ExpiryBlock(ctx context.Context) *hexutil.Uint64 {
+	if r.restriction.ExpiryBlock == 0 {
+		return nil
+	}
+	expiry := hexutil.Uint64(r.restriction.ExpiryBlock)
+	return &expiry
+}
+
+func (r *Restriction) ExpiryTime(ctx context.Context) *hexutil.Uint64 {
+	if r.restriction.ExpiryTime == 0 {
+		return nil
+	}
+	expiry := hexutil.Uint64(r.restriction.ExpiryTime)
+	return &expiry
+}
+
+func (r *Restriction) Active(ctx context.Context) bool {
+	return r.active
+}
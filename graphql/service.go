@@ -56,6 +56,9 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
 
+	// Resolvers of authenticated data check the request's credentials
+	ctx = context.WithValue(ctx, requestKey{}, r)
+
 	if timeout, ok := rpc.ContextRequestTimeout(ctx); ok {
 		timer = time.AfterFunc(timeout, func() {
 			responded.Do(func() {
@@ -114,7 +117,12 @@ func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterS
 // newHandler returns a new `http.Handler` that will answer GraphQL queries.
 // It additionally exports an interactive query browser on the / endpoint.
 func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) (*handler, error) {
-	q := Resolver{backend, filterSystem}
+	q := Resolver{
+		backend:      backend,
+		filterSystem: filterSystem,
+		poi:          poiAPI(stack),
+		auth:         stack.AuthorizeRequest,
+	}
 
 	s, err := graphql.ParseSchema(schema, &q)
 	if err != nil {
@@ -0,0 +1,137 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// defaultAddressTxLimit is the number of transactions returned per page of
+	// the transaction history of an address if no limit is given.
+	defaultAddressTxLimit = 100
+
+	// maxAddressTxLimit is the maximum number of transactions returned per page
+	// of the transaction history of an address.
+	maxAddressTxLimit = 1000
+)
+
+// AddressAPI provides an API to access the transaction history of addresses,
+// served from the address transaction index.
+type AddressAPI struct {
+	b Backend
+}
+
+// NewAddressAPI creates a new address transaction history API instance.
+func NewAddressAPI(b Backend) *AddressAPI {
+	return &AddressAPI{b}
+}
+
+// AddressTransaction is a transaction touching an address, along with the
+// directions it touches the address in.
+type AddressTransaction struct {
+	*RPCTransaction
+	Direction []string `json:"direction"`
+}
+
+// AddressTransactions is a page of the transaction history of an address, with
+// the cursor of the next page if there are more transactions.
+type AddressTransactions struct {
+	Transactions []*AddressTransaction `json:"transactions"`
+	Next         *hexutil.Bytes        `json:"next"`
+}
+
+// directionNames returns the names of the directions a transaction touches an
+// address in.
+func directionNames(direction byte) []string {
+	var names []string
+	if direction&rawdb.AddressTxSender != 0 {
+		names = append(names, "sender")
+	}
+	if direction&rawdb.AddressTxRecipient != 0 {
+		names = append(names, "recipient")
+	}
+	if direction&rawdb.AddressTxCreation != 0 {
+		names = append(names, "creation")
+	}
+	return names
+}
+
+// GetTransactionsByAddress returns the transactions sent by, sent to or creating
+// the given address, from the most recent one. The results are paginated: the
+// returned cursor is to be passed in to retrieve the next page, at most limit
+// transactions long.
+func (api *AddressAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, cursor *hexutil.Bytes, limit *hexutil.Uint64) (*AddressTransactions, error) {
+	n := defaultAddressTxLimit
+	if limit != nil {
+		if *limit == 0 || *limit > maxAddressTxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxAddressTxLimit)
+		}
+		n = int(*limit)
+	}
+	var start []byte
+	if cursor != nil {
+		start = *cursor
+	}
+	entries, next, err := api.b.AddressTransactions(ctx, address, start, n)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		config = api.b.ChainConfig()
+		result = &AddressTransactions{Transactions: make([]*AddressTransaction, 0, len(entries))}
+		block  *types.Block
+	)
+	for _, entry := range entries {
+		if block == nil || block.NumberU64() != entry.BlockNumber {
+			block, err = api.b.BlockByNumber(ctx, rpc.BlockNumber(entry.BlockNumber))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				continue
+			}
+		}
+		tx := newRPCTransactionFromBlockIndex(block, uint64(entry.Index), config)
+		if tx == nil {
+			continue
+		}
+		// Skip entries of blocks rewound without being unindexed, which may
+		// refer to transactions unrelated to the address.
+		if tx.From != address && (tx.To == nil || *tx.To != address) &&
+			(tx.To != nil || crypto.CreateAddress(tx.From, uint64(tx.Nonce)) != address) {
+			continue
+		}
+		result.Transactions = append(result.Transactions, &AddressTransaction{
+			RPCTransaction: tx,
+			Direction:      directionNames(entry.Direction),
+		})
+	}
+	if next != nil {
+		enc := hexutil.Bytes(next)
+		result.Next = &enc
+	}
+	return result, nil
+}
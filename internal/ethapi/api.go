@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -219,13 +220,64 @@ func (s *TxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCT
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *TxPoolAPI) Status() map[string]hexutil.Uint {
-	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+// RPCTxLifecycleEvent represents a step in the lifecycle of a pooled transaction
+// that will serialize to the RPC representation.
+type RPCTxLifecycleEvent struct {
+	Hash        common.Hash     `json:"hash"`
+	Stage       txpool.TxStage  `json:"stage"`
+	Time        time.Time       `json:"time"`
+	Source      string          `json:"source,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+	ReplacedBy  *common.Hash    `json:"replacedBy,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+// newRPCTxLifecycleEvent returns a lifecycle step that will serialize to the RPC
+// representation.
+func newRPCTxLifecycleEvent(ev txpool.TxLifecycleEvent) *RPCTxLifecycleEvent {
+	result := &RPCTxLifecycleEvent{
+		Hash:   ev.Hash,
+		Stage:  ev.Stage,
+		Time:   ev.Time,
+		Source: ev.Source,
+		Reason: ev.Reason,
+	}
+	switch ev.Stage {
+	case txpool.TxStageReplaced:
+		result.ReplacedBy = &ev.ReplacedBy
+	case txpool.TxStageIncluded:
+		number := hexutil.Uint64(ev.BlockNumber)
+		result.BlockHash = &ev.BlockHash
+		result.BlockNumber = &number
 	}
+	return result
+}
+
+// Status returns the number of pending and queued transaction in the pool. If
+// a transaction hash is given, the recorded lifecycle of that transaction is
+// returned instead.
+func (s *TxPoolAPI) Status(hash *common.Hash) map[string]interface{} {
+	if hash == nil {
+		pending, queue := s.b.Stats()
+		return map[string]interface{}{
+			"pending": hexutil.Uint(pending),
+			"queued":  hexutil.Uint(queue),
+		}
+	}
+	steps := s.b.TxLifecycle(*hash)
+	lifecycle := make([]*RPCTxLifecycleEvent, 0, len(steps))
+	for _, ev := range steps {
+		lifecycle = append(lifecycle, newRPCTxLifecycleEvent(ev))
+	}
+	result := map[string]interface{}{
+		"hash":      *hash,
+		"lifecycle": lifecycle,
+	}
+	if len(steps) > 0 {
+		result["stage"] = steps[len(steps)-1].Stage
+	}
+	return result
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -1044,6 +1096,38 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a new header object with the overridden fields.
+// Note: MakeHeader ignores BlobBaseFee if set. That's because the header
+// has no such field.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	if diff == nil {
+		return header
+	}
+	h := types.CopyHeader(header)
+	if diff.Number != nil {
+		h.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		h.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		h.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		h.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		h.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		h.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		h.BaseFee = diff.BaseFee.ToInt()
+	}
+	return h
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -1325,6 +1409,10 @@ type RPCTransaction struct {
 	R                   *hexutil.Big      `json:"r"`
 	S                   *hexutil.Big      `json:"s"`
 	YParity             *hexutil.Uint64   `json:"yParity,omitempty"`
+	FeePayer            *common.Address   `json:"feePayer,omitempty"`
+	FeePayerV           *hexutil.Big      `json:"feePayerV,omitempty"`
+	FeePayerR           *hexutil.Big      `json:"feePayerR,omitempty"`
+	FeePayerS           *hexutil.Big      `json:"feePayerS,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1399,6 +1487,26 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		}
 		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
 		result.BlobVersionedHashes = tx.BlobHashes()
+
+	case types.FeePayerTxType:
+		al := tx.AccessList()
+		yparity := hexutil.Uint64(v.Sign())
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.YParity = &yparity
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		pv, pr, ps := tx.RawFeePayerSignatureValues()
+		result.FeePayer = tx.FeePayer()
+		result.FeePayerV = (*hexutil.Big)(pv)
+		result.FeePayerR = (*hexutil.Big)(pr)
+		result.FeePayerS = (*hexutil.Big)(ps)
 	}
 	return result
 }
@@ -1716,6 +1824,9 @@ func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber u
 		fields["blobGasUsed"] = hexutil.Uint64(receipt.BlobGasUsed)
 		fields["blobGasPrice"] = (*hexutil.Big)(receipt.BlobGasPrice)
 	}
+	if payer := tx.FeePayer(); payer != nil {
+		fields["feePayer"] = *payer
+	}
 
 	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
 	if receipt.ContractAddress != (common.Address{}) {
@@ -1796,32 +1907,11 @@ func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionAr
 
 	// ============= THÊM KIỂM TRA BẢO MẬT =============
 	from := args.from()
-	
-	// Kiểm tra blacklist
-	if core.GetSecurityConfig().IsBlacklisted(from) {
-		log.Debug("Rejected blacklisted transaction via RPC", "from", from)
-		return common.Hash{}, core.ErrBlacklistedAddress
-	}
-	
-	// Kiểm tra frozen accounts
-	if core.GetSecurityConfig().IsFrozen(from) {
-		log.Debug("Rejected transaction from frozen account via RPC", "from", from)
-		return common.Hash{}, core.ErrFrozenAccount
-	}
-	
-	// Kiểm tra contract destination nếu là frozen contract
-	if args.To != nil && core.GetSecurityConfig().IsContractFrozen(*args.To) {
-		log.Debug("Rejected transaction to frozen contract via RPC", "from", from, "to", args.To)
-		return common.Hash{}, core.ErrFrozenContract
-	}
-	
-	// Kiểm tra whitelist cho contract deployment
-	if args.To == nil && args.Data != nil && len(*args.Data) > 0 {
-		// Đây là transaction tạo contract
-		if !core.GetSecurityConfig().IsWhitelisted(from) {
-			log.Debug("Rejected contract deployment from non-whitelisted address via RPC", "from", from)
-			return common.Hash{}, core.ErrNotWhitelistedForDeploy
-		}
+	head := s.b.CurrentHeader()
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, args.To, args.data(), head); err != nil {
+		log.Debug("Rejected transaction via RPC", "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(common.Hash{}, from, args.To, err, core.RejectionPathRPC)
+		return common.Hash{}, err
 	}
 	// ============= KẾT THÚC KIỂM TRA BẢO MẬT =============
 
@@ -1859,6 +1949,39 @@ func (s *TransactionAPI) FillTransaction(ctx context.Context, args TransactionAr
 	return &SignTransactionResult{data, tx}, nil
 }
 
+// TxLifecycle creates a subscription that is triggered each time a transaction
+// handled by the pool reaches a new step of its lifecycle. If a hash is given,
+// only the steps of that transaction are sent.
+func (s *TransactionAPI) TxLifecycle(ctx context.Context, hash *common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var (
+		rpcSub = notifier.CreateSubscription()
+		events = make(chan txpool.TxLifecycleEvent, 128)
+		sub    = s.b.SubscribeTxLifecycleEvent(events)
+	)
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				if hash != nil && ev.Hash != *hash {
+					continue
+				}
+				notifier.Notify(rpcSub.ID, newRPCTxLifecycleEvent(ev))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // SendRawTransaction will add the signed transaction to the transaction pool.
 // The sender is responsible for signing the transaction and using the correct nonce.
 // ======================================================
@@ -1877,32 +2000,11 @@ func (s *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.B
 	if err != nil {
 		return common.Hash{}, err
 	}
-	
-	// Kiểm tra blacklist
-	if core.GetSecurityConfig().IsBlacklisted(from) {
-		log.Debug("Rejected blacklisted raw transaction", "hash", tx.Hash(), "from", from)
-		return common.Hash{}, core.ErrBlacklistedAddress
-	}
-	
-	// Kiểm tra frozen accounts
-	if core.GetSecurityConfig().IsFrozen(from) {
-		log.Debug("Rejected raw transaction from frozen account", "hash", tx.Hash(), "from", from)
-		return common.Hash{}, core.ErrFrozenAccount
-	}
-	
-	// Kiểm tra contract destination nếu là frozen contract
-	if tx.To() != nil && core.GetSecurityConfig().IsContractFrozen(*tx.To()) {
-		log.Debug("Rejected raw transaction to frozen contract", "hash", tx.Hash(), "from", from, "to", tx.To())
-		return common.Hash{}, core.ErrFrozenContract
-	}
-	
-	// Kiểm tra whitelist cho contract deployment
-	if tx.To() == nil && len(tx.Data()) > 0 {
-		// Đây là transaction tạo contract
-		if !core.GetSecurityConfig().IsWhitelisted(from) {
-			log.Debug("Rejected contract deployment from non-whitelisted address via raw transaction", "hash", tx.Hash(), "from", from)
-			return common.Hash{}, core.ErrNotWhitelistedForDeploy
-		}
+	head := s.b.CurrentHeader()
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), head); err != nil {
+		log.Debug("Rejected raw transaction", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathRPC)
+		return common.Hash{}, err
 	}
 	// ============= KẾT THÚC KIỂM TRA BẢO MẬT =============
 	
@@ -43,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -585,6 +586,21 @@ func (b testBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) even
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendBundle(ctx context.Context, bundle *txpool.Bundle) error {
+	panic("implement me")
+}
+func (b testBackend) SendPrivateTx(ctx context.Context, tx *types.Transaction, maxBlock uint64) error {
+	panic("implement me")
+}
+func (b testBackend) CancelPrivateTx(ctx context.Context, hash common.Hash, from common.Address, sig []byte) error {
+	panic("implement me")
+}
+func (b testBackend) SendConditionalTx(ctx context.Context, tx *types.Transaction, conditions *txpool.TxConditions) error {
+	panic("implement me")
+}
+func (b testBackend) AddressTransactions(ctx context.Context, addr common.Address, cursor []byte, limit int) ([]rawdb.AddressTxEntry, []byte, error) {
+	return b.chain.AddressTransactions(addr, cursor, limit)
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return true, tx, blockHash, blockNumber, index, nil
@@ -604,6 +620,12 @@ func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transactio
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) TxLifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	panic("implement me")
+}
+func (b testBackend) SubscribeTxLifecycleEvent(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	panic("implement me")
+}
 func (b testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
 func (b testBackend) Engine() consensus.Engine         { return b.chain.Engine() }
 func (b testBackend) GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error) {
@@ -622,6 +644,10 @@ func (b testBackend) BloomStatus() (uint64, uint64) { panic("implement me") }
 func (b testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	panic("implement me")
 }
+func (b testBackend) LogIndexStatus() (uint64, bool) { panic("implement me") }
+func (b testBackend) LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	panic("implement me")
+}
 
 func TestEstimateGas(t *testing.T) {
 	t.Parallel()
@@ -976,6 +1002,177 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genBlocks = 4
+		signer    = types.HomesteadSigner{}
+
+		// The contract returns the hash of the previous block.
+		blockHasher = common.HexToAddress("0xb10c")
+		// The contract reverts with no data.
+		reverter = common.HexToAddress("0xdead")
+		// The contract emits a LOG0 of its calldata word and forwards its
+		// value to accounts[1].
+		forwarder     = common.HexToAddress("0xf0")
+		forwarderCode = hexutil.Bytes(append(common.FromHex("60003560005260206000a0600060006000600034"+"73"), append(accounts[1].addr.Bytes(), common.FromHex("5af100")...)...))
+	)
+	backend := newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: b.BaseFee(), Data: nil}), signer, accounts[0].key)
+		b.AddTx(tx)
+		b.SetPoS()
+	})
+	api := NewBlockChainAPI(backend)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	overrides := StateOverride{
+		blockHasher: OverrideAccount{Code: hex2Bytes("60014303406000526020" + "6000f3")},
+		reverter:    OverrideAccount{Code: hex2Bytes("60006000fd")},
+		forwarder:   OverrideAccount{Code: &forwarderCode},
+	}
+	number := hexutil.Big(*big.NewInt(int64(genBlocks + 3)))
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{
+				StateOverrides: &overrides,
+				Calls: []TransactionArgs{{
+					From:  &accounts[0].addr,
+					To:    &forwarder,
+					Value: (*hexutil.Big)(big.NewInt(500)),
+					Input: hex2Bytes("000000000000000000000000000000000000000000000000000000000000002a"),
+				}, {
+					From: &accounts[0].addr,
+					To:   &reverter,
+				}},
+			},
+			{
+				BlockOverrides: &BlockOverrides{Number: &number},
+				Calls: []TransactionArgs{{
+					From: &accounts[0].addr,
+					To:   &blockHasher,
+				}},
+			},
+		},
+		TraceTransfers: true,
+	}, &latest)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("wrong number of blocks: have %d, want 3", len(results))
+	}
+	head := backend.chain.CurrentHeader()
+	for i, result := range results {
+		if have, want := result["number"].(*hexutil.Big).ToInt().Uint64(), head.Number.Uint64()+uint64(i)+1; have != want {
+			t.Errorf("block %d: wrong number: have %d, want %d", i, have, want)
+		}
+		if have, want := uint64(result["timestamp"].(hexutil.Uint64)), head.Time+uint64(i+1)*timestampIncrement; have != want {
+			t.Errorf("block %d: wrong timestamp: have %d, want %d", i, have, want)
+		}
+	}
+	// The forwarder call emits its own log along with the transfers to the
+	// forwarder and from it to accounts[1].
+	calls := results[0]["calls"].([]simCallResult)
+	if len(calls) != 2 {
+		t.Fatalf("wrong number of calls: have %d, want 2", len(calls))
+	}
+	if calls[0].Status != hexutil.Uint64(types.ReceiptStatusSuccessful) || calls[0].Error != nil {
+		t.Fatalf("forwarder call failed: %+v", calls[0].Error)
+	}
+	logs := calls[0].Logs
+	if len(logs) != 3 {
+		t.Fatalf("wrong number of logs: have %d, want 3", len(logs))
+	}
+	hash := results[0]["hash"].(common.Hash)
+	for i, l := range logs {
+		if l.BlockHash != hash || l.Index != uint(i) {
+			t.Errorf("log %d: wrong position: have %x/%d, want %x/%d", i, l.BlockHash, l.Index, hash, i)
+		}
+	}
+	if logs[0].Address != transferAddress || logs[0].Topics[1] != common.BytesToHash(accounts[0].addr.Bytes()) || logs[0].Topics[2] != common.BytesToHash(forwarder.Bytes()) {
+		t.Errorf("wrong transfer log to the forwarder: %+v", logs[0])
+	}
+	if logs[1].Address != forwarder || !bytes.Equal(logs[1].Data, common.BigToHash(big.NewInt(42)).Bytes()) {
+		t.Errorf("wrong forwarder log: %+v", logs[1])
+	}
+	if logs[2].Address != transferAddress || logs[2].Topics[2] != common.BytesToHash(accounts[1].addr.Bytes()) || new(big.Int).SetBytes(logs[2].Data).Uint64() != 500 {
+		t.Errorf("wrong transfer log from the forwarder: %+v", logs[2])
+	}
+	if calls[1].Status != hexutil.Uint64(types.ReceiptStatusFailed) || calls[1].Error == nil || calls[1].Error.Code != 3 {
+		t.Errorf("wrong result of reverting call: %+v", calls[1])
+	}
+	// BLOCKHASH resolves the simulated blocks, including the empty ones.
+	calls = results[2]["calls"].([]simCallResult)
+	if have, want := common.BytesToHash(calls[0].ReturnValue), results[1]["hash"].(common.Hash); have != want {
+		t.Errorf("wrong parent hash: have %x, want %x", have, want)
+	}
+	if have, want := results[1]["parentHash"].(common.Hash), hash; have != want {
+		t.Errorf("wrong parent of empty block: have %x, want %x", have, want)
+	}
+
+	// Check the errors of invalid simulations.
+	var (
+		txGas    = hexutil.Uint64(params.TxGas)
+		past     = hexutil.Big(*big.NewInt(1))
+		pastTime = hexutil.Uint64(head.Time)
+		gas      = hexutil.Uint64(100_000_000)
+	)
+	for i, tt := range []struct {
+		opts simOpts
+		code int
+	}{
+		{simOpts{}, errCodeInvalidParams},
+		{simOpts{BlockStateCalls: []simBlock{{BlockOverrides: &BlockOverrides{Number: &past}}}}, errCodeBlockNumberInvalid},
+		{simOpts{BlockStateCalls: []simBlock{{BlockOverrides: &BlockOverrides{Time: &pastTime}}}}, errCodeBlockTimestampInvalid},
+		{simOpts{BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, Gas: &gas}}}}}, errCodeBlockGasLimitReached},
+		{simOpts{BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Gas: &txGas, Nonce: new(hexutil.Uint64)}}}}, Validation: true}, errCodeNonceTooLow},
+	} {
+		_, err := api.SimulateV1(context.Background(), tt.opts, &latest)
+		var coded interface{ ErrorCode() int }
+		if !errors.As(err, &coded) || coded.ErrorCode() != tt.code {
+			t.Errorf("test %d: wrong error: have %v, want code %d", i, err, tt.code)
+		}
+	}
+}
+
+// Tests that the simulated blocks are spaced by the block period of the chain.
+func TestSimulateTimestampIncrement(t *testing.T) {
+	t.Parallel()
+
+	base := &types.Header{Number: big.NewInt(10), Time: 1000}
+	tests := []struct {
+		config *params.ChainConfig
+		want   uint64
+	}{
+		{&params.ChainConfig{}, timestampIncrement},
+		{&params.ChainConfig{PoI: &params.PoIConfig{Period: 2}}, 2},
+		{&params.ChainConfig{Clique: &params.CliqueConfig{Period: 5}}, 5},
+	}
+	for i, tt := range tests {
+		sim := &simulator{base: base, chainConfig: tt.config}
+		number := hexutil.Big(*big.NewInt(13))
+		blocks, err := sim.sanitizeChain([]simBlock{{}, {BlockOverrides: &BlockOverrides{Number: &number}}})
+		if err != nil {
+			t.Fatalf("test %d: failed to sanitize chain: %v", i, err)
+		}
+		if len(blocks) != 3 {
+			t.Fatalf("test %d: wrong number of blocks: have %d, want 3", i, len(blocks))
+		}
+		for j, block := range blocks {
+			if have, want := uint64(*block.BlockOverrides.Time), base.Time+uint64(j+1)*tt.want; have != want {
+				t.Errorf("test %d: block %d: wrong timestamp: have %d, want %d", i, j, have, want)
+			}
+		}
+	}
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -2053,3 +2250,55 @@ func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc s
 	}
 	require.JSONEqf(t, string(want), string(data), "test %d: json not match, want: %s, have: %s", testid, string(want), string(data))
 }
+
+type cancelBackend struct {
+	*backendMock
+	hash common.Hash
+	from common.Address
+}
+
+func (b *cancelBackend) CancelPrivateTx(ctx context.Context, hash common.Hash, from common.Address, sig []byte) error {
+	b.hash, b.from = hash, from
+	return nil
+}
+
+func TestCancelPrivateTransaction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _  = crypto.GenerateKey()
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		hash    = common.HexToHash("0x01")
+		backend = &cancelBackend{backendMock: newBackendMock()}
+		api     = NewPrivateTxAPI(backend)
+	)
+	sign := func(msg []byte) hexutil.Bytes {
+		sig, err := crypto.Sign(msg, key)
+		if err != nil {
+			t.Fatalf("failed to sign cancellation: %v", err)
+		}
+		sig[crypto.RecoveryIDOffset] += 27
+		return sig
+	}
+	// Unsigned cancellations are rejected
+	if _, err := api.CancelPrivateTransaction(context.Background(), hash, nil); err == nil {
+		t.Fatal("unsigned cancellation accepted")
+	}
+	if backend.hash != (common.Hash{}) {
+		t.Fatal("unsigned cancellation reached the backend")
+	}
+	// Signatures over anything but the cancel message recover another sender
+	if _, err := api.CancelPrivateTransaction(context.Background(), hash, sign(hash.Bytes())); err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if backend.from == from {
+		t.Error("cancellation of raw hash attributed to the sender")
+	}
+	// Signed cancellations are handed on with the recovered sender
+	if _, err := api.CancelPrivateTransaction(context.Background(), hash, sign(PrivateCancelHash(hash))); err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if backend.hash != hash || backend.from != from {
+		t.Errorf("cancellation mismatch: have %x from %x, want %x from %x", backend.hash, backend.from, hash, from)
+	}
+}
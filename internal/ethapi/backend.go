@@ -28,7 +28,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -75,7 +77,12 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendBundle(ctx context.Context, bundle *txpool.Bundle) error
+	SendPrivateTx(ctx context.Context, tx *types.Transaction, maxBlock uint64) error
+	CancelPrivateTx(ctx context.Context, hash common.Hash, from common.Address, sig []byte) error
+	SendConditionalTx(ctx context.Context, tx *types.Transaction, conditions *txpool.TxConditions) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error)
+	AddressTransactions(ctx context.Context, addr common.Address, cursor []byte, limit int) ([]rawdb.AddressTxEntry, []byte, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
@@ -83,6 +90,8 @@ type Backend interface {
 	TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
 	TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	TxLifecycle(hash common.Hash) []txpool.TxLifecycleEvent
+	SubscribeTxLifecycleEvent(ch chan<- txpool.TxLifecycleEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
@@ -97,6 +106,8 @@ type Backend interface {
 	SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+	LogIndexStatus() (uint64, bool)
+	LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error)
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -111,6 +122,18 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "eth",
 			Service:   NewTransactionAPI(apiBackend, nonceLock),
+		}, {
+			Namespace: "eth",
+			Service:   NewBundleAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewPrivateTxAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewConditionalTxAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewAddressAPI(apiBackend),
 		}, {
 			Namespace: "txpool",
 			Service:   NewTxPoolAPI(apiBackend),
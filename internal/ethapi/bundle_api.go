@@ -0,0 +1,240 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI provides an API to submit and simulate atomic transaction bundles.
+type BundleAPI struct {
+	b Backend
+}
+
+// NewBundleAPI creates a new bundle API instance.
+func NewBundleAPI(b Backend) *BundleAPI {
+	return &BundleAPI{b}
+}
+
+// SendBundleArgs represents the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	MinBlock          *hexutil.Uint64 `json:"minBlock"`
+	MaxBlock          *hexutil.Uint64 `json:"maxBlock"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// SendBundle submits a bundle of signed transactions which will be included
+// into a block atomically, in the given order, within the target block range.
+// If no maximum block is given, the bundle stays eligible for as long as the
+// pool allows. It returns the hash identifying the bundle.
+func (api *BundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for i, tx := range txs {
+		if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if !api.b.UnprotectedAllowed() && !tx.Protected() {
+			return common.Hash{}, fmt.Errorf("transaction %d: only replay-protected (EIP-155) transactions allowed over RPC", i)
+		}
+	}
+	bundle := &txpool.Bundle{
+		Txs:          txs,
+		RevertingTxs: args.RevertingTxHashes,
+	}
+	if args.MinBlock != nil {
+		bundle.MinBlock = uint64(*args.MinBlock)
+	}
+	if args.MaxBlock != nil {
+		bundle.MaxBlock = uint64(*args.MaxBlock)
+	}
+	if err := api.b.SendBundle(ctx, bundle); err != nil {
+		return common.Hash{}, err
+	}
+	hash := bundle.Hash()
+	log.Info("Submitted bundle", "hash", hash, "txs", len(txs), "minblock", bundle.MinBlock, "maxblock", bundle.MaxBlock)
+	return hash, nil
+}
+
+// CallBundleArgs represents the arguments of eth_callBundle.
+type CallBundleArgs struct {
+	Txs              []hexutil.Bytes        `json:"txs"`
+	StateBlockNumber *rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Timestamp        *hexutil.Uint64        `json:"timestamp"`
+	Coinbase         *common.Address        `json:"coinbase"`
+}
+
+// CallBundleTxResult is the simulation outcome of a single bundled transaction.
+type CallBundleTxResult struct {
+	TxHash     common.Hash     `json:"txHash"`
+	From       common.Address  `json:"fromAddress"`
+	To         *common.Address `json:"toAddress"`
+	GasUsed    hexutil.Uint64  `json:"gasUsed"`
+	ReturnData hexutil.Bytes   `json:"returnData,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// CallBundleResult is the simulation outcome of a bundle.
+type CallBundleResult struct {
+	BundleHash       common.Hash          `json:"bundleHash"`
+	StateBlockNumber hexutil.Uint64       `json:"stateBlockNumber"`
+	TotalGasUsed     hexutil.Uint64       `json:"totalGasUsed"`
+	CoinbaseDiff     *hexutil.Big         `json:"coinbaseDiff"`
+	Results          []CallBundleTxResult `json:"results"`
+}
+
+// CallBundle simulates a bundle on top of the given state block, as if it was
+// included at the beginning of the next block. Transactions which revert are
+// reported in the results; transactions which are invalid abort the simulation.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if args.StateBlockNumber != nil {
+		blockNrOrHash = *args.StateBlockNumber
+	}
+	state, parent, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// Assemble the header of the block the bundle is simulated in
+	config := api.b.ChainConfig()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: parent.Difficulty,
+		Coinbase:   parent.Coinbase,
+	}
+	if args.Timestamp != nil {
+		header.Time = uint64(*args.Timestamp)
+	}
+	if args.Coinbase != nil {
+		header.Coinbase = *args.Coinbase
+	}
+	if config.IsLondon(header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(config, parent)
+	}
+	// Setup context so it may be cancelled when the call has completed or timed out
+	var cancel context.CancelFunc
+	if timeout := api.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var (
+		signer   = types.MakeSigner(config, header.Number, header.Time)
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		blockCtx = core.NewEVMBlockContext(header, NewChainContext(ctx, api.b), &header.Coinbase)
+		balance  = state.GetBalance(header.Coinbase).ToBig()
+		result   = &CallBundleResult{
+			BundleHash:       (&txpool.Bundle{Txs: txs}).Hash(),
+			StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		}
+	)
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if err := core.GetSecurityConfig().CheckTransaction(from, tx.To(), tx.Data(), header.Number.Uint64(), header.Time); err != nil {
+			return nil, fmt.Errorf("transaction %d (%x): %w", i, tx.Hash(), err)
+		}
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d (%x): %w", i, tx.Hash(), err)
+		}
+		state.SetTxContext(tx.Hash(), i)
+		evm := api.b.GetEVM(ctx, msg, state, header, &vm.Config{}, &blockCtx)
+		go func() {
+			<-ctx.Done()
+			evm.Cancel()
+		}()
+		res, err := core.ApplyMessage(evm, msg, gp)
+		if err := state.Error(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", api.b.RPCEVMTimeout())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d (%x): %w", i, tx.Hash(), err)
+		}
+		state.Finalise(config.IsEIP158(header.Number))
+
+		txResult := CallBundleTxResult{
+			TxHash:  tx.Hash(),
+			From:    from,
+			To:      tx.To(),
+			GasUsed: hexutil.Uint64(res.UsedGas),
+		}
+		if res.Failed() {
+			if revert := res.Revert(); len(revert) > 0 {
+				txResult.Error = newRevertError(revert).Error()
+			} else {
+				txResult.Error = res.Err.Error()
+			}
+		} else {
+			txResult.ReturnData = res.Return()
+		}
+		result.Results = append(result.Results, txResult)
+		result.TotalGasUsed += hexutil.Uint64(res.UsedGas)
+	}
+	diff := new(big.Int).Sub(state.GetBalance(header.Coinbase).ToBig(), balance)
+	result.CoinbaseDiff = (*hexutil.Big)(diff)
+	return result, nil
+}
+
+// decodeBundleTxs decodes the raw transactions of a bundle.
+func decodeBundleTxs(raw []hexutil.Bytes) (types.Transactions, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("bundle missing transactions")
+	}
+	txs := make(types.Transactions, len(raw))
+	for i, input := range raw {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if tx.Type() == types.BlobTxType {
+			return nil, fmt.Errorf("transaction %d: blob transactions cannot be bundled", i)
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ConditionalTxAPI provides an API to submit transactions which may only be
+// included in blocks satisfying a set of conditions.
+type ConditionalTxAPI struct {
+	b Backend
+}
+
+// NewConditionalTxAPI creates a new conditional transaction API instance.
+func NewConditionalTxAPI(b Backend) *ConditionalTxAPI {
+	return &ConditionalTxAPI{b}
+}
+
+// TransactionConditions represents the conditions of
+// eth_sendRawTransactionConditional. The known accounts map addresses to the
+// storage slot values they must hold right before the transaction executes.
+type TransactionConditions struct {
+	BlockNumberMin *hexutil.Uint64                                `json:"blockNumberMin"`
+	BlockNumberMax *hexutil.Uint64                                `json:"blockNumberMax"`
+	TimestampMin   *hexutil.Uint64                                `json:"timestampMin"`
+	TimestampMax   *hexutil.Uint64                                `json:"timestampMax"`
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash `json:"knownAccounts"`
+}
+
+// toConditions converts the RPC conditions into the pool representation.
+func (args *TransactionConditions) toConditions() *txpool.TxConditions {
+	conditions := &txpool.TxConditions{KnownAccounts: args.KnownAccounts}
+	if args.BlockNumberMin != nil {
+		conditions.BlockNumberMin = (*uint64)(args.BlockNumberMin)
+	}
+	if args.BlockNumberMax != nil {
+		conditions.BlockNumberMax = (*uint64)(args.BlockNumberMax)
+	}
+	if args.TimestampMin != nil {
+		conditions.TimestampMin = (*uint64)(args.TimestampMin)
+	}
+	if args.TimestampMax != nil {
+		conditions.TimestampMax = (*uint64)(args.TimestampMax)
+	}
+	return conditions
+}
+
+// SendRawTransactionConditional submits a signed transaction which is held out
+// of the pending set until a block being built satisfies its conditions: not
+// before or after the given block numbers and timestamps, and only if the known
+// accounts hold the given storage values. The transaction is never gossiped. If
+// its conditions expire, it is dropped and the reason is reported through the
+// lifecycle of the transaction (txpool_status).
+func (api *ConditionalTxAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, options TransactionConditions) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if tx.Type() == types.BlobTxType {
+		return common.Hash{}, errors.New("blob transactions cannot be conditional")
+	}
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !api.b.UnprotectedAllowed() && !tx.Protected() {
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	head := api.b.CurrentHeader()
+	from, err := types.Sender(types.MakeSigner(api.b.ChainConfig(), head.Number, head.Time), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), head); err != nil {
+		log.Debug("Rejected conditional transaction", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathRPC)
+		return common.Hash{}, err
+	}
+	if err := api.b.SendConditionalTx(ctx, tx, options.toConditions()); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted conditional transaction", "hash", tx.Hash(), "from", from, "nonce", tx.Nonce())
+	return tx.Hash(), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// transferTopic is the topic of the logs emitted for ether transfers, the
+	// same as the ERC-20 Transfer event.
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	// transferAddress is the pseudo-address emitting the logs of ether
+	// transfers, as defined by ERC-7528.
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+)
+
+// transferTracer is an EVM logger adding a log to the state for every ether
+// transfer made by a call frame. The logs are added within the frame making
+// the transfer, so they are discarded along with the frame's own logs if it
+// reverts.
+type transferTracer struct {
+	env *vm.EVM
+}
+
+// newTransferTracer creates a tracer logging ether transfers.
+func newTransferTracer() *transferTracer {
+	return &transferTracer{}
+}
+
+func (t *transferTracer) captureTransfer(from, to common.Address, value *big.Int) {
+	if value == nil || value.Sign() <= 0 {
+		return
+	}
+	t.env.StateDB.AddLog(&types.Log{
+		Address: transferAddress,
+		Topics: []common.Hash{
+			transferTopic,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:        common.BigToHash(value).Bytes(),
+		BlockNumber: t.env.Context.BlockNumber.Uint64(),
+	})
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *transferTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.captureTransfer(from, to, value)
+}
+
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Delegated frames run in the context of the caller, moving no ether
+	if typ == vm.DELEGATECALL || typ == vm.CALLCODE {
+		return
+	}
+	t.captureTransfer(from, to, value)
+}
+
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *transferTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
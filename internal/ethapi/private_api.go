@@ -0,0 +1,125 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PrivateTxAPI provides an API to submit transactions which are never gossiped
+// to the network, only handed to the local miner and the configured validators.
+type PrivateTxAPI struct {
+	b Backend
+}
+
+// NewPrivateTxAPI creates a new private transaction API instance.
+func NewPrivateTxAPI(b Backend) *PrivateTxAPI {
+	return &PrivateTxAPI{b}
+}
+
+// PrivateTransactionArgs represents the arguments of eth_sendPrivateTransaction.
+type PrivateTransactionArgs struct {
+	Tx             hexutil.Bytes   `json:"tx"`
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+// SendPrivateTransaction submits a signed transaction which is kept out of the
+// public transaction gossip. If it is not included until the maximum block
+// number, the transaction is dropped. If no maximum block number is given, the
+// transaction stays eligible for as long as the pool allows.
+func (api *PrivateTxAPI) SendPrivateTransaction(ctx context.Context, args PrivateTransactionArgs) (common.Hash, error) {
+	if len(args.Tx) == 0 {
+		return common.Hash{}, errors.New("missing transaction")
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Tx); err != nil {
+		return common.Hash{}, err
+	}
+	if tx.Type() == types.BlobTxType {
+		return common.Hash{}, errors.New("blob transactions cannot be private")
+	}
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !api.b.UnprotectedAllowed() && !tx.Protected() {
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	head := api.b.CurrentHeader()
+	from, err := types.Sender(types.MakeSigner(api.b.ChainConfig(), head.Number, head.Time), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := core.GetSecurityConfig().CheckPendingTransaction(from, tx.To(), tx.Data(), head); err != nil {
+		log.Debug("Rejected private transaction", "hash", tx.Hash(), "from", from, "err", err)
+		core.GetSecurityConfig().NotifyRejection(tx.Hash(), from, tx.To(), err, core.RejectionPathRPC)
+		return common.Hash{}, err
+	}
+	var maxBlock uint64
+	if args.MaxBlockNumber != nil {
+		maxBlock = uint64(*args.MaxBlockNumber)
+	}
+	if err := api.b.SendPrivateTx(ctx, tx, maxBlock); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "hash", tx.Hash(), "from", from, "nonce", tx.Nonce(), "maxblock", maxBlock)
+	return tx.Hash(), nil
+}
+
+// PrivateCancelHash returns the hash the sender of a private transaction has to
+// sign to cancel it. It is the EIP-191 personal message hash of the text
+// "cancelPrivateTransaction:" followed by the hex encoded transaction hash.
+func PrivateCancelHash(hash common.Hash) []byte {
+	return accounts.TextHash([]byte("cancelPrivateTransaction:" + hash.Hex()))
+}
+
+// CancelPrivateTransaction drops a not yet included private transaction, along
+// with all subsequent private transactions of the same sender. Validators the
+// transaction was forwarded to are asked to drop it too.
+//
+// The cancellation must be signed by the sender of the transaction, see
+// PrivateCancelHash for the signed message.
+func (api *PrivateTxAPI) CancelPrivateTransaction(ctx context.Context, hash common.Hash, signature hexutil.Bytes) (bool, error) {
+	if len(signature) != crypto.SignatureLength {
+		return false, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+	if signature[crypto.RecoveryIDOffset] != 27 && signature[crypto.RecoveryIDOffset] != 28 {
+		return false, errors.New("invalid Ethereum signature (V is not 27 or 28)")
+	}
+	sig := common.CopyBytes(signature)
+	sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+
+	pub, err := crypto.SigToPub(PrivateCancelHash(hash), sig)
+	if err != nil {
+		return false, err
+	}
+	from := crypto.PubkeyToAddress(*pub)
+	if err := api.b.CancelPrivateTx(ctx, hash, from, signature); err != nil {
+		return false, err
+	}
+	log.Info("Cancelled private transaction", "hash", hash, "from", from)
+	return true, nil
+}
@@ -19,9 +19,10 @@ package ethapi
 import (
     "context"
     "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/common/hexutil"
     "github.com/ethereum/go-ethereum/core"
     "github.com/ethereum/go-ethereum/log"
-    
+    "github.com/ethereum/go-ethereum/rpc"
 )
 
 // SecurityAPI cung cấp các API liên quan đến bảo mật
@@ -38,15 +39,18 @@ func NewSecurityAPI(b Backend) *SecurityAPI {
 // ============= WHITELIST APIS =============
 // AddToWhitelist thêm địa chỉ vào whitelist deploy contract
 func (api *SecurityAPI) AddToWhitelist(ctx context.Context, address common.Address) (bool, error) {
-
-    core.GetSecurityConfig().AddToWhitelist(address)
+    if err := core.GetSecurityConfig().AddToWhitelist(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
 
 // RemoveFromWhitelist xóa địa chỉ khỏi whitelist
 func (api *SecurityAPI) RemoveFromWhitelist(ctx context.Context, address common.Address) (bool, error) {
-    core.GetSecurityConfig().RemoveFromWhitelist(address)
+    if err := core.GetSecurityConfig().RemoveFromWhitelist(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
@@ -67,13 +71,17 @@ func (api *SecurityAPI) GetWhitelist(ctx context.Context) ([]common.Address, err
 // AddToBlacklist thêm địa chỉ vào blacklist
 // Địa chỉ bị blacklist không thể giao dịch hoặc tương tác với blockchain
 func (api *SecurityAPI) AddToBlacklist(ctx context.Context, address common.Address) (bool, error) {
-    core.GetSecurityConfig().AddToBlacklist(address)
+    if err := core.GetSecurityConfig().AddToBlacklist(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
 // RemoveFromBlacklist xóa địa chỉ khỏi blacklist
 func (api *SecurityAPI) RemoveFromBlacklist(ctx context.Context, address common.Address) (bool, error) {
-    core.GetSecurityConfig().RemoveFromBlacklist(address)
+    if err := core.GetSecurityConfig().RemoveFromBlacklist(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
@@ -93,13 +101,17 @@ func (api *SecurityAPI) GetBlacklist(ctx context.Context) ([]common.Address, err
 // ============= FREEZE APIS =============
 // FreezeAccount đóng băng toàn bộ tài sản, contract và tương tác của địa chỉ
 func (api *SecurityAPI) FreezeAccount(ctx context.Context, address common.Address) (bool, error) {
-    core.GetSecurityConfig().FreezeAccount(address)
+    if err := core.GetSecurityConfig().FreezeAccount(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
 // UnfreezeAccount bỏ đóng băng tài khoản
 func (api *SecurityAPI) UnfreezeAccount(ctx context.Context, address common.Address) (bool, error) {
-    core.GetSecurityConfig().UnfreezeAccount(address)
+    if err := core.GetSecurityConfig().UnfreezeAccount(address); err != nil {
+        return false, err
+    }
     return true, nil
 }
 
@@ -149,4 +161,222 @@ func (api *SecurityAPI) GetAllDeployedContracts(ctx context.Context) (map[string
 // IsContractFrozen kiểm tra xem một contract có bị đóng băng hay không
 func (api *SecurityAPI) IsContractFrozen(ctx context.Context, contractAddr common.Address) (bool, error) {
     return core.GetSecurityConfig().IsContractFrozen(contractAddr), nil
-}
\ No newline at end of file
+}
+
+// ============= RESTRICTION APIS =============
+// RestrictionArgs là tham số để tạo một hạn chế có thời hạn
+type RestrictionArgs struct {
+    Scope       string            `json:"scope"`
+    Reason      hexutil.Uint64    `json:"reason"`
+    Contracts   []common.Address  `json:"contracts,omitempty"`
+    ExpiryBlock *hexutil.Uint64   `json:"expiryBlock,omitempty"`
+    ExpiryTime  *hexutil.Uint64   `json:"expiryTime,omitempty"`
+}
+
+// RPCRestriction là biểu diễn JSON của một hạn chế
+type RPCRestriction struct {
+    Address     common.Address    `json:"address"`
+    Scope       string            `json:"scope"`
+    Reason      hexutil.Uint64    `json:"reason"`
+    Contracts   []common.Address  `json:"contracts,omitempty"`
+    ExpiryBlock *hexutil.Uint64   `json:"expiryBlock,omitempty"`
+    ExpiryTime  *hexutil.Uint64   `json:"expiryTime,omitempty"`
+    Active      bool              `json:"active"`
+}
+
+// newRPCRestriction chuyển đổi hạn chế sang dạng JSON, đánh giá hiệu lực tại
+// block và thời điểm cho trước
+func newRPCRestriction(r *core.Restriction, number uint64, time uint64) *RPCRestriction {
+    result := &RPCRestriction{
+        Address:   r.Address,
+        Scope:     r.Scope.String(),
+        Reason:    hexutil.Uint64(r.Reason),
+        Contracts: r.Contracts,
+        Active:    r.Active(number, time),
+    }
+    if r.ExpiryBlock != 0 {
+        expiry := hexutil.Uint64(r.ExpiryBlock)
+        result.ExpiryBlock = &expiry
+    }
+    if r.ExpiryTime != 0 {
+        expiry := hexutil.Uint64(r.ExpiryTime)
+        result.ExpiryTime = &expiry
+    }
+    return result
+}
+
+// AddRestriction thêm hạn chế có thời hạn cho một địa chỉ
+func (api *SecurityAPI) AddRestriction(ctx context.Context, address common.Address, args RestrictionArgs) (bool, error) {
+    scope, err := core.ParseRestrictionScope(args.Scope)
+    if err != nil {
+        return false, err
+    }
+    r := &core.Restriction{
+        Address:   address,
+        Scope:     scope,
+        Reason:    uint64(args.Reason),
+        Contracts: args.Contracts,
+    }
+    if args.ExpiryBlock != nil {
+        r.ExpiryBlock = uint64(*args.ExpiryBlock)
+    }
+    if args.ExpiryTime != nil {
+        r.ExpiryTime = uint64(*args.ExpiryTime)
+    }
+    if err := core.GetSecurityConfig().AddRestriction(r); err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// RemoveRestriction xóa hạn chế theo phạm vi của một địa chỉ
+func (api *SecurityAPI) RemoveRestriction(ctx context.Context, address common.Address, scope string) (bool, error) {
+    s, err := core.ParseRestrictionScope(scope)
+    if err != nil {
+        return false, err
+    }
+    if err := core.GetSecurityConfig().RemoveRestriction(address, s); err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// GetRestrictions trả về tất cả các hạn chế, kèm trạng thái hiệu lực tại block hiện tại
+func (api *SecurityAPI) GetRestrictions(ctx context.Context) ([]*RPCRestriction, error) {
+    head := api.b.CurrentHeader()
+    
+    restrictions := core.GetSecurityConfig().GetRestrictions()
+    result := make([]*RPCRestriction, 0, len(restrictions))
+    for _, r := range restrictions {
+        result = append(result, newRPCRestriction(r, head.Number.Uint64(), head.Time))
+    }
+    return result, nil
+}
+
+// AddressStatus là kết quả của security_checkAddress
+type AddressStatus struct {
+    Address        common.Address    `json:"address"`
+    Whitelisted    bool              `json:"whitelisted"`
+    Blacklisted    bool              `json:"blacklisted"`
+    Frozen         bool              `json:"frozen"`
+    ContractFrozen bool              `json:"contractFrozen"`
+    Restrictions   []*RPCRestriction `json:"restrictions"`
+}
+
+// CheckAddress trả về toàn bộ trạng thái bảo mật của một địa chỉ tại block hiện tại
+func (api *SecurityAPI) CheckAddress(ctx context.Context, address common.Address) (*AddressStatus, error) {
+    head := api.b.CurrentHeader()
+    sc := core.GetSecurityConfig()
+    
+    status := &AddressStatus{
+        Address:        address,
+        Whitelisted:    sc.IsWhitelisted(address),
+        Blacklisted:    sc.IsBlacklisted(address),
+        Frozen:         sc.IsFrozen(address),
+        ContractFrozen: sc.IsContractFrozen(address),
+        Restrictions:   []*RPCRestriction{},
+    }
+    for _, r := range sc.GetAddressRestrictions(address) {
+        status.Restrictions = append(status.Restrictions, newRPCRestriction(r, head.Number.Uint64(), head.Time))
+    }
+    return status, nil
+}
+
+// ============= SUBSCRIPTION APIS =============
+// RPCPolicyChange là thông báo thay đổi chính sách gửi tới subscriber
+type RPCPolicyChange struct {
+    Action      string            `json:"action"`
+    Address     common.Address    `json:"address"`
+    Contract    *common.Address   `json:"contract,omitempty"`
+    Reason      *hexutil.Uint64   `json:"reason,omitempty"`
+    Restriction *RPCRestriction   `json:"restriction,omitempty"`
+}
+
+// RPCRejectedTransaction là thông báo giao dịch bị chặn gửi tới subscriber
+type RPCRejectedTransaction struct {
+    Hash    *common.Hash      `json:"hash,omitempty"`
+    From    common.Address    `json:"from"`
+    To      *common.Address   `json:"to"`
+    Reason  string            `json:"reason"`
+    Path    string            `json:"path"`
+}
+
+// PolicyChanges tạo subscription nhận thông báo mỗi khi chính sách bảo mật thay đổi
+func (api *SecurityAPI) PolicyChanges(ctx context.Context) (*rpc.Subscription, error) {
+    notifier, supported := rpc.NotifierFromContext(ctx)
+    if !supported {
+        return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+    }
+    var (
+        rpcSub = notifier.CreateSubscription()
+        events = make(chan core.SecurityPolicyEvent, 128)
+        sub    = core.GetSecurityConfig().SubscribePolicyChanges(events)
+    )
+    go func() {
+        defer sub.Unsubscribe()
+        
+        for {
+            select {
+            case ev := <-events:
+                change := &RPCPolicyChange{
+                    Action:   ev.Action,
+                    Address:  ev.Address,
+                    Contract: ev.Contract,
+                }
+                if ev.Restriction != nil {
+                    head := api.b.CurrentHeader()
+                    reason := hexutil.Uint64(ev.Restriction.Reason)
+                    change.Reason = &reason
+                    change.Restriction = newRPCRestriction(ev.Restriction, head.Number.Uint64(), head.Time)
+                }
+                notifier.Notify(rpcSub.ID, change)
+            case <-rpcSub.Err():
+                return
+            case <-notifier.Closed():
+                return
+            }
+        }
+    }()
+    
+    return rpcSub, nil
+}
+
+// RejectedTransactions tạo subscription nhận thông báo mỗi khi một giao dịch bị
+// chặn bởi chính sách bảo mật, tại txpool, RPC hoặc khi xử lý block
+func (api *SecurityAPI) RejectedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+    notifier, supported := rpc.NotifierFromContext(ctx)
+    if !supported {
+        return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+    }
+    var (
+        rpcSub = notifier.CreateSubscription()
+        events = make(chan core.SecurityRejectionEvent, 128)
+        sub    = core.GetSecurityConfig().SubscribeRejections(events)
+    )
+    go func() {
+        defer sub.Unsubscribe()
+        
+        for {
+            select {
+            case ev := <-events:
+                rejected := &RPCRejectedTransaction{
+                    From:   ev.From,
+                    To:     ev.To,
+                    Reason: ev.Err.Error(),
+                    Path:   ev.Path,
+                }
+                if ev.Hash != (common.Hash{}) {
+                    hash := ev.Hash
+                    rejected.Hash = &hash
+                }
+                notifier.Notify(rpcSub.ID, rejected)
+            case <-rpcSub.Err():
+                return
+            case <-notifier.Closed():
+                return
+            }
+        }
+    }()
+    
+    return rpcSub, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestSecuritySubscriptions(t *testing.T) {
+	prev := core.GetSecurityConfig()
+	sc := core.NewSecurityConfig()
+	core.SetSecurityConfig(sc)
+	defer core.SetSecurityConfig(prev)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("security", NewSecurityAPI(nil)); err != nil {
+		t.Fatalf("failed to register api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		changes     = make(chan RPCPolicyChange)
+		rejections  = make(chan RPCRejectedTransaction)
+		addr        = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		hash        = common.HexToHash("0x01")
+	)
+	defer cancel()
+
+	changeSub, err := client.Subscribe(ctx, "security", changes, "policyChanges")
+	if err != nil {
+		t.Fatalf("failed to subscribe to policy changes: %v", err)
+	}
+	defer changeSub.Unsubscribe()
+	rejectSub, err := client.Subscribe(ctx, "security", rejections, "rejectedTransactions")
+	if err != nil {
+		t.Fatalf("failed to subscribe to rejected transactions: %v", err)
+	}
+	defer rejectSub.Unsubscribe()
+
+	sc.AddToBlacklist(addr)
+	select {
+	case change := <-changes:
+		if change.Action != core.PolicyActionAddToBlacklist || change.Address != addr {
+			t.Errorf("policy change mismatch: %+v", change)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for policy change")
+	}
+
+	sc.NotifyRejection(hash, addr, nil, core.ErrBlacklistedAddress, core.RejectionPathPool)
+	select {
+	case rejected := <-rejections:
+		if rejected.Hash == nil || *rejected.Hash != hash || rejected.From != addr || rejected.Path != core.RejectionPathPool || rejected.Reason != core.ErrBlacklistedAddress.Error() {
+			t.Errorf("rejected transaction mismatch: %+v", rejected)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for rejected transaction")
+	}
+}
+
+var errTestWrite = errors.New("write failed")
+
+// failingWriteDB is a database whose batches fail to write.
+type failingWriteDB struct {
+	ethdb.Database
+}
+
+func (db failingWriteDB) NewBatch() ethdb.Batch { return failingBatch{db.Database.NewBatch()} }
+
+type failingBatch struct {
+	ethdb.Batch
+}
+
+func (b failingBatch) Write() error { return errTestWrite }
+
+// Tests that list updates report persistence failures and leave the in-memory
+// policy unchanged.
+func TestSecurityListPersistFailure(t *testing.T) {
+	prev := core.GetSecurityConfig()
+	defer core.SetSecurityConfig(prev)
+
+	var (
+		db   = rawdb.NewMemoryDatabase()
+		addr = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		api  = NewSecurityAPI(nil)
+		ctx  = context.Background()
+	)
+	sc, err := core.LoadSecurityConfig(db)
+	if err != nil {
+		t.Fatalf("failed to load security config: %v", err)
+	}
+	sc.AddToWhitelist(addr)
+	sc.AddToBlacklist(addr)
+	sc.FreezeAccount(addr)
+
+	failing, err := core.LoadSecurityConfig(failingWriteDB{db})
+	if err != nil {
+		t.Fatalf("failed to load security config: %v", err)
+	}
+	core.SetSecurityConfig(failing)
+
+	other := common.HexToAddress("0x000000000000000000000000000000000000bbbb")
+	for name, call := range map[string]func() (bool, error){
+		"AddToWhitelist":      func() (bool, error) { return api.AddToWhitelist(ctx, other) },
+		"RemoveFromWhitelist": func() (bool, error) { return api.RemoveFromWhitelist(ctx, addr) },
+		"AddToBlacklist":      func() (bool, error) { return api.AddToBlacklist(ctx, other) },
+		"RemoveFromBlacklist": func() (bool, error) { return api.RemoveFromBlacklist(ctx, addr) },
+		"FreezeAccount":       func() (bool, error) { return api.FreezeAccount(ctx, other) },
+		"UnfreezeAccount":     func() (bool, error) { return api.UnfreezeAccount(ctx, addr) },
+	} {
+		if ok, err := call(); ok || !errors.Is(err, errTestWrite) {
+			t.Errorf("%s: result mismatch: have (%v, %v), want (false, %v)", name, ok, err, errTestWrite)
+		}
+	}
+	if !failing.IsWhitelisted(addr) || failing.IsWhitelisted(other) {
+		t.Error("whitelist changed after failed write")
+	}
+	if !failing.IsBlacklisted(addr) || failing.IsBlacklisted(other) {
+		t.Error("blacklist changed after failed write")
+	}
+	if !failing.IsFrozen(addr) || failing.IsFrozen(other) {
+		t.Error("frozen accounts changed after failed write")
+	}
+}
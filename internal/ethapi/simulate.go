@@ -0,0 +1,500 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated
+	// in a single request.
+	maxSimulateBlocks = 256
+
+	// timestampIncrement is the default increment between block timestamps, used
+	// if the consensus engine doesn't produce blocks at a fixed period.
+	timestampIncrement = 12
+)
+
+// simBlock is a batch of calls to be simulated sequentially.
+type simBlock struct {
+	BlockOverrides *BlockOverrides
+	StateOverrides *StateOverride
+	Calls          []TransactionArgs
+}
+
+// simCallResult is the result of a simulated call.
+type simCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *callError     `json:"error,omitempty"`
+}
+
+func (r simCallResult) MarshalJSON() ([]byte, error) {
+	type callResultAlias simCallResult
+	// Marshal logs to be an empty array instead of nil when empty
+	if r.Logs == nil {
+		r.Logs = []*types.Log{}
+	}
+	return json.Marshal(callResultAlias(r))
+}
+
+// simOpts are the inputs to eth_simulateV1.
+type simOpts struct {
+	BlockStateCalls        []simBlock
+	TraceTransfers         bool
+	Validation             bool
+	ReturnFullTransactions bool
+}
+
+// simulator is a stateful object that simulates a series of blocks.
+// It is not safe for concurrent use.
+type simulator struct {
+	b              Backend
+	state          *state.StateDB
+	base           *types.Header
+	chainConfig    *params.ChainConfig
+	budget         uint64 // Gas left for the whole simulation, unlimited if zero
+	traceTransfers bool
+	validate       bool
+	fullTx         bool
+}
+
+// execute runs the simulation of a series of blocks.
+func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var (
+		cancel  context.CancelFunc
+		timeout = sim.b.RPCEVMTimeout()
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// Make sure the context is cancelled when the call has completed
+	// this makes sure resources are cleaned up.
+	defer cancel()
+
+	blocks, err := sim.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results = make([]map[string]interface{}, len(blocks))
+		headers = make([]*types.Header, 0, len(blocks))
+		parent  = sim.base
+	)
+	for bi, block := range blocks {
+		result, senders, callResults, err := sim.processBlock(ctx, &block, parent, headers, timeout)
+		if err != nil {
+			return nil, err
+		}
+		enc := RPCMarshalBlock(result, true, false, sim.chainConfig)
+		if sim.fullTx {
+			txs := make([]interface{}, len(senders))
+			for i, sender := range senders {
+				tx := newRPCTransactionFromBlockIndex(result, uint64(i), sim.chainConfig)
+				tx.From = sender
+				txs[i] = tx
+			}
+			enc["transactions"] = txs
+		}
+		enc["calls"] = callResults
+		results[bi] = enc
+
+		parent = result.Header()
+		headers = append(headers, parent)
+	}
+	return results, nil
+}
+
+// processBlock executes the calls of a simulated block on top of its parent,
+// returning the resulting block along with the senders and results of the
+// calls.
+func (sim *simulator) processBlock(ctx context.Context, block *simBlock, parent *types.Header, headers []*types.Header, timeout time.Duration) (*types.Block, []common.Address, []simCallResult, error) {
+	header := sim.makeHeader(block.BlockOverrides, parent)
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, nil, nil, err
+	}
+	blockContext := core.NewEVMBlockContext(header, &simChainContext{ctx: ctx, b: sim.b, headers: headers}, &header.Coinbase)
+	if block.BlockOverrides.BlobBaseFee != nil {
+		blockContext.BlobBaseFee = block.BlockOverrides.BlobBaseFee.ToInt()
+	}
+	var (
+		gasUsed     uint64
+		blobGasUsed uint64
+		gp          = new(core.GasPool).AddGas(header.GasLimit)
+		txs         = make([]*types.Transaction, len(block.Calls))
+		senders     = make([]common.Address, len(block.Calls))
+		receipts    = make([]*types.Receipt, len(block.Calls))
+		callResults = make([]simCallResult, len(block.Calls))
+		vmConfig    = vm.Config{NoBaseFee: !sim.validate}
+	)
+	if sim.traceTransfers {
+		vmConfig.Tracer = newTransferTracer()
+	}
+	evm := vm.NewEVM(blockContext, vm.TxContext{GasPrice: new(big.Int)}, sim.state, sim.chainConfig, vmConfig)
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	if header.ParentBeaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, evm, sim.state)
+	}
+	for i, call := range block.Calls {
+		if err := sim.sanitizeCall(&call, header, &blockContext, gasUsed); err != nil {
+			return nil, nil, nil, err
+		}
+		tx := call.toTransaction()
+		msg, err := call.ToMessage(0, header.BaseFee)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		msg.SkipAccountChecks = !sim.validate
+
+		evm.Reset(core.NewEVMTxContext(msg), sim.state)
+		sim.state.SetTxContext(tx.Hash(), i)
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if err != nil {
+			return nil, nil, nil, txValidationError(err)
+		}
+		if err := sim.state.Error(); err != nil {
+			return nil, nil, nil, err
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, nil, nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		var root []byte
+		if sim.chainConfig.IsByzantium(header.Number) {
+			sim.state.Finalise(true)
+		} else {
+			root = sim.state.IntermediateRoot(sim.chainConfig.IsEIP158(header.Number)).Bytes()
+		}
+		gasUsed += result.UsedGas
+		if sim.budget > 0 {
+			sim.budget -= result.UsedGas
+		}
+		logs := sim.state.GetLogs(tx.Hash(), header.Number.Uint64(), common.Hash{})
+		receipts[i] = sim.makeReceipt(tx, msg, result, logs, root, gasUsed, &blockContext)
+		blobGasUsed += receipts[i].BlobGasUsed
+
+		callRes := simCallResult{ReturnValue: result.Return(), Logs: logs, GasUsed: hexutil.Uint64(result.UsedGas)}
+		if result.Failed() {
+			callRes.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if errors.Is(result.Err, vm.ErrExecutionReverted) {
+				// If the result contains a revert reason, try to unpack it.
+				revertErr := newRevertError(result.Revert())
+				callRes.Error = &callError{Message: revertErr.Error(), Code: revertErr.ErrorCode(), Data: revertErr.reason}
+			} else {
+				callRes.Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+			}
+		} else {
+			callRes.Status = hexutil.Uint64(types.ReceiptStatusSuccessful)
+		}
+		txs[i], senders[i], callResults[i] = tx, call.from(), callRes
+	}
+	header.Root = sim.state.IntermediateRoot(sim.chainConfig.IsEIP158(header.Number))
+	header.GasUsed = gasUsed
+	if sim.chainConfig.IsCancun(header.Number, header.Time) {
+		header.BlobGasUsed = &blobGasUsed
+	}
+	var withdrawals types.Withdrawals
+	if sim.chainConfig.IsShanghai(header.Number, header.Time) {
+		withdrawals = make([]*types.Withdrawal, 0)
+	}
+	b := types.NewBlockWithWithdrawals(header, txs, nil, receipts, withdrawals, trie.NewStackTrie(nil))
+	repairLogs(callResults, b.Hash())
+	return b, senders, callResults, nil
+}
+
+// makeHeader assembles the header of a simulated block on top of its parent.
+func (sim *simulator) makeHeader(overrides *BlockOverrides, parent *types.Header) *types.Header {
+	header := overrides.MakeHeader(&types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: parent.Difficulty,
+		GasLimit:   parent.GasLimit,
+	})
+	if sim.chainConfig.IsLondon(header.Number) {
+		// In non-validation mode the base fee is zero unless overridden, so
+		// that calls can be made without funding the senders.
+		if overrides.BaseFee == nil {
+			if sim.validate {
+				header.BaseFee = eip1559.CalcBaseFee(sim.chainConfig, parent)
+			} else {
+				header.BaseFee = new(big.Int)
+			}
+		}
+	}
+	if sim.chainConfig.IsCancun(header.Number, header.Time) {
+		var excess uint64
+		if parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+			excess = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excess
+		header.ParentBeaconRoot = new(common.Hash)
+	}
+	return header
+}
+
+// sanitizeCall fills in the defaults of a simulated call, checking that it
+// fits in the gas left in the block.
+func (sim *simulator) sanitizeCall(call *TransactionArgs, header *types.Header, blockContext *vm.BlockContext, gasUsed uint64) error {
+	if call.Nonce == nil {
+		nonce := sim.state.GetNonce(call.from())
+		call.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	// Let the call run wild unless explicitly specified.
+	if call.Gas == nil {
+		remaining := header.GasLimit - gasUsed
+		call.Gas = (*hexutil.Uint64)(&remaining)
+	}
+	if gasUsed+uint64(*call.Gas) > header.GasLimit {
+		return &blockGasLimitReachedError{fmt.Sprintf("block gas limit reached: %d >= %d", gasUsed, header.GasLimit)}
+	}
+	if sim.b.RPCGasCap() > 0 {
+		if sim.budget == 0 {
+			return &clientLimitExceededError{message: "RPC gas cap exhausted"}
+		}
+		if uint64(*call.Gas) > sim.budget {
+			budget := sim.budget
+			call.Gas = (*hexutil.Uint64)(&budget)
+		}
+	}
+	if call.ChainID == nil {
+		call.ChainID = (*hexutil.Big)(sim.chainConfig.ChainID)
+	}
+	if call.Value == nil {
+		call.Value = new(hexutil.Big)
+	}
+	if call.BlobHashes != nil {
+		if call.To == nil {
+			return errors.New(`missing "to" in blob transaction`)
+		}
+		if call.BlobFeeCap == nil {
+			feeCap := new(big.Int)
+			if blockContext.BlobBaseFee != nil {
+				feeCap.Set(blockContext.BlobBaseFee)
+			}
+			call.BlobFeeCap = (*hexutil.Big)(feeCap)
+		}
+	}
+	// Default to paying the base fee with no tip, which is free unless the
+	// base fee is overridden or validation is enabled.
+	if call.GasPrice != nil {
+		return nil
+	}
+	if !sim.chainConfig.IsLondon(header.Number) {
+		if call.MaxFeePerGas == nil && call.MaxPriorityFeePerGas == nil {
+			call.GasPrice = new(hexutil.Big)
+		}
+		return nil
+	}
+	if call.MaxPriorityFeePerGas == nil {
+		call.MaxPriorityFeePerGas = new(hexutil.Big)
+	}
+	if call.MaxFeePerGas == nil {
+		feeCap := new(big.Int).Add(header.BaseFee, call.MaxPriorityFeePerGas.ToInt())
+		call.MaxFeePerGas = (*hexutil.Big)(feeCap)
+	}
+	return nil
+}
+
+// makeReceipt creates the receipt of a simulated transaction.
+func (sim *simulator) makeReceipt(tx *types.Transaction, msg *core.Message, result *core.ExecutionResult, logs []*types.Log, root []byte, cumulativeGasUsed uint64, blockContext *vm.BlockContext) *types.Receipt {
+	receipt := &types.Receipt{
+		Type:              tx.Type(),
+		PostState:         root,
+		CumulativeGasUsed: cumulativeGasUsed,
+		TxHash:            tx.Hash(),
+		GasUsed:           result.UsedGas,
+		Logs:              logs,
+		TransactionIndex:  uint(sim.state.TxIndex()),
+	}
+	if result.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		receipt.Status = types.ReceiptStatusSuccessful
+	}
+	if tx.Type() == types.BlobTxType {
+		receipt.BlobGasUsed = uint64(len(tx.BlobHashes()) * params.BlobTxBlobGasPerBlob)
+		receipt.BlobGasPrice = blockContext.BlobBaseFee
+	}
+	if msg.To == nil {
+		receipt.ContractAddress = crypto.CreateAddress(msg.From, tx.Nonce())
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	return receipt
+}
+
+// repairLogs updates the block hash in the logs of the simulated calls, and
+// numbers them within the block.
+func repairLogs(calls []simCallResult, hash common.Hash) {
+	var index uint
+	for i := range calls {
+		for _, l := range calls[i].Logs {
+			l.BlockHash = hash
+			l.Index = index
+			index++
+		}
+	}
+}
+
+// timestampIncrement returns the increment between the timestamps of simulated
+// blocks, the block period of the chain if it has one.
+func (sim *simulator) timestampIncrement() uint64 {
+	switch {
+	case sim.chainConfig.PoI != nil && sim.chainConfig.PoI.Period > 0:
+		return sim.chainConfig.PoI.Period
+	case sim.chainConfig.Clique != nil && sim.chainConfig.Clique.Period > 0:
+		return sim.chainConfig.Clique.Period
+	}
+	return timestampIncrement
+}
+
+// sanitizeChain checks that the simulated blocks are in order, filling in
+// their numbers and timestamps if missing and the gaps between them with
+// empty blocks.
+func (sim *simulator) sanitizeChain(blocks []simBlock) ([]simBlock, error) {
+	var (
+		res           = make([]simBlock, 0, len(blocks))
+		base          = sim.base
+		prevNumber    = base.Number
+		prevTimestamp = base.Time
+	)
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = new(BlockOverrides)
+		}
+		if block.BlockOverrides.Number == nil {
+			n := new(big.Int).Add(prevNumber, big.NewInt(1))
+			block.BlockOverrides.Number = (*hexutil.Big)(n)
+		}
+		diff := new(big.Int).Sub(block.BlockOverrides.Number.ToInt(), prevNumber)
+		if diff.Sign() <= 0 {
+			return nil, &invalidBlockNumberError{fmt.Sprintf("block numbers must be in order: %d <= %d", block.BlockOverrides.Number.ToInt().Uint64(), prevNumber)}
+		}
+		if total := new(big.Int).Sub(block.BlockOverrides.Number.ToInt(), base.Number); total.Cmp(big.NewInt(maxSimulateBlocks)) > 0 {
+			return nil, &clientLimitExceededError{message: "too many blocks"}
+		}
+		// Fill the gap with empty blocks
+		for i := uint64(1); i < diff.Uint64(); i++ {
+			n := new(big.Int).Add(prevNumber, new(big.Int).SetUint64(i))
+			t := prevTimestamp + sim.timestampIncrement()
+			res = append(res, simBlock{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(n), Time: (*hexutil.Uint64)(&t)}})
+			prevTimestamp = t
+		}
+		prevNumber = block.BlockOverrides.Number.ToInt()
+
+		if block.BlockOverrides.Time == nil {
+			t := prevTimestamp + sim.timestampIncrement()
+			block.BlockOverrides.Time = (*hexutil.Uint64)(&t)
+		} else if t := uint64(*block.BlockOverrides.Time); t <= prevTimestamp {
+			return nil, &invalidBlockTimestampError{fmt.Sprintf("block timestamps must be in order: %d <= %d", t, prevTimestamp)}
+		}
+		prevTimestamp = uint64(*block.BlockOverrides.Time)
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// simChainContext is the chain context of the simulated blocks, resolving the
+// headers of the blocks simulated so far along with the canonical ones.
+type simChainContext struct {
+	ctx     context.Context
+	b       Backend
+	headers []*types.Header
+}
+
+func (context *simChainContext) Engine() consensus.Engine {
+	return context.b.Engine()
+}
+
+func (context *simChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	for _, header := range context.headers {
+		if header.Number.Uint64() == number {
+			if header.Hash() != hash {
+				return nil
+			}
+			return header
+		}
+	}
+	return NewChainContext(context.ctx, context.b).GetHeader(hash, number)
+}
+
+// SimulateV1 executes series of transactions on top of a base state.
+// The transactions are packed into blocks. For each block, block header
+// fields can be overridden. The state can also be overridden prior to
+// execution of each block.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (s *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	state, base, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	sim := &simulator{
+		b:              s.b,
+		state:          state,
+		base:           base,
+		chainConfig:    s.b.ChainConfig(),
+		budget:         s.b.RPCGasCap(),
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
+}
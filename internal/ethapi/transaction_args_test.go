@@ -31,7 +31,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -377,6 +379,19 @@ func (b *backendMock) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) eve
 	return nil
 }
 func (b *backendMock) SendTx(ctx context.Context, signedTx *types.Transaction) error { return nil }
+func (b *backendMock) SendBundle(ctx context.Context, bundle *txpool.Bundle) error   { return nil }
+func (b *backendMock) SendPrivateTx(ctx context.Context, tx *types.Transaction, maxBlock uint64) error {
+	return nil
+}
+func (b *backendMock) CancelPrivateTx(ctx context.Context, hash common.Hash, from common.Address, sig []byte) error {
+	return nil
+}
+func (b *backendMock) SendConditionalTx(ctx context.Context, tx *types.Transaction, conditions *txpool.TxConditions) error {
+	return nil
+}
+func (b *backendMock) AddressTransactions(ctx context.Context, addr common.Address, cursor []byte, limit int) ([]rawdb.AddressTxEntry, []byte, error) {
+	return nil, nil, nil
+}
 func (b *backendMock) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
 	return false, nil, [32]byte{}, 0, 0, nil
 }
@@ -392,10 +407,20 @@ func (b *backendMock) TxPoolContent() (map[common.Address][]*types.Transaction,
 func (b *backendMock) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
 	return nil, nil
 }
-func (b *backendMock) SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription      { return nil }
+func (b *backendMock) SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription { return nil }
+func (b *backendMock) TxLifecycle(hash common.Hash) []txpool.TxLifecycleEvent {
+	return nil
+}
+func (b *backendMock) SubscribeTxLifecycleEvent(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return nil
+}
 func (b *backendMock) BloomStatus() (uint64, uint64)                                        { return 0, 0 }
 func (b *backendMock) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
-func (b *backendMock) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription         { return nil }
+func (b *backendMock) LogIndexStatus() (uint64, bool)                                       { return 0, false }
+func (b *backendMock) LogIndexBlocks(ctx context.Context, addresses []common.Address, topics [][]common.Hash, begin, end uint64) ([]uint64, error) {
+	return nil, nil
+}
+func (b *backendMock) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription { return nil }
 func (b *backendMock) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	return nil
 }
@@ -31,6 +31,7 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	"tobe":     TobeJs,
 	"security":   SecurityJs,  // THÊM DÒNG NÀY
 }
 
@@ -438,6 +439,18 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCallMany',
+			call: 'debug_traceCallMany',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'liveTraces',
+			call: 'debug_liveTraces',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -541,12 +554,38 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'simulateV1',
+			call: 'eth_simulateV1',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'eth_callBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'sendPrivateTransaction',
+			call: 'eth_sendPrivateTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'cancelPrivateTransaction',
+			call: 'eth_cancelPrivateTransaction',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -589,6 +628,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'eth_getTransactionsByAddress',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'getProof',
 			call: 'eth_getProof',
@@ -791,6 +836,11 @@ web3._extend({
 			name: 'inspect',
 			getter: 'txpool_inspect'
 		}),
+		new web3._extend.Method({
+			name: 'lifecycle',
+			call: 'txpool_status',
+			params: 1
+		}),
 		new web3._extend.Property({
 			name: 'status',
 			getter: 'txpool_status',
@@ -913,6 +963,27 @@ web3._extend({
 });
 `
 
+const TobeJs = `
+web3._extend({
+	property: 'tobe',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getTokenTransfers',
+			call: 'tobe_getTokenTransfers',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getTokenHolders',
+			call: 'tobe_getTokenHolders',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+	],
+});
+`
+
 // Thêm const cho Security API
 const SecurityJs = `
 web3._extend({
@@ -985,6 +1056,29 @@ web3._extend({
             call: 'security_isContractFrozen',
             params: 1,
             inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+        }),
+        new web3._extend.Method({
+            name: 'addRestriction',
+            call: 'security_addRestriction',
+            params: 2,
+            inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+        }),
+        new web3._extend.Method({
+            name: 'removeRestriction',
+            call: 'security_removeRestriction',
+            params: 2,
+            inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+        }),
+        new web3._extend.Method({
+            name: 'getRestrictions',
+            call: 'security_getRestrictions',
+            params: 0
+        }),
+        new web3._extend.Method({
+            name: 'checkAddress',
+            call: 'security_checkAddress',
+            params: 1,
+            inputFormatter: [web3._extend.formatters.inputAddressFormatter]
         })
     ]
 });
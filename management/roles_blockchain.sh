@@ -36,7 +36,7 @@ case "$1" in
     echo "- admin.* (quản trị node)"
     echo "- security.* (bảo mật)"
     echo ""
-    ./build/bin/geth attach ~/ethereum/node1/geth.ipc
+    ./build/bin/geth attach ~/ethereum/node1/mining-admin.ipc
     ;;
 
 "security-admin")
@@ -57,7 +57,7 @@ case "$1" in
     echo "- miner.* (điều khiển đào)"
     echo "- admin.* (quản trị node)"
     echo ""
-    ./build/bin/geth attach ~/ethereum/node1/geth.ipc
+    ./build/bin/geth attach ~/ethereum/node1/security-admin.ipc
     ;;
 
 "network-admin")
@@ -78,7 +78,7 @@ case "$1" in
     echo "- miner.* (điều khiển đào)"
     echo "- security.* (bảo mật)"
     echo ""
-    ./build/bin/geth attach ~/ethereum/node1/geth.ipc
+    ./build/bin/geth attach ~/ethereum/node1/network-admin.ipc
     ;;
 
 "read-only")
@@ -98,7 +98,7 @@ case "$1" in
     echo "- Tất cả lệnh thay đổi dữ liệu"
     echo "- Không thể unlock, mining, admin"
     echo ""
-    ./build/bin/geth attach ~/ethereum/node1/geth.ipc
+    ./build/bin/geth attach ~/ethereum/node1/read-only.ipc
     ;;
 
 *)
@@ -119,7 +119,7 @@ case "$1" in
     echo "  $0 mining-admin     # Console điều khiển đào"
     echo "  $0 security-admin   # Console quản lý bảo mật"
     echo ""
-    echo "💡 Lưu ý: Script này chỉ hiển thị hướng dẫn."
-    echo "   Việc thực thi quyền phụ thuộc vào cấu hình thực tế."
+    echo "💡 Lưu ý: Mỗi role dùng một IPC socket riêng (<role>.ipc), node chỉ"
+    echo "   phục vụ các method được phép trong [Node.RPCRoles] của file TOML."
     ;;
 esac
@@ -53,6 +53,9 @@ type Config struct {
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+	BuildBudget       time.Duration // The maximum time allowance for filling a sealing block (0 = derived from the block period)
+
+	TxOrdering TxOrdering // Policy ordering the transactions of different accounts in a block
 }
 
 // DefaultConfig contains default settings for miner.
@@ -66,6 +69,8 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+
+	TxOrdering: TxOrderingFee,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
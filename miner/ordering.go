@@ -21,11 +21,85 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/holiman/uint256"
 )
 
+// TxOrdering is the name of a policy deciding the order in which the pending
+// transactions of different accounts are included into blocks. Transactions of
+// the same account are always included in nonce order.
+type TxOrdering string
+
+const (
+	TxOrderingFee   TxOrdering = "fee"   // Highest effective miner tip first, earliest arrival on ties
+	TxOrderingFIFO  TxOrdering = "fifo"  // Earliest arrival first, regardless of fees
+	TxOrderingLanes TxOrdering = "lanes" // Security whitelisted senders first, then by fee
+)
+
+// orderingPolicy decides the order in which the next transactions of different
+// accounts are included into a block.
+type orderingPolicy interface {
+	// less reports whether transaction a should be included before b.
+	less(a, b *txWithMinerFee) bool
+}
+
+// newOrderingPolicy creates the ordering policy of the given name for building
+// a single block, or nil if the name is unknown.
+func newOrderingPolicy(name TxOrdering) orderingPolicy {
+	switch name {
+	case TxOrderingFee, "":
+		return feeOrdering{}
+	case TxOrderingFIFO:
+		return fifoOrdering{}
+	case TxOrderingLanes:
+		return laneOrdering{priority: core.GetSecurityConfig().GetWhitelistAddresses()}
+	default:
+		return nil
+	}
+}
+
+// feeOrdering is the profit-maximizing policy, including the transactions paying
+// the highest miner tip first. Equal tips are ordered by arrival time.
+type feeOrdering struct{}
+
+func (feeOrdering) less(a, b *txWithMinerFee) bool {
+	// If the prices are equal, use the time the transaction was first seen for
+	// deterministic sorting
+	cmp := a.fees.Cmp(b.fees)
+	if cmp == 0 {
+		return a.tx.Time.Before(b.tx.Time)
+	}
+	return cmp > 0
+}
+
+// fifoOrdering includes the transactions in the order they were first seen by
+// the node, regardless of the fees paid. Simultaneous arrivals are ordered by
+// miner tip.
+type fifoOrdering struct{}
+
+func (fifoOrdering) less(a, b *txWithMinerFee) bool {
+	if a.tx.Time.Equal(b.tx.Time) {
+		return a.fees.Gt(b.fees)
+	}
+	return a.tx.Time.Before(b.tx.Time)
+}
+
+// laneOrdering includes the transactions of prioritized senders ahead of all
+// others, ordering by fee within each lane. The priority senders are a snapshot
+// of the security whitelist taken when the block building started.
+type laneOrdering struct {
+	priority map[common.Address]bool
+}
+
+func (o laneOrdering) less(a, b *txWithMinerFee) bool {
+	if pa, pb := o.priority[a.from], o.priority[b.from]; pa != pb {
+		return pa
+	}
+	return feeOrdering{}.less(a, b)
+}
+
 // txWithMinerFee wraps a transaction with its gas price or effective miner gasTipCap
 type txWithMinerFee struct {
 	tx   *txpool.LazyTransaction
@@ -35,11 +109,15 @@ type txWithMinerFee struct {
 
 // newTxWithMinerFee creates a wrapped transaction, calculating the effective
 // miner gasTipCap if a base fee is provided.
-// Returns error in case of a negative effective miner gasTipCap.
-func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee *uint256.Int) (*txWithMinerFee, error) {
+// Returns error in case of a negative effective miner gasTipCap, unless the
+// sender is fee exempt, in which case the transaction pays no tip.
+func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee *uint256.Int, exempt func(common.Address) bool) (*txWithMinerFee, error) {
 	tip := new(uint256.Int).Set(tx.GasTipCap)
 	if baseFee != nil {
 		if tx.GasFeeCap.Cmp(baseFee) < 0 {
+			if exempt != nil && exempt(from) {
+				return &txWithMinerFee{tx: tx, from: from, fees: new(uint256.Int)}, nil
+			}
 			return nil, types.ErrGasFeeCapTooLow
 		}
 		tip = new(uint256.Int).Sub(tx.GasFeeCap, baseFee)
@@ -54,113 +132,118 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	}, nil
 }
 
-// txByPriceAndTime implements both the sort and the heap interface, making it useful
-// for all at once sorting as well as individually adding and removing elements.
-type txByPriceAndTime []*txWithMinerFee
-
-func (s txByPriceAndTime) Len() int { return len(s) }
-func (s txByPriceAndTime) Less(i, j int) bool {
-	// If the prices are equal, use the time the transaction was first seen for
-	// deterministic sorting
-	cmp := s[i].fees.Cmp(s[j].fees)
-	if cmp == 0 {
-		return s[i].tx.Time.Before(s[j].tx.Time)
-	}
-	return cmp > 0
+// txHeads implements both the sort and the heap interface, ordering transactions
+// by the given policy. It is useful for all at once sorting as well as for
+// individually adding and removing elements.
+type txHeads struct {
+	list   []*txWithMinerFee
+	policy orderingPolicy
 }
-func (s txByPriceAndTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-func (s *txByPriceAndTime) Push(x interface{}) {
-	*s = append(*s, x.(*txWithMinerFee))
+func (s *txHeads) Len() int           { return len(s.list) }
+func (s *txHeads) Less(i, j int) bool { return s.policy.less(s.list[i], s.list[j]) }
+func (s *txHeads) Swap(i, j int)      { s.list[i], s.list[j] = s.list[j], s.list[i] }
+
+func (s *txHeads) Push(x interface{}) {
+	s.list = append(s.list, x.(*txWithMinerFee))
 }
 
-func (s *txByPriceAndTime) Pop() interface{} {
-	old := *s
+func (s *txHeads) Pop() interface{} {
+	old := s.list
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*s = old[0 : n-1]
+	s.list = old[0 : n-1]
 	return x
 }
 
-// transactionsByPriceAndNonce represents a set of transactions that can return
-// transactions in a profit-maximizing sorted order, while supporting removing
-// entire batches of transactions for non-executable accounts.
-type transactionsByPriceAndNonce struct {
+// orderedTransactions represents a set of transactions that can return
+// transactions sorted by an ordering policy, while supporting removing entire
+// batches of transactions for non-executable accounts.
+type orderedTransactions struct {
 	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
-	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
+	heads   *txHeads                                     // Next transaction for each unique account (policy heap)
 	signer  types.Signer                                 // Signer for the set of transactions
 	baseFee *uint256.Int                                 // Current base fee
+	exempt  func(common.Address) bool                    // Reports fee exempt senders allowed below the base fee (nil = none)
 }
 
-// newTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// newOrderedTransactions creates a transaction set that can retrieve transactions
+// sorted by the given policy in a nonce-honouring way.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int) *transactionsByPriceAndNonce {
+func newOrderedTransactions(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, policy orderingPolicy, exempt func(common.Address) bool) *orderedTransactions {
 	// Convert the basefee from header format to uint256 format
 	var baseFeeUint *uint256.Int
 	if baseFee != nil {
 		baseFeeUint = uint256.MustFromBig(baseFee)
 	}
-	// Initialize a price and received time based heap with the head transactions
-	heads := make(txByPriceAndTime, 0, len(txs))
+	// Initialize a policy ordered heap with the head transactions
+	heads := &txHeads{list: make([]*txWithMinerFee, 0, len(txs)), policy: policy}
 	for from, accTxs := range txs {
-		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFeeUint)
+		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFeeUint, exempt)
 		if err != nil {
 			delete(txs, from)
 			continue
 		}
-		heads = append(heads, wrapped)
+		heads.list = append(heads.list, wrapped)
 		txs[from] = accTxs[1:]
 	}
-	heap.Init(&heads)
+	heap.Init(heads)
 
 	// Assemble and return the transaction set
-	return &transactionsByPriceAndNonce{
+	return &orderedTransactions{
 		txs:     txs,
 		heads:   heads,
 		signer:  signer,
 		baseFee: baseFeeUint,
+		exempt:  exempt,
 	}
 }
 
-// Peek returns the next transaction by price.
-func (t *transactionsByPriceAndNonce) Peek() (*txpool.LazyTransaction, *uint256.Int) {
-	if len(t.heads) == 0 {
+// Peek returns the next transaction by the ordering policy.
+func (t *orderedTransactions) Peek() (*txpool.LazyTransaction, *uint256.Int) {
+	if len(t.heads.list) == 0 {
 		return nil, nil
 	}
-	return t.heads[0].tx, t.heads[0].fees
+	return t.heads.list[0].tx, t.heads.list[0].fees
+}
+
+// Before reports whether the next transaction of the set should be included
+// ahead of the next one of the other set. Both sets must be non-empty and share
+// the same ordering policy.
+func (t *orderedTransactions) Before(other *orderedTransactions) bool {
+	return t.heads.policy.less(t.heads.list[0], other.heads.list[0])
 }
 
 // Shift replaces the current best head with the next one from the same account.
-func (t *transactionsByPriceAndNonce) Shift() {
-	acc := t.heads[0].from
+func (t *orderedTransactions) Shift() {
+	acc := t.heads.list[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
-		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
-			t.heads[0], t.txs[acc] = wrapped, txs[1:]
-			heap.Fix(&t.heads, 0)
+		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee, t.exempt); err == nil {
+			t.heads.list[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
 			return
 		}
 	}
-	heap.Pop(&t.heads)
+	heap.Pop(t.heads)
 }
 
 // Pop removes the best transaction, *not* replacing it with the next one from
 // the same account. This should be used when a transaction cannot be executed
 // and hence all subsequent ones should be discarded from the same account.
-func (t *transactionsByPriceAndNonce) Pop() {
-	heap.Pop(&t.heads)
+func (t *orderedTransactions) Pop() {
+	heap.Pop(t.heads)
 }
 
-// Empty returns if the price heap is empty. It can be used to check it simpler
-// than calling peek and checking for nil return.
-func (t *transactionsByPriceAndNonce) Empty() bool {
-	return len(t.heads) == 0
+// Empty returns if the heap is empty. It can be used to check it simpler than
+// calling peek and checking for nil return.
+func (t *orderedTransactions) Empty() bool {
+	return len(t.heads.list) == 0
 }
 
 // Clear removes the entire content of the heap.
-func (t *transactionsByPriceAndNonce) Clear() {
-	t.heads, t.txs = nil, nil
+func (t *orderedTransactions) Clear() {
+	t.heads.list, t.txs = nil, nil
 }
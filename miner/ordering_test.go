@@ -102,7 +102,7 @@ func testTransactionPriceNonceSort(t *testing.T, baseFee *big.Int) {
 		expectedCount += count
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := newTransactionsByPriceAndNonce(signer, groups, baseFee)
+	txset := newOrderedTransactions(signer, groups, baseFee, feeOrdering{}, nil)
 
 	txs := types.Transactions{}
 	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
@@ -168,7 +168,7 @@ func TestTransactionTimeSort(t *testing.T) {
 		})
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := newTransactionsByPriceAndNonce(signer, groups, nil)
+	txset := newOrderedTransactions(signer, groups, nil, feeOrdering{}, nil)
 
 	txs := types.Transactions{}
 	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
@@ -194,3 +194,52 @@ func TestTransactionTimeSort(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the ordering policies honour account nonces regardless of how they
+// order transactions across accounts.
+func TestOrderingPoliciesNonceOrder(t *testing.T) {
+	t.Parallel()
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	signer := types.HomesteadSigner{}
+
+	for _, ordering := range []TxOrdering{TxOrderingFee, TxOrderingFIFO, TxOrderingLanes} {
+		groups := make(map[common.Address][]*txpool.LazyTransaction)
+		for i, key := range []*ecdsa.PrivateKey{key1, key2} {
+			addr := crypto.PubkeyToAddress(key.PublicKey)
+			for nonce := uint64(0); nonce < 3; nonce++ {
+				// Later nonces pay more and arrive earlier, tempting reordering
+				tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100, big.NewInt(int64(10*nonce+uint64(i)+1)), nil), signer, key)
+				tx.SetTime(time.Unix(int64(10-nonce), 0))
+				groups[addr] = append(groups[addr], &txpool.LazyTransaction{
+					Hash:      tx.Hash(),
+					Tx:        tx,
+					Time:      tx.Time(),
+					GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+					GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+					Gas:       tx.Gas(),
+				})
+			}
+		}
+		txset := newOrderedTransactions(signer, groups, nil, newOrderingPolicy(ordering), nil)
+
+		nonces := make(map[common.Address]uint64)
+		count := 0
+		for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
+			from, _ := types.Sender(signer, tx.Tx)
+			if tx.Tx.Nonce() != nonces[from] {
+				t.Errorf("%s: nonce order violated for %x: have %d, want %d", ordering, from[:4], tx.Tx.Nonce(), nonces[from])
+			}
+			nonces[from]++
+			count++
+			txset.Shift()
+		}
+		if count != 6 {
+			t.Errorf("%s: ordered transactions mismatch: have %d, want 6", ordering, count)
+		}
+	}
+	if newOrderingPolicy("unknown") != nil {
+		t.Error("unknown ordering policy created")
+	}
+}
@@ -19,6 +19,8 @@ package miner
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -35,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
@@ -80,6 +83,12 @@ var (
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
 )
 
+var (
+	buildTimer        = metrics.NewRegisteredTimer("miner/build/time", nil)
+	buildPeriodHist   = metrics.NewRegisteredHistogram("miner/build/period", nil, metrics.NewExpDecaySample(1028, 0.015)) // Build time in percentage of the block period
+	buildOverrunMeter = metrics.NewRegisteredMeter("miner/build/overrun", nil)                                            // Blocks sealed partially due to the building budget
+)
+
 // environment is the worker's current environment and holds all
 // information of the sealing block generation.
 type environment struct {
@@ -94,6 +103,9 @@ type environment struct {
 	receipts []*types.Receipt
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+
+	exemptGas uint64                   // Gas used by fee exempt system accounts
+	hidden    map[common.Hash]struct{} // Transactions not to be exposed before the block is sealed
 }
 
 // copy creates a deep copy of environment.
@@ -105,6 +117,8 @@ func (env *environment) copy() *environment {
 		coinbase: env.coinbase,
 		header:   types.CopyHeader(env.header),
 		receipts: copyReceipts(env.receipts),
+
+		exemptGas: env.exemptGas,
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -116,9 +130,21 @@ func (env *environment) copy() *environment {
 	cpy.sidecars = make([]*types.BlobTxSidecar, len(env.sidecars))
 	copy(cpy.sidecars, env.sidecars)
 
+	if env.hidden != nil {
+		cpy.hidden = maps.Clone(env.hidden)
+	}
 	return cpy
 }
 
+// hide marks a transaction as private to the sealer, keeping it out of the
+// pending block and the pending logs.
+func (env *environment) hide(hash common.Hash) {
+	if env.hidden == nil {
+		env.hidden = make(map[common.Hash]struct{})
+	}
+	env.hidden[hash] = struct{}{}
+}
+
 // discard terminates the background prefetcher go-routine. It should
 // always be called for all created environment instances otherwise
 // the go-routine leak can happen.
@@ -228,10 +254,20 @@ type worker struct {
 	// in case there are some computation expensive transactions in txpool.
 	newpayloadTimeout time.Duration
 
+	// buildBudget is the maximum time allowance for filling a sealing block with
+	// transactions, after which whatever has been filled is sealed. It's derived
+	// from the consensus block period unless configured explicitly, zero meaning
+	// no limit.
+	buildBudget time.Duration
+
 	// recommit is the time interval to re-create sealing work or to re-build
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering is the policy deciding the order in which the transactions of
+	// different accounts are included into blocks.
+	ordering TxOrdering
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -279,6 +315,14 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.recommit = recommit
 
+	// Sanitize the transaction ordering policy if it's unknown.
+	ordering := worker.config.TxOrdering
+	if newOrderingPolicy(ordering) == nil {
+		log.Warn("Sanitizing miner transaction ordering", "provided", ordering, "updated", TxOrderingFee)
+		ordering = TxOrderingFee
+	}
+	worker.ordering = ordering
+
 	// Sanitize the timeout config for creating payload.
 	newpayloadTimeout := worker.config.NewPayloadTimeout
 	if newpayloadTimeout == 0 {
@@ -290,6 +334,19 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	// Derive the block building budget from the consensus period if unset.
+	var (
+		period      = blockPeriod(chainConfig)
+		buildBudget = worker.config.BuildBudget
+	)
+	if buildBudget == 0 {
+		buildBudget = time.Duration(float64(period) * params.BuildBudgetRatio)
+	}
+	if period > 0 && buildBudget > period {
+		log.Warn("Block building budget exceeds the block period", "budget", buildBudget, "period", period)
+	}
+	worker.buildBudget = buildBudget
+
 	worker.wg.Add(4)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
@@ -562,8 +619,9 @@ func (w *worker) mainLoop() {
 						BlobGas:   tx.BlobGas(),
 					})
 				}
-				plainTxs := newTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee) // Mixed bag of everrything, yolo
-				blobTxs := newTransactionsByPriceAndNonce(w.current.signer, nil, w.current.header.BaseFee)  // Empty bag, don't bother optimising
+				policy := newOrderingPolicy(w.ordering)
+				plainTxs := newOrderedTransactions(w.current.signer, txs, w.current.header.BaseFee, policy, w.feeExempt(w.current.header)) // Mixed bag of everrything, yolo
+				blobTxs := newOrderedTransactions(w.current.signer, nil, w.current.header.BaseFee, policy, w.feeExempt(w.current.header))  // Empty bag, don't bother optimising
 
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, plainTxs, blobTxs, nil)
@@ -739,6 +797,17 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 
 // updateSnapshot updates pending snapshot block, receipts and state.
 func (w *worker) updateSnapshot(env *environment) {
+	// The pending block is public, rebuild it without the transactions which
+	// must stay private until the block is sealed.
+	if len(env.hidden) > 0 {
+		public, err := w.publicEnv(env)
+		if err != nil {
+			log.Warn("Failed to build public pending block", "err", err)
+			return
+		}
+		defer public.discard()
+		env = public
+	}
 	w.snapshotMu.Lock()
 	defer w.snapshotMu.Unlock()
 
@@ -753,6 +822,50 @@ func (w *worker) updateSnapshot(env *environment) {
 	w.snapshotState = env.state.Copy()
 }
 
+// publicEnv re-executes the public transactions of the environment on top of
+// its parent state, leaving the hidden ones out. Transactions failing without
+// the hidden ones are dropped.
+func (w *worker) publicEnv(env *environment) (*environment, error) {
+	parent := w.chain.GetHeader(env.header.ParentHash, env.header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, errors.New("missing parent")
+	}
+	header := types.CopyHeader(env.header)
+	header.GasUsed = 0
+	if header.BlobGasUsed != nil {
+		header.BlobGasUsed = new(uint64)
+	}
+	public, err := w.makeEnv(parent, header, env.coinbase)
+	if err != nil {
+		return nil, err
+	}
+	public.gasPool = new(core.GasPool).AddGas(header.GasLimit)
+	if header.ParentBeaconRoot != nil {
+		context := core.NewEVMBlockContext(header, w.chain, nil)
+		vmenv := vm.NewEVM(context, vm.TxContext{}, public.state, w.chainConfig, vm.Config{})
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, public.state)
+	}
+	for _, tx := range env.txs {
+		if _, ok := env.hidden[tx.Hash()]; ok {
+			continue
+		}
+		public.state.SetTxContext(tx.Hash(), public.tcount)
+		receipt, err := w.applyTransaction(public, tx)
+		if err != nil {
+			log.Trace("Dropping transaction from public pending block", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		public.txs = append(public.txs, tx)
+		public.receipts = append(public.receipts, receipt)
+		if tx.Type() == types.BlobTxType {
+			public.blobs += len(tx.BlobHashes())
+			*header.BlobGasUsed += receipt.BlobGasUsed
+		}
+		public.tcount++
+	}
+	return public, nil
+}
+
 func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*types.Log, error) {
 	if tx.Type() == types.BlobTxType {
 		return w.commitBlobTransaction(env, tx)
@@ -804,7 +917,7 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *orderedTransactions, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -833,10 +946,10 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		// Retrieve the next transaction and abort if all done.
 		var (
 			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
+			txs *orderedTransactions
 		)
-		pltx, ptip := plainTxs.Peek()
-		bltx, btip := blobTxs.Peek()
+		pltx, _ := plainTxs.Peek()
+		bltx, _ := blobTxs.Peek()
 
 		switch {
 		case pltx == nil:
@@ -844,7 +957,7 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		case bltx == nil:
 			txs, ltx = plainTxs, pltx
 		default:
-			if ptip.Lt(btip) {
+			if blobTxs.Before(plainTxs) {
 				txs, ltx = blobTxs, bltx
 			} else {
 				txs, ltx = plainTxs, pltx
@@ -882,6 +995,14 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 			txs.Pop()
 			continue
 		}
+		// Cap the gas used by fee exempt system accounts to their per block
+		// budget, so they can't starve the paying users.
+		exempt, left := w.exemptGasLeft(env, from)
+		if exempt && left < tx.Gas() {
+			log.Trace("Not enough fee exempt gas left for transaction", "hash", ltx.Hash, "left", left, "needed", tx.Gas())
+			txs.Pop()
+			continue
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -893,9 +1014,17 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 			txs.Shift()
 
 		case errors.Is(err, nil):
-			// Everything ok, collect the logs and shift in the next transaction from the same account
-			coalescedLogs = append(coalescedLogs, logs...)
+			// Everything ok, collect the logs and shift in the next transaction from the same account.
+			// Private transactions and their logs stay hidden until the block is sealed.
+			if _, private := ltx.Pool.(txpool.PrivatePool); private {
+				env.hide(tx.Hash())
+			} else {
+				coalescedLogs = append(coalescedLogs, logs...)
+			}
 			env.tcount++
+			if exempt {
+				env.exemptGas += env.receipts[len(env.receipts)-1].GasUsed
+			}
 			txs.Shift()
 
 		default:
@@ -923,6 +1052,230 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 	return nil
 }
 
+// commitBundles tries to include the given bundles into the block, each of them
+// atomically. A bundle is kept only if all of its transactions execute, reverts
+// being tolerated for the ones the bundle allows to revert, and if it pays the
+// coinbase at least minTip per gas used.
+func (w *worker) commitBundles(env *environment, bundles []*txpool.Bundle, minTip *uint256.Int, interrupt *atomic.Int32) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	for _, bundle := range bundles {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		// If we don't have enough gas for any further transactions then we're done.
+		if env.gasPool.Gas() < params.TxGas {
+			log.Trace("Not enough gas for further bundles", "have", env.gasPool, "want", params.TxGas)
+			break
+		}
+		if err := w.commitBundle(env, bundle, minTip); err != nil {
+			log.Debug("Bundle skipped", "hash", bundle.Hash(), "err", err)
+			continue
+		}
+		log.Debug("Committed bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs), "number", env.header.Number)
+	}
+	// Bundled transactions are private until the block is sealed, so neither they
+	// nor their logs are published with the pending block.
+	return nil
+}
+
+// commitBundle executes all transactions of a bundle on top of the environment.
+// The state journal does not survive across transactions, so the bundle is run
+// on a copy of the environment, which only replaces the original if none of the
+// transactions fails and the bundle pays enough.
+func (w *worker) commitBundle(env *environment, bundle *txpool.Bundle, minTip *uint256.Int) error {
+	var (
+		work     = env.copy()
+		balance  = new(uint256.Int).Set(work.state.GetBalance(work.coinbase))
+		totalGas uint64
+	)
+	work.blobs = env.blobs
+
+	for _, tx := range bundle.Txs {
+		if tx.Protected() && !w.chainConfig.IsEIP155(work.header.Number) {
+			return fmt.Errorf("replay protected transaction %x before EIP155", tx.Hash())
+		}
+		// Fee exempt system accounts are held to their per block gas budget
+		// within bundles too, the whole bundle is dropped if it doesn't fit.
+		from, err := types.Sender(work.signer, tx)
+		if err != nil {
+			return fmt.Errorf("transaction %x: %w", tx.Hash(), err)
+		}
+		exempt, left := w.exemptGasLeft(work, from)
+		if exempt && left < tx.Gas() {
+			return fmt.Errorf("transaction %x exceeds the fee exempt gas budget: left %d, needed %d", tx.Hash(), left, tx.Gas())
+		}
+		work.state.SetTxContext(tx.Hash(), work.tcount)
+
+		receipt, err := w.applyTransaction(work, tx)
+		if err != nil {
+			return fmt.Errorf("transaction %x: %w", tx.Hash(), err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !bundle.CanRevert(tx.Hash()) {
+			return fmt.Errorf("transaction %x reverted", tx.Hash())
+		}
+		work.txs = append(work.txs, tx)
+		work.receipts = append(work.receipts, receipt)
+		work.hide(tx.Hash())
+		work.tcount++
+		if exempt {
+			work.exemptGas += receipt.GasUsed
+		}
+		totalGas += receipt.GasUsed
+	}
+	// The bundle executed fine, make sure it pays more than the regular
+	// transactions it displaces.
+	profit := new(uint256.Int)
+	if current := work.state.GetBalance(work.coinbase); current.Gt(balance) {
+		profit.Sub(current, balance)
+	}
+	if price := profit.Div(profit, uint256.NewInt(totalGas)); minTip != nil && price.Lt(minTip) {
+		return fmt.Errorf("bundle underpriced: effective tip %v, required %v", price, minTip)
+	}
+	// Swap in the updated environment, moving the prefetcher over as well
+	env.state.StopPrefetcher()
+	work.state.StartPrefetcher("miner")
+	*env = *work
+	return nil
+}
+
+// commitConditionals tries to include the given conditional transactions into
+// the block by effective miner tip, checking their conditions on top of the state
+// built so far. Once a transaction of an account is skipped, all later ones of
+// the same account are skipped too, as their nonces can no longer match.
+func (w *worker) commitConditionals(env *environment, txs []*txpool.ConditionalTx, interrupt *atomic.Int32) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	// Order the transactions by effective miner tip, keeping each account's
+	// transactions in nonce order
+	var (
+		pending    = make(map[common.Address][]*txpool.LazyTransaction)
+		conditions = make(map[common.Hash]*txpool.TxConditions)
+	)
+	for _, ctx := range txs {
+		from, _ := types.Sender(env.signer, ctx.Tx) // already validated by the pool
+		pending[from] = append(pending[from], &txpool.LazyTransaction{
+			Hash:      ctx.Tx.Hash(),
+			Tx:        ctx.Tx,
+			Time:      ctx.Time,
+			GasFeeCap: uint256.MustFromBig(ctx.Tx.GasFeeCap()),
+			GasTipCap: uint256.MustFromBig(ctx.Tx.GasTipCap()),
+			Gas:       ctx.Tx.Gas(),
+		})
+		conditions[ctx.Tx.Hash()] = ctx.Conditions
+	}
+	ordered := newOrderedTransactions(env.signer, pending, env.header.BaseFee, feeOrdering{}, w.feeExempt(env.header))
+	for {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		// If we don't have enough gas for any further transactions then we're done.
+		if env.gasPool.Gas() < params.TxGas {
+			log.Trace("Not enough gas for further conditional transactions", "have", env.gasPool, "want", params.TxGas)
+			break
+		}
+		ltx, _ := ordered.Peek()
+		if ltx == nil {
+			break
+		}
+		tx := ltx.Tx
+		from, _ := types.Sender(env.signer, tx)
+
+		if env.gasPool.Gas() < tx.Gas() {
+			log.Trace("Not enough gas left for conditional transaction", "hash", tx.Hash(), "left", env.gasPool.Gas(), "needed", tx.Gas())
+			ordered.Pop()
+			continue
+		}
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			log.Trace("Ignoring replay protected conditional transaction", "hash", tx.Hash(), "eip155", w.chainConfig.EIP155Block)
+			ordered.Pop()
+			continue
+		}
+		exempt, left := w.exemptGasLeft(env, from)
+		if exempt && left < tx.Gas() {
+			log.Trace("Not enough fee exempt gas left for conditional transaction", "hash", tx.Hash(), "left", left, "needed", tx.Gas())
+			ordered.Pop()
+			continue
+		}
+		err := conditions[tx.Hash()].CheckBlock(env.header)
+		if err == nil {
+			err = conditions[tx.Hash()].CheckState(env.state)
+		}
+		if err != nil {
+			log.Trace("Conditional transaction not eligible, account skipped", "hash", tx.Hash(), "err", err)
+			ordered.Pop()
+			continue
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx); err != nil {
+			log.Debug("Conditional transaction failed, account skipped", "hash", tx.Hash(), "err", err)
+			ordered.Pop()
+			continue
+		}
+		env.hide(tx.Hash())
+		env.tcount++
+		if exempt {
+			env.exemptGas += env.receipts[len(env.receipts)-1].GasUsed
+		}
+		ordered.Shift()
+	}
+	// Conditional transactions are not public until the block is sealed, so
+	// neither they nor their logs are published with the pending block.
+	return nil
+}
+
+// feeExempt returns a callback reporting whether an account is exempt from
+// transaction fees in the block of the given header.
+func (w *worker) feeExempt(header *types.Header) func(common.Address) bool {
+	return func(addr common.Address) bool {
+		return w.chainConfig.IsFeeExempt(header.Number, addr)
+	}
+}
+
+// exemptGasLeft reports whether the account is exempt from transaction fees in
+// the block being built, and if so, how much of the per block gas budget of the
+// fee exempt accounts is still available.
+func (w *worker) exemptGasLeft(env *environment, from common.Address) (bool, uint64) {
+	if !w.chainConfig.IsFeeExempt(env.header.Number, from) {
+		return false, 0
+	}
+	limit := w.chainConfig.FeeExempt.GasLimit
+	if limit == 0 {
+		return true, math.MaxUint64
+	}
+	if env.exemptGas >= limit {
+		return true, 0
+	}
+	return true, limit - env.exemptGas
+}
+
+// bestMinerTip returns the highest effective miner tip offered by the next
+// executable transaction of any account in the given pending set.
+func bestMinerTip(pending map[common.Address][]*txpool.LazyTransaction, baseFee *uint256.Int) *uint256.Int {
+	best := new(uint256.Int)
+	for from, txs := range pending {
+		if len(txs) == 0 {
+			continue
+		}
+		wrapped, err := newTxWithMinerFee(txs[0], from, baseFee, nil)
+		if err != nil {
+			continue
+		}
+		if wrapped.fees.Gt(best) {
+			best = wrapped.fees
+		}
+	}
+	return best
+}
+
 // generateParams wraps various of settings for generating sealing task.
 type generateParams struct {
 	timestamp   uint64            // The timestamp for sealing task
@@ -1019,8 +1372,9 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 }
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// into the given sealing block. With the fee ordering policy, local transactions are
+// included ahead of remote ones, each group ordered by fee. The other policies order
+// all transactions together, so their lanes and arrival order apply to locals too.
 func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) error {
 	w.mu.RLock()
 	tip := w.tip
@@ -1042,6 +1396,29 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := w.eth.TxPool().Pending(filter)
 
+	// Include the bundles paying more than the regular transactions first, they
+	// need to land atomically and ahead of anything they might conflict with.
+	if bundles := w.eth.TxPool().PendingBundles(env.header.Number.Uint64()); len(bundles) > 0 {
+		minTip := bestMinerTip(pendingPlainTxs, filter.BaseFee)
+		if err := w.commitBundles(env, bundles, minTip, interrupt); err != nil {
+			return err
+		}
+	}
+	// Include the conditional transactions the block satisfies, their state
+	// conditions being checked right before each of them executes.
+	if txs := w.eth.TxPool().PendingConditional(env.header); len(txs) > 0 {
+		if err := w.commitConditionals(env, txs, interrupt); err != nil {
+			return err
+		}
+	}
+
+	policy := newOrderingPolicy(w.ordering)
+	if _, fee := policy.(feeOrdering); !fee {
+		plainTxs := newOrderedTransactions(env.signer, pendingPlainTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
+		blobTxs := newOrderedTransactions(env.signer, pendingBlobTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
+
+		return w.commitTransactions(env, plainTxs, blobTxs, interrupt)
+	}
 	// Split the pending transactions into locals and remotes.
 	localPlainTxs, remotePlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	localBlobTxs, remoteBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
@@ -1058,16 +1435,16 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	}
 	// Fill the block with all available pending transactions.
 	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(env.signer, localPlainTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
+		blobTxs := newOrderedTransactions(env.signer, localBlobTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
 
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remotePlainTxs) > 0 || len(remoteBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee)
+		plainTxs := newOrderedTransactions(env.signer, remotePlainTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
+		blobTxs := newOrderedTransactions(env.signer, remoteBlobTxs, env.header.BaseFee, policy, w.feeExempt(env.header))
 
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
@@ -1132,8 +1509,27 @@ func (w *worker) commitWork(interrupt *atomic.Int32, timestamp int64) {
 	if err != nil {
 		return
 	}
-	// Fill pending transactions from the txpool into the block.
+	// Fill pending transactions from the txpool into the block, sealing what has
+	// been filled once the building budget runs out so the slot isn't missed.
+	if interrupt == nil {
+		interrupt = new(atomic.Int32)
+	}
+	var timer *time.Timer
+	if w.buildBudget > 0 {
+		if remaining := w.buildBudget - time.Since(start); remaining > 0 {
+			timer = time.AfterFunc(remaining, func() {
+				interrupt.CompareAndSwap(commitInterruptNone, commitInterruptTimeout)
+			})
+		} else {
+			interrupt.CompareAndSwap(commitInterruptNone, commitInterruptTimeout)
+		}
+	}
 	err = w.fillTransactions(interrupt, work)
+	if timer != nil {
+		timer.Stop()
+	}
+	w.updateBuildMetrics(start)
+
 	switch {
 	case err == nil:
 		// The entire block is filled, decrease resubmit interval in case
@@ -1160,6 +1556,13 @@ func (w *worker) commitWork(interrupt *atomic.Int32, timestamp int64) {
 		// which could result in higher uncle rate.
 		work.discard()
 		return
+
+	case errors.Is(err, errBlockInterruptedByTimeout):
+		// The building budget ran out, seal the partially filled block rather
+		// than missing the slot.
+		buildOverrunMeter.Mark(1)
+		log.Warn("Block building budget exceeded, sealing partial block", "number", work.header.Number,
+			"txs", work.tcount, "gas", work.header.GasUsed, "budget", common.PrettyDuration(w.buildBudget))
 	}
 	// Submit the generated block for consensus sealing.
 	w.commit(work.copy(), w.fullTaskHook, true, start)
@@ -1172,6 +1575,28 @@ func (w *worker) commitWork(interrupt *atomic.Int32, timestamp int64) {
 	w.current = work
 }
 
+// updateBuildMetrics reports the time spent filling a sealing block, both in
+// absolute terms and relative to the consensus block period.
+func (w *worker) updateBuildMetrics(start time.Time) {
+	elapsed := time.Since(start)
+	buildTimer.Update(elapsed)
+	if period := blockPeriod(w.chainConfig); period > 0 {
+		buildPeriodHist.Update(int64(100 * elapsed / period))
+	}
+}
+
+// blockPeriod returns the block period enforced by the consensus engine of the
+// chain, or zero if blocks aren't produced at fixed intervals.
+func blockPeriod(config *params.ChainConfig) time.Duration {
+	switch {
+	case config.PoI != nil:
+		return time.Duration(config.PoI.Period) * time.Second
+	case config.Clique != nil:
+		return time.Duration(config.Clique.Period) * time.Second
+	}
+	return 0
+}
+
 // commit runs any post-transaction state modifications, assembles the final block
 // and commits new work if consensus engine is running.
 // Note the assumption is held that the mutation is allowed to the passed env, do
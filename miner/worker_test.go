@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"sync/atomic"
 	"testing"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/txpool/privatepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -63,6 +65,9 @@ var (
 	testUserKey, _  = crypto.GenerateKey()
 	testUserAddress = crypto.PubkeyToAddress(testUserKey.PublicKey)
 
+	testRemoteKey, _  = crypto.GenerateKey()
+	testRemoteAddress = crypto.PubkeyToAddress(testRemoteKey.PublicKey)
+
 	// Test transactions
 	pendingTxs []*types.Transaction
 	newTxs     []*types.Transaction
@@ -117,7 +122,7 @@ type testWorkerBackend struct {
 func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine, db ethdb.Database, n int) *testWorkerBackend {
 	var gspec = &core.Genesis{
 		Config: chainConfig,
-		Alloc:  types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+		Alloc:  types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}, testRemoteAddress: {Balance: testBankFunds}},
 	}
 	switch e := engine.(type) {
 	case *clique.Clique:
@@ -255,6 +260,46 @@ func testEmptyWork(t *testing.T, chainConfig *params.ChainConfig, engine consens
 	}
 }
 
+func TestBuildBudgetEthash(t *testing.T) {
+	t.Parallel()
+	testBuildBudget(t, ethashChainConfig, ethash.NewFaker())
+}
+func TestBuildBudgetClique(t *testing.T) {
+	t.Parallel()
+	testBuildBudget(t, cliqueChainConfig, clique.New(cliqueChainConfig.Clique, rawdb.NewMemoryDatabase()))
+}
+
+// testBuildBudget checks that once the building budget runs out, the block is
+// sealed with whatever has been filled instead of being abandoned.
+func testBuildBudget(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine) {
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, chainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	if want := time.Duration(float64(blockPeriod(chainConfig)) * params.BuildBudgetRatio); w.buildBudget != want {
+		t.Fatalf("derived budget mismatch: have %v, want %v", w.buildBudget, want)
+	}
+	w.buildBudget = time.Nanosecond
+
+	taskCh := make(chan struct{}, 2)
+	w.newTaskHook = func(task *task) {
+		if task.block.NumberU64() == 1 {
+			if len(task.receipts) != 0 {
+				t.Errorf("receipt number mismatch: have %d, want 0", len(task.receipts))
+			}
+			taskCh <- struct{}{}
+		}
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+	w.start() // Start mining!
+	select {
+	case <-taskCh:
+	case <-time.NewTimer(3 * time.Second).C:
+		t.Error("partial block not sealed")
+	}
+}
+
 func TestAdjustIntervalEthash(t *testing.T) {
 	t.Parallel()
 	testAdjustInterval(t, ethashChainConfig, ethash.NewFaker())
@@ -508,3 +553,511 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+func TestCommitBundle(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+	env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+
+	var (
+		signer   = types.LatestSigner(ethashChainConfig)
+		gasPrice = big.NewInt(10 * params.InitialBaseFee)
+		transfer = func(nonce uint64) *types.Transaction {
+			return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+				Nonce:    nonce,
+				To:       &testUserAddress,
+				Value:    big.NewInt(1000),
+				Gas:      params.TxGas,
+				GasPrice: gasPrice,
+			})
+		}
+		minTip = uint256.NewInt(params.InitialBaseFee)
+	)
+	// A bundle failing halfway must leave no trace in the block
+	bundle := &txpool.Bundle{Txs: types.Transactions{transfer(0), transfer(2)}}
+	if err := w.commitBundle(env, bundle, minTip); err == nil {
+		t.Fatal("failing bundle committed")
+	}
+	if len(env.txs) != 0 || len(env.receipts) != 0 || env.tcount != 0 || env.header.GasUsed != 0 {
+		t.Fatalf("failing bundle not reverted: txs %d, receipts %d, gas %d", len(env.txs), len(env.receipts), env.header.GasUsed)
+	}
+	if nonce := env.state.GetNonce(testBankAddress); nonce != 0 {
+		t.Fatalf("failing bundle state not reverted: nonce %d", nonce)
+	}
+	// A bundle paying less than the regular transactions must be skipped
+	bundle = &txpool.Bundle{Txs: types.Transactions{transfer(0), transfer(1)}}
+	if err := w.commitBundle(env, bundle, uint256.MustFromBig(gasPrice)); err == nil {
+		t.Fatal("underpriced bundle committed")
+	}
+	if len(env.txs) != 0 || env.gasPool.Gas() != env.header.GasLimit {
+		t.Fatalf("underpriced bundle not reverted: txs %d, gas left %d", len(env.txs), env.gasPool.Gas())
+	}
+	// A profitable bundle must be committed in order
+	if err := w.commitBundle(env, bundle, minTip); err != nil {
+		t.Fatalf("failed to commit bundle: %v", err)
+	}
+	if len(env.txs) != 2 || env.txs[0].Hash() != bundle.Txs[0].Hash() || env.txs[1].Hash() != bundle.Txs[1].Hash() {
+		t.Fatalf("bundle not committed in order: %d txs", len(env.txs))
+	}
+	if env.header.GasUsed != 2*params.TxGas {
+		t.Fatalf("gas used mismatch: have %d, want %d", env.header.GasUsed, 2*params.TxGas)
+	}
+	// The bundle must stay private until the block is sealed
+	w.updateSnapshot(env)
+	if block, state := w.pending(); len(block.Transactions()) != 0 || state.GetNonce(testBankAddress) != 0 {
+		t.Fatalf("bundle leaked into pending block: %d txs, nonce %d", len(block.Transactions()), state.GetNonce(testBankAddress))
+	}
+}
+
+// Tests that private transactions are included in the sealing block but left
+// out of the public pending block and state.
+func TestPrivateTransactionsHidden(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	var (
+		signer     = types.LatestSigner(ethashChainConfig)
+		privKey, _ = crypto.GenerateKey()
+		privAddr   = crypto.PubkeyToAddress(privKey.PublicKey)
+		privPool   = privatepool.New(privatepool.DefaultConfig, b.chain)
+	)
+	defer privPool.Close()
+	env.state.AddBalance(privAddr, uint256.NewInt(params.Ether))
+
+	lazy := func(tx *types.Transaction, pool txpool.SubPool) *txpool.LazyTransaction {
+		return &txpool.LazyTransaction{
+			Pool:      pool,
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+			GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+			Gas:       tx.Gas(),
+		}
+	}
+	private := types.MustSignNewTx(privKey, signer, &types.LegacyTx{
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(10 * params.InitialBaseFee),
+	})
+	pending := map[common.Address][]*txpool.LazyTransaction{
+		testBankAddress: {lazy(pendingTxs[0], nil)},
+		privAddr:        {lazy(private, privPool)},
+	}
+	policy := newOrderingPolicy(TxOrderingFee)
+	plainTxs := newOrderedTransactions(env.signer, pending, env.header.BaseFee, policy, nil)
+	blobTxs := newOrderedTransactions(env.signer, nil, env.header.BaseFee, policy, nil)
+	if err := w.commitTransactions(env, plainTxs, blobTxs, nil); err != nil {
+		t.Fatalf("failed to commit transactions: %v", err)
+	}
+	if len(env.txs) != 2 {
+		t.Fatalf("sealing block transaction count mismatch: have %d, want 2", len(env.txs))
+	}
+	w.updateSnapshot(env)
+
+	block, state := w.pending()
+	if block == nil {
+		t.Fatal("pending block not available")
+	}
+	if have := len(block.Transactions()); have != 1 || block.Transactions()[0].Hash() != pendingTxs[0].Hash() {
+		t.Fatalf("pending block transactions mismatch: have %d", have)
+	}
+	if block.GasUsed() != params.TxGas {
+		t.Errorf("pending block gas used mismatch: have %d, want %d", block.GasUsed(), params.TxGas)
+	}
+	if nonce := state.GetNonce(privAddr); nonce != 0 {
+		t.Errorf("private transaction leaked into pending state: nonce %d", nonce)
+	}
+	if nonce := state.GetNonce(testBankAddress); nonce != 1 {
+		t.Errorf("public transaction missing from pending state: nonce %d", nonce)
+	}
+}
+
+// Tests that the configured ordering policy decides which transactions make it
+// into a block with room for only some of them.
+func TestTransactionOrderingPolicies(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	var (
+		signer      = types.LatestSigner(ethashChainConfig)
+		priority, _ = crypto.GenerateKey() // Whitelisted, cheap and last to arrive
+		rich, _     = crypto.GenerateKey() // Best paying, second to arrive
+		early, _    = crypto.GenerateKey() // Average paying, first to arrive
+	)
+	core.GetSecurityConfig().AddToWhitelist(crypto.PubkeyToAddress(priority.PublicKey))
+	defer core.GetSecurityConfig().RemoveFromWhitelist(crypto.PubkeyToAddress(priority.PublicKey))
+
+	transfer := func(key *ecdsa.PrivateKey, price int64, arrival int64) *txpool.LazyTransaction {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(price * params.InitialBaseFee),
+		})
+		tx.SetTime(time.Unix(arrival, 0))
+		return &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+			GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+			Gas:       tx.Gas(),
+		}
+	}
+	tests := []struct {
+		ordering TxOrdering
+		want     []*ecdsa.PrivateKey
+	}{
+		{TxOrderingFee, []*ecdsa.PrivateKey{rich, early}},
+		{TxOrderingFIFO, []*ecdsa.PrivateKey{early, rich}},
+		{TxOrderingLanes, []*ecdsa.PrivateKey{priority, rich}},
+	}
+	for _, tt := range tests {
+		env, err := w.prepareWork(&generateParams{
+			timestamp: uint64(time.Now().Unix()),
+			coinbase:  common.HexToAddress("0xdeadbeef"),
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to prepare work: %v", tt.ordering, err)
+		}
+		// Leave room for two out of the three transactions
+		env.gasPool = new(core.GasPool).AddGas(2 * params.TxGas)
+
+		pending := make(map[common.Address][]*txpool.LazyTransaction)
+		for i, key := range []*ecdsa.PrivateKey{priority, rich, early} {
+			addr := crypto.PubkeyToAddress(key.PublicKey)
+			env.state.AddBalance(addr, uint256.NewInt(params.Ether))
+			pending[addr] = []*txpool.LazyTransaction{transfer(key, []int64{2, 5, 3}[i], []int64{3, 2, 1}[i])}
+		}
+		w.ordering = tt.ordering
+		policy := newOrderingPolicy(w.ordering)
+		plainTxs := newOrderedTransactions(env.signer, pending, env.header.BaseFee, policy, nil)
+		blobTxs := newOrderedTransactions(env.signer, nil, env.header.BaseFee, policy, nil)
+		if err := w.commitTransactions(env, plainTxs, blobTxs, nil); err != nil {
+			t.Fatalf("%s: failed to commit transactions: %v", tt.ordering, err)
+		}
+		if len(env.txs) != len(tt.want) {
+			t.Fatalf("%s: included transactions mismatch: have %d, want %d", tt.ordering, len(env.txs), len(tt.want))
+		}
+		for i, tx := range env.txs {
+			from, _ := types.Sender(signer, tx)
+			if want := crypto.PubkeyToAddress(tt.want[i].PublicKey); from != want {
+				t.Errorf("%s: transaction %d sender mismatch: have %x, want %x", tt.ordering, i, from, want)
+			}
+		}
+		env.discard()
+	}
+}
+
+// Tests that local transactions are only prioritised by the fee ordering policy,
+// the other policies ordering them along with the remote ones.
+func TestLocalTransactionOrdering(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	// The local bank transaction is pending, add a better paying remote one
+	// from a whitelisted sender
+	core.GetSecurityConfig().AddToWhitelist(testRemoteAddress)
+	defer core.GetSecurityConfig().RemoveFromWhitelist(testRemoteAddress)
+
+	remote := types.MustSignNewTx(testRemoteKey, types.LatestSigner(ethashChainConfig), &types.LegacyTx{
+		To:       &testUserAddress,
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(10 * params.InitialBaseFee),
+	})
+	if errs := b.txPool.Add([]*types.Transaction{remote}, false, true); errs[0] != nil {
+		t.Fatalf("failed to add remote transaction: %v", errs[0])
+	}
+	tests := []struct {
+		ordering TxOrdering
+		want     common.Hash
+	}{
+		{TxOrderingFee, pendingTxs[0].Hash()},
+		{TxOrderingLanes, remote.Hash()},
+	}
+	for _, tt := range tests {
+		env, err := w.prepareWork(&generateParams{
+			timestamp: uint64(time.Now().Unix()),
+			coinbase:  common.HexToAddress("0xdeadbeef"),
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to prepare work: %v", tt.ordering, err)
+		}
+		// Leave room for a single transaction
+		env.gasPool = new(core.GasPool).AddGas(params.TxGas)
+
+		w.ordering = tt.ordering
+		if err := w.fillTransactions(nil, env); err != nil {
+			t.Fatalf("%s: failed to fill transactions: %v", tt.ordering, err)
+		}
+		if len(env.txs) != 1 || env.txs[0].Hash() != tt.want {
+			t.Errorf("%s: included transaction mismatch: have %d txs, want %x", tt.ordering, len(env.txs), tt.want)
+		}
+		env.discard()
+	}
+}
+
+// Tests that fee exempt system accounts are included below the base fee, but
+// only up to their per block gas budget.
+func TestFeeExemptGasBudget(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	var (
+		exemptKey, _ = crypto.GenerateKey()
+		payingKey, _ = crypto.GenerateKey()
+		exempt       = crypto.PubkeyToAddress(exemptKey.PublicKey)
+		paying       = crypto.PubkeyToAddress(payingKey.PublicKey)
+	)
+	config := *ethashChainConfig
+	config.FeeExemptBlock = common.Big0
+	config.FeeExempt = &params.FeeExemptConfig{Accounts: []common.Address{exempt}, GasLimit: 2 * params.TxGas}
+
+	w, _ := newTestWorker(t, &config, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	signer := types.LatestSigner(&config)
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64, price *big.Int) *txpool.LazyTransaction {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: price,
+		})
+		return &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+			GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+			Gas:       tx.Gas(),
+		}
+	}
+	env.state.AddBalance(paying, uint256.NewInt(params.Ether))
+
+	pending := map[common.Address][]*txpool.LazyTransaction{
+		exempt: {transfer(exemptKey, 0, common.Big0), transfer(exemptKey, 1, common.Big0), transfer(exemptKey, 2, common.Big0)},
+		paying: {transfer(payingKey, 0, big.NewInt(2*params.InitialBaseFee))},
+	}
+	var (
+		exemptTxs  = []*types.Transaction{pending[exempt][0].Tx, pending[exempt][1].Tx}
+		payingTx   = pending[paying][0].Tx
+		overBudget = pending[exempt][2].Tx
+	)
+
+	policy := newOrderingPolicy(TxOrderingFee)
+	plainTxs := newOrderedTransactions(env.signer, pending, env.header.BaseFee, policy, w.feeExempt(env.header))
+	blobTxs := newOrderedTransactions(env.signer, nil, env.header.BaseFee, policy, w.feeExempt(env.header))
+	if err := w.commitTransactions(env, plainTxs, blobTxs, nil); err != nil {
+		t.Fatalf("failed to commit transactions: %v", err)
+	}
+	included := make(map[common.Address]int)
+	for _, tx := range env.txs {
+		from, _ := types.Sender(signer, tx)
+		included[from]++
+	}
+	if included[exempt] != 2 {
+		t.Errorf("fee exempt transactions mismatch: have %d, want %d", included[exempt], 2)
+	}
+	if included[paying] != 1 {
+		t.Errorf("paying transactions mismatch: have %d, want %d", included[paying], 1)
+	}
+	if env.exemptGas != 2*params.TxGas {
+		t.Errorf("fee exempt gas mismatch: have %d, want %d", env.exemptGas, 2*params.TxGas)
+	}
+	// Conditional transactions are held to the same budget
+	conditional := &txpool.ConditionalTx{Tx: overBudget, Conditions: new(txpool.TxConditions)}
+	if err := w.commitConditionals(env, []*txpool.ConditionalTx{conditional}, nil); err != nil {
+		t.Fatalf("failed to commit conditional transactions: %v", err)
+	}
+	if len(env.txs) != 3 {
+		t.Errorf("conditional fee exempt transaction exceeded the budget: have %d txs, want %d", len(env.txs), 3)
+	}
+	// Bundles are held to the same budget, as a whole
+	bundle := &txpool.Bundle{Txs: types.Transactions{overBudget}}
+	if err := w.commitBundle(env, bundle, nil); err == nil {
+		t.Error("bundle exceeding the fee exempt budget committed")
+	}
+	if len(env.txs) != 3 || env.exemptGas != 2*params.TxGas {
+		t.Errorf("rejected bundle not reverted: have %d txs, exempt gas %d", len(env.txs), env.exemptGas)
+	}
+	fresh, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer fresh.discard()
+	fresh.gasPool = new(core.GasPool).AddGas(fresh.header.GasLimit)
+	fresh.state.AddBalance(paying, uint256.NewInt(params.Ether))
+
+	bundle = &txpool.Bundle{Txs: exemptTxs}
+	if err := w.commitBundle(fresh, bundle, nil); err != nil {
+		t.Fatalf("failed to commit bundle within the fee exempt budget: %v", err)
+	}
+	if fresh.exemptGas != 2*params.TxGas {
+		t.Errorf("bundle fee exempt gas mismatch: have %d, want %d", fresh.exemptGas, 2*params.TxGas)
+	}
+	bundle = &txpool.Bundle{Txs: types.Transactions{payingTx, overBudget}}
+	if err := w.commitBundle(fresh, bundle, nil); err == nil {
+		t.Error("bundle exceeding the fee exempt budget committed")
+	}
+	if len(fresh.txs) != 2 {
+		t.Errorf("partially committed bundle: have %d txs, want %d", len(fresh.txs), 2)
+	}
+}
+
+func TestCommitConditionals(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	var (
+		otherKey, _ = crypto.GenerateKey()
+		other       = crypto.PubkeyToAddress(otherKey.PublicKey)
+		signer      = types.LatestSigner(ethashChainConfig)
+		slot        = common.Hash{0x01}
+		next        = env.header.Number.Uint64() + 1
+	)
+	env.state.AddBalance(other, uint256.NewInt(params.Ether))
+	env.state.SetState(testUserAddress, slot, common.Hash{0xaa})
+
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64, conditions *txpool.TxConditions) *txpool.ConditionalTx {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(2 * params.InitialBaseFee),
+		})
+		return &txpool.ConditionalTx{Tx: tx, Conditions: conditions}
+	}
+	txs := []*txpool.ConditionalTx{
+		// Storage condition met, included
+		transfer(testBankKey, 0, &txpool.TxConditions{KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			testUserAddress: {slot: {0xaa}},
+		}}),
+		// Block condition not met, skipped along with the rest of the account
+		transfer(testBankKey, 1, &txpool.TxConditions{BlockNumberMin: &next}),
+		transfer(testBankKey, 2, &txpool.TxConditions{}),
+		// Storage condition not met, skipped
+		transfer(otherKey, 0, &txpool.TxConditions{KnownAccounts: map[common.Address]map[common.Hash]common.Hash{
+			testUserAddress: {slot: {0xbb}},
+		}}),
+	}
+	if err := w.commitConditionals(env, txs, nil); err != nil {
+		t.Fatalf("failed to commit conditional transactions: %v", err)
+	}
+	if len(env.txs) != 1 || env.txs[0].Hash() != txs[0].Tx.Hash() {
+		t.Fatalf("included transactions mismatch: have %d, want the first only", len(env.txs))
+	}
+	// Conditional transactions must stay private until the block is sealed
+	w.updateSnapshot(env)
+	if block, _ := w.pending(); len(block.Transactions()) != 0 {
+		t.Fatalf("conditional transaction leaked into pending block: %d txs", len(block.Transactions()))
+	}
+}
+
+// Tests that conditional transactions are included by effective miner tip,
+// while the transactions of a single account stay in nonce order.
+func TestCommitConditionalsOrdering(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, _ := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{
+		timestamp: uint64(time.Now().Unix()),
+		coinbase:  common.HexToAddress("0xdeadbeef"),
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	var (
+		otherKey, _ = crypto.GenerateKey()
+		other       = crypto.PubkeyToAddress(otherKey.PublicKey)
+		signer      = types.LatestSigner(ethashChainConfig)
+	)
+	env.state.AddBalance(other, uint256.NewInt(params.Ether))
+
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64, price int64) *txpool.ConditionalTx {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(price),
+		})
+		return &txpool.ConditionalTx{Tx: tx, Conditions: new(txpool.TxConditions)}
+	}
+	txs := []*txpool.ConditionalTx{
+		transfer(testBankKey, 0, 2*params.InitialBaseFee),
+		transfer(testBankKey, 1, 4*params.InitialBaseFee),
+		transfer(otherKey, 0, 3*params.InitialBaseFee),
+	}
+	if err := w.commitConditionals(env, txs, nil); err != nil {
+		t.Fatalf("failed to commit conditional transactions: %v", err)
+	}
+	want := []*types.Transaction{txs[2].Tx, txs[0].Tx, txs[1].Tx}
+	if len(env.txs) != len(want) {
+		t.Fatalf("included transactions mismatch: have %d, want %d", len(env.txs), len(want))
+	}
+	for i, tx := range want {
+		if env.txs[i].Hash() != tx.Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, env.txs[i].Hash(), tx.Hash())
+		}
+	}
+}
@@ -207,35 +207,59 @@ type Config struct {
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
+	// RPCRoles defines named roles for access control on the authenticated RPC
+	// endpoints and the role IPC sockets. Every role maps to an allowlist whose
+	// entries are either a method ("miner_start"), a namespace ("miner" or
+	// "miner_*") or "*" for everything. On the authenticated endpoints the role
+	// is selected by the "role" claim of the JWT token; tokens without a role
+	// claim are limited to the default authenticated modules.
+	RPCRoles map[string][]string `toml:",omitempty"`
+
+	// IPCRoles lists additional IPC sockets which each serve a single role from
+	// RPCRoles. Access to the sockets is governed by their file permissions.
+	IPCRoles []IPCRoleConfig `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
 }
 
+// IPCRoleConfig is a dedicated IPC socket serving a single RPC role.
+type IPCRoleConfig struct {
+	Role string // name of the role in RPCRoles
+	Path string // socket path, plain file names are placed in the data directory
+	Mode string `toml:",omitempty"` // octal file mode of the socket, e.g. "0660" (default "0600")
+}
+
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
 // account the set data folders as well as the designated platform we're currently
 // running on.
 func (c *Config) IPCEndpoint() string {
+	return c.resolveIPCPath(c.IPCPath)
+}
+
+// resolveIPCPath resolves the given IPC path into an endpoint.
+func (c *Config) resolveIPCPath(path string) string {
 	// Short circuit if IPC has not been enabled
-	if c.IPCPath == "" {
+	if path == "" {
 		return ""
 	}
 	// On windows we can only use plain top-level pipes
 	if runtime.GOOS == "windows" {
-		if strings.HasPrefix(c.IPCPath, `\\.\pipe\`) {
-			return c.IPCPath
+		if strings.HasPrefix(path, `\\.\pipe\`) {
+			return path
 		}
-		return `\\.\pipe\` + c.IPCPath
+		return `\\.\pipe\` + path
 	}
 	// Resolve names into the data directory full paths otherwise
-	if filepath.Base(c.IPCPath) == c.IPCPath {
+	if filepath.Base(path) == path {
 		if c.DataDir == "" {
-			return filepath.Join(os.TempDir(), c.IPCPath)
+			return filepath.Join(os.TempDir(), path)
 		}
-		return filepath.Join(c.DataDir, c.IPCPath)
+		return filepath.Join(c.DataDir, path)
 	}
-	return c.IPCPath
+	return path
 }
 
 // NodeDB returns the path to the discovery node database.
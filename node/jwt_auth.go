@@ -31,10 +31,21 @@ import (
 // See https://github.com/ethereum/execution-apis/blob/main/src/engine/authentication.md
 // for more details about this authentication scheme.
 func NewJWTAuth(jwtsecret [32]byte) rpc.HTTPAuth {
+	return NewJWTRoleAuth(jwtsecret, "")
+}
+
+// NewJWTRoleAuth creates an rpc client authentication provider that uses JWT and
+// acts as the given RPC role. The node only serves the methods allowed for the
+// role, as configured in its RPCRoles. An empty role behaves like NewJWTAuth.
+func NewJWTRoleAuth(jwtsecret [32]byte, role string) rpc.HTTPAuth {
 	return func(h http.Header) error {
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		claims := jwt.MapClaims{
 			"iat": &jwt.NumericDate{Time: time.Now()},
-		})
+		}
+		if role != "" {
+			claims["role"] = role
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 		s, err := token.SignedString(jwtsecret[:])
 		if err != nil {
 			return fmt.Errorf("failed to create JWT token: %w", err)
@@ -17,6 +17,8 @@
 package node
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -26,6 +28,16 @@ import (
 
 const jwtExpiryTimeout = 60 * time.Second
 
+// jwtClaims are the claims accepted in authentication tokens. Besides the
+// registered claims, a token may carry the name of the RPC role it acts as.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role,omitempty"`
+}
+
+// rpcRoleKey is the request context key of the role selected by the token.
+type rpcRoleKey struct{}
+
 type jwtHandler struct {
 	keyFunc func(token *jwt.Token) (interface{}, error)
 	next    http.Handler
@@ -43,38 +55,49 @@ func newJWTHandler(secret []byte, next http.Handler) http.Handler {
 
 // ServeHTTP implements http.Handler
 func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	claims, err := verifyJWT(r, handler.keyFunc)
+	switch {
+	case err != nil:
+		http.Error(out, err.Error(), http.StatusUnauthorized)
+	case claims.Role != "":
+		handler.next.ServeHTTP(out, r.WithContext(context.WithValue(r.Context(), rpcRoleKey{}, claims.Role)))
+	default:
+		handler.next.ServeHTTP(out, r)
+	}
+}
+
+// verifyJWT checks the bearer token of a request, returning its claims.
+func verifyJWT(r *http.Request, keyFunc jwt.Keyfunc) (*jwtClaims, error) {
 	var (
 		strToken string
-		claims   jwt.RegisteredClaims
+		claims   jwtClaims
 	)
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
 		strToken = strings.TrimPrefix(auth, "Bearer ")
 	}
 	if len(strToken) == 0 {
-		http.Error(out, "missing token", http.StatusUnauthorized)
-		return
+		return nil, errors.New("missing token")
 	}
 	// We explicitly set only HS256 allowed, and also disables the
 	// claim-check: the RegisteredClaims internally requires 'iat' to
 	// be no later than 'now', but we allow for a bit of drift.
-	token, err := jwt.ParseWithClaims(strToken, &claims, handler.keyFunc,
+	token, err := jwt.ParseWithClaims(strToken, &claims, keyFunc,
 		jwt.WithValidMethods([]string{"HS256"}),
 		jwt.WithoutClaimsValidation())
 
 	switch {
 	case err != nil:
-		http.Error(out, err.Error(), http.StatusUnauthorized)
+		return nil, err
 	case !token.Valid:
-		http.Error(out, "invalid token", http.StatusUnauthorized)
+		return nil, errors.New("invalid token")
 	case !claims.VerifyExpiresAt(time.Now(), false): // optional
-		http.Error(out, "token is expired", http.StatusUnauthorized)
+		return nil, errors.New("token is expired")
 	case claims.IssuedAt == nil:
-		http.Error(out, "missing issued-at", http.StatusUnauthorized)
+		return nil, errors.New("missing issued-at")
 	case time.Since(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "stale token", http.StatusUnauthorized)
+		return nil, errors.New("stale token")
 	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "future token", http.StatusUnauthorized)
-	default:
-		handler.next.ServeHTTP(out, r)
+		return nil, errors.New("future token")
 	}
+	return &claims, nil
 }
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -38,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gofrs/flock"
+	"github.com/golang-jwt/jwt/v4"
 )
 
 // Node is a container on which services can be registered.
@@ -55,14 +57,17 @@ type Node struct {
 	state         int           // Tracks state of node lifecycle
 
 	lock          sync.Mutex
-	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	http          *httpServer //
-	ws            *httpServer //
-	httpAuth      *httpServer //
-	wsAuth        *httpServer //
-	ipc           *ipcServer  // Stores information about the ipc http server
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	lifecycles    []Lifecycle         // All registered backends, services, and auxiliary services that have a lifecycle
+	rpcAPIs       []rpc.API           // List of APIs currently provided by the node
+	http          *httpServer         //
+	ws            *httpServer         //
+	httpAuth      *httpServer         //
+	wsAuth        *httpServer         //
+	ipc           *ipcServer          // Stores information about the ipc http server
+	ipcRoles      []*ipcServer        // Role-restricted IPC servers
+	rpcRoles      map[string]*rpcRole // Roles available on the authenticated endpoints
+	jwtSecret     []byte              // Secret of the authenticated endpoints, nil if disabled
+	inprocHandler *rpc.Server         // In-process RPC request handler to process the API requests
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -154,6 +159,31 @@ func New(conf *Config) (*Node, error) {
 	node.wsAuth = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
 	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
 
+	// Configure role-based access control.
+	roles, err := newRPCRoles(conf.RPCRoles)
+	if err != nil {
+		return nil, err
+	}
+	node.rpcRoles = roles
+	for _, ipcRole := range conf.IPCRoles {
+		role, ok := roles[ipcRole.Role]
+		if !ok {
+			return nil, fmt.Errorf("IPC endpoint %q: unknown role %q", ipcRole.Path, ipcRole.Role)
+		}
+		endpoint := conf.resolveIPCPath(ipcRole.Path)
+		if endpoint == "" {
+			return nil, fmt.Errorf("IPC endpoint for role %q has no path", ipcRole.Role)
+		}
+		mode := os.FileMode(0600)
+		if ipcRole.Mode != "" {
+			m, err := strconv.ParseUint(ipcRole.Mode, 8, 32)
+			if err != nil || m > 0777 {
+				return nil, fmt.Errorf("IPC endpoint %q: invalid file mode %q", ipcRole.Path, ipcRole.Mode)
+			}
+			mode = os.FileMode(m)
+		}
+		node.ipcRoles = append(node.ipcRoles, newRoleIPCServer(node.log, endpoint, role, mode))
+	}
 	return node, nil
 }
 
@@ -399,6 +429,11 @@ func (n *Node) startRPC() error {
 			return err
 		}
 	}
+	for _, ipc := range n.ipcRoles {
+		if err := ipc.start(apis); err != nil {
+			return err
+		}
+	}
 	var (
 		servers           []*httpServer
 		openAPIs, allAPIs = n.getAPIs()
@@ -451,6 +486,7 @@ func (n *Node) startRPC() error {
 		}
 		sharedConfig := rpcEndpointConfig{
 			jwtSecret:              secret,
+			roles:                  n.rpcRoles,
 			batchItemLimit:         engineAPIBatchItemLimit,
 			batchResponseSizeLimit: engineAPIBatchResponseSizeLimit,
 			httpBodyLimit:          engineAPIBodyLimit,
@@ -507,6 +543,7 @@ func (n *Node) startRPC() error {
 		if err := initAuth(n.config.AuthPort, jwtSecret); err != nil {
 			return err
 		}
+		n.jwtSecret = jwtSecret
 	}
 	// Start the servers
 	for _, server := range servers {
@@ -534,6 +571,9 @@ func (n *Node) stopRPC() {
 	n.httpAuth.stop()
 	n.wsAuth.stop()
 	n.ipc.stop()
+	for _, ipc := range n.ipcRoles {
+		ipc.stop()
+	}
 	n.stopInProc()
 }
 
@@ -604,6 +644,44 @@ func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
 	return unauthenticated, n.rpcAPIs
 }
 
+// RegisteredAPIs returns the RPC APIs registered on the node so far.
+func (n *Node) RegisteredAPIs() []rpc.API {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	apis := make([]rpc.API, len(n.rpcAPIs))
+	copy(apis, n.rpcAPIs)
+	return apis
+}
+
+// AuthorizeRequest checks that an HTTP request carries a valid JWT token of the
+// authenticated RPC endpoints, and that the role selected by the token, if any,
+// permits the given RPC method. It lets handlers mounted on the HTTP server guard
+// the data otherwise only served by the authenticated endpoints.
+func (n *Node) AuthorizeRequest(r *http.Request, method string) error {
+	n.lock.Lock()
+	secret, roles := n.jwtSecret, n.rpcRoles
+	n.lock.Unlock()
+
+	if secret == nil {
+		return errors.New("authenticated endpoints are not enabled")
+	}
+	claims, err := verifyJWT(r, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	})
+	if err != nil {
+		return err
+	}
+	if claims.Role == "" {
+		return nil
+	}
+	role, ok := roles[claims.Role]
+	if !ok {
+		return fmt.Errorf("unknown role %q", claims.Role)
+	}
+	return role.check(method)
+}
+
 // RegisterHandler mounts a handler on the given path on the canonical HTTP server.
 //
 // The name of the handler is shown in a log message when the HTTP server starts
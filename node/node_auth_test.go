@@ -235,3 +235,74 @@ func offsetTimeAuth(secret [32]byte, offset time.Duration) rpc.HTTPAuth {
 		return nil
 	}
 }
+
+// Tests that handlers mounted on the HTTP server can authorize requests with
+// the tokens and roles of the authenticated endpoints.
+func TestAuthorizeRequest(t *testing.T) {
+	var secret [32]byte
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatalf("failed to create jwt secret: %v", err)
+	}
+	jwtPath := path.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatalf("failed to prepare jwt secret file: %v", err)
+	}
+	node, err := New(&Config{
+		AuthAddr:  "127.0.0.1",
+		AuthPort:  0,
+		JWTSecret: jwtPath,
+		RPCRoles:  map[string][]string{"auditor": {"security_getWhitelist"}},
+	})
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs([]rpc.API{{
+		Namespace:     "security",
+		Service:       helloRPC("hello security"),
+		Authenticated: true,
+	}})
+	request := func(auth rpc.HTTPAuth) *http.Request {
+		r, _ := http.NewRequest(http.MethodPost, "http://localhost/graphql", nil)
+		if auth != nil {
+			if err := auth(r.Header); err != nil {
+				t.Fatalf("failed to set auth header: %v", err)
+			}
+		}
+		return r
+	}
+	if err := node.AuthorizeRequest(request(NewJWTAuth(secret)), "security_getWhitelist"); err == nil {
+		t.Fatal("expected authorization to fail before the node is started")
+	}
+	if err := node.Start(); err != nil {
+		t.Fatalf("failed to start test node: %v", err)
+	}
+	defer node.Close()
+
+	var otherSecret [32]byte
+	if _, err := crand.Read(otherSecret[:]); err != nil {
+		t.Fatalf("failed to create jwt secret: %v", err)
+	}
+	tests := []struct {
+		name   string
+		auth   rpc.HTTPAuth
+		method string
+		ok     bool
+	}{
+		{"no token", nil, "security_getWhitelist", false},
+		{"bad secret", NewJWTAuth(otherSecret), "security_getWhitelist", false},
+		{"stale", offsetTimeAuth(secret, -time.Minute*2), "security_getWhitelist", false},
+		{"no role", NewJWTAuth(secret), "security_getBlacklist", true},
+		{"role allowed", NewJWTRoleAuth(secret, "auditor"), "security_getWhitelist", true},
+		{"role denied", NewJWTRoleAuth(secret, "auditor"), "security_getBlacklist", false},
+		{"unknown role", NewJWTRoleAuth(secret, "nobody"), "security_getWhitelist", false},
+	}
+	for _, test := range tests {
+		err := node.AuthorizeRequest(request(test.auth), test.method)
+		if test.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%s: expected authorization to fail", test.name)
+		}
+	}
+}
@@ -22,7 +22,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -636,3 +639,68 @@ func containsAPI(stackAPIs []rpc.API, api rpc.API) bool {
 	}
 	return false
 }
+
+// Tests that role IPC endpoints only serve the methods allowed for their role.
+func TestNodeIPCRoles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("role sockets rely on unix file permissions")
+	}
+	conf := testNodeConfig()
+	conf.DataDir = t.TempDir()
+	conf.RPCRoles = map[string][]string{
+		"greeter": {"test_greet"},
+		"reader":  {"eth"},
+	}
+	conf.IPCRoles = []IPCRoleConfig{
+		{Role: "greeter", Path: "greeter.ipc", Mode: "0640"},
+		{Role: "reader", Path: "reader.ipc"},
+	}
+	stack, err := New(conf)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	defer stack.Close()
+	stack.RegisterAPIs(apis())
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	for path, mode := range map[string]os.FileMode{"greeter.ipc": 0640, "reader.ipc": 0600} {
+		info, err := os.Stat(filepath.Join(conf.DataDir, path))
+		if err != nil {
+			t.Fatalf("missing role socket: %v", err)
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("socket %s: have mode %v, want %v", path, info.Mode().Perm(), mode)
+		}
+	}
+	greet := func(path string) error {
+		client, err := rpc.Dial(filepath.Join(conf.DataDir, path))
+		if err != nil {
+			t.Fatalf("failed to dial %s: %v", path, err)
+		}
+		defer client.Close()
+		var result string
+		return client.Call(&result, "test_greet")
+	}
+	if err := greet("greeter.ipc"); err != nil {
+		t.Errorf("permitted call failed: %v", err)
+	}
+	err = greet("reader.ipc")
+	if rpcErr, ok := err.(rpc.Error); !ok || rpcErr.ErrorCode() != -32004 {
+		t.Errorf("expected access denied error, got %v", err)
+	}
+}
+
+func TestNodeIPCRolesConfig(t *testing.T) {
+	conf := testNodeConfig()
+	conf.DataDir = t.TempDir()
+	conf.IPCRoles = []IPCRoleConfig{{Role: "missing", Path: "missing.ipc"}}
+	if _, err := New(conf); err == nil {
+		t.Error("expected error for unknown role")
+	}
+	conf.RPCRoles = map[string][]string{"missing": {"eth"}}
+	conf.IPCRoles[0].Mode = "0999"
+	if _, err := New(conf); err == nil {
+		t.Error("expected error for invalid socket mode")
+	}
+}
@@ -19,10 +19,13 @@ package node
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -53,7 +56,8 @@ type wsConfig struct {
 }
 
 type rpcEndpointConfig struct {
-	jwtSecret              []byte // optional JWT secret
+	jwtSecret              []byte              // optional JWT secret
+	roles                  map[string]*rpcRole // optional roles, selected by JWT claim
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
@@ -61,7 +65,16 @@ type rpcEndpointConfig struct {
 
 type rpcHandler struct {
 	http.Handler
-	server *rpc.Server
+	server      *rpc.Server
+	roleServers map[string]*rpc.Server
+}
+
+// stop stops the RPC server and all role servers of the handler.
+func (h *rpcHandler) stop() {
+	h.server.Stop()
+	for _, srv := range h.roleServers {
+		srv.Stop()
+	}
 }
 
 type httpServer struct {
@@ -303,18 +316,27 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	}
 
 	// Create RPC server and handler.
-	srv := rpc.NewServer()
-	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
-	if config.httpBodyLimit > 0 {
-		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	srv, err := newRPCServer(apis, config.Modules, config.rpcEndpointConfig)
+	if err != nil {
+		return err
 	}
-	if err := RegisterApis(apis, config.Modules, srv); err != nil {
+	roleServers, err := newRoleServers(apis, config.rpcEndpointConfig)
+	if err != nil {
 		return err
 	}
+	var handler http.Handler = srv
+	if len(roleServers) > 0 {
+		roleHandlers := make(map[string]http.Handler, len(roleServers))
+		for name, roleSrv := range roleServers {
+			roleHandlers[name] = roleSrv
+		}
+		handler = newRoleHandler(srv, roleHandlers)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
-		server:  srv,
+		Handler:     NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		server:      srv,
+		roleServers: roleServers,
 	})
 	return nil
 }
@@ -324,7 +346,7 @@ func (h *httpServer) disableRPC() bool {
 	handler := h.httpHandler.Load().(*rpcHandler)
 	if handler != nil {
 		h.httpHandler.Store((*rpcHandler)(nil))
-		handler.server.Stop()
+		handler.stop()
 	}
 	return handler != nil
 }
@@ -338,18 +360,27 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 		return fmt.Errorf("JSON-RPC over WebSocket is already enabled")
 	}
 	// Create RPC server and handler.
-	srv := rpc.NewServer()
-	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
-	if config.httpBodyLimit > 0 {
-		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	srv, err := newRPCServer(apis, config.Modules, config.rpcEndpointConfig)
+	if err != nil {
+		return err
 	}
-	if err := RegisterApis(apis, config.Modules, srv); err != nil {
+	roleServers, err := newRoleServers(apis, config.rpcEndpointConfig)
+	if err != nil {
 		return err
 	}
+	handler := srv.WebsocketHandler(config.Origins)
+	if len(roleServers) > 0 {
+		roleHandlers := make(map[string]http.Handler, len(roleServers))
+		for name, roleSrv := range roleServers {
+			roleHandlers[name] = roleSrv.WebsocketHandler(config.Origins)
+		}
+		handler = newRoleHandler(handler, roleHandlers)
+	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
-		server:  srv,
+		Handler:     NewWSHandlerStack(handler, config.jwtSecret),
+		server:      srv,
+		roleServers: roleServers,
 	})
 	return nil
 }
@@ -371,7 +402,7 @@ func (h *httpServer) disableWS() bool {
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil {
 		h.wsHandler.Store((*rpcHandler)(nil))
-		ws.server.Stop()
+		ws.stop()
 	}
 	return ws != nil
 }
@@ -586,6 +617,8 @@ func newGzipHandler(next http.Handler) http.Handler {
 type ipcServer struct {
 	log      log.Logger
 	endpoint string
+	role     *rpcRole    // optional role restricting the served methods
+	mode     os.FileMode // socket file mode of role endpoints
 
 	mu       sync.Mutex
 	listener net.Listener
@@ -596,6 +629,12 @@ func newIPCServer(log log.Logger, endpoint string) *ipcServer {
 	return &ipcServer{log: log, endpoint: endpoint}
 }
 
+// newRoleIPCServer creates an IPC server that only serves the methods allowed
+// for the given role. The socket file is created with the given mode.
+func newRoleIPCServer(log log.Logger, endpoint string, role *rpcRole, mode os.FileMode) *ipcServer {
+	return &ipcServer{log: log.New("role", role.name), endpoint: endpoint, role: role, mode: mode}
+}
+
 // Start starts the httpServer's http.Server
 func (is *ipcServer) start(apis []rpc.API) error {
 	is.mu.Lock()
@@ -604,7 +643,16 @@ func (is *ipcServer) start(apis []rpc.API) error {
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	var (
+		listener net.Listener
+		srv      *rpc.Server
+		err      error
+	)
+	if is.role == nil {
+		listener, srv, err = rpc.StartIPCEndpoint(is.endpoint, apis)
+	} else {
+		listener, srv, err = is.startRole(apis)
+	}
 	if err != nil {
 		is.log.Warn("IPC opening failed", "url", is.endpoint, "error", err)
 		return err
@@ -614,6 +662,29 @@ func (is *ipcServer) start(apis []rpc.API) error {
 	return nil
 }
 
+// startRole opens the IPC endpoint of a role server. The access filter is
+// installed before the listener accepts any connection.
+func (is *ipcServer) startRole(apis []rpc.API) (net.Listener, *rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := RegisterApis(apis, nil, srv); err != nil {
+		return nil, nil, err
+	}
+	srv.SetAccessFilter(is.role.check)
+
+	listener, err := rpc.ListenIPC(is.endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	if runtime.GOOS != "windows" && is.mode != 0 {
+		if err := os.Chmod(is.endpoint, is.mode); err != nil {
+			listener.Close()
+			return nil, nil, err
+		}
+	}
+	go srv.ServeListener(listener)
+	return listener, srv, nil
+}
+
 func (is *ipcServer) stop() error {
 	is.mu.Lock()
 	defer is.mu.Unlock()
@@ -628,6 +699,138 @@ func (is *ipcServer) stop() error {
 	return err
 }
 
+// newRPCServer creates an RPC server with the given endpoint limits, serving the
+// allowed modules of the given APIs.
+func newRPCServer(apis []rpc.API, modules []string, config rpcEndpointConfig) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	if config.httpBodyLimit > 0 {
+		srv.SetHTTPBodyLimit(config.httpBodyLimit)
+	}
+	if err := RegisterApis(apis, modules, srv); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// newRoleServers creates an RPC server for every configured role. Role servers
+// register all the given APIs and rely on the role's allowlist for access control.
+func newRoleServers(apis []rpc.API, config rpcEndpointConfig) (map[string]*rpc.Server, error) {
+	if len(config.roles) == 0 {
+		return nil, nil
+	}
+	servers := make(map[string]*rpc.Server, len(config.roles))
+	for name, role := range config.roles {
+		srv, err := newRPCServer(apis, nil, config)
+		if err != nil {
+			for _, srv := range servers {
+				srv.Stop()
+			}
+			return nil, err
+		}
+		srv.SetAccessFilter(role.check)
+		servers[name] = srv
+	}
+	return servers, nil
+}
+
+// rpcRole is a named allowlist of RPC methods and namespaces.
+type rpcRole struct {
+	name       string
+	all        bool
+	namespaces map[string]bool
+	methods    map[string]bool
+}
+
+// newRPCRole creates a role from its allowlist entries. An entry is either a
+// method ("miner_start"), a namespace ("miner" or "miner_*") or "*".
+func newRPCRole(name string, entries []string) (*rpcRole, error) {
+	if name == "" {
+		return nil, errors.New("empty role name")
+	}
+	role := &rpcRole{
+		name:       name,
+		namespaces: make(map[string]bool),
+		methods:    make(map[string]bool),
+	}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "*":
+			role.all = true
+		case entry == "" || strings.HasPrefix(entry, "_") || strings.HasSuffix(entry, "_"):
+			return nil, fmt.Errorf("role %q: invalid allowlist entry %q", name, entry)
+		case strings.HasSuffix(entry, "_*"):
+			role.namespaces[strings.TrimSuffix(entry, "_*")] = true
+		case strings.Contains(entry, "*"):
+			return nil, fmt.Errorf("role %q: unsupported wildcard in %q", name, entry)
+		case strings.Contains(entry, "_"):
+			role.methods[entry] = true
+		default:
+			role.namespaces[entry] = true
+		}
+	}
+	return role, nil
+}
+
+// newRPCRoles parses the role definitions of the node configuration.
+func newRPCRoles(config map[string][]string) (map[string]*rpcRole, error) {
+	roles := make(map[string]*rpcRole, len(config))
+	for name, entries := range config {
+		role, err := newRPCRole(name, entries)
+		if err != nil {
+			return nil, err
+		}
+		roles[name] = role
+	}
+	return roles, nil
+}
+
+// allows reports whether the role may invoke the given method. The methods of
+// the rpc metadata namespace are always allowed, clients need them to discover
+// the available modules.
+func (r *rpcRole) allows(method string) bool {
+	if r.all || r.methods[method] {
+		return true
+	}
+	namespace, _, _ := strings.Cut(method, "_")
+	return namespace == rpc.MetadataApi || r.namespaces[namespace]
+}
+
+// check implements rpc.AccessFilter.
+func (r *rpcRole) check(method string) error {
+	if r.allows(method) {
+		return nil
+	}
+	return fmt.Errorf("not permitted for role %q", r.name)
+}
+
+// roleHandler dispatches requests to the handler of the role selected by the
+// authentication layer. Requests without a role are served by the default handler.
+type roleHandler struct {
+	def   http.Handler
+	roles map[string]http.Handler
+}
+
+func newRoleHandler(def http.Handler, roles map[string]http.Handler) http.Handler {
+	return &roleHandler{def: def, roles: roles}
+}
+
+// ServeHTTP implements http.Handler
+func (h *roleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	role, ok := r.Context().Value(rpcRoleKey{}).(string)
+	if !ok || role == "" {
+		h.def.ServeHTTP(w, r)
+		return
+	}
+	handler, ok := h.roles[role]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown role %q", role), http.StatusForbidden)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
 // RegisterApis checks the given modules' availability, generates an allowlist based on the allowed modules,
 // and then registers all of the APIs exposed by the services.
 func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
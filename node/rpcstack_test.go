@@ -18,6 +18,7 @@ package node
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -449,6 +450,97 @@ func TestJWT(t *testing.T) {
 	srv.stop()
 }
 
+func TestRPCRoleAllowlist(t *testing.T) {
+	role, err := newRPCRole("mining-admin", []string{"miner", "eth_*", "admin_nodeInfo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for method, want := range map[string]bool{
+		"miner_start":             true,
+		"eth_blockNumber":         true,
+		"admin_nodeInfo":          true,
+		"admin_addPeer":           false,
+		"security_addToWhitelist": false,
+		"rpc_modules":             true,
+	} {
+		if have := role.allows(method); have != want {
+			t.Errorf("method %s: have %v, want %v", method, have, want)
+		}
+	}
+	all, err := newRPCRole("super-admin", []string{"*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !all.allows("security_addToBlacklist") {
+		t.Error("wildcard role denied a method")
+	}
+	for _, entries := range [][]string{{""}, {"miner_"}, {"_start"}, {"miner_st*"}} {
+		if _, err := newRPCRole("bad", entries); err == nil {
+			t.Errorf("entries %q: expected error", entries)
+		}
+	}
+	if _, err := newRPCRole("", nil); err == nil {
+		t.Error("expected error for empty role name")
+	}
+}
+
+func TestJWTRoles(t *testing.T) {
+	var secret = []byte("secret")
+	roles, err := newRPCRoles(map[string][]string{
+		"greeter": {"test_greet"},
+		"reader":  {"eth"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := rpcEndpointConfig{jwtSecret: secret, roles: roles}
+	srv := createAndStartServer(t, &httpConfig{rpcEndpointConfig: cfg}, false, nil, nil)
+	defer srv.stop()
+	url := fmt.Sprintf("http://%v", srv.listenAddr())
+
+	token := func(role string) string {
+		claims := testClaim{"iat": time.Now().Unix()}
+		if role != "" {
+			claims["role"] = role
+		}
+		ss, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		return "Bearer " + ss
+	}
+	call := func(role string) (int, *rpcErrorResponse) {
+		resp := rpcRequest(t, url, "test_greet", "Authorization", token(role))
+		var result struct {
+			Error *rpcErrorResponse `json:"error"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return resp.StatusCode, result.Error
+	}
+	if status, rpcErr := call(""); status != http.StatusOK || rpcErr != nil {
+		t.Errorf("no role: status %d, error %v", status, rpcErr)
+	}
+	if status, rpcErr := call("greeter"); status != http.StatusOK || rpcErr != nil {
+		t.Errorf("permitted role: status %d, error %v", status, rpcErr)
+	}
+	status, rpcErr := call("reader")
+	if status != http.StatusOK || rpcErr == nil {
+		t.Fatalf("denied role: status %d, error %v", status, rpcErr)
+	}
+	if rpcErr.Code != -32004 || !strings.Contains(rpcErr.Message, `role "reader"`) {
+		t.Errorf("denied role: wrong error %d %q", rpcErr.Code, rpcErr.Message)
+	}
+	if status, _ := call("unknown"); status != http.StatusForbidden {
+		t.Errorf("unknown role: status %d, want %d", status, http.StatusForbidden)
+	}
+}
+
+type rpcErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
 func TestGzipHandler(t *testing.T) {
 	type gzipTest struct {
 		name    string
@@ -368,6 +368,14 @@ func (c *PoIConfig) String() string {
 	return "poi"
 }
 
+// FeeExemptConfig is the set of system accounts (e.g. oracles and bridge
+// relayers) whose transactions are accepted below the base fee and the pool
+// price limit.
+type FeeExemptConfig struct {
+	Accounts []common.Address `json:"accounts"` // Senders exempt from transaction fees
+	GasLimit uint64           `json:"gasLimit"` // Gas per block available to fee exempt transactions (0 = unlimited)
+}
+
 
 // ChainConfig is the core config which determines the blockchain settings.
 //
@@ -405,6 +413,15 @@ type ChainConfig struct {
 	PragueTime   *uint64 `json:"pragueTime,omitempty"`   // Prague switch time (nil = no fork, 0 = already on prague)
 	VerkleTime   *uint64 `json:"verkleTime,omitempty"`   // Verkle switch time (nil = no fork, 0 = already on verkle)
 
+	// Chain specific forks, scheduled by block number
+
+	FeePayerBlock  *big.Int `json:"feePayerBlock,omitempty"`  // Fee payer transactions switch block (nil = no fork, 0 = already activated)
+	FeeExemptBlock *big.Int `json:"feeExemptBlock,omitempty"` // Fee exempt system accounts switch block (nil = no fork, 0 = already activated)
+
+	// FeeExempt lists the system accounts allowed to transact without paying
+	// for gas from FeeExemptBlock on (nil = no fee exempt accounts).
+	FeeExempt *FeeExemptConfig `json:"feeExempt,omitempty"`
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -504,6 +521,12 @@ func (c *ChainConfig) Description() string {
 	if c.GrayGlacierBlock != nil {
 		banner += fmt.Sprintf(" - Gray Glacier:                #%-8v (https://github.com/ethereum/execution-specs/blob/master/network-upgrades/mainnet-upgrades/gray-glacier.md)\n", c.GrayGlacierBlock)
 	}
+	if c.FeePayerBlock != nil {
+		banner += fmt.Sprintf(" - Fee payer transactions:      #%-8v\n", c.FeePayerBlock)
+	}
+	if c.FeeExemptBlock != nil {
+		banner += fmt.Sprintf(" - Fee exempt accounts:         #%-8v\n", c.FeeExemptBlock)
+	}
 	banner += "\n"
 
 	// Add a special section for the merge as it's non-obvious
@@ -610,6 +633,26 @@ func (c *ChainConfig) IsGrayGlacier(num *big.Int) bool {
 	return isBlockForked(c.GrayGlacierBlock, num)
 }
 
+// IsFeePayer returns whether num is either equal to the fee payer transactions
+// fork block or greater.
+func (c *ChainConfig) IsFeePayer(num *big.Int) bool {
+	return isBlockForked(c.FeePayerBlock, num)
+}
+
+// IsFeeExempt returns whether transactions sent by addr in block num are exempt
+// from paying for gas.
+func (c *ChainConfig) IsFeeExempt(num *big.Int, addr common.Address) bool {
+	if c.FeeExempt == nil || !isBlockForked(c.FeeExemptBlock, num) {
+		return false
+	}
+	for _, account := range c.FeeExempt.Accounts {
+		if account == addr {
+			return true
+		}
+	}
+	return false
+}
+
 // IsTerminalPoWBlock returns whether the given block is the last block of PoW stage.
 func (c *ChainConfig) IsTerminalPoWBlock(parentTotalDiff *big.Int, totalDiff *big.Int) bool {
 	if c.TerminalTotalDifficulty == nil {
@@ -787,6 +830,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkBlockIncompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, headNumber) {
 		return newBlockCompatError("Merge netsplit fork block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	if isForkBlockIncompatible(c.FeePayerBlock, newcfg.FeePayerBlock, headNumber) {
+		return newBlockCompatError("Fee payer fork block", c.FeePayerBlock, newcfg.FeePayerBlock)
+	}
+	if isForkBlockIncompatible(c.FeeExemptBlock, newcfg.FeeExemptBlock, headNumber) {
+		return newBlockCompatError("Fee exempt fork block", c.FeeExemptBlock, newcfg.FeeExemptBlock)
+	}
 	if isForkTimestampIncompatible(c.ShanghaiTime, newcfg.ShanghaiTime, headTimestamp) {
 		return newTimestampCompatError("Shanghai fork timestamp", c.ShanghaiTime, newcfg.ShanghaiTime)
 	}
@@ -92,6 +92,17 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToBlock: 30,
 			},
 		},
+		{
+			stored:    &ChainConfig{FeeExemptBlock: big.NewInt(10)},
+			new:       &ChainConfig{FeeExemptBlock: big.NewInt(20)},
+			headBlock: 15,
+			wantErr: &ConfigCompatError{
+				What:          "Fee exempt fork block",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(20),
+				RewindToBlock: 9,
+			},
+		},
 		{
 			stored:        &ChainConfig{ShanghaiTime: newUint64(10)},
 			new:           &ChainConfig{ShanghaiTime: newUint64(20)},
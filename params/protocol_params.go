@@ -173,6 +173,11 @@ const (
 
 	BlobTxTargetBlobGasPerBlock = 3 * BlobTxBlobGasPerBlob // Target consumable blob gas for data blobs per block (for 1559-like pricing)
 	MaxBlobGasPerBlock          = 6 * BlobTxBlobGasPerBlob // Maximum consumable blob gas for data blobs per block
+
+	// BuildBudgetRatio is the share of the consensus block period allowed for
+	// filling a block with transactions by default. The rest is left for
+	// finalizing, sealing and propagating the block.
+	BuildBudgetRatio = 0.5
 )
 
 // Gas discount table for BLS12-381 G1 and G2 multi exponentiation operations
@@ -55,12 +55,14 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(accessDeniedError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeAccessDenied     = -32004
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -81,6 +83,20 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("the method %s does not exist/is not available", e.method)
 }
 
+// accessDeniedError is returned when the server's access filter rejects a method.
+type accessDeniedError struct {
+	method string
+	reason error
+}
+
+func (e *accessDeniedError) ErrorCode() int { return errcodeAccessDenied }
+
+func (e *accessDeniedError) Error() string {
+	return fmt.Sprintf("access to method %s denied: %v", e.method, e.reason)
+}
+
+func (e *accessDeniedError) Unwrap() error { return e.reason }
+
 type notificationsUnsupportedError struct{}
 
 func (e notificationsUnsupportedError) Error() string {
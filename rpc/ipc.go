@@ -39,6 +39,12 @@ func (s *Server) ServeListener(l net.Listener) error {
 	}
 }
 
+// ListenIPC creates a listener on the given IPC endpoint. On Unix any leftover socket
+// file is removed and the new one is only accessible by the current user.
+func ListenIPC(endpoint string) (net.Listener, error) {
+	return ipcListen(endpoint)
+}
+
 // DialIPC create a new IPC client that connects to the given endpoint. On Unix it assumes
 // the endpoint is the full path to a unix socket, and Windows the endpoint is an
 // identifier for a named pipe.
@@ -87,6 +87,23 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// AccessFilter decides whether a method may be invoked on a server. It returns nil
+// to permit the call, or an error describing why the call was denied.
+type AccessFilter func(method string) error
+
+// SetAccessFilter installs a filter that is consulted before every method call and
+// subscription. Denied requests are answered with an 'access denied' error. The
+// filter receives the full method name, e.g. "miner_start" or "eth_subscribe".
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetAccessFilter(filter AccessFilter) {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	s.services.filter = filter
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -19,6 +19,8 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net"
 	"os"
@@ -192,3 +194,42 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestServerAccessFilter(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetAccessFilter(func(method string) error {
+		if strings.HasPrefix(method, "nftest_") {
+			return errors.New("namespace not permitted")
+		}
+		return nil
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatalf("permitted call failed: %v", err)
+	}
+	checkDenied := func(err error) {
+		t.Helper()
+		re, ok := err.(Error)
+		if !ok {
+			t.Fatalf("wrong error type: %v", err)
+		}
+		if re.ErrorCode() != errcodeAccessDenied {
+			t.Fatalf("wrong error code: have %d want %d", re.ErrorCode(), errcodeAccessDenied)
+		}
+	}
+	var i int
+	checkDenied(client.Call(&i, "nftest_echo", 1))
+
+	_, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 1, 1)
+	checkDenied(err)
+
+	// Unknown methods are still reported as missing rather than denied.
+	err = client.Call(&i, "nftest_missing")
+	if re, ok := err.(Error); !ok || re.ErrorCode() != -32601 {
+		t.Fatalf("wrong error for unknown method: %v", err)
+	}
+}
@@ -38,6 +38,7 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	filter   AccessFilter // optional method access control
 }
 
 // service represents a registered object.
@@ -102,6 +103,21 @@ func (r *serviceRegistry) callback(method string) *callback {
 	return r.services[before].callbacks[after]
 }
 
+// checkAccess runs the access filter, if any, for the given RPC method name.
+func (r *serviceRegistry) checkAccess(method string) error {
+	r.mu.Lock()
+	filter := r.filter
+	r.mu.Unlock()
+
+	if filter == nil {
+		return nil
+	}
+	if err := filter(method); err != nil {
+		return &accessDeniedError{method: method, reason: err}
+	}
+	return nil
+}
+
 // subscription returns a subscription callback in the given service.
 func (r *serviceRegistry) subscription(service, name string) *callback {
 	r.mu.Lock()
@@ -275,6 +275,20 @@ func (db *Database) Recover(target common.Hash) error {
 	return pdb.Recover(target, loader)
 }
 
+// HistoricReader constructs a reader for accessing the historical state with
+// the given root, reconstructed from the state histories. It's only supported
+// by path-based database and will return an error for others.
+func (db *Database) HistoricReader(root common.Hash) (*pathdb.HistoricalStateReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	if db.config.IsVerkle {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricReader(root, trie.NewMerkleLoader(db))
+}
+
 // Recoverable returns the indicator if the specified state is enabled to be
 // recovered. It's only supported by path-based database and will return an
 // error for others.
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -606,3 +607,67 @@ func copyStorages(set map[common.Hash]map[common.Hash][]byte) map[common.Hash]ma
 	}
 	return copied
 }
+
+func TestHistoricReader(t *testing.T) {
+	tester := newTester(t, 0)
+	defer tester.release()
+
+	var (
+		index  = tester.bottomIndex()
+		bottom = tester.roots[index]
+		loader = newHashLoader(tester.snapAccounts[bottom], tester.snapStorages[bottom])
+	)
+	// The disk layer and the layers above are not historical
+	for _, root := range []common.Hash{bottom, tester.roots[index+1], {0x1}} {
+		if _, err := tester.db.HistoricReader(root, loader); err == nil {
+			t.Fatalf("Expected error for non-historical state %x", root)
+		}
+	}
+	for i := index - 1; i >= 0; i -= 16 {
+		root := tester.roots[i]
+		reader, err := tester.db.HistoricReader(root, loader)
+		if err != nil {
+			t.Fatalf("Failed to open historic reader, root: %x, err: %v", root, err)
+		}
+		for addrHash, blob := range tester.snapAccounts[root] {
+			addr := tester.preimages[addrHash]
+			want, _ := types.FullAccount(blob)
+			have, err := reader.Account(addr)
+			if err != nil {
+				t.Fatalf("Failed to read account, root: %x, address: %x, err: %v", root, addr, err)
+			}
+			if !reflect.DeepEqual(have, want) {
+				t.Fatalf("Account mismatch, root: %x, address: %x, want: %v, got: %v", root, addr, want, have)
+			}
+			for slotHash, slot := range tester.snapStorages[root][addrHash] {
+				_, want, _, _ := rlp.Split(slot)
+				have, err := reader.Storage(addr, slotHash)
+				if err != nil {
+					t.Fatalf("Failed to read slot, root: %x, address: %x, slot: %x, err: %v", root, addr, slotHash, err)
+				}
+				if !bytes.Equal(have, want) {
+					t.Fatalf("Slot mismatch, root: %x, address: %x, slot: %x, want: %x, got: %x", root, addr, slotHash, want, have)
+				}
+			}
+		}
+		// Accounts created afterwards are not present in the historical state
+		for addrHash := range tester.snapAccounts[bottom] {
+			if _, ok := tester.snapAccounts[root][addrHash]; ok {
+				continue
+			}
+			if have, err := reader.Account(tester.preimages[addrHash]); have != nil || err != nil {
+				t.Fatalf("Unexpected account, root: %x, address: %x, got: %v, err: %v", root, tester.preimages[addrHash], have, err)
+			}
+		}
+	}
+	// The states too far below the disk layer are not served
+	defer func(depth uint64) { maxHistoricDepth = depth }(maxHistoricDepth)
+	maxHistoricDepth = 1
+
+	if _, err := tester.db.HistoricReader(tester.roots[index-1], loader); err != nil {
+		t.Fatalf("Failed to open historic reader within depth limit: %v", err)
+	}
+	if _, err := tester.db.HistoricReader(tester.roots[index-2], loader); err == nil {
+		t.Fatal("Expected error for historical state beyond depth limit")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie/triestate"
+	"golang.org/x/exp/slices"
+)
+
+// HistoricalStateReader is a read-only reader of a historical state which has
+// left the in-memory layers. Each state history holds the values of the entries
+// mutated by a transition as they were before it, so the value of an entry in
+// the historical state is the one held by the first history made since which
+// mutates it. Entries left unchanged since are read from the disk layer.
+//
+// The state histories are not indexed by entry, every lookup scans them in
+// order, which gets slower as the historical state gets older. To bound the
+// cost of the lookups, only the states at most maxHistoricDepth histories
+// below the disk layer are served.
+type HistoricalStateReader struct {
+	db     *Database
+	loader triestate.TrieLoader // Loader of the tries of the disk layer
+	root   common.Hash          // Root of the historical state
+	id     uint64               // State id of the historical state
+}
+
+// maxHistoricDepth is the maximum number of state histories scanned by a lookup
+// in a historical state.
+var maxHistoricDepth = uint64(8192)
+
+// HistoricReader constructs a reader of the historical state with the given
+// root. The state must be a canonical state below the disk layer, with all the
+// state histories made since retained, and at most maxHistoricDepth of them.
+func (db *Database) HistoricReader(root common.Hash, loader triestate.TrieLoader) (*HistoricalStateReader, error) {
+	if db.freezer == nil {
+		return nil, errors.New("state histories are not available")
+	}
+	root = types.TrieRootHash(root)
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	bottom := db.tree.bottom().stateID()
+	if *id >= bottom {
+		return nil, fmt.Errorf("state %#x is not historical", root)
+	}
+	if bottom-*id > maxHistoricDepth {
+		return nil, fmt.Errorf("state %#x is too old, %d histories behind the disk layer (limit %d)", root, bottom-*id, maxHistoricDepth)
+	}
+	m, err := readHistoryMeta(db.freezer, *id+1)
+	if err != nil {
+		return nil, err
+	}
+	if m.parent != root {
+		return nil, errUnexpectedHistory
+	}
+	return &HistoricalStateReader{db: db, loader: loader, root: root, id: *id}, nil
+}
+
+// Root returns the root of the historical state.
+func (r *HistoricalStateReader) Root() common.Hash {
+	return r.root
+}
+
+// Account returns the account with the given address in the historical state,
+// nil if it didn't exist.
+func (r *HistoricalStateReader) Account(addr common.Address) (*types.StateAccount, error) {
+	blob, err := r.lookup(func(id uint64) ([]byte, bool, error) {
+		_, data, found, err := readHistoryAccount(r.db.freezer, id, addr)
+		return data, found, err
+	}, func(root common.Hash) ([]byte, error) {
+		tr, err := r.loader.OpenTrie(root)
+		if err != nil {
+			return nil, err
+		}
+		return tr.Get(crypto.Keccak256(addr.Bytes()))
+	})
+	if len(blob) == 0 || err != nil {
+		return nil, err
+	}
+	// Histories hold accounts in the slim format, which decodes the full one too
+	return types.FullAccount(blob)
+}
+
+// Storage returns the value of the storage slot with the given hash of the
+// account with the given address in the historical state, nil if it didn't
+// exist.
+func (r *HistoricalStateReader) Storage(addr common.Address, slotHash common.Hash) ([]byte, error) {
+	blob, err := r.lookup(func(id uint64) ([]byte, bool, error) {
+		m, err := readHistoryMeta(r.db.freezer, id)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, incomplete := slices.BinarySearchFunc(m.incomplete, addr, common.Address.Cmp); incomplete {
+			return nil, false, fmt.Errorf("incomplete storage history of %#x in state history %d", addr, id)
+		}
+		index, _, found, err := readHistoryAccount(r.db.freezer, id, addr)
+		if !found || err != nil {
+			return nil, false, err
+		}
+		return readHistorySlot(r.db.freezer, id, index, slotHash)
+	}, func(root common.Hash) ([]byte, error) {
+		tr, err := r.loader.OpenTrie(root)
+		if err != nil {
+			return nil, err
+		}
+		addrHash := crypto.Keccak256Hash(addr.Bytes())
+		blob, err := tr.Get(addrHash.Bytes())
+		if len(blob) == 0 || err != nil {
+			return nil, err
+		}
+		account, err := types.FullAccount(blob)
+		if err != nil {
+			return nil, err
+		}
+		st, err := r.loader.OpenStorageTrie(root, addrHash, account.Root)
+		if err != nil {
+			return nil, err
+		}
+		return st.Get(slotHash.Bytes())
+	})
+	if len(blob) == 0 || err != nil {
+		return nil, err
+	}
+	_, content, _, err := rlp.Split(blob)
+	return content, err
+}
+
+// lookup resolves an entry in the historical state, scanning the state
+// histories made since with find, and reading it from the disk layer with
+// read if none of them mutates it.
+func (r *HistoricalStateReader) lookup(find func(id uint64) ([]byte, bool, error), read func(root common.Hash) ([]byte, error)) ([]byte, error) {
+	for {
+		// The histories up to the tail id are pruned
+		tail, err := r.db.freezer.Tail()
+		if err != nil {
+			return nil, err
+		}
+		if r.id < tail {
+			return nil, fmt.Errorf("state history of %#x is pruned", r.root)
+		}
+		dl := r.db.tree.bottom()
+		if dl.stateID()-r.id > maxHistoricDepth {
+			return nil, fmt.Errorf("state %#x is too old, %d histories behind the disk layer (limit %d)", r.root, dl.stateID()-r.id, maxHistoricDepth)
+		}
+		for id := r.id + 1; id <= dl.stateID(); id++ {
+			blob, found, err := find(id)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				return blob, nil
+			}
+		}
+		blob, err := read(dl.rootHash())
+		if err == nil {
+			return blob, nil
+		}
+		// The disk layer may have been superseded during the lookup, in which
+		// case the new one is scanned up to.
+		if r.db.tree.bottom() == dl {
+			return nil, err
+		}
+	}
+}
+
+// readHistoryMeta reads and decodes the metadata of the state history with
+// the given id.
+func readHistoryMeta(freezer *rawdb.ResettableFreezer, id uint64) (*meta, error) {
+	blob := rawdb.ReadStateHistoryMeta(freezer, id)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("state history not found %d", id)
+	}
+	var m meta
+	if err := m.decode(blob); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// readHistoryAccount looks up an account in the state history with the given
+// id, returning its index and its data if the history mutates it.
+func readHistoryAccount(freezer *rawdb.ResettableFreezer, id uint64, addr common.Address) (accountIndex, []byte, bool, error) {
+	indexes := rawdb.ReadStateAccountIndex(freezer, id)
+	if len(indexes)%accountIndexSize != 0 {
+		return accountIndex{}, nil, false, errors.New("account index buffer is corrupted")
+	}
+	n := len(indexes) / accountIndexSize
+	pos := sort.Search(n, func(i int) bool {
+		return bytes.Compare(indexes[i*accountIndexSize:i*accountIndexSize+common.AddressLength], addr.Bytes()) >= 0
+	})
+	if pos == n || !bytes.Equal(indexes[pos*accountIndexSize:pos*accountIndexSize+common.AddressLength], addr.Bytes()) {
+		return accountIndex{}, nil, false, nil
+	}
+	var index accountIndex
+	index.decode(indexes[pos*accountIndexSize : (pos+1)*accountIndexSize])
+
+	data := rawdb.ReadStateAccountHistory(freezer, id)
+	if last := index.offset + uint32(index.length); uint32(len(data)) < last {
+		return accountIndex{}, nil, false, errors.New("account data buffer is corrupted")
+	}
+	return index, data[index.offset : index.offset+uint32(index.length)], true, nil
+}
+
+// readHistorySlot looks up a storage slot of an account in the state history
+// with the given id, returning its data if the history mutates it.
+func readHistorySlot(freezer *rawdb.ResettableFreezer, id uint64, account accountIndex, slotHash common.Hash) ([]byte, bool, error) {
+	if account.storageSlots == 0 {
+		return nil, false, nil
+	}
+	var (
+		indexes = rawdb.ReadStateStorageIndex(freezer, id)
+		start   = int(account.storageOffset) * slotIndexSize
+		end     = int(account.storageOffset+account.storageSlots) * slotIndexSize
+	)
+	if len(indexes) < end {
+		return nil, false, errors.New("storage index buffer is corrupted")
+	}
+	indexes = indexes[start:end]
+
+	n := int(account.storageSlots)
+	pos := sort.Search(n, func(i int) bool {
+		return bytes.Compare(indexes[i*slotIndexSize:i*slotIndexSize+common.HashLength], slotHash.Bytes()) >= 0
+	})
+	if pos == n || !bytes.Equal(indexes[pos*slotIndexSize:pos*slotIndexSize+common.HashLength], slotHash.Bytes()) {
+		return nil, false, nil
+	}
+	var index slotIndex
+	index.decode(indexes[pos*slotIndexSize : (pos+1)*slotIndexSize])
+
+	data := rawdb.ReadStateStorageHistory(freezer, id)
+	if last := index.offset + uint32(index.length); uint32(len(data)) < last {
+		return nil, false, errors.New("storage data buffer is corrupted")
+	}
+	return data[index.offset : index.offset+uint32(index.length)], true, nil
+}